@@ -0,0 +1,67 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/epinio/epinio/acceptance/helpers/catalog"
+	v1 "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Secret environment variables", func() {
+	var namespace, app string
+	containerImageURL := "splatform/sample-app"
+
+	BeforeEach(func() {
+		namespace = catalog.NewNamespaceName()
+		env.SetupAndTargetNamespace(namespace)
+
+		app = catalog.NewAppName()
+		env.MakeContainerImageApp(app, 1, containerImageURL)
+		setAppEnvSecret(namespace, app, "DATABASE_PASSWORD", "hunter2")
+	})
+
+	AfterEach(func() {
+		env.DeleteNamespace(namespace)
+	})
+
+	get := func(path string, into interface{}) {
+		response, err := env.Curl("GET", fmt.Sprintf("%s%s/%s", serverURL, v1.Root, path), strings.NewReader(""))
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		bodyBytes, err := ioutil.ReadAll(response.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusOK), string(bodyBytes))
+		Expect(json.Unmarshal(bodyBytes, into)).To(Succeed())
+	}
+
+	It("masks their values in the application details, unless revealed", func() {
+		appObj := appFromAPI(namespace, app)
+		Expect(appObj.Configuration.Environment).To(HaveKeyWithValue("DATABASE_PASSWORD", models.EnvMaskedValue))
+
+		var revealed models.App
+		get(v1.Routes.Path("AppShow", namespace, app)+"?reveal=true", &revealed)
+		Expect(revealed.Configuration.Environment).To(HaveKeyWithValue("DATABASE_PASSWORD", "hunter2"))
+
+		var apps models.AppList
+		get(v1.Routes.Path("Apps", namespace), &apps)
+		Expect(apps).To(HaveLen(1))
+		Expect(apps[0].Configuration.Environment).To(HaveKeyWithValue("DATABASE_PASSWORD", models.EnvMaskedValue))
+	})
+
+	It("masks their value when shown, unless revealed", func() {
+		var variable models.EnvVariable
+		get(v1.Routes.Path("EnvShow", namespace, app, "DATABASE_PASSWORD"), &variable)
+		Expect(variable.Value).To(Equal(models.EnvMaskedValue))
+
+		get(v1.Routes.Path("EnvShow", namespace, app, "DATABASE_PASSWORD")+"?reveal=true", &variable)
+		Expect(variable.Value).To(Equal("hunter2"))
+	})
+})
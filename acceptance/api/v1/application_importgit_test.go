@@ -60,11 +60,31 @@ var _ = Describe("AppImportGit Endpoint", func() {
 			Expect(err).ToNot(HaveOccurred(), string(bodyBytes))
 			Expect(response.StatusCode).To(Equal(http.StatusOK), string(bodyBytes))
 
-			var importResponse models.ImportGitResponse
-			err = json.Unmarshal(bodyBytes, &importResponse)
+			var operation models.Operation
+			err = json.Unmarshal(bodyBytes, &operation)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(importResponse.BlobUID).ToNot(BeEmpty())
-			Expect(importResponse.BlobUID).To(MatchRegexp(".+-.+-.+-.+-.+"))
+			Expect(operation.ID).ToNot(BeEmpty())
+			Expect(operation.Kind).To(Equal(models.OperationImportGit))
+
+			By("waiting for the import to finish")
+			Eventually(func() string {
+				response, err := env.Curl("GET", serverURL+v1.Root+"/"+v1.Routes.Path("OperationShow", operation.ID), strings.NewReader(""))
+				Expect(err).ToNot(HaveOccurred())
+				defer response.Body.Close()
+
+				bodyBytes, err := ioutil.ReadAll(response.Body)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.StatusCode).To(Equal(http.StatusOK), string(bodyBytes))
+
+				err = json.Unmarshal(bodyBytes, &operation)
+				Expect(err).ToNot(HaveOccurred())
+				return operation.State
+			}, "2m", "2s").Should(Equal(models.OperationSucceeded), operation.Error)
+
+			result := models.ImportGitResponse{}
+			Expect(operation.DecodeResult(&result)).To(Succeed())
+			Expect(result.BlobUID).ToNot(BeEmpty())
+			Expect(result.BlobUID).To(MatchRegexp(".+-.+-.+-.+-.+"))
 		})
 	})
 })
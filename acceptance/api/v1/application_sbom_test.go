@@ -0,0 +1,70 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/epinio/epinio/acceptance/helpers/catalog"
+	v1 "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AppSBOM Endpoint", func() {
+	var (
+		namespace string
+		app       string
+	)
+
+	BeforeEach(func() {
+		namespace = catalog.NewNamespaceName()
+		env.SetupAndTargetNamespace(namespace)
+		app = catalog.NewAppName()
+	})
+
+	AfterEach(func() {
+		env.DeleteApp(app)
+		env.DeleteNamespace(namespace)
+	})
+
+	sbomRequest := func() (int, []byte) {
+		response, err := env.Curl("GET", fmt.Sprintf("%s%s/%s",
+			serverURL, v1.Root, v1.Routes.Path("AppSBOM", namespace, app)), strings.NewReader(""))
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		bodyBytes, err := ioutil.ReadAll(response.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return response.StatusCode, bodyBytes
+	}
+
+	It("returns the SBOM and provenance of the staged image", func() {
+		env.MakeApp(app, 1, true)
+
+		var bodyBytes []byte
+		Eventually(func() int {
+			var status int
+			status, bodyBytes = sbomRequest()
+			return status
+		}, 2*time.Minute, 5*time.Second).Should(Equal(http.StatusOK))
+
+		sbom := models.AppSBOM{}
+		Expect(json.Unmarshal(bodyBytes, &sbom)).To(Succeed())
+		Expect(sbom.Provenance.App.Name).To(Equal(app))
+		Expect(sbom.Provenance.BlobUID).ToNot(BeEmpty())
+		Expect(sbom.Provenance.ImageDigest).ToNot(BeEmpty())
+		Expect(sbom.Documents).ToNot(BeEmpty())
+	})
+
+	It("reports applications with images not staged by Epinio", func() {
+		env.MakeContainerImageApp(app, 1, "splatform/sample-app")
+
+		status, bodyBytes := sbomRequest()
+		Expect(status).To(Equal(http.StatusNotFound), string(bodyBytes))
+	})
+})
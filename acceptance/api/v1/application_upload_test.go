@@ -1,9 +1,13 @@
 package v1_test
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/epinio/epinio/acceptance/helpers/catalog"
 	"github.com/epinio/epinio/acceptance/testenv"
@@ -61,3 +65,61 @@ var _ = Describe("AppUpload Endpoint", func() {
 		})
 	})
 })
+
+var _ = Describe("AppUploadSession Endpoints", func() {
+	var namespace string
+
+	BeforeEach(func() {
+		namespace = catalog.NewNamespaceName()
+		env.SetupAndTargetNamespace(namespace)
+	})
+
+	AfterEach(func() {
+		env.DeleteNamespace(namespace)
+	})
+
+	call := func(method, uri string, body io.Reader, into interface{}) {
+		resp, err := env.Curl(method, uri, body)
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+
+		bodyBytes, err := ioutil.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK), string(bodyBytes))
+		Expect(json.Unmarshal(bodyBytes, into)).To(Succeed())
+	}
+
+	It("assembles the uploaded parts into a blob", func() {
+		tarball, err := ioutil.ReadFile(testenv.TestAssetPath("sample-app.tar"))
+		Expect(err).ToNot(HaveOccurred())
+
+		session := models.UploadSession{}
+		call("POST", serverURL+v1.Root+"/"+v1.Routes.Path("AppUploadSessionCreate", namespace, "testapp"),
+			strings.NewReader(`{"hash":"some-hash"}`), &session)
+		Expect(session.BlobUID).ToNot(BeEmpty())
+		Expect(session.UploadID).ToNot(BeEmpty())
+
+		sessionURL := func(route string, params ...interface{}) string {
+			params = append([]interface{}{namespace, "testapp", session.BlobUID}, params...)
+			return serverURL + v1.Root + "/" + v1.Routes.Path(route, params...) + "?upload=" + url.QueryEscape(session.UploadID)
+		}
+
+		part := models.UploadPart{}
+		call("PUT", sessionURL("AppUploadSessionPart", "1"), bytes.NewReader(tarball), &part)
+		Expect(part.Number).To(Equal(1))
+		Expect(part.Size).To(Equal(int64(len(tarball))))
+
+		stored := models.UploadSession{}
+		call("GET", sessionURL("AppUploadSessionShow"), nil, &stored)
+		Expect(stored.Parts).To(Equal([]models.UploadPart{part}))
+
+		upload := models.UploadResponse{}
+		call("POST", sessionURL("AppUploadSessionComplete"), nil, &upload)
+		Expect(upload.BlobUID).To(Equal(session.BlobUID))
+
+		resp, err := env.Curl("GET", sessionURL("AppUploadSessionShow"), nil)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+	})
+})
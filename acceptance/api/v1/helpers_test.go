@@ -282,3 +282,20 @@ func createApplicationWithChart(name string, namespace string, chart string) (*h
 	url := serverURL + v1.Root + "/" + v1.Routes.Path("AppCreate", namespace)
 	return env.Curl("POST", url, strings.NewReader(body))
 }
+
+func setAppEnvSecret(namespace, app, name, value string) {
+	data, err := json.Marshal(models.EnvVariableMap{name: value})
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+
+	response, err := env.Curl("POST",
+		fmt.Sprintf("%s%s/%s?secret=true",
+			serverURL, v1.Root, v1.Routes.Path("EnvSet", namespace, app)),
+		strings.NewReader(string(data)))
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	ExpectWithOffset(1, response).ToNot(BeNil())
+
+	defer response.Body.Close()
+	bodyBytes, err := ioutil.ReadAll(response.Body)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	ExpectWithOffset(1, response.StatusCode).To(Equal(http.StatusOK), string(bodyBytes))
+}
@@ -0,0 +1,57 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	api "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ImageGC Endpoint", func() {
+	var user, password string
+
+	gcRequest := func(user, password string) *http.Response {
+		endpoint := fmt.Sprintf("%s%s/%s", serverURL, api.Root, api.Routes.Path("ImageGC"))
+		request, err := http.NewRequest(http.MethodGet, endpoint, nil)
+		Expect(err).ToNot(HaveOccurred())
+		request.SetBasicAuth(user, password)
+
+		response, err := env.Client().Do(request)
+		Expect(err).ToNot(HaveOccurred())
+		return response
+	}
+
+	AfterEach(func() {
+		env.DeleteEpinioUser(user)
+	})
+
+	It("reports the old images to admins, without deleting them", func() {
+		user, password = env.CreateEpinioUser("admin", nil)
+
+		response := gcRequest(user, password)
+		defer response.Body.Close()
+		bodyBytes, err := ioutil.ReadAll(response.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusOK), string(bodyBytes))
+
+		report := models.ImageGCReport{}
+		Expect(json.Unmarshal(bodyBytes, &report)).To(Succeed())
+		Expect(report.DryRun).To(BeTrue())
+		for _, image := range report.Removed {
+			Expect(image.Deleted).To(BeFalse())
+		}
+	})
+
+	It("is forbidden for users", func() {
+		user, password = env.CreateEpinioUser("user", nil)
+
+		response := gcRequest(user, password)
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+	})
+})
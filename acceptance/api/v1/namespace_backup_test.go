@@ -0,0 +1,116 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/epinio/epinio/acceptance/helpers/catalog"
+	api "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Namespace backup API endpoints", func() {
+	var namespace, restored, app, configuration, tmpDir string
+
+	const containerImageURL = "splatform/sample-app"
+
+	BeforeEach(func() {
+		namespace = catalog.NewNamespaceName()
+		restored = catalog.NewNamespaceName()
+		app = catalog.NewAppName()
+		configuration = catalog.NewConfigurationName()
+		env.SetupAndTargetNamespace(namespace)
+
+		env.MakeConfiguration(configuration)
+		env.MakeContainerImageApp(app, 1, containerImageURL)
+		env.BindAppConfiguration(app, configuration, namespace)
+
+		var err error
+		tmpDir, err = ioutil.TempDir("", "epinio-backup")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		env.DeleteNamespace(namespace)
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	backup := func() string {
+		url := fmt.Sprintf("%s%s/%s", serverURL, api.Root, api.Routes.Path("NamespaceBackup", namespace))
+		response, err := env.Curl("GET", url, nil)
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Expect(response.Header.Get("Content-Type")).To(Equal("application/gzip"))
+
+		path := filepath.Join(tmpDir, namespace+".tar.gz")
+		out, err := os.Create(path)
+		Expect(err).ToNot(HaveOccurred())
+		defer out.Close()
+		_, err = io.Copy(out, response.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return path
+	}
+
+	restore := func(archive, as string) (int, []byte) {
+		url := fmt.Sprintf("%s%s/%s?as=%s", serverURL, api.Root, api.Routes.Path("NamespaceRestore"), as)
+		request, err := uploadRequest(url, archive)
+		Expect(err).ToNot(HaveOccurred())
+		response, err := env.Client().Do(request)
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		body, err := ioutil.ReadAll(response.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return response.StatusCode, body
+	}
+
+	It("restores a backup into a new namespace", func() {
+		archive := backup()
+
+		status, body := restore(archive, restored)
+		Expect(status).To(Equal(http.StatusOK), string(body))
+		defer env.DeleteNamespace(restored)
+
+		resp := models.NamespaceRestoreResponse{}
+		Expect(json.Unmarshal(body, &resp)).To(Succeed())
+		Expect(resp.Namespace).To(Equal(restored))
+		Expect(resp.Apps).To(ConsistOf(app))
+		Expect(resp.Configurations).To(ConsistOf(configuration))
+		Expect(resp.Warnings).To(BeEmpty())
+
+		url := fmt.Sprintf("%s%s/%s", serverURL, api.Root, api.Routes.Path("AppShow", restored, app))
+		response, err := env.Curl("GET", url, nil)
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+		restoredApp := models.App{}
+		Expect(json.NewDecoder(response.Body).Decode(&restoredApp)).To(Succeed())
+		Expect(restoredApp.Configuration.Configurations).To(ConsistOf(configuration))
+		Expect(restoredApp.ImageURL).To(Equal(containerImageURL))
+		Expect(restoredApp.Workload).ToNot(BeNil())
+	})
+
+	It("refuses to restore over an existing namespace", func() {
+		archive := backup()
+
+		status, body := restore(archive, namespace)
+		Expect(status).To(Equal(http.StatusConflict), string(body))
+	})
+
+	It("rejects files which are not archives", func() {
+		path := filepath.Join(tmpDir, "bogus.tar.gz")
+		Expect(ioutil.WriteFile(path, []byte("bogus"), 0600)).To(Succeed())
+
+		status, body := restore(path, restored)
+		Expect(status).To(Equal(http.StatusBadRequest), string(body))
+	})
+})
@@ -0,0 +1,127 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/epinio/epinio/acceptance/helpers/catalog"
+	"github.com/epinio/epinio/acceptance/helpers/proc"
+	api "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Namespace deletion preview and protection", func() {
+	var namespace, app, configuration string
+
+	const containerImageURL = "splatform/sample-app"
+
+	BeforeEach(func() {
+		namespace = catalog.NewNamespaceName()
+		app = catalog.NewAppName()
+		configuration = catalog.NewConfigurationName()
+		env.SetupAndTargetNamespace(namespace)
+
+		env.MakeConfiguration(configuration)
+		env.MakeContainerImageApp(app, 1, containerImageURL)
+		env.BindAppConfiguration(app, configuration, namespace)
+	})
+
+	AfterEach(func() {
+		env.DeleteNamespace(namespace)
+	})
+
+	deleteNamespace := func(query string) (int, []byte) {
+		url := fmt.Sprintf("%s%s/%s%s", serverURL, api.Root, api.Routes.Path("NamespaceDelete", namespace), query)
+		response, err := env.Curl("DELETE", url, strings.NewReader(""))
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		bodyBytes, err := ioutil.ReadAll(response.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return response.StatusCode, bodyBytes
+	}
+
+	verifyNamespaceExists := func() {
+		out, err := proc.Kubectl("get", "namespace", namespace)
+		Expect(err).ToNot(HaveOccurred(), out)
+	}
+
+	It("previews the deletion without deleting anything", func() {
+		status, bodyBytes := deleteNamespace("?dryrun=true")
+		Expect(status).To(Equal(http.StatusOK), string(bodyBytes))
+
+		var preview models.NamespaceDeletePreview
+		Expect(json.Unmarshal(bodyBytes, &preview)).To(Succeed())
+		Expect(preview.Namespace).To(Equal(namespace))
+		Expect(preview.Apps).To(ConsistOf(app))
+		Expect(preview.Configurations).To(ContainElement(configuration))
+		Expect(preview.Routes).ToNot(BeEmpty())
+		Expect(preview.IsBlocked()).To(BeFalse())
+
+		verifyNamespaceExists()
+	})
+
+	It("refuses to delete a protected namespace until the protection is cleared", func() {
+		protect(api.Routes.Path("NamespaceProtection", namespace), true)
+
+		status, bodyBytes := deleteNamespace("?dryrun=true")
+		Expect(status).To(Equal(http.StatusOK), string(bodyBytes))
+		var preview models.NamespaceDeletePreview
+		Expect(json.Unmarshal(bodyBytes, &preview)).To(Succeed())
+		Expect(preview.Protected).To(BeTrue())
+
+		status, bodyBytes = deleteNamespace("")
+		Expect(status).To(Equal(http.StatusForbidden), string(bodyBytes))
+		Expect(string(bodyBytes)).To(ContainSubstring("is protected"))
+		verifyNamespaceExists()
+
+		protect(api.Routes.Path("NamespaceProtection", namespace), false)
+
+		status, bodyBytes = deleteNamespace("")
+		Expect(status).To(Equal(http.StatusOK), string(bodyBytes))
+		env.VerifyNamespaceNotExist(namespace)
+
+		// Recreate the namespace for the cleanup
+		env.SetupAndTargetNamespace(namespace)
+	})
+
+	It("refuses to delete a protected application, and its namespace", func() {
+		protect(api.Routes.Path("AppProtection", namespace, app), true)
+		defer protect(api.Routes.Path("AppProtection", namespace, app), false)
+
+		url := fmt.Sprintf("%s%s/%s", serverURL, api.Root, api.Routes.Path("AppDelete", namespace, app))
+		response, err := env.Curl("DELETE", url, strings.NewReader(""))
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+
+		status, bodyBytes := deleteNamespace("")
+		Expect(status).To(Equal(http.StatusForbidden), string(bodyBytes))
+		Expect(string(bodyBytes)).To(ContainSubstring(app))
+
+		status, bodyBytes = deleteNamespace("?dryrun=true")
+		Expect(status).To(Equal(http.StatusOK), string(bodyBytes))
+		var preview models.NamespaceDeletePreview
+		Expect(json.Unmarshal(bodyBytes, &preview)).To(Succeed())
+		Expect(preview.ProtectedApps).To(ConsistOf(app))
+	})
+})
+
+// protect sets or clears the protection of the resource at the path.
+func protect(path string, protected bool) {
+	body, err := json.Marshal(models.ProtectionRequest{Protected: protected})
+	Expect(err).ToNot(HaveOccurred())
+
+	url := fmt.Sprintf("%s%s/%s", serverURL, api.Root, path)
+	response, err := env.Curl("PUT", url, strings.NewReader(string(body)))
+	Expect(err).ToNot(HaveOccurred())
+	defer response.Body.Close()
+	bodyBytes, err := ioutil.ReadAll(response.Body)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(response.StatusCode).To(Equal(http.StatusOK), string(bodyBytes))
+}
@@ -0,0 +1,112 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/epinio/epinio/acceptance/helpers/catalog"
+	"github.com/epinio/epinio/acceptance/helpers/proc"
+	api "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/internal/quota"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Namespace quota API endpoints", func() {
+	var namespace string
+
+	BeforeEach(func() {
+		namespace = catalog.NewNamespaceName()
+		env.SetupAndTargetNamespace(namespace)
+	})
+
+	AfterEach(func() {
+		env.DeleteNamespace(namespace)
+	})
+
+	endpoint := func(path string) string {
+		return fmt.Sprintf("%s%s/%s", serverURL, api.Root, path)
+	}
+
+	request := func(method, path, body string) (int, []byte) {
+		response, err := env.Curl(method, endpoint(path), strings.NewReader(body))
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		bodyBytes, err := ioutil.ReadAll(response.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return response.StatusCode, bodyBytes
+	}
+
+	showQuota := func() models.NamespaceQuotaResponse {
+		status, body := request("GET", api.Routes.Path("NamespaceQuota", namespace), "")
+		Expect(status).To(Equal(http.StatusOK), string(body))
+
+		resp := models.NamespaceQuotaResponse{}
+		Expect(json.Unmarshal(body, &resp)).To(Succeed())
+		return resp
+	}
+
+	It("sets, enforces and removes a quota", func() {
+		Expect(showQuota().Quota.IsEmpty()).To(BeTrue())
+
+		status, body := request("PUT", api.Routes.Path("NamespaceQuotaSet", namespace),
+			`{"apps":1,"configurations":1,"memory":"1Gi"}`)
+		Expect(status).To(Equal(http.StatusOK), string(body))
+
+		out, err := proc.Kubectl("get", "resourcequota", quota.ResourceQuotaName, "-n", namespace,
+			"-o", "jsonpath={.spec.hard.requests\\.memory}")
+		Expect(err).ToNot(HaveOccurred(), out)
+		Expect(out).To(Equal("1Gi"))
+
+		out, err = proc.Kubectl("get", "limitrange", quota.LimitRangeName, "-n", namespace)
+		Expect(err).ToNot(HaveOccurred(), out)
+
+		status, body = request("POST", api.Routes.Path("AppCreate", namespace),
+			fmt.Sprintf(`{"name":"%s"}`, catalog.NewAppName()))
+		Expect(status).To(Equal(http.StatusCreated), string(body))
+
+		status, body = request("POST", api.Routes.Path("AppCreate", namespace),
+			fmt.Sprintf(`{"name":"%s"}`, catalog.NewAppName()))
+		Expect(status).To(Equal(http.StatusForbidden), string(body))
+		Expect(string(body)).To(ContainSubstring("Namespace quota exceeded"))
+		Expect(string(body)).To(ContainSubstring("1 apps requested, with 1 in use of at most 1"))
+
+		resp := showQuota()
+		Expect(resp.Quota).To(Equal(models.NamespaceQuota{Apps: 1, Configurations: 1, Memory: "1Gi"}))
+		Expect(resp.Usage.Apps).To(Equal(int32(1)))
+		Expect(resp.Usage.Instances).To(Equal(int32(1)))
+
+		status, body = request("PUT", api.Routes.Path("NamespaceQuotaSet", namespace), `{}`)
+		Expect(status).To(Equal(http.StatusOK), string(body))
+		Expect(showQuota().Quota.IsEmpty()).To(BeTrue())
+
+		out, err = proc.Kubectl("get", "resourcequota", "-n", namespace, "-o", "name")
+		Expect(err).ToNot(HaveOccurred(), out)
+		Expect(out).To(BeEmpty())
+	})
+
+	It("rejects bad quotas", func() {
+		status, body := request("PUT", api.Routes.Path("NamespaceQuotaSet", namespace), `{"cpu":"plenty"}`)
+		Expect(status).To(Equal(http.StatusBadRequest), string(body))
+	})
+
+	It("forbids users to set quotas", func() {
+		user, password := env.CreateEpinioUser("user", []string{namespace})
+		defer env.DeleteEpinioUser(user)
+
+		request, err := http.NewRequest(http.MethodPut, endpoint(api.Routes.Path("NamespaceQuotaSet", namespace)),
+			strings.NewReader(`{"apps":100}`))
+		Expect(err).ToNot(HaveOccurred())
+		request.SetBasicAuth(user, password)
+
+		response, err := env.Client().Do(request)
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+	})
+})
@@ -0,0 +1,102 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/epinio/epinio/acceptance/helpers/catalog"
+	"github.com/epinio/epinio/acceptance/helpers/proc"
+	api "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/internal/policy"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policy API endpoints", func() {
+	var namespace string
+
+	BeforeEach(func() {
+		namespace = catalog.NewNamespaceName()
+		env.SetupAndTargetNamespace(namespace)
+
+		out, err := proc.Kubectl("create", "configmap", "guardrails", "-n", namespace,
+			"--from-literal="+policy.KeyMaxInstances+"=2",
+			"--from-literal="+policy.KeyForbiddenEnv+"=SECRET_*")
+		Expect(err).ToNot(HaveOccurred(), out)
+		out, err = proc.Kubectl("label", "configmap", "guardrails", "-n", namespace, policy.PolicyLabel+"=true")
+		Expect(err).ToNot(HaveOccurred(), out)
+	})
+
+	AfterEach(func() {
+		env.DeleteNamespace(namespace)
+	})
+
+	endpoint := func(path string) string {
+		return fmt.Sprintf("%s%s/%s", serverURL, api.Root, path)
+	}
+
+	request := func(method, path, body string) (int, []byte) {
+		response, err := env.Curl(method, endpoint(path), strings.NewReader(body))
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		bodyBytes, err := ioutil.ReadAll(response.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return response.StatusCode, bodyBytes
+	}
+
+	It("lists and shows the policies of the namespace", func() {
+		status, body := request("GET", api.Routes.Path("Policies", namespace), "")
+		Expect(status).To(Equal(http.StatusOK), string(body))
+
+		policies := models.PolicyList{}
+		Expect(json.Unmarshal(body, &policies)).To(Succeed())
+		Expect(policies).To(ContainElement(models.Policy{
+			Name:         "guardrails",
+			Namespace:    namespace,
+			MaxInstances: 2,
+			ForbiddenEnv: []string{"SECRET_*"},
+		}))
+
+		status, body = request("GET", api.Routes.Path("PolicyShow", namespace, "guardrails"), "")
+		Expect(status).To(Equal(http.StatusOK), string(body))
+
+		status, body = request("GET", api.Routes.Path("PolicyShow", namespace, "bogus"), "")
+		Expect(status).To(Equal(http.StatusNotFound), string(body))
+	})
+
+	It("evaluates application settings without changing anything", func() {
+		status, body := request("POST", api.Routes.Path("PolicyEvaluate", namespace),
+			`{"instances":3,"environment":["PORT","SECRET_TOKEN"]}`)
+		Expect(status).To(Equal(http.StatusOK), string(body))
+
+		result := models.PolicyEvaluationResponse{}
+		Expect(json.Unmarshal(body, &result)).To(Succeed())
+		Expect(result.Allowed).To(BeFalse())
+		Expect(result.Violations).To(HaveLen(2))
+		Expect(result.Violations[0].Rule).To(Equal(policy.RuleMaxInstances))
+		Expect(result.Violations[1].Rule).To(Equal(policy.RuleForbiddenEnv))
+
+		status, body = request("POST", api.Routes.Path("PolicyEvaluate", namespace), `{"instances":2}`)
+		Expect(status).To(Equal(http.StatusOK), string(body))
+		Expect(json.Unmarshal(body, &result)).To(Succeed())
+		Expect(result.Allowed).To(BeTrue())
+	})
+
+	It("rejects the creation of an application breaking a policy", func() {
+		app := catalog.NewAppName()
+		status, body := request("POST", api.Routes.Path("AppCreate", namespace),
+			fmt.Sprintf(`{"name":"%s","configuration":{"instances":3}}`, app))
+		Expect(status).To(Equal(http.StatusForbidden), string(body))
+		Expect(string(body)).To(ContainSubstring("Rejected by policy 'guardrails'"))
+		Expect(string(body)).To(ContainSubstring("3 instances requested, at most 2 are allowed"))
+
+		status, body = request("POST", api.Routes.Path("AppCreate", namespace),
+			fmt.Sprintf(`{"name":"%s","configuration":{"instances":2}}`, app))
+		Expect(status).To(Equal(http.StatusCreated), string(body))
+	})
+})
@@ -0,0 +1,102 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/epinio/epinio/acceptance/helpers/catalog"
+	"github.com/epinio/epinio/acceptance/helpers/proc"
+	api "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Registry credentials API endpoints", func() {
+	var namespace string
+
+	BeforeEach(func() {
+		namespace = catalog.NewNamespaceName()
+		env.SetupAndTargetNamespace(namespace)
+	})
+
+	AfterEach(func() {
+		env.DeleteNamespace(namespace)
+	})
+
+	endpoint := func(path string) string {
+		return fmt.Sprintf("%s%s/%s", serverURL, api.Root, path)
+	}
+
+	addCredential := func(body string) (int, string) {
+		response, err := env.Curl("POST", endpoint(api.Routes.Path("RegistryCredentialCreate", namespace)),
+			strings.NewReader(body))
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		bodyBytes, err := ioutil.ReadAll(response.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return response.StatusCode, string(bodyBytes)
+	}
+
+	listCredentials := func() models.RegistryCredentialList {
+		response, err := env.Curl("GET", endpoint(api.Routes.Path("RegistryCredentials", namespace)),
+			strings.NewReader(""))
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		bodyBytes, err := ioutil.ReadAll(response.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusOK), string(bodyBytes))
+
+		credentials := models.RegistryCredentialList{}
+		Expect(json.Unmarshal(bodyBytes, &credentials)).To(Succeed())
+		return credentials
+	}
+
+	It("adds, lists and removes a credential of the application service account", func() {
+		status, body := addCredential(`{"name":"private","url":"private.example.com","username":"team","password":"secret"}`)
+		Expect(status).To(Equal(http.StatusCreated), body)
+
+		Expect(listCredentials()).To(Equal(models.RegistryCredentialList{
+			{Name: "private", URL: "private.example.com", Username: "team"},
+		}))
+
+		out, err := proc.Kubectl("get", "serviceaccount", namespace, "-n", namespace,
+			"-o", "jsonpath={.imagePullSecrets[*].name}")
+		Expect(err).ToNot(HaveOccurred(), out)
+		Expect(out).To(ContainSubstring("registry-creds"))
+		Expect(out).To(ContainSubstring("registry-credential-private"))
+
+		status, body = addCredential(`{"name":"private","url":"private.example.com","username":"team","password":"secret"}`)
+		Expect(status).To(Equal(http.StatusConflict), body)
+
+		response, err := env.Curl("DELETE", endpoint(api.Routes.Path("RegistryCredentialDelete", namespace, "private")),
+			strings.NewReader(""))
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+		Expect(listCredentials()).To(BeEmpty())
+
+		out, err = proc.Kubectl("get", "serviceaccount", namespace, "-n", namespace,
+			"-o", "jsonpath={.imagePullSecrets[*].name}")
+		Expect(err).ToNot(HaveOccurred(), out)
+		Expect(out).ToNot(ContainSubstring("registry-credential-private"))
+	})
+
+	It("rejects credentials without url", func() {
+		status, body := addCredential(`{"name":"private","username":"team","password":"secret"}`)
+		Expect(status).To(Equal(http.StatusBadRequest), body)
+	})
+
+	It("reports unknown credentials", func() {
+		response, err := env.Curl("DELETE", endpoint(api.Routes.Path("RegistryCredentialDelete", namespace, "missing")),
+			strings.NewReader(""))
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+	})
+})
@@ -1,31 +1,89 @@
 package helpers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
 	"path"
+	"path/filepath"
+	"strings"
 
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 	"github.com/mholt/archiver/v3"
 	"github.com/pkg/errors"
 )
 
-func Tar(dir string) (string, string, error) {
-	files, err := ioutil.ReadDir(dir)
-	if err != nil {
-		return "", "", errors.Wrap(err, "cannot read the apps source files")
-	}
-	sources := []string{}
-	for _, f := range files {
-		// The FileInfo entries returned by ReadDir provide
-		// only the base name of the file or directory they
-		// are for. We have to add back the path of the
-		// application directory to get the proper paths to
-		// the files and directories to assemble in the
-		// tarball.
+// IgnoreFiles are the files holding the patterns of the application sources not to
+// upload, in order of preference. Only the first one found in a directory of the sources
+// is used, its patterns apply to that directory and below, like those of nested
+// `.gitignore` files. The patterns follow the syntax of `.gitignore`.
+var IgnoreFiles = []string{".epinioignore", ".gitignore", ".cfignore"}
+
+// SourceFile is a file or directory of the application sources.
+type SourceFile struct {
+	// Path is the slash-separated path of the file, relative to the sources.
+	Path string
+	Info os.FileInfo
+}
+
+// SourceFiles returns the files and directories of the application sources in the
+// directory, in lexical order. Files matched by the ignore files of the sources are left
+// out, see IgnoreFiles, as are the git config files.
+func SourceFiles(dir string) ([]SourceFile, error) {
+	patterns := []gitignore.Pattern{}
+
+	files := []SourceFile{}
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if file == dir {
+			patterns, err = ignorePatterns(patterns, dir, nil)
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
 		// Ignore git config files in the app sources.
-		if f.Name() == ".git" || f.Name() == ".gitignore" || f.Name() == ".gitmodules" || f.Name() == ".gitconfig" || f.Name() == ".git-credentials" {
-			continue
+		ignored := filepath.Dir(file) == filepath.Clean(dir) && ignoredSource(info.Name())
+		if ignored || gitignore.NewMatcher(patterns).Match(strings.Split(rel, "/"), info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		files = append(files, SourceFile{Path: rel, Info: info})
+
+		// Note: The walk is depth-first, in lexical order. The patterns of a
+		// directory are scoped to it, they do not affect the files after it.
+		if info.IsDir() {
+			patterns, err = ignorePatterns(patterns, file, strings.Split(rel, "/"))
+			return err
 		}
-		sources = append(sources, path.Join(dir, f.Name()))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read the apps source files")
+	}
+
+	return files, nil
+}
+
+// Tar packs the application sources in the directory into a tarball, placed in a new
+// temporary directory. It returns the directory and the tarball. See SourceFiles for the
+// files packed.
+func Tar(dir string) (string, string, error) {
+	files, err := SourceFiles(dir)
+	if err != nil {
+		return "", "", err
 	}
 
 	// create a tmpDir - tarball dir and POST
@@ -35,10 +93,135 @@ func Tar(dir string) (string, string, error) {
 	}
 
 	tarball := path.Join(tmpDir, "blob.tar")
-	err = archiver.Archive(sources, tarball)
+	err = archive(dir, files, tarball)
 	if err != nil {
 		return tmpDir, "", errors.Wrap(err, "can't create archive")
 	}
 
 	return tmpDir, tarball, nil
 }
+
+func archive(dir string, files []SourceFile, tarball string) error {
+	out, err := os.Create(tarball)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	tar := archiver.NewTar()
+	if err := tar.Create(out); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		source := filepath.Join(dir, filepath.FromSlash(file.Path))
+		if err := archiveFile(tar, source, file); err != nil {
+			return err
+		}
+	}
+
+	if err := tar.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func archiveFile(tar *archiver.Tar, source string, file SourceFile) error {
+	entry := archiver.File{
+		FileInfo: archiver.FileInfo{
+			FileInfo:   file.Info,
+			CustomName: file.Path,
+			SourcePath: source,
+		},
+	}
+
+	if file.Info.Mode().IsRegular() {
+		f, err := os.Open(source)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		entry.ReadCloser = f
+	}
+
+	return tar.Write(entry)
+}
+
+// ignorePatterns adds the patterns of the ignore file in the directory of the sources, if
+// there is one, to the patterns. The domain is the path of the directory in the sources,
+// the new patterns apply to it and below.
+func ignorePatterns(patterns []gitignore.Pattern, dir string, domain []string) ([]gitignore.Pattern, error) {
+	for _, name := range IgnoreFiles {
+		content, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read %s", name)
+		}
+
+		for _, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimRight(line, "\r")
+			if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			patterns = append(patterns, gitignore.ParsePattern(line, domain))
+		}
+		return patterns, nil
+	}
+
+	return patterns, nil
+}
+
+// SourceHash returns a hash of the application sources in the directory. It covers the
+// same files as Tar, with their relative paths, permissions, and contents, and does not
+// depend on timestamps. Unchanged sources have the same hash.
+func SourceHash(dir string) (string, error) {
+	files, err := SourceFiles(dir)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+
+	// Note: SourceFiles are in lexical order, making the hash deterministic.
+	for _, source := range files {
+		file := filepath.Join(dir, filepath.FromSlash(source.Path))
+		info := source.Info
+
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(file)
+			if err != nil {
+				return "", errors.Wrap(err, "cannot hash the apps source files")
+			}
+			fmt.Fprintf(hash, "link %s %s\n", source.Path, target)
+		case info.IsDir():
+			fmt.Fprintf(hash, "dir %s %o\n", source.Path, info.Mode().Perm())
+		default:
+			fmt.Fprintf(hash, "file %s %o %d\n", source.Path, info.Mode().Perm(), info.Size())
+			if err := hashFile(hash, file); err != nil {
+				return "", errors.Wrap(err, "cannot hash the apps source files")
+			}
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func hashFile(hash io.Writer, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(hash, f)
+	return err
+}
+
+// ignoredSource returns true for the files at the top of the app sources which are not
+// part of the app.
+func ignoredSource(name string) bool {
+	return name == ".git" || name == ".epinioignore" || name == ".gitignore" || name == ".gitmodules" || name == ".gitconfig" || name == ".git-credentials"
+}
@@ -0,0 +1,193 @@
+package helpers_test
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/epinio/epinio/helpers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SourceHash", func() {
+	var dir, initial string
+
+	write := func(file, content string) {
+		full := filepath.Join(dir, file)
+		Expect(os.MkdirAll(filepath.Dir(full), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(full, []byte(content), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "epinio-sources")
+		Expect(err).ToNot(HaveOccurred())
+
+		write("main.go", "package main")
+		write("static/index.html", "<html></html>")
+
+		initial, err = helpers.SourceHash(dir)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("is stable for unchanged sources", func() {
+		later := time.Now().Add(time.Hour)
+		Expect(os.Chtimes(filepath.Join(dir, "main.go"), later, later)).To(Succeed())
+
+		Expect(helpers.SourceHash(dir)).To(Equal(initial))
+	})
+
+	It("changes with the contents of files", func() {
+		write("static/index.html", "<html><body></body></html>")
+		Expect(helpers.SourceHash(dir)).ToNot(Equal(initial))
+	})
+
+	It("changes with new files", func() {
+		write("static/style.css", "")
+		Expect(helpers.SourceHash(dir)).ToNot(Equal(initial))
+	})
+
+	It("changes with renamed files", func() {
+		Expect(os.Rename(filepath.Join(dir, "main.go"), filepath.Join(dir, "app.go"))).To(Succeed())
+		Expect(helpers.SourceHash(dir)).ToNot(Equal(initial))
+	})
+
+	It("changes with the permissions of files", func() {
+		Expect(os.Chmod(filepath.Join(dir, "main.go"), 0755)).To(Succeed())
+		Expect(helpers.SourceHash(dir)).ToNot(Equal(initial))
+	})
+
+	It("ignores the git files not packaged by Tar", func() {
+		write(".git/HEAD", "ref: refs/heads/main")
+		write(".gitignore", "*.o")
+		Expect(helpers.SourceHash(dir)).To(Equal(initial))
+	})
+})
+
+var _ = Describe("SourceFiles", func() {
+	var dir string
+
+	write := func(file, content string) {
+		full := filepath.Join(dir, file)
+		Expect(os.MkdirAll(filepath.Dir(full), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(full, []byte(content), 0644)).To(Succeed())
+	}
+
+	paths := func() []string {
+		files, err := helpers.SourceFiles(dir)
+		Expect(err).ToNot(HaveOccurred())
+		result := []string{}
+		for _, file := range files {
+			result = append(result, file.Path)
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "epinio-sources")
+		Expect(err).ToNot(HaveOccurred())
+
+		write("main.go", "package main")
+		write("node_modules/left-pad/index.js", "")
+		write("build/app", "")
+		write(".env", "SECRET=1")
+		write(".git/HEAD", "ref: refs/heads/main")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("lists all sources without ignore file, except git files", func() {
+		Expect(paths()).To(Equal([]string{
+			".env", "build", "build/app", "main.go",
+			"node_modules", "node_modules/left-pad", "node_modules/left-pad/index.js",
+		}))
+	})
+
+	It("honours .epinioignore", func() {
+		write(".epinioignore", "# local only\nnode_modules/\n/build\n.env\n")
+		Expect(paths()).To(Equal([]string{"main.go"}))
+	})
+
+	It("supports negated patterns", func() {
+		write(".epinioignore", "*.go\n!main.go\nnode_modules\nbuild\n")
+		write("main_test.go", "package main")
+		Expect(paths()).To(Equal([]string{".env", "main.go"}))
+	})
+
+	It("prefers .epinioignore over .gitignore", func() {
+		write(".epinioignore", "build\n")
+		write(".gitignore", "node_modules\n")
+		Expect(paths()).To(ContainElement("node_modules"))
+		Expect(paths()).ToNot(ContainElement("build"))
+	})
+
+	It("falls back to .gitignore, then .cfignore", func() {
+		write(".cfignore", "build\n")
+		Expect(paths()).ToNot(ContainElement("build"))
+		Expect(paths()).To(ContainElement("node_modules"))
+
+		write(".gitignore", "node_modules\n")
+		Expect(paths()).To(ContainElement("build"))
+		Expect(paths()).ToNot(ContainElement("node_modules"))
+	})
+
+	It("honours nested ignore files, below their directory", func() {
+		write(".gitignore", "/build\n")
+		write("node_modules/.gitignore", "*.js\n")
+		write("node_modules/left-pad/README.md", "")
+		write("static/index.js", "")
+		Expect(paths()).To(Equal([]string{
+			".env", "main.go",
+			"node_modules", "node_modules/.gitignore", "node_modules/left-pad", "node_modules/left-pad/README.md",
+			"static", "static/index.js",
+		}))
+
+		write("node_modules/left-pad/.gitignore", "!index.js\n")
+		Expect(paths()).To(ContainElement("node_modules/left-pad/index.js"))
+	})
+
+	It("leaves the ignored files out of the tarball and its hash", func() {
+		before, err := helpers.SourceHash(dir)
+		Expect(err).ToNot(HaveOccurred())
+
+		write(".epinioignore", "node_modules\n")
+		after, err := helpers.SourceHash(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(after).ToNot(Equal(before))
+
+		write("node_modules/right-pad/index.js", "")
+		Expect(helpers.SourceHash(dir)).To(Equal(after))
+
+		tmpDir, tarball, err := helpers.Tar(dir)
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+
+		f, err := os.Open(tarball)
+		Expect(err).ToNot(HaveOccurred())
+		defer f.Close()
+
+		names := []string{}
+		reader := tar.NewReader(f)
+		for {
+			header, err := reader.Next()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).ToNot(HaveOccurred())
+			names = append(names, header.Name)
+		}
+		Expect(names).To(ConsistOf(".env", "build/", "build/app", "main.go"))
+	})
+})
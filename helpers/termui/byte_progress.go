@@ -0,0 +1,85 @@
+package termui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/epinio/epinio/helpers/bytes"
+	"github.com/fatih/color"
+	"github.com/kyokomi/emoji"
+)
+
+// This file implements the ByteProgress, a progress bar for transfers of a known number
+// of bytes. It redraws its line as the transfer advances.
+
+type ByteProgress struct {
+	ui      *UI
+	mu      sync.Mutex
+	message string
+	drawn   string // drawn is the last line drawn, to skip redraws without change
+	active  bool
+}
+
+// Standard values for the byte-based progress
+const barWidth = 30
+
+// ByteProgress creates and returns an active byte-based progress bar. Report the
+// progress of the transfer with Update.
+func (u *UI) ByteProgress(message string) *ByteProgress {
+	return &ByteProgress{
+		ui:      u,
+		message: message,
+		active:  true,
+	}
+}
+
+// Update redraws the bar for the number of bytes transferred so far, out of the total.
+func (p *ByteProgress) Update(done, total int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Progress is shown at the verbosity of the progress notes.
+	if !p.active || p.ui.verbosity < 1 {
+		return
+	}
+
+	if done > total {
+		done = total
+	}
+	percent := 100
+	if total > 0 {
+		percent = int(done * 100 / total)
+	}
+	filled := percent * barWidth / 100
+
+	line := fmt.Sprintf("%s [%s%s] %3d%% %s / %s",
+		p.message,
+		strings.Repeat("=", filled), strings.Repeat(" ", barWidth-filled),
+		percent, bytes.ByteCountIEC(done), bytes.ByteCountIEC(total))
+	if line == p.drawn {
+		return
+	}
+
+	// Pad with blanks to clear the rest of a longer previous line.
+	padding := len(p.drawn) - len(line)
+	if padding < 0 {
+		padding = 0
+	}
+	p.drawn = line
+
+	fmt.Fprintf(color.Output, "\r%s%s", emoji.Sprintf(":three-thirty: %s", line), strings.Repeat(" ", padding))
+}
+
+// Stop ends the bar, leaving its last state on the screen.
+func (p *ByteProgress) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.active {
+		p.active = false
+		if p.drawn != "" {
+			fmt.Fprintln(color.Output)
+		}
+	}
+}
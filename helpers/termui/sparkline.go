@@ -0,0 +1,37 @@
+package termui
+
+import "strings"
+
+// This file implements a sparkline, a compact bar chart of a series of
+// values fitting into a single line of text, i.e. a table cell.
+
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders the values as a string of block characters, one per value, scaled
+// between the smallest and largest of the values.
+func Sparkline(values []int64) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+
+	var line strings.Builder
+	for _, v := range values {
+		tick := 0
+		if max > min {
+			tick = int((v - min) * int64(len(sparkTicks)-1) / (max - min))
+		}
+		line.WriteRune(sparkTicks[tick])
+	}
+
+	return line.String()
+}
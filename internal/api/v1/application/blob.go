@@ -0,0 +1,52 @@
+package application
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/epinio/epinio/internal/blobstore"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+)
+
+// BlobDownload handles the API endpoint GET /blobs/:blob
+// It returns the contents of the blob, to the staging jobs of the filesystem blob store.
+// The endpoint is not authenticated. Instead the request has to carry the download token
+// of the blob and its expiry, see blobstore.DownloadURL.
+func (hc Controller) BlobDownload(c *gin.Context) apierror.APIErrors {
+	blobUID := c.Param("blob")
+
+	// Unknown blobs, and bad or expired tokens are reported alike, to not reveal the blobs to
+	// unauthenticated callers.
+	notFound := apierror.NewNotFoundError("Blob not found")
+
+	if blobstore.Backend() != blobstore.BackendFilesystem {
+		return notFound
+	}
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		return notFound
+	}
+	if !blobstore.ValidDownloadToken(blobUID, c.Query("token"), expires, time.Now()) {
+		return notFound
+	}
+
+	store, err := blobstore.NewFilesystem(blobstore.Path())
+	if err != nil {
+		return apierror.InternalError(err, "accessing the blob store")
+	}
+
+	reader, size, err := store.Open(blobUID)
+	if err != nil {
+		if blobstore.IsNotFound(err) {
+			return notFound
+		}
+		return apierror.InternalError(err, "reading the blob")
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, size, "application/x-tar", reader, nil)
+	return nil
+}
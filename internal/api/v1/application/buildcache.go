@@ -0,0 +1,84 @@
+package application
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/buildcache"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// BuildCacheShow handles the API endpoint GET /namespaces/:namespace/applications/:app/buildcache
+// It returns the details of the application's build cache.
+func (hc Controller) BuildCacheShow(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	appRef := models.NewAppRef(c.Param("app"), c.Param("namespace"))
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	exists, err := application.Exists(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !exists {
+		return apierror.AppIsNotKnown(appRef.Name)
+	}
+
+	cache, err := buildcache.Get(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if cache == nil {
+		return apierror.NewNotFoundError("Application has no build cache",
+			"it is created by the next staging of the application")
+	}
+
+	response.OKReturn(c, cache)
+	return nil
+}
+
+// BuildCachePurge handles the API endpoint DELETE /namespaces/:namespace/applications/:app/buildcache
+// It removes the application's build cache. The next staging starts with an empty cache.
+func (hc Controller) BuildCachePurge(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	appRef := models.NewAppRef(c.Param("app"), c.Param("namespace"))
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	exists, err := application.Exists(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !exists {
+		return apierror.AppIsNotKnown(appRef.Name)
+	}
+
+	err = buildcache.Purge(ctx, cluster, appRef)
+	if err == buildcache.ErrStaging {
+		return apierror.NewBadRequest("Cannot purge the build cache while the application is staging",
+			"cancel the staging with `epinio app stage cancel`, or wait for it to finish")
+	}
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
+
+// buildCacheSettings returns the server's settings for new build caches.
+func buildCacheSettings() buildcache.Settings {
+	return buildcache.Settings{
+		Size:         viper.GetString("staging-cache-size"),
+		StorageClass: viper.GetString("staging-cache-storage-class"),
+	}
+}
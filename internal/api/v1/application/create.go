@@ -2,12 +2,14 @@ package application
 
 import (
 	"github.com/epinio/epinio/helpers/kubernetes"
+	namespaceapi "github.com/epinio/epinio/internal/api/v1/namespace"
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/appchart"
 	"github.com/epinio/epinio/internal/application"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
 	"github.com/epinio/epinio/internal/configurations"
 	"github.com/epinio/epinio/internal/domain"
+	"github.com/epinio/epinio/internal/policy"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/gin-gonic/gin"
@@ -71,6 +73,12 @@ func (hc Controller) Create(c *gin.Context) apierror.APIErrors {
 		return apierror.NewMultiError(theIssues)
 	}
 
+	apierr := application.ValidateEnvironmentFrom(ctx, cluster, namespace,
+		createRequest.Configuration.EnvironmentFrom, createRequest.Configuration.Configurations)
+	if apierr != nil {
+		return apierr
+	}
+
 	var routes []string
 	if len(createRequest.Configuration.Routes) > 0 {
 		routes = createRequest.Configuration.Routes
@@ -97,6 +105,29 @@ func (hc Controller) Create(c *gin.Context) apierror.APIErrors {
 		return apierror.AppChartIsNotKnown(chart)
 	}
 
+	desired := DefaultInstances
+	if createRequest.Configuration.Instances != nil {
+		desired = *createRequest.Configuration.Instances
+	}
+
+	apierr = policy.Enforce(ctx, cluster, namespace, models.PolicySubject{
+		App:         createRequest.Name,
+		Instances:   &desired,
+		Environment: policy.EnvironmentNames(createRequest.Configuration),
+		AppChart:    chart,
+	})
+	if apierr != nil {
+		return apierr
+	}
+
+	apierr = namespaceapi.EnforceQuota(ctx, cluster, namespace, models.NamespaceUsage{
+		Apps:      1,
+		Instances: desired,
+	})
+	if apierr != nil {
+		return apierr
+	}
+
 	// Arguments found OK, now we can modify the system state
 
 	err = application.Create(ctx, cluster, appRef, username, routes, chart)
@@ -104,11 +135,6 @@ func (hc Controller) Create(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err)
 	}
 
-	desired := DefaultInstances
-	if createRequest.Configuration.Instances != nil {
-		desired = *createRequest.Configuration.Instances
-	}
-
 	err = application.ScalingSet(ctx, cluster, appRef, desired)
 	if err != nil {
 		return apierror.InternalError(err)
@@ -128,6 +154,20 @@ func (hc Controller) Create(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err)
 	}
 
+	err = application.EnvironmentFromSet(ctx, cluster, appRef,
+		createRequest.Configuration.EnvironmentFrom, true)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if createRequest.Configuration.StagingEnvironment != nil {
+		err = application.StagingEnvironmentSet(ctx, cluster, appRef,
+			*createRequest.Configuration.StagingEnvironment, true)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+	}
+
 	response.Created(c)
 	return nil
 }
@@ -4,15 +4,22 @@ import (
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/appmetrics"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/sbom"
+	"github.com/epinio/epinio/internal/staginglogs"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/gin-gonic/gin"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 // Delete handles the API endpoint DELETE /namespaces/:namespace/applications/:app
 // It removes the named application
 func (hc Controller) Delete(c *gin.Context) apierror.APIErrors {
 	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
 	namespace := c.Param("namespace")
 	appName := c.Param("app")
 
@@ -23,12 +30,15 @@ func (hc Controller) Delete(c *gin.Context) apierror.APIErrors {
 
 	app := models.NewAppRef(appName, namespace)
 
-	found, err := application.Exists(ctx, cluster, app)
+	appCR, err := application.Get(ctx, cluster, app)
 	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return apierror.AppIsNotKnown(appName)
+		}
 		return apierror.InternalError(err)
 	}
-	if !found {
-		return apierror.AppIsNotKnown(appName)
+	if application.Protected(appCR) {
+		return apierror.AppIsProtected(appName)
 	}
 
 	configurations, err := application.BoundConfigurationNames(ctx, cluster, app)
@@ -45,6 +55,23 @@ func (hc Controller) Delete(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err)
 	}
 
+	// The application is gone. The removal of its stored staging logs and SBOMs is
+	// best-effort, a failure must not report the deletion as failed.
+	err = staginglogs.Delete(ctx, cluster, app)
+	if err != nil {
+		log.Error(err, "deleting the stored staging logs", "namespace", namespace, "app", appName)
+	}
+
+	err = sbom.Delete(ctx, cluster, app)
+	if err != nil {
+		log.Error(err, "deleting the stored SBOMs", "namespace", namespace, "app", appName)
+	}
+
+	// Drop the collected metrics, a recreated application must not show them.
+	if collector := appmetrics.Running(); collector != nil {
+		collector.Forget(app)
+	}
+
 	response.OKReturn(c, resp)
 	return nil
 }
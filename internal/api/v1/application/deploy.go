@@ -8,6 +8,7 @@ import (
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/application"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/policy"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/gin-gonic/gin"
@@ -52,6 +53,29 @@ func (hc Controller) Deploy(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err, "failed to get the application resource")
 	}
 
+	app, err := application.Lookup(ctx, cluster, namespace, name)
+	if err != nil {
+		return apierror.InternalError(err, "failed to get the application")
+	}
+	if app == nil {
+		return apierror.AppIsNotKnown("cannot deploy app, application resource is missing")
+	}
+
+	subject := models.PolicySubject{
+		App:         name,
+		Instances:   app.Configuration.Instances,
+		Environment: policy.EnvironmentNames(app.Configuration),
+		AppChart:    app.Configuration.AppChart,
+	}
+	// Images of a container origin are not built by Epinio, their registry is checked.
+	if req.Origin.Kind == models.OriginContainer {
+		subject.ContainerImage = req.ImageURL
+	}
+	apierr := policy.Enforce(ctx, cluster, namespace, subject)
+	if apierr != nil {
+		return apierr
+	}
+
 	err = deploy.UpdateImageURL(ctx, cluster, applicationCR, req.ImageURL)
 	if err != nil {
 		return apierror.InternalError(err, "failed to set application's image url")
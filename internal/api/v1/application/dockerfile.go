@@ -0,0 +1,174 @@
+package application
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/epinio/epinio/internal/application"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+const (
+	// defaultDockerfileBuilderImage is the image building from a Dockerfile, if the
+	// staging scripts do not specify one.
+	defaultDockerfileBuilderImage = "gcr.io/kaniko-project/executor:v1.9.1"
+
+	// defaultDockerfile is the path of the Dockerfile in the sources, if the
+	// application does not specify one.
+	defaultDockerfile = "Dockerfile"
+
+	// appSourceDir is the directory the unpack step places the application sources in.
+	appSourceDir = "/workspace/source/app"
+)
+
+// getStagingStrategy returns the staging strategy and Dockerfile settings defined on the
+// request. Dockerfile settings without a strategy imply the dockerfile strategy. If the
+// request defines neither, the strategy and settings previously used are taken from the
+// staging settings of the Application CR. Without any, the strategy is buildpacks.
+func getStagingStrategy(req models.StageRequest, app *unstructured.Unstructured) (string, models.DockerfileBuild, apierror.APIErrors) {
+	strategy := req.Strategy
+	dockerfile := models.DockerfileBuild{}
+
+	if req.Dockerfile != nil {
+		dockerfile = *req.Dockerfile
+		if strategy == "" {
+			strategy = models.StagingStrategyDockerfile
+		}
+	}
+
+	if strategy == "" {
+		used, err := application.Staging(app)
+		if err != nil {
+			return "", dockerfile, apierror.InternalError(err)
+		}
+
+		strategy = used.Strategy
+		dockerfile.Path = used.Dockerfile
+		dockerfile.Target = used.Target
+		dockerfile.BuildArgs = used.BuildArgs
+	}
+
+	switch strategy {
+	case "", models.StagingStrategyBuildpacks:
+		if req.Dockerfile != nil {
+			return "", dockerfile, apierror.NewBadRequest("Dockerfile settings require the dockerfile staging strategy")
+		}
+		return models.StagingStrategyBuildpacks, models.DockerfileBuild{}, nil
+	case models.StagingStrategyDockerfile:
+		if dockerfile.Path == "" {
+			dockerfile.Path = defaultDockerfile
+		}
+		clean := path.Clean(dockerfile.Path)
+		if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return "", dockerfile, apierror.NewBadRequest("Dockerfile path has to be inside the application sources",
+				dockerfile.Path)
+		}
+		dockerfile.Path = clean
+		return strategy, dockerfile, nil
+	}
+
+	return "", dockerfile, apierror.NewBadRequest(fmt.Sprintf("Unknown staging strategy '%s'", strategy),
+		"expected one of buildpacks, dockerfile")
+}
+
+// stagingSettings returns the staging strategy and Dockerfile settings of the parameters
+// in the form recorded in the Application CR, for use by the next staging. A non-empty
+// build environment is recorded by its hash, so that a change of it prevents the reuse
+// of the stage.
+func stagingSettings(params stageParam) application.StagingSettings {
+	settings := application.StagingSettings{
+		Strategy: params.Strategy,
+	}
+	if len(params.Environment) > 0 {
+		settings.Environment = environmentHash(params.Environment)
+	}
+	if params.Strategy != models.StagingStrategyDockerfile {
+		return settings
+	}
+
+	settings.Dockerfile = params.Dockerfile.Path
+	settings.Target = params.Dockerfile.Target
+	if len(params.Dockerfile.BuildArgs) > 0 {
+		settings.BuildArgs = params.Dockerfile.BuildArgs
+	}
+
+	return settings
+}
+
+// environmentHash returns the hash of the sorted environment.
+func environmentHash(environment models.EnvVariableList) string {
+	hash := sha256.New()
+	for _, ev := range environment {
+		fmt.Fprintf(hash, "%d:%s=%d:%s\n", len(ev.Name), ev.Name, len(ev.Value), ev.Value)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// DockerfileBuilderArgs returns the arguments of the builder building the image from
+// the Dockerfile, and pushing it to the destination. The layers are cached in the cache
+// directory. A non-empty registry certificate is the path of the certificate to trust
+// for the registry of the destination.
+func DockerfileBuilderArgs(dockerfile models.DockerfileBuild, destination, cacheDir, registryCert string) []string {
+	args := []string{
+		fmt.Sprintf("--context=dir://%s", appSourceDir),
+		fmt.Sprintf("--dockerfile=%s", path.Join(appSourceDir, dockerfile.Path)),
+		fmt.Sprintf("--destination=%s", destination),
+		"--cache=true",
+		fmt.Sprintf("--cache-dir=%s", cacheDir),
+	}
+
+	if dockerfile.Target != "" {
+		args = append(args, fmt.Sprintf("--target=%s", dockerfile.Target))
+	}
+
+	names := []string{}
+	for name := range dockerfile.BuildArgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, fmt.Sprintf("--build-arg=%s=%s", name, dockerfile.BuildArgs[name]))
+	}
+
+	if registryCert != "" {
+		registryHost := strings.SplitN(destination, "/", 2)[0]
+		args = append(args, fmt.Sprintf("--registry-certificate=%s=%s", registryHost, registryCert))
+	}
+
+	return args
+}
+
+// dockerfileContainer returns the container building the application image from its
+// Dockerfile. It sees the same sources, cache, and registry credentials as the
+// buildpack builder.
+func dockerfileContainer(app stageParam, env []corev1.EnvVar, volumeMounts []corev1.VolumeMount) corev1.Container {
+	mounts := []corev1.VolumeMount{}
+	for _, mount := range volumeMounts {
+		// The builder looks for the registry credentials in its own location.
+		if mount.Name == "registry-creds" {
+			mount.MountPath = "/kaniko/.docker/"
+		}
+		mounts = append(mounts, mount)
+	}
+
+	registryCert := ""
+	if app.RegistryCASecret != "" && app.RegistryCAHash != "" {
+		registryCert = fmt.Sprintf("/etc/ssl/certs/%s", app.RegistryCAHash)
+	}
+
+	return corev1.Container{
+		Name:         "dockerfile",
+		Image:        app.DockerfileImage,
+		Args:         DockerfileBuilderArgs(app.Dockerfile, app.ImageURL(app.RegistryURL), "/workspace/cache/kaniko", registryCert),
+		Env:          env,
+		VolumeMounts: mounts,
+	}
+}
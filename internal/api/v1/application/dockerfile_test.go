@@ -0,0 +1,44 @@
+package application_test
+
+import (
+	"github.com/epinio/epinio/internal/api/v1/application"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DockerfileBuilderArgs", func() {
+	It("builds the Dockerfile of the sources into the destination", func() {
+		args := application.DockerfileBuilderArgs(models.DockerfileBuild{Path: "Dockerfile"},
+			"registry.test/apps/ws-app:abc", "/workspace/cache/kaniko", "")
+		Expect(args).To(Equal([]string{
+			"--context=dir:///workspace/source/app",
+			"--dockerfile=/workspace/source/app/Dockerfile",
+			"--destination=registry.test/apps/ws-app:abc",
+			"--cache=true",
+			"--cache-dir=/workspace/cache/kaniko",
+		}))
+	})
+
+	It("passes target, sorted build arguments, and the registry certificate", func() {
+		args := application.DockerfileBuilderArgs(models.DockerfileBuild{
+			Path:   "build/Dockerfile",
+			Target: "runtime",
+			BuildArgs: map[string]string{
+				"VERSION": "1.2",
+				"MODE":    "release",
+			},
+		}, "registry.test/apps/ws-app:abc", "/cache", "/etc/ssl/certs/1234abcd.0")
+		Expect(args).To(Equal([]string{
+			"--context=dir:///workspace/source/app",
+			"--dockerfile=/workspace/source/app/build/Dockerfile",
+			"--destination=registry.test/apps/ws-app:abc",
+			"--cache=true",
+			"--cache-dir=/cache",
+			"--target=runtime",
+			"--build-arg=MODE=release",
+			"--build-arg=VERSION=1.2",
+			"--registry-certificate=registry.test=/etc/ssl/certs/1234abcd.0",
+		}))
+	})
+})
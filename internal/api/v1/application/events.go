@@ -0,0 +1,84 @@
+package application
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/epinio/epinio/internal/appevents"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// Events handles the API endpoint GET /namespaces/:namespace/events
+// It upgrades the connection to a websocket and pushes the events of the applications
+// in the namespace over it, as they happen. The optional query parameter `app`
+// restricts the events to the named application.
+func (hc Controller) Events(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx).WithName("events")
+
+	namespace := c.Param("namespace")
+	appName := c.Query("app")
+
+	broker := appevents.Running()
+	if broker == nil {
+		return apierror.NewAPIError("application events are not available", "", http.StatusServiceUnavailable)
+	}
+
+	log.Info("upgrade to web socket")
+
+	var upgrader = newUpgrader()
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	defer conn.Close()
+
+	events, cancel := broker.Subscribe(namespace)
+	defer cancel()
+
+	// The client does not send anything. Reading is only needed to notice when it
+	// closes the connection.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	log.Info("streaming begin", "namespace", namespace, "app", appName)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("streaming done")
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Time{})
+			return nil
+		case <-closed:
+			log.Info("streaming done, client closed connection")
+			return nil
+		case event := <-events:
+			if appName != "" && event.App.Name != appName {
+				continue
+			}
+
+			msg, err := json.Marshal(event)
+			if err != nil {
+				log.Error(err, "failed to encode event")
+				continue
+			}
+
+			err = conn.WriteMessage(websocket.TextMessage, msg)
+			if err != nil {
+				log.V(1).Error(err, "failed to write to websockets")
+				return nil
+			}
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 // FullIndex handles the API endpoint GET /applications
 // It lists all the known applications in all namespaces, with and without workload.
+// The values of environment variables marked as secret are masked.
 func (hc Controller) FullIndex(c *gin.Context) apierror.APIErrors {
 	ctx := c.Request.Context()
 	user := requestctx.User(ctx)
@@ -29,6 +30,13 @@ func (hc Controller) FullIndex(c *gin.Context) apierror.APIErrors {
 
 	filteredApps := auth.FilterResources(user, allApps)
 
+	for i := range filteredApps {
+		err = application.MaskAppSecrets(ctx, cluster, &filteredApps[i])
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+	}
+
 	response.OKReturn(c, filteredApps)
 	return nil
 }
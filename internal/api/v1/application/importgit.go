@@ -1,96 +1,162 @@
 package application
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
+	"path/filepath"
 
 	"github.com/gin-gonic/gin"
-	git "github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/epinio/epinio/helpers"
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/blobstore"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
-	"github.com/epinio/epinio/internal/helmchart"
-	"github.com/epinio/epinio/internal/s3manager"
+	"github.com/epinio/epinio/internal/gitimport"
+	"github.com/epinio/epinio/internal/operations"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 )
 
 // ImportGit handles the API endpoint /namespaces/:namespace/applications/:app/import-git.
-// It receives a Git repo url and revision, clones that (shallow clone), creates a tarball
-// of the repo and puts it on S3.
+// It receives a Git repo url and revision, and submits their import as an operation. The
+// import clones the repo, creates a tarball of it (or of the requested subdirectory) and
+// puts it into the blob store, in the background. The returned operation is followed through the
+// operations API, its result is the id of the new blob.
 func (hc Controller) ImportGit(c *gin.Context) apierror.APIErrors {
 	ctx := c.Request.Context()
-	log := requestctx.Logger(ctx)
 
 	namespace := c.Param("namespace")
 	name := c.Param("app")
+	appRef := models.NewAppRef(name, namespace)
+
+	gitRef := models.GitRef{
+		URL:          c.PostForm("giturl"),
+		Revision:     c.PostForm("gitrev"),
+		Credentials:  c.PostForm("gitcredentials"),
+		Subdirectory: c.PostForm("gitsubdir"),
+		Submodules:   c.PostForm("gitsubmodules") == "true",
+	}
+	if gitRef.URL == "" {
+		return apierror.NewBadRequest("missing git repository url")
+	}
+	username := requestctx.User(ctx).Username
+
+	pool := operations.Running()
+	if pool == nil {
+		return apierror.InternalError(errors.New("operations are not running"))
+	}
+
+	operation, err := pool.Submit(models.OperationImportGit, appRef,
+		func(ctx context.Context, step func(string)) (interface{}, error) {
+			resp, apierr := importGit(ctx, appRef, gitRef, username, step)
+			if apierr != nil {
+				return nil, stepError("importing the sources", apierr)
+			}
+			return resp, nil
+		})
+	if err != nil {
+		if err == operations.ErrBusy {
+			return apierror.NewAPIError(err.Error(), "", http.StatusServiceUnavailable)
+		}
+		return apierror.InternalError(err)
+	}
+
+	requestctx.Logger(ctx).Info("import submitted", "namespace", namespace, "app", name, "operation", operation.ID)
+
+	// Return the operation importing the sources
+	response.OKReturn(c, operation)
+	return nil
+}
+
+// importGit clones the revision of the Git repository, creates a tarball of the repo (or
+// of the requested subdirectory) and puts it into the blob store. It returns the id of the blob, and
+// the commit the revision resolved to. The steps of the import are reported as they
+// begin.
+func importGit(ctx context.Context, app models.AppRef, gitRef models.GitRef, username string, step func(string)) (*models.ImportGitResponse, apierror.APIErrors) {
+	log := requestctx.Logger(ctx)
+	namespace := app.Namespace
+	name := app.Name
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return nil, apierror.InternalError(err, "failed to get access to a kube client")
+	}
 
-	url := c.PostForm("giturl")
-	revision := c.PostForm("gitrev")
+	var auth transport.AuthMethod
+	if gitRef.Credentials != "" {
+		auth, err = gitimport.Credentials(ctx, cluster, namespace, gitRef.Credentials, gitRef.URL)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, apierror.NewBadRequest(fmt.Sprintf("Git credentials '%s' not found", gitRef.Credentials))
+			}
+			return nil, apierror.BadRequest(err, "bad git credentials")
+		}
+	}
 
-	gitRepo, err := ioutil.TempDir("", "epinio-app")
+	tmpRoot, err := ioutil.TempDir("", "epinio-app")
 	if err != nil {
-		return apierror.InternalError(err, "can't create temp directory")
+		return nil, apierror.InternalError(err, "can't create temp directory")
 	}
-	defer os.RemoveAll(gitRepo)
+	defer os.RemoveAll(tmpRoot)
+	gitRepo := filepath.Join(tmpRoot, "repo")
 
 	// Fetch the git repo
-	// TODO: This is pulling the git repository on user request (synchronously).
-	// This can be a slow process. A solution with background workers would be
-	// more appropriate. The "pull from git" feature may be redesigned and implemented
-	// through an "external" component that monitors git repos. In that case this code
-	// will be removed.
-	_, err = git.PlainCloneContext(ctx, gitRepo, false, &git.CloneOptions{
-		URL:           url,
-		ReferenceName: plumbing.NewBranchReferenceName(revision),
-		SingleBranch:  true,
-		Depth:         1,
-	})
+	step("cloning")
+	commit, err := gitimport.Clone(ctx, gitRepo, gitRef, auth)
 	if err != nil {
-		return apierror.InternalError(err, fmt.Sprintf("cloning the git repository: %s, revision: %s", url, revision))
+		return nil, apierror.InternalError(err, fmt.Sprintf("cloning the git repository: %s, revision: %s", gitRef.URL, gitRef.Revision))
+	}
+
+	sources, err := gitimport.SourceDir(gitRepo, gitRef.Subdirectory)
+	if err != nil {
+		return nil, apierror.BadRequest(err)
 	}
 
 	// Create a tarball
-	tmpDir, tarball, err := helpers.Tar(gitRepo)
+	step("archiving")
+	tmpDir, tarball, err := helpers.Tar(sources)
 	defer func() {
 		if tmpDir != "" {
 			_ = os.RemoveAll(tmpDir)
 		}
 	}()
 	if err != nil {
-		return apierror.InternalError(err, "create a tarball from the git repository")
+		return nil, apierror.InternalError(err, "create a tarball from the git repository")
 	}
 
-	// Upload to S3
-	cluster, err := kubernetes.GetCluster(ctx)
+	// Upload to the blob store
+	step("uploading")
+	store, err := blobstore.New(ctx, cluster)
 	if err != nil {
-		return apierror.InternalError(err, "failed to get access to a kube client")
-	}
-	connectionDetails, err := s3manager.GetConnectionDetails(ctx, cluster, helmchart.Namespace(), "epinio-s3-connection-details")
-	if err != nil {
-		return apierror.InternalError(err, "fetching the S3 connection details from the Kubernetes secret")
-	}
-	manager, err := s3manager.New(connectionDetails)
-	if err != nil {
-		return apierror.InternalError(err, "creating an S3 manager")
+		return nil, apierror.InternalError(err, "accessing the blob store")
 	}
 
-	username := requestctx.User(ctx).Username
-	blobUID, err := manager.Upload(ctx, tarball, map[string]string{
+	blobUID, err := store.Upload(ctx, tarball, map[string]string{
 		"app": name, "namespace": namespace, "username": username,
 	})
 	if err != nil {
-		return apierror.InternalError(err, "uploading the application sources blob")
+		return nil, apierror.InternalError(err, "uploading the application sources blob")
 	}
-	log.Info("uploaded app", "namespace", namespace, "app", name, "blobUID", blobUID)
+	log.Info("uploaded app", "namespace", namespace, "app", name, "blobUID", blobUID, "commit", commit)
 
-	// Return the id of the new blob
-	response.OKReturn(c, models.ImportGitResponse{
+	return &models.ImportGitResponse{
 		BlobUID: blobUID,
-	})
-	return nil
+		Commit:  commit,
+	}, nil
+}
+
+// stepError converts the API errors of a step of a background operation into an error.
+func stepError(step string, apierr apierror.APIErrors) error {
+	first := apierr.Errors()[0]
+	if first.Details == "" {
+		return errors.Errorf("%s: %s", step, first.Title)
+	}
+	return errors.Errorf("%s: %s: %s", step, first.Title, first.Details)
 }
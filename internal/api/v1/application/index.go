@@ -10,6 +10,7 @@ import (
 
 // Index handles the API endpoint GET /namespaces/:namespace/applications
 // It lists all the known applications in the specified namespace, with and without workload.
+// The values of environment variables marked as secret are masked.
 func (hc Controller) Index(c *gin.Context) apierror.APIErrors {
 	ctx := c.Request.Context()
 	namespace := c.Param("namespace")
@@ -24,6 +25,13 @@ func (hc Controller) Index(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err)
 	}
 
+	for i := range apps {
+		err = application.MaskAppSecrets(ctx, cluster, &apps[i])
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+	}
+
 	response.OKReturn(c, apps)
 	return nil
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/application"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/staginglogs"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
@@ -67,6 +68,26 @@ func (hc Controller) Logs(c *gin.Context) {
 		return
 	}
 
+	// Logs of staging runs whose pod is gone are served from storage, if kept.
+	var storedLogs []tailer.ContainerLogLine
+	if stageID != "" {
+		exists, err := staginglogs.PodExists(ctx, cluster, namespace, stageID)
+		if err != nil {
+			response.Error(c, apierror.InternalError(err))
+			return
+		}
+		if !exists {
+			lines, found, err := staginglogs.Load(ctx, cluster, namespace, stageID)
+			if err != nil {
+				response.Error(c, apierror.InternalError(err))
+				return
+			}
+			if found {
+				storedLogs = lines
+			}
+		}
+	}
+
 	log.Info("process query")
 
 	followStr := c.Query("follow")
@@ -82,6 +103,16 @@ func (hc Controller) Logs(c *gin.Context) {
 
 	follow := followStr == "true"
 
+	if storedLogs != nil {
+		log.Info("sending stored logs", "lines", len(storedLogs))
+
+		err = sendStoredLogs(conn, storedLogs)
+		if err != nil {
+			log.V(1).Error(err, "error occurred after upgrading the websockets connection")
+		}
+		return
+	}
+
 	log.Info("streaming mode", "follow", follow)
 	log.Info("streaming begin")
 
@@ -187,6 +218,26 @@ func (hc Controller) streamPodLogs(ctx context.Context, conn *websocket.Conn, na
 	return conn.Close()
 }
 
+// sendStoredLogs sends the log lines to the websocket connection, and closes it.
+func sendStoredLogs(conn *websocket.Conn, lines []tailer.ContainerLogLine) error {
+	for _, line := range lines {
+		msg, err := json.Marshal(line)
+		if err != nil {
+			return err
+		}
+		if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	if err := conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Time{}); err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
 // https://pkg.go.dev/github.com/gorilla/websocket#hdr-Origin_Considerations
 // Regarding matching accessControlAllowOrigin and origin header:
 // https: //developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Access-Control-Allow-Origin
@@ -0,0 +1,87 @@
+package application
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/appmetrics"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultMetricsSince = time.Hour
+	defaultMetricsStep  = time.Minute
+)
+
+// Metrics handles the API endpoint GET /namespaces/:namespace/applications/:app/metrics
+// It returns the history of the application's resource usage, as recorded by the
+// server's metrics collector. The optional query parameters `since` and `step` select
+// how far to reach into the past, and the size of the buckets samples are averaged in.
+func (hc Controller) Metrics(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	appName := c.Param("app")
+
+	since, err := durationQuery(c, "since", defaultMetricsSince)
+	if err != nil {
+		return apierror.BadRequest(err, "bad value for query parameter `since`")
+	}
+	step, err := durationQuery(c, "step", defaultMetricsStep)
+	if err != nil {
+		return apierror.BadRequest(err, "bad value for query parameter `step`")
+	}
+	if since <= 0 || step <= 0 {
+		return apierror.NewBadRequest("query parameters `since` and `step` have to be positive durations")
+	}
+
+	collector := appmetrics.Running()
+	if collector == nil {
+		return apierror.NewAPIError("metrics collection is disabled", "", http.StatusServiceUnavailable)
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	appRef := models.NewAppRef(appName, namespace)
+	exists, err := application.Exists(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !exists {
+		return apierror.AppIsNotKnown(appName)
+	}
+
+	samples := []models.AppMetricSample{}
+	for _, s := range collector.Query(appRef, since, step, time.Now()) {
+		samples = append(samples, models.AppMetricSample{
+			Timestamp:   s.Time.Format(time.RFC3339), // ISO 8601
+			MilliCPUs:   s.MilliCPUs,
+			MemoryBytes: s.MemoryBytes,
+			Instances:   s.Instances,
+		})
+	}
+
+	response.OKReturn(c, models.AppMetricsResponse{
+		Since:   since.String(),
+		Step:    step.String(),
+		Samples: samples,
+	})
+	return nil
+}
+
+// durationQuery returns the value of the named query parameter as a duration, or the
+// default if the parameter is not specified.
+func durationQuery(c *gin.Context, name string, defaultValue time.Duration) (time.Duration, error) {
+	value := c.Query(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	return time.ParseDuration(value)
+}
@@ -0,0 +1,46 @@
+package application
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Protection handles the API endpoint PUT /namespaces/:namespace/applications/:app/protection
+// It sets or clears the protection of the application. A protected application cannot be
+// deleted, nor its namespace.
+func (hc Controller) Protection(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	appName := c.Param("app")
+
+	var request models.ProtectionRequest
+	if err := c.BindJSON(&request); err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	app := models.NewAppRef(appName, namespace)
+
+	found, err := application.Exists(ctx, cluster, app)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !found {
+		return apierror.AppIsNotKnown(appName)
+	}
+
+	if err := application.SetProtected(ctx, cluster, app, request.Protected); err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
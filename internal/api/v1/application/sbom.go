@@ -0,0 +1,64 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/sbom"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// SBOM handles the API endpoint GET /namespaces/:namespace/applications/:app/sbom
+// It returns the SBOM and provenance of the running image of the application, or of the
+// image of the staging run named by the `stage` query parameter.
+func (hc Controller) SBOM(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	appRef := models.NewAppRef(c.Param("app"), c.Param("namespace"))
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	app, err := application.Get(ctx, cluster, appRef)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return apierror.AppIsNotKnown(appRef.Name)
+		}
+		return apierror.InternalError(err)
+	}
+
+	stageID := c.Query("stage")
+	if stageID == "" {
+		// Staged images are tagged with the ID of their staging run.
+		imageURL, err := application.ImageURL(app)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+		if i := strings.LastIndex(imageURL, ":"); i >= 0 && !strings.Contains(imageURL[i+1:], "/") {
+			stageID = imageURL[i+1:]
+		}
+	}
+	if stageID == "" {
+		return apierror.NewNotFoundError("Application has no staged image",
+			"images of a container origin are not staged by Epinio")
+	}
+
+	appSBOM, found, err := sbom.Load(ctx, cluster, appRef, stageID)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !found {
+		return apierror.NewNotFoundError(fmt.Sprintf("No SBOM recorded for stage %s", stageID),
+			"SBOMs are recorded for successful staging runs")
+	}
+
+	response.OKReturn(c, appSBOM)
+	return nil
+}
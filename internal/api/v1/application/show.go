@@ -9,7 +9,9 @@ import (
 )
 
 // Show handles the API endpoint GET /namespaces/:namespace/applications/:app
-// It returns the details of the specified application.
+// It returns the details of the specified application. The values of environment
+// variables marked as secret are masked, unless the query parameter `reveal` is set to
+// `true`.
 func (hc Controller) Show(c *gin.Context) apierror.APIErrors {
 	ctx := c.Request.Context()
 	namespace := c.Param("namespace")
@@ -29,6 +31,13 @@ func (hc Controller) Show(c *gin.Context) apierror.APIErrors {
 		return apierror.AppIsNotKnown(appName)
 	}
 
+	if c.Query("reveal") != "true" {
+		err = application.MaskAppSecrets(ctx, cluster, app)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+	}
+
 	response.OKReturn(c, app)
 	return nil
 }
@@ -3,6 +3,8 @@ package application
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
@@ -12,21 +14,27 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/epinio/epinio/helpers/cahash"
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/helpers/randstr"
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/blobstore"
+	"github.com/epinio/epinio/internal/buildcache"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
 	"github.com/epinio/epinio/internal/duration"
 	"github.com/epinio/epinio/internal/helmchart"
 	"github.com/epinio/epinio/internal/names"
+	"github.com/epinio/epinio/internal/policy"
 	"github.com/epinio/epinio/internal/registry"
 	"github.com/epinio/epinio/internal/s3manager"
+	"github.com/epinio/epinio/internal/sbom"
+	"github.com/epinio/epinio/internal/staginglogs"
+	"github.com/epinio/epinio/internal/stagingqueue"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 )
@@ -34,7 +42,11 @@ import (
 type stageParam struct {
 	models.AppRef
 	BlobUID             string
+	BlobURL             string
 	BuilderImage        string
+	Strategy            string
+	Dockerfile          models.DockerfileBuild
+	DockerfileImage     string
 	DownloadImage       string
 	UnpackImage         string
 	Environment         models.EnvVariableList
@@ -46,6 +58,7 @@ type stageParam struct {
 	PreviousStageID     string
 	RegistryCASecret    string
 	RegistryCAHash      string
+	Timeout             time.Duration
 }
 
 // ImageURL returns the URL of the container image to be, using the
@@ -55,46 +68,24 @@ func (app *stageParam) ImageURL(registryURL string) string {
 	return fmt.Sprintf("%s/%s-%s:%s", registryURL, app.Namespace, app.Name, app.Stage.ID)
 }
 
-// ensurePVC creates a PVC for the application if one doesn't already exist.
-// This PVC is used to store the application source blobs (as they are uploaded
-// on the "upload" endpoint). It is also mounted in the staging pod, as the
-// "source" workspace.
-// The same PVC stores the application's build cache (on a separate directory).
-func ensurePVC(ctx context.Context, cluster *kubernetes.Cluster, ar models.AppRef) error {
-	_, err := cluster.Kubectl.CoreV1().PersistentVolumeClaims(helmchart.Namespace()).
-		Get(ctx, ar.MakePVCName(), metav1.GetOptions{})
-	if err != nil && !apierrors.IsNotFound(err) { // Unknown error, irrelevant to non-existence
-		return err
-	}
-	if err == nil { // pvc already exists
-		return nil
-	}
+// blobFetchScript downloads the blob from the API server, for the filesystem blob store.
+// It stores the blob where the download script of the S3 store does, for the unpack
+// script.
+const blobFetchScript = `curl --fail --silent --show-error --location --retry 3 --output "/workspace/source/${BLOBID}.tar" "${BLOBURL}"`
 
-	// From here on, only if the PVC is missing
-	_, err = cluster.Kubectl.CoreV1().PersistentVolumeClaims(helmchart.Namespace()).
-		Create(ctx, &corev1.PersistentVolumeClaim{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      ar.MakePVCName(),
-				Namespace: helmchart.Namespace(),
-			},
-			Spec: corev1.PersistentVolumeClaimSpec{
-				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
-				Resources: corev1.ResourceRequirements{
-					Requests: map[corev1.ResourceName]resource.Quantity{
-						corev1.ResourceStorage: resource.MustParse("1Gi"),
-					},
-				},
-			},
-		}, metav1.CreateOptions{})
-
-	return err
+// stagingTimeout returns the maximum duration of a staging run, as configured for the
+// server. Without configuration it is the regular time given to building an application.
+func stagingTimeout() time.Duration {
+	if timeout := viper.GetDuration("staging-timeout"); timeout > 0 {
+		return timeout
+	}
+	return duration.ToAppBuilt()
 }
 
 // Stage handles the API endpoint /namespaces/:namespace/applications/:app/stage
 // It creates a Job resource to stage the app
 func (hc Controller) Stage(c *gin.Context) apierror.APIErrors {
 	ctx := c.Request.Context()
-	log := requestctx.Logger(ctx)
 
 	namespace := c.Param("namespace")
 	name := c.Param("app")
@@ -111,35 +102,72 @@ func (hc Controller) Stage(c *gin.Context) apierror.APIErrors {
 		return apierror.NewBadRequest("namespace parameter from URL does not match namespace param in body")
 	}
 
+	resp, apierr := stageApp(ctx, req, username)
+	if apierr != nil {
+		return apierr
+	}
+
+	response.OKReturn(c, resp)
+	return nil
+}
+
+// stageApp creates the Job resource staging the app as requested, on behalf of the user.
+func stageApp(ctx context.Context, req models.StageRequest, username string) (*models.StageResponse, apierror.APIErrors) {
+	log := requestctx.Logger(ctx)
+	namespace := req.App.Namespace
+
 	cluster, err := kubernetes.GetCluster(ctx)
 	if err != nil {
-		return apierror.InternalError(err, "failed to get access to a kube client")
+		return nil, apierror.InternalError(err, "failed to get access to a kube client")
 	}
 
 	// check application resource
 	app, err := application.Get(ctx, cluster, req.App)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			return apierror.AppIsNotKnown("cannot stage app, application resource is missing")
+			return nil, apierror.AppIsNotKnown("cannot stage app, application resource is missing")
 		}
-		return apierror.InternalError(err, "failed to get the application resource")
+		return nil, apierror.InternalError(err, "failed to get the application resource")
 	}
 
 	config, err := cluster.GetConfigMap(ctx, helmchart.Namespace(), helmchart.EpinioStageScriptsName)
 	if err != nil {
-		return apierror.InternalError(err, "failed to retrieve staging image refs")
+		return nil, apierror.InternalError(err, "failed to retrieve staging image refs")
 	}
 
 	// get builder image from either request, application, or default as final fallback
 
 	builderImage, builderErr := getBuilderImage(req, app)
 	if builderErr != nil {
-		return builderErr
+		return nil, builderErr
 	}
 	if builderImage == "" {
 		builderImage = config.Data["builderImage"]
 	}
 
+	strategy, dockerfile, strategyErr := getStagingStrategy(req, app)
+	if strategyErr != nil {
+		return nil, strategyErr
+	}
+
+	dockerfileImage := config.Data["dockerfileBuilderImage"]
+	if dockerfileImage == "" {
+		dockerfileImage = defaultDockerfileBuilderImage
+	}
+
+	// The policies restrict the image the build runs in.
+	builder := builderImage
+	if strategy == models.StagingStrategyDockerfile {
+		builder = dockerfileImage
+	}
+	apierr := policy.Enforce(ctx, cluster, namespace, models.PolicySubject{
+		App:     req.App.Name,
+		Builder: builder,
+	})
+	if apierr != nil {
+		return nil, apierr
+	}
+
 	downloadImage := config.Data["downloadImage"]
 	unpackImage := config.Data["unpackImage"]
 
@@ -147,33 +175,42 @@ func (hc Controller) Stage(c *gin.Context) apierror.APIErrors {
 
 	staging, err := application.CurrentlyStaging(ctx, cluster, req.App.Namespace, req.App.Name)
 	if err != nil {
-		return apierror.InternalError(err)
+		return nil, apierror.InternalError(err)
 	}
 	if staging {
-		return apierror.NewBadRequest("Staging job for image ID still running")
+		return nil, apierror.NewBadRequest("Staging job for image ID still running",
+			"cancel it with `epinio app stage cancel`, or wait for it to finish")
 	}
 
-	s3ConnectionDetails, err := s3manager.GetConnectionDetails(ctx, cluster,
-		helmchart.Namespace(), helmchart.S3ConnectionDetailsSecretName)
-	if err != nil {
-		return apierror.InternalError(err, "failed to fetch the S3 connection details")
+	blobUID, blobErr := getBlobUID(ctx, cluster, req, app)
+	if blobErr != nil {
+		return nil, blobErr
 	}
 
-	blobUID, blobErr := getBlobUID(ctx, s3ConnectionDetails, req, app)
-	if blobErr != nil {
-		return blobErr
+	// The job fetches the blob from S3, or from the API server for the filesystem store.
+	s3ConnectionDetails := s3manager.ConnectionDetails{}
+	blobURL := ""
+	if blobstore.Backend() == blobstore.BackendFilesystem {
+		blobURL = blobstore.DownloadURL(blobUID)
+	} else {
+		s3ConnectionDetails, err = s3manager.GetConnectionDetails(ctx, cluster,
+			helmchart.Namespace(), helmchart.S3ConnectionDetailsSecretName)
+		if err != nil {
+			return nil, apierror.InternalError(err, "failed to fetch the S3 connection details")
+		}
 	}
 
 	// Create uid identifying the staging job to be
 
 	uid, err := randstr.Hex16()
 	if err != nil {
-		return apierror.InternalError(err, "failed to generate a uid")
+		return nil, apierror.InternalError(err, "failed to generate a uid")
 	}
 
-	environment, err := application.Environment(ctx, cluster, req.App)
+	// Note: The runtime environment is not given to the build. See BuildEnvironment.
+	environment, err := application.BuildEnvironment(ctx, cluster, req.App)
 	if err != nil {
-		return apierror.InternalError(err, "failed to access application runtime environment")
+		return nil, apierror.InternalError(err, "failed to access application staging environment")
 	}
 
 	owner := metav1.OwnerReference{
@@ -187,7 +224,7 @@ func (hc Controller) Stage(c *gin.Context) apierror.APIErrors {
 	// From the view of the new build we are about to create this is the previous id.
 	previousID, err := application.StageID(app)
 	if err != nil {
-		return apierror.InternalError(err, "failed to determine application stage id")
+		return nil, apierror.InternalError(err, "failed to determine application stage id")
 	}
 	if previousID == "" {
 		previousID = uid
@@ -195,7 +232,7 @@ func (hc Controller) Stage(c *gin.Context) apierror.APIErrors {
 
 	registryPublicURL, err := getRegistryURL(ctx, cluster)
 	if err != nil {
-		return apierror.InternalError(err, "getting the Epinio registry public URL")
+		return nil, apierror.InternalError(err, "getting the Epinio registry public URL")
 	}
 
 	registryCertificateSecret := viper.GetString("registry-certificate-secret")
@@ -203,16 +240,20 @@ func (hc Controller) Stage(c *gin.Context) apierror.APIErrors {
 	if registryCertificateSecret != "" {
 		registryCertificateHash, err = getRegistryCertificateHash(ctx, cluster, helmchart.Namespace(), registryCertificateSecret)
 		if err != nil {
-			return apierror.InternalError(err, "cannot calculate Certificate hash")
+			return nil, apierror.InternalError(err, "cannot calculate Certificate hash")
 		}
 	}
 
 	params := stageParam{
 		AppRef:              req.App,
 		BuilderImage:        builderImage,
+		Strategy:            strategy,
+		Dockerfile:          dockerfile,
+		DockerfileImage:     dockerfileImage,
 		DownloadImage:       downloadImage,
 		UnpackImage:         unpackImage,
 		BlobUID:             blobUID,
+		BlobURL:             blobURL,
 		Environment:         environment.List(),
 		Owner:               owner,
 		RegistryURL:         registryPublicURL,
@@ -222,39 +263,79 @@ func (hc Controller) Stage(c *gin.Context) apierror.APIErrors {
 		Username:            username,
 		RegistryCAHash:      registryCertificateHash,
 		RegistryCASecret:    registryCertificateSecret,
+		Timeout:             stagingTimeout(),
 	}
 
-	err = ensurePVC(ctx, cluster, req.App)
+	err = buildcache.Ensure(ctx, cluster, req.App, buildCacheSettings())
 	if err != nil {
-		return apierror.InternalError(err, "failed to ensure a PersistenVolumeClaim for the application source and cache")
+		return nil, apierror.InternalError(err, "failed to ensure a PersistenVolumeClaim for the application source and cache")
 	}
 
 	job, jobenv := newJobRun(params)
 
+	// With limits on concurrent builds the job is queued, and started by the scheduler.
+	scheduler := stagingqueue.Running()
+	if scheduler != nil {
+		job.Spec.Suspend = pointer.Bool(true)
+	}
+
 	// Note: The secret is deleted with the job in function `Unstage()`.
 	err = cluster.CreateSecret(ctx, helmchart.Namespace(), *jobenv)
 	if err != nil {
-		return apierror.InternalError(err, fmt.Sprintf("failed to create job env: %#v", jobenv))
+		return nil, apierror.InternalError(err, fmt.Sprintf("failed to create job env: %#v", jobenv))
 	}
 
 	err = cluster.CreateJob(ctx, helmchart.Namespace(), job)
 	if err != nil {
-		return apierror.InternalError(err, fmt.Sprintf("failed to create job run: %#v", job))
+		return nil, apierror.InternalError(err, fmt.Sprintf("failed to create job run: %#v", job))
 	}
 
 	if err := updateApp(ctx, cluster, app, params); err != nil {
-		return apierror.InternalError(err, "updating application CR with staging information")
+		return nil, apierror.InternalError(err, "updating application CR with staging information")
+	}
+
+	provenance, err := stageProvenance(app, params)
+	if err != nil {
+		return nil, apierror.InternalError(err, "failed to determine the staging provenance")
+	}
+
+	// Keep the logs of the run beyond the life of its job, and record the SBOM of its
+	// image when it succeeded. The capture outlives the request, but not the API server,
+	// see package staginglogs.
+	captureCtx := requestctx.WithLogger(context.Background(), log)
+	go func() {
+		if err := staginglogs.Capture(captureCtx, cluster, req.App, uid); err != nil {
+			log.Info("storing the staging logs failed", "uid", uid, "error", err.Error())
+		}
+		if err := sbom.Capture(captureCtx, cluster, provenance); err != nil {
+			log.Info("storing the SBOM failed", "uid", uid, "error", err.Error())
+		}
+	}()
+
+	position := 0
+	if scheduler != nil {
+		// Start the job right away if the limits allow. Failures are retried by the
+		// scheduler in the background.
+		if err := scheduler.Dispatch(ctx, cluster); err != nil {
+			log.Info("staging dispatch failed", "error", err.Error())
+		}
+
+		position, err = stagingqueue.Position(ctx, cluster, uid)
+		if err != nil {
+			return nil, apierror.InternalError(err, "determining the staging queue position")
+		}
 	}
 
 	imageURL := params.ImageURL(params.RegistryURL)
 
-	log.Info("staged app", "namespace", helmchart.Namespace(), "app", params.AppRef, "uid", uid, "image", imageURL)
+	log.Info("staged app", "namespace", helmchart.Namespace(), "app", params.AppRef, "uid", uid, "image", imageURL,
+		"strategy", strategy, "queue-position", position)
 
-	response.OKReturn(c, models.StageResponse{
-		Stage:    models.NewStage(uid),
-		ImageURL: imageURL,
-	})
-	return nil
+	return &models.StageResponse{
+		Stage:         models.NewStage(uid),
+		ImageURL:      imageURL,
+		QueuePosition: position,
+	}, nil
 }
 
 // Staged handles the API endpoint /namespaces/:namespace/staging/:stage_id/complete
@@ -270,6 +351,17 @@ func (hc Controller) Staged(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err)
 	}
 
+	if apierr := waitForStaging(ctx, cluster, namespace, id); apierr != nil {
+		return apierr
+	}
+
+	response.OK(c)
+	return nil
+}
+
+// waitForStaging waits for the identified staging run of the namespace to be done, and
+// returns an error if it did not succeed.
+func waitForStaging(ctx context.Context, cluster *kubernetes.Cluster, namespace, id string) apierror.APIErrors {
 	// Wait for the staging to be done, then check if it ended in failure.
 	// Select the job for this stage `id`.
 	selector := fmt.Sprintf("app.kubernetes.io/component=staging,app.kubernetes.io/part-of=%s,epinio.io/stage-id=%s",
@@ -284,34 +376,168 @@ func (hc Controller) Staged(c *gin.Context) apierror.APIErrors {
 	}
 
 	for _, job := range jobList.Items {
-		// Wait for job to be done
-		err = cluster.WaitForJobDone(ctx, helmchart.Namespace(), job.Name, duration.ToAppBuilt())
+		// Wait for a queued job to be started by the scheduler. This has no timeout of
+		// its own, the wait ends with the request.
+		err = wait.PollImmediateUntil(time.Second, func() (bool, error) {
+			current, err := cluster.Kubectl.BatchV1().Jobs(helmchart.Namespace()).Get(ctx, job.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			return !stagingqueue.Suspended(*current), nil
+		}, ctx.Done())
+		if apierrors.IsNotFound(err) {
+			return apierror.NewAPIError("Staging was cancelled",
+				fmt.Sprintf("stage-id = %s", id), http.StatusGone)
+		}
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+
+		// Wait for job to be done. The job is given a bit more than its own deadline,
+		// to have kube report the deadline, instead of the wait timing out first.
+		timeout := duration.ToAppBuilt()
+		if job.Spec.ActiveDeadlineSeconds != nil {
+			timeout = time.Duration(*job.Spec.ActiveDeadlineSeconds)*time.Second + time.Minute
+		}
+
+		err = cluster.WaitForJobDone(ctx, helmchart.Namespace(), job.Name, timeout)
+		if apierrors.IsNotFound(err) {
+			return apierror.NewAPIError("Staging was cancelled",
+				fmt.Sprintf("stage-id = %s", id), http.StatusGone)
+		}
 		if err != nil {
 			return apierror.InternalError(err)
 		}
+
 		// Check job for failure
-		failed, err := cluster.IsJobFailed(ctx, job.Name, helmchart.Namespace())
+		doneJob, err := cluster.Kubectl.BatchV1().Jobs(helmchart.Namespace()).Get(ctx, job.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return apierror.NewAPIError("Staging was cancelled",
+				fmt.Sprintf("stage-id = %s", id), http.StatusGone)
+		}
 		if err != nil {
 			return apierror.InternalError(err)
 		}
-		if failed {
+		for _, condition := range doneJob.Status.Conditions {
+			if condition.Type != batchv1.JobFailed || condition.Status != corev1.ConditionTrue {
+				continue
+			}
+			if condition.Reason == "DeadlineExceeded" {
+				limit := time.Duration(*doneJob.Spec.ActiveDeadlineSeconds) * time.Second
+				return apierror.NewInternalError("Failed to stage, the build exceeded its maximum duration",
+					fmt.Sprintf("stage-id = %s", id),
+					fmt.Sprintf("maximum duration = %s", limit))
+			}
 			return apierror.NewInternalError("Failed to stage",
 				fmt.Sprintf("stage-id = %s", id))
 		}
 	}
 
+	return nil
+}
+
+// StagingStatus handles the API endpoint /namespaces/:namespace/staging (GET)
+// It returns the running and queued staging runs of the namespace, and the limits on
+// concurrently running builds.
+func (hc Controller) StagingStatus(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	builds, err := stagingqueue.Status(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	resp := models.StagingStatusResponse{
+		Builds: builds,
+	}
+	if scheduler := stagingqueue.Running(); scheduler != nil {
+		resp.MaxConcurrent = scheduler.Limits().Global
+		resp.MaxConcurrentNamespace = scheduler.Limits().Namespace
+	}
+
+	response.OKReturn(c, resp)
+	return nil
+}
+
+// StageCancel handles the API endpoint /namespaces/:namespace/staging/:stage_id (DELETE)
+// It stops the Job resource staging the app, and removes the Secret holding its
+// environment.
+func (hc Controller) StageCancel(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
+
+	namespace := c.Param("namespace")
+	id := c.Param("stage_id")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	job, err := application.StagingJob(ctx, cluster, namespace, id)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if job == nil {
+		return apierror.NewNotFoundError("Staging run not found", fmt.Sprintf("stage-id = %s", id))
+	}
+	if !application.JobStaging(*job) {
+		return apierror.NewBadRequest("Staging already finished", fmt.Sprintf("stage-id = %s", id))
+	}
+
+	log.Info("cancel staging", "namespace", namespace, "stage-id", id, "job", job.Name)
+
+	err = application.StagingCancel(ctx, cluster, job)
+	if err != nil {
+		return apierror.InternalError(err, "failed to cancel the staging job")
+	}
+
 	response.OK(c)
 	return nil
 }
 
-func validateBlob(ctx context.Context, blobUID string, app models.AppRef, s3ConnectionDetails s3manager.ConnectionDetails) apierror.APIErrors {
+// stageProvenance returns the provenance record of the image built by the staging run.
+func stageProvenance(app *unstructured.Unstructured, params stageParam) (models.Provenance, error) {
+	provenance := models.Provenance{
+		App:          params.AppRef,
+		StageID:      params.Stage.ID,
+		ImageURL:     params.ImageURL(params.RegistryURL),
+		BlobUID:      params.BlobUID,
+		Strategy:     params.Strategy,
+		BuilderImage: params.BuilderImage,
+		Username:     params.Username,
+		CreatedAt:    metav1.Now(),
+	}
+	if params.Strategy == models.StagingStrategyDockerfile {
+		provenance.BuilderImage = params.DockerfileImage
+	}
+
+	origin, err := application.Origin(app)
+	if err != nil {
+		return provenance, err
+	}
+	if origin.Kind == models.OriginGit && origin.Git != nil {
+		provenance.GitURL = origin.Git.URL
+		provenance.GitCommit = origin.Git.Commit
+	}
+
+	return provenance, nil
+}
+
+func validateBlob(ctx context.Context, cluster *kubernetes.Cluster, blobUID string, app models.AppRef) apierror.APIErrors {
 
-	manager, err := s3manager.New(s3ConnectionDetails)
+	store, err := blobstore.New(ctx, cluster)
 	if err != nil {
-		return apierror.InternalError(err, "creating an S3 manager")
+		return apierror.InternalError(err, "accessing the blob store")
 	}
 
-	blobMeta, err := manager.Meta(ctx, blobUID)
+	blobMeta, err := store.Meta(ctx, blobUID)
 	if err != nil {
 		return apierror.InternalError(err, "querying blob id meta-data")
 	}
@@ -343,8 +569,8 @@ func validateBlob(ctx context.Context, blobUID string, app models.AppRef, s3Conn
 
 // newJobRun is a helper which creates the Job related resources from
 // the given staging params. That is the job itself, and a secret
-// holding the job's environment. Which is a copy of the app
-// environment + standard variables.
+// holding the job's environment. Which is a copy of the app's staging
+// environment + standard variables. Only the builder container sees it.
 func newJobRun(app stageParam) (*batchv1.Job, *corev1.Secret) {
 
 	jobName := names.GenerateResourceName("stage", app.Namespace, app.Name, app.Stage.ID)
@@ -363,12 +589,15 @@ func newJobRun(app stageParam) (*batchv1.Job, *corev1.Secret) {
 	// Note: `source` is required because the mounted files are not executable.
 
 	// runtime: AWSCLIImage
-	awsScript := fmt.Sprintf("source /stage-support/%s", helmchart.EpinioStageDownload)
+	downloadScript := fmt.Sprintf("source /stage-support/%s", helmchart.EpinioStageDownload)
+	if app.BlobURL != "" {
+		downloadScript = blobFetchScript
+	}
 
 	// runtime: BashImage
 	unpackScript := fmt.Sprintf(`source /stage-support/%s`, helmchart.EpinioStageUnpack)
 
-	// runtime: app.BuilderImage
+	// runtime: app.BuilderImage, for the buildpacks strategy
 	buildpackScript := fmt.Sprintf(`source /stage-support/%s`, helmchart.EpinioStageBuild)
 
 	// build configuration
@@ -398,13 +627,14 @@ func newJobRun(app stageParam) (*batchv1.Job, *corev1.Secret) {
 			Value: app.ImageURL(app.RegistryURL),
 		},
 	}
+	if app.BlobURL != "" {
+		stageEnv = append(stageEnv, corev1.EnvVar{
+			Name:  "BLOBURL",
+			Value: app.BlobURL,
+		})
+	}
 
 	volumeMounts := []corev1.VolumeMount{
-		{
-			Name:      "s3-creds",
-			MountPath: "/root/.aws",
-			ReadOnly:  true,
-		},
 		{
 			Name:      "source",
 			SubPath:   "source",
@@ -424,11 +654,6 @@ func newJobRun(app stageParam) (*batchv1.Job, *corev1.Secret) {
 			Name:      "staging",
 			MountPath: "/stage-support",
 		},
-		{
-			Name:      "app-environment",
-			MountPath: "/workspace/source/appenv",
-			ReadOnly:  true,
-		},
 	}
 
 	cacheClaim := &corev1.PersistentVolumeClaimVolumeSource{
@@ -470,15 +695,6 @@ func newJobRun(app stageParam) (*batchv1.Job, *corev1.Secret) {
 				EmptyDir: &corev1.EmptyDirVolumeSource{},
 			},
 		},
-		{
-			Name: "s3-creds",
-			VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName:  helmchart.S3ConnectionDetailsSecretName,
-					DefaultMode: pointer.Int32(420),
-				},
-			},
-		},
 		{
 			Name: "registry-creds",
 			VolumeSource: corev1.VolumeSource{
@@ -496,10 +712,40 @@ func newJobRun(app stageParam) (*batchv1.Job, *corev1.Secret) {
 		},
 	}
 
-	volumes, volumeMounts = mountS3Certs(volumes, volumeMounts)
+	if app.BlobURL == "" {
+		volumes, volumeMounts = mountS3Creds(volumes, volumeMounts)
+		volumes, volumeMounts = mountS3Certs(volumes, volumeMounts)
+	}
 	volumes, volumeMounts = mountRegistryCerts(app, volumes, volumeMounts)
 
-	// Create job environment as a copy of the app environment, plus standard variable.
+	// The staging environment is for the builder only.
+	builderVolumeMounts := append([]corev1.VolumeMount{}, volumeMounts...)
+	builderVolumeMounts = append(builderVolumeMounts, corev1.VolumeMount{
+		Name:      "app-environment",
+		MountPath: "/workspace/source/appenv",
+		ReadOnly:  true,
+	})
+
+	builder := corev1.Container{
+		Name:    "buildpack",
+		Image:   app.BuilderImage,
+		Command: []string{"/bin/bash"},
+		Args: []string{
+			"-c",
+			buildpackScript,
+		},
+		Env:          stageEnv,
+		VolumeMounts: builderVolumeMounts,
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser:  pointer.Int64(1000),
+			RunAsGroup: pointer.Int64(1000),
+		},
+	}
+	if app.Strategy == models.StagingStrategyDockerfile {
+		builder = dockerfileContainer(app, stageEnv, builderVolumeMounts)
+	}
+
+	// Create job environment as a copy of the app's staging environment, plus standard variable.
 	env := make(map[string][]byte)
 
 	env["CNB_PLATFORM_API"] = []byte("0.4")
@@ -539,7 +785,8 @@ func newJobRun(app stageParam) (*batchv1.Job, *corev1.Secret) {
 			},
 		},
 		Spec: batchv1.JobSpec{
-			BackoffLimit: pointer.Int32(0),
+			BackoffLimit:          pointer.Int32(0),
+			ActiveDeadlineSeconds: pointer.Int64(int64(app.Timeout.Seconds())),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: map[string]string{
@@ -566,7 +813,7 @@ func newJobRun(app stageParam) (*batchv1.Job, *corev1.Secret) {
 							Command:      []string{"/bin/bash"},
 							Args: []string{
 								"-c",
-								awsScript,
+								downloadScript,
 							},
 							Env: stageEnv,
 						},
@@ -582,23 +829,7 @@ func newJobRun(app stageParam) (*batchv1.Job, *corev1.Secret) {
 							Env: stageEnv,
 						},
 					},
-					Containers: []corev1.Container{
-						{
-							Name:    "buildpack",
-							Image:   app.BuilderImage,
-							Command: []string{"/bin/bash"},
-							Args: []string{
-								"-c",
-								buildpackScript,
-							},
-							Env:          stageEnv,
-							VolumeMounts: volumeMounts,
-							SecurityContext: &corev1.SecurityContext{
-								RunAsUser:  pointer.Int64(1000),
-								RunAsGroup: pointer.Int64(1000),
-							},
-						},
-					},
+					Containers:    []corev1.Container{builder},
 					RestartPolicy: corev1.RestartPolicyNever,
 					Volumes:       volumes,
 				},
@@ -667,7 +898,7 @@ func getBuilderImage(req models.StageRequest, app *unstructured.Unstructured) (s
 	return builderImage, nil
 }
 
-func getBlobUID(ctx context.Context, s3ConnectionDetails s3manager.ConnectionDetails, req models.StageRequest, app *unstructured.Unstructured) (string, apierror.APIErrors) {
+func getBlobUID(ctx context.Context, cluster *kubernetes.Cluster, req models.StageRequest, app *unstructured.Unstructured) (string, apierror.APIErrors) {
 	var blobUID string
 	var err error
 	var returnErr apierror.APIErrors
@@ -688,7 +919,7 @@ func getBlobUID(ctx context.Context, s3ConnectionDetails s3manager.ConnectionDet
 	}
 
 	// Validate incoming blob id before attempting to stage
-	apierr := validateBlob(ctx, blobUID, req.App, s3ConnectionDetails)
+	apierr := validateBlob(ctx, cluster, blobUID, req.App)
 	if apierr != nil {
 		return "", apierr
 	}
@@ -715,6 +946,10 @@ func updateApp(ctx context.Context, cluster *kubernetes.Cluster, app *unstructur
 	if err := unstructured.SetNestedField(app.Object, params.BuilderImage, "spec", "builderimage"); err != nil {
 		return err
 	}
+	if err := application.SetStaging(app, stagingSettings(params)); err != nil {
+		return err
+	}
+	application.AddStageHistory(app, params.Stage.ID)
 
 	client, err := cluster.ClientApp()
 	if err != nil {
@@ -731,6 +966,25 @@ func updateApp(ctx context.Context, cluster *kubernetes.Cluster, app *unstructur
 	return err
 }
 
+func mountS3Creds(volumes []corev1.Volume, volumeMounts []corev1.VolumeMount) ([]corev1.Volume, []corev1.VolumeMount) {
+	volumes = append(volumes, corev1.Volume{
+		Name: "s3-creds",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  helmchart.S3ConnectionDetailsSecretName,
+				DefaultMode: pointer.Int32(420),
+			},
+		},
+	})
+	volumeMounts = append(volumeMounts, corev1.VolumeMount{
+		Name:      "s3-creds",
+		MountPath: "/root/.aws",
+		ReadOnly:  true,
+	})
+
+	return volumes, volumeMounts
+}
+
 func mountS3Certs(volumes []corev1.Volume, volumeMounts []corev1.VolumeMount) ([]corev1.Volume, []corev1.VolumeMount) {
 	if s3CertificateSecret := viper.GetString("s3-certificate-secret"); s3CertificateSecret != "" {
 		volumes = append(volumes, corev1.Volume{
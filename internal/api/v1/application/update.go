@@ -6,11 +6,13 @@ import (
 
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/internal/api/v1/deploy"
+	namespaceapi "github.com/epinio/epinio/internal/api/v1/namespace"
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/appchart"
 	"github.com/epinio/epinio/internal/application"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
 	"github.com/epinio/epinio/internal/configurations"
+	"github.com/epinio/epinio/internal/policy"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/gin-gonic/gin"
@@ -62,6 +64,8 @@ func (hc Controller) Update(c *gin.Context) apierror.APIErrors { // nolint:gocyc
 	// if there is nothing to change
 	if updateRequest.Instances == nil &&
 		len(updateRequest.Environment) == 0 &&
+		len(updateRequest.EnvironmentFrom) == 0 &&
+		updateRequest.StagingEnvironment == nil &&
 		updateRequest.Configurations == nil &&
 		len(updateRequest.Routes) == 0 &&
 		updateRequest.AppChart == "" {
@@ -69,6 +73,49 @@ func (hc Controller) Update(c *gin.Context) apierror.APIErrors { // nolint:gocyc
 		return nil
 	}
 
+	// Validate the configuration references of the environment against the bindings
+	// the app will have after the update.
+
+	if len(updateRequest.EnvironmentFrom) > 0 || updateRequest.Configurations != nil {
+		references := app.Configuration.EnvironmentFrom
+		if len(updateRequest.EnvironmentFrom) > 0 {
+			references = updateRequest.EnvironmentFrom
+		}
+		bound := app.Configuration.Configurations
+		if updateRequest.Configurations != nil {
+			bound = updateRequest.Configurations
+		}
+
+		apierr := application.ValidateEnvironmentFrom(ctx, cluster, namespace, references, bound)
+		if apierr != nil {
+			return apierr
+		}
+	}
+
+	// Evaluate the policies against the changed settings.
+
+	subject := models.PolicySubject{
+		App:         appName,
+		Instances:   updateRequest.Instances,
+		Environment: policy.EnvironmentNames(updateRequest),
+	}
+	if updateRequest.AppChart != app.Configuration.AppChart {
+		subject.AppChart = updateRequest.AppChart
+	}
+	apierr := policy.Enforce(ctx, cluster, namespace, subject)
+	if apierr != nil {
+		return apierr
+	}
+
+	if updateRequest.Instances != nil && app.Configuration.Instances != nil {
+		apierr := namespaceapi.EnforceQuota(ctx, cluster, namespace, models.NamespaceUsage{
+			Instances: *updateRequest.Instances - *app.Configuration.Instances,
+		})
+		if apierr != nil {
+			return apierr
+		}
+	}
+
 	// Save all changes to the relevant parts of the app resources (CRD, secrets, and the like).
 
 	if updateRequest.AppChart != "" && updateRequest.AppChart != app.Configuration.AppChart {
@@ -119,6 +166,20 @@ func (hc Controller) Update(c *gin.Context) apierror.APIErrors { // nolint:gocyc
 		}
 	}
 
+	if len(updateRequest.EnvironmentFrom) > 0 {
+		err := application.EnvironmentFromSet(ctx, cluster, app.Meta, updateRequest.EnvironmentFrom, true)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+	}
+
+	if updateRequest.StagingEnvironment != nil {
+		err := application.StagingEnvironmentSet(ctx, cluster, app.Meta, *updateRequest.StagingEnvironment, true)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+	}
+
 	if updateRequest.Configurations != nil {
 		var okToBind []string
 
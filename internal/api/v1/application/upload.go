@@ -1,19 +1,30 @@
 package application
 
 import (
+	"context"
+	"reflect"
+	"strings"
+
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/blobstore"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
-	"github.com/epinio/epinio/internal/helmchart"
-	"github.com/epinio/epinio/internal/s3manager"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
+// sourceHashMeta is the blob meta data holding the hash of the sources, as computed by
+// the client.
+const sourceHashMeta = "source-hash"
+
 // Upload handles the API endpoint /namespaces/:namespace/applications/:app/store.
 // It receives the application data as a tarball and stores it. Then
-// it creates the k8s resources needed for staging
+// it creates the k8s resources needed for staging. The optional `hash` query parameter is
+// the hash of the sources, recorded with the blob for UploadMatch.
 func (hc Controller) Upload(c *gin.Context) apierror.APIErrors {
 	ctx := c.Request.Context()
 	log := requestctx.Logger(ctx)
@@ -36,19 +47,19 @@ func (hc Controller) Upload(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err, "failed to get access to a kube client")
 	}
 
-	connectionDetails, err := s3manager.GetConnectionDetails(ctx, cluster, helmchart.Namespace(), helmchart.S3ConnectionDetailsSecretName)
-	if err != nil {
-		return apierror.InternalError(err, "fetching the S3 connection details from the Kubernetes secret")
-	}
-	manager, err := s3manager.New(connectionDetails)
+	store, err := blobstore.New(ctx, cluster)
 	if err != nil {
-		return apierror.InternalError(err, "creating an S3 manager")
+		return apierror.InternalError(err, "accessing the blob store")
 	}
 
 	username := requestctx.User(ctx).Username
-	blobUID, err := manager.UploadStream(ctx, file, fileheader.Size, map[string]string{
+	metadata := map[string]string{
 		"app": name, "namespace": namespace, "username": username,
-	})
+	}
+	if hash := c.Query("hash"); hash != "" {
+		metadata[sourceHashMeta] = hash
+	}
+	blobUID, err := store.UploadStream(ctx, file, fileheader.Size, metadata)
 	if err != nil {
 		return apierror.InternalError(err, "uploading the application sources blob")
 	}
@@ -60,3 +71,130 @@ func (hc Controller) Upload(c *gin.Context) apierror.APIErrors {
 	})
 	return nil
 }
+
+// UploadMatch handles the API endpoint /namespaces/:namespace/applications/:app/store/match.
+// It checks if the blob of the application's last staging holds the sources with the
+// requested hash, and returns it for reuse. If the last staging was successful, and used
+// the requested staging settings as well, the stage is returned too, for reuse without
+// staging.
+func (hc Controller) UploadMatch(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
+	appRef := models.NewAppRef(c.Param("app"), c.Param("namespace"))
+
+	var req models.UploadMatchRequest
+	if err := c.BindJSON(&req); err != nil {
+		return apierror.BadRequest(err)
+	}
+	if req.Hash == "" {
+		return apierror.NewBadRequest("missing source hash")
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err, "failed to get access to a kube client")
+	}
+
+	app, err := application.Get(ctx, cluster, appRef)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return apierror.AppIsNotKnown(appRef.Name)
+		}
+		return apierror.InternalError(err)
+	}
+
+	resp := models.UploadMatchResponse{}
+
+	blobUID, err := findPreviousBlobUID(app)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if blobUID == "" {
+		response.OKReturn(c, resp)
+		return nil
+	}
+
+	store, err := blobstore.New(ctx, cluster)
+	if err != nil {
+		return apierror.InternalError(err, "accessing the blob store")
+	}
+
+	meta, err := store.Meta(ctx, blobUID)
+	if err != nil {
+		if blobstore.IsNotFound(err) {
+			response.OKReturn(c, resp)
+			return nil
+		}
+		return apierror.InternalError(err, "querying blob id meta-data")
+	}
+	if blobstore.MetaValue(meta, sourceHashMeta) != req.Hash {
+		response.OKReturn(c, resp)
+		return nil
+	}
+	resp.BlobUID = blobUID
+
+	stageID, imageURL, apierr := reusableStage(ctx, cluster, app, appRef, req)
+	if apierr != nil {
+		return apierr
+	}
+	resp.StageID = stageID
+	resp.ImageURL = imageURL
+
+	log.Info("upload match", "namespace", appRef.Namespace, "app", appRef.Name, "blobUID", blobUID, "stage", stageID)
+
+	response.OKReturn(c, resp)
+	return nil
+}
+
+// reusableStage returns the stage and image of the application's last staging, if that
+// staging succeeded, and the requested staging settings are the same as the ones it used.
+// The build environment of the application is part of these settings. Otherwise the
+// results are empty.
+func reusableStage(ctx context.Context, cluster *kubernetes.Cluster, app *unstructured.Unstructured, appRef models.AppRef, req models.UploadMatchRequest) (string, string, apierror.APIErrors) {
+	stageID, err := application.StageID(app)
+	if err != nil {
+		return "", "", apierror.InternalError(err)
+	}
+	imageURL, err := application.ImageURL(app)
+	if err != nil {
+		return "", "", apierror.InternalError(err)
+	}
+
+	// The image of a stage is only recorded after the stage succeeded.
+	if stageID == "" || !strings.HasSuffix(imageURL, ":"+stageID) {
+		return "", "", nil
+	}
+
+	stageReq := models.StageRequest{
+		BuilderImage: req.BuilderImage,
+		Strategy:     req.Strategy,
+		Dockerfile:   req.Dockerfile,
+	}
+
+	builderImage, apierr := getBuilderImage(models.StageRequest{}, app)
+	if apierr != nil {
+		return "", "", apierr
+	}
+	if req.BuilderImage != "" && req.BuilderImage != builderImage {
+		return "", "", nil
+	}
+
+	strategy, dockerfile, apierr := getStagingStrategy(stageReq, app)
+	if apierr != nil {
+		return "", "", apierr
+	}
+	environment, err := application.BuildEnvironment(ctx, cluster, appRef)
+	if err != nil {
+		return "", "", apierror.InternalError(err, "failed to access application staging environment")
+	}
+	used, err := application.Staging(app)
+	if err != nil {
+		return "", "", apierror.InternalError(err)
+	}
+	current := stagingSettings(stageParam{Strategy: strategy, Dockerfile: dockerfile, Environment: environment.List()})
+	if !reflect.DeepEqual(used, current) {
+		return "", "", nil
+	}
+
+	return stageID, imageURL, nil
+}
@@ -0,0 +1,251 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/blobstore"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+)
+
+// The chunked upload of application sources is an alternative to Upload, for large
+// sources and flaky connections. The client starts an upload session, sends the sources
+// in numbered parts, and completes the session to assemble the blob. A failed part is
+// sent again. The client resumes an interrupted upload by asking the session for the
+// parts stored so far. The sessions are multipart uploads of the blob store, identified
+// by the blob and the `upload` query parameter, and belong to the application they were
+// started for.
+
+// UploadSessionCreate handles the API endpoint POST /namespaces/:namespace/applications/:app/store/sessions
+// It starts a chunked upload of the application's sources.
+func (hc Controller) UploadSessionCreate(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
+
+	namespace := c.Param("namespace")
+	name := c.Param("app")
+
+	var req models.UploadSessionRequest
+	if err := c.BindJSON(&req); err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	store, apierr := uploadStore(ctx)
+	if apierr != nil {
+		return apierr
+	}
+
+	username := requestctx.User(ctx).Username
+	metadata := map[string]string{
+		"app": name, "namespace": namespace, "username": username,
+	}
+	if req.Hash != "" {
+		metadata[sourceHashMeta] = req.Hash
+	}
+	blobUID, uploadID, err := store.InitiateUpload(ctx, metadata)
+	if err != nil {
+		return apierror.InternalError(err, "starting the upload of the application sources")
+	}
+
+	log.Info("upload session started", "namespace", namespace, "app", name, "blobUID", blobUID)
+
+	response.OKReturn(c, models.UploadSession{
+		BlobUID:  blobUID,
+		UploadID: uploadID,
+	})
+	return nil
+}
+
+// UploadSessionShow handles the API endpoint GET /namespaces/:namespace/applications/:app/store/sessions/:blob
+// It returns the upload session with the parts stored so far.
+func (hc Controller) UploadSessionShow(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+
+	blobUID, uploadID, apierr := uploadSessionParams(c)
+	if apierr != nil {
+		return apierr
+	}
+
+	store, apierr := uploadStore(ctx)
+	if apierr != nil {
+		return apierr
+	}
+
+	apierr = checkUploadSession(ctx, c, store, blobUID, uploadID)
+	if apierr != nil {
+		return apierr
+	}
+
+	parts, err := store.ListParts(ctx, blobUID, uploadID)
+	if err != nil {
+		return uploadSessionError(err, blobUID)
+	}
+
+	session := models.UploadSession{
+		BlobUID:  blobUID,
+		UploadID: uploadID,
+	}
+	for _, part := range parts {
+		session.Parts = append(session.Parts, models.UploadPart{Number: part.Number, Size: part.Size})
+	}
+
+	response.OKReturn(c, session)
+	return nil
+}
+
+// UploadSessionPart handles the API endpoint PUT /namespaces/:namespace/applications/:app/store/sessions/:blob/parts/:part
+// It stores the request body as the numbered part of the upload. Sending a part again
+// replaces it.
+func (hc Controller) UploadSessionPart(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+
+	blobUID, uploadID, apierr := uploadSessionParams(c)
+	if apierr != nil {
+		return apierr
+	}
+
+	number, err := strconv.Atoi(c.Param("part"))
+	if err != nil || number < 1 || number > 10000 {
+		return apierror.NewBadRequest("part number has to be between 1 and 10000")
+	}
+
+	size := c.Request.ContentLength
+	if size <= 0 || size > models.MaxUploadPartSize {
+		return apierror.NewBadRequest(fmt.Sprintf("part size has to be known, and at most %d bytes", models.MaxUploadPartSize))
+	}
+
+	store, apierr := uploadStore(ctx)
+	if apierr != nil {
+		return apierr
+	}
+
+	apierr = checkUploadSession(ctx, c, store, blobUID, uploadID)
+	if apierr != nil {
+		return apierr
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, size)
+	part, err := store.UploadPart(ctx, blobUID, uploadID, number, body, size)
+	if err != nil {
+		return uploadSessionError(err, blobUID)
+	}
+
+	response.OKReturn(c, models.UploadPart{
+		Number: part.Number,
+		Size:   part.Size,
+	})
+	return nil
+}
+
+// UploadSessionComplete handles the API endpoint POST /namespaces/:namespace/applications/:app/store/sessions/:blob/complete
+// It assembles the stored parts into the blob, and ends the session. The response is
+// the same as for Upload.
+func (hc Controller) UploadSessionComplete(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
+
+	blobUID, uploadID, apierr := uploadSessionParams(c)
+	if apierr != nil {
+		return apierr
+	}
+
+	store, apierr := uploadStore(ctx)
+	if apierr != nil {
+		return apierr
+	}
+
+	apierr = checkUploadSession(ctx, c, store, blobUID, uploadID)
+	if apierr != nil {
+		return apierr
+	}
+
+	if err := store.CompleteUpload(ctx, blobUID, uploadID); err != nil {
+		return uploadSessionError(err, blobUID)
+	}
+
+	log.Info("uploaded app", "namespace", c.Param("namespace"), "app", c.Param("app"), "blobUID", blobUID)
+
+	response.OKReturn(c, models.UploadResponse{
+		BlobUID: blobUID,
+	})
+	return nil
+}
+
+// UploadSessionDelete handles the API endpoint DELETE /namespaces/:namespace/applications/:app/store/sessions/:blob
+// It aborts the upload, discarding the stored parts.
+func (hc Controller) UploadSessionDelete(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+
+	blobUID, uploadID, apierr := uploadSessionParams(c)
+	if apierr != nil {
+		return apierr
+	}
+
+	store, apierr := uploadStore(ctx)
+	if apierr != nil {
+		return apierr
+	}
+
+	apierr = checkUploadSession(ctx, c, store, blobUID, uploadID)
+	if apierr != nil {
+		return apierr
+	}
+
+	if err := store.AbortUpload(ctx, blobUID, uploadID); err != nil {
+		return uploadSessionError(err, blobUID)
+	}
+
+	response.OK(c)
+	return nil
+}
+
+func uploadSessionParams(c *gin.Context) (string, string, apierror.APIErrors) {
+	uploadID := c.Query("upload")
+	if uploadID == "" {
+		return "", "", apierror.NewBadRequest("missing upload id")
+	}
+	return c.Param("blob"), uploadID, nil
+}
+
+// checkUploadSession rejects sessions which were not started for the application of the
+// request, as missing. This keeps users from reaching into the sessions of namespaces
+// they have no access to.
+func checkUploadSession(ctx context.Context, c *gin.Context, store blobstore.Store, blobUID, uploadID string) apierror.APIErrors {
+	meta, err := store.UploadMeta(ctx, blobUID, uploadID)
+	if err != nil {
+		return uploadSessionError(err, blobUID)
+	}
+	if blobstore.MetaValue(meta, "namespace") != c.Param("namespace") ||
+		blobstore.MetaValue(meta, "app") != c.Param("app") {
+		return uploadSessionError(blobstore.ErrNotFound, blobUID)
+	}
+	return nil
+}
+
+func uploadSessionError(err error, blobUID string) apierror.APIErrors {
+	if blobstore.IsNotFound(err) {
+		return apierror.NewNotFoundError(fmt.Sprintf("upload session for blob '%s' not found", blobUID))
+	}
+	return apierror.InternalError(err)
+}
+
+func uploadStore(ctx context.Context) (blobstore.Store, apierror.APIErrors) {
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return nil, apierror.InternalError(err, "failed to get access to a kube client")
+	}
+
+	store, err := blobstore.New(ctx, cluster)
+	if err != nil {
+		return nil, apierror.InternalError(err, "accessing the blob store")
+	}
+
+	return store, nil
+}
@@ -0,0 +1,294 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/deploy"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/gitwebhook"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+const (
+	// webhookUser is the user recorded for builds triggered by git webhooks.
+	webhookUser = "git-webhook"
+
+	// maxWebhookPayload is the largest payload accepted from git hosting services.
+	maxWebhookPayload = 10 << 20
+)
+
+// WebhookShow handles the API endpoint GET /namespaces/:namespace/applications/:app/webhook
+// It returns the location and secret of the application's git webhook.
+func (hc Controller) WebhookShow(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	appRef := models.NewAppRef(c.Param("app"), c.Param("namespace"))
+
+	cluster, apierr := webhookCluster(ctx, appRef)
+	if apierr != nil {
+		return apierr
+	}
+
+	secret, enabled, err := application.WebhookSecret(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OKReturn(c, webhookResponse(appRef, secret, enabled))
+	return nil
+}
+
+// WebhookEnable handles the API endpoint POST /namespaces/:namespace/applications/:app/webhook
+// It enables the application's git webhook. Enabling an enabled webhook keeps its secret.
+func (hc Controller) WebhookEnable(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	appRef := models.NewAppRef(c.Param("app"), c.Param("namespace"))
+
+	cluster, apierr := webhookCluster(ctx, appRef)
+	if apierr != nil {
+		return apierr
+	}
+
+	secret, enabled, err := application.WebhookSecret(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !enabled {
+		secret, err = application.WebhookSecretRotate(ctx, cluster, appRef)
+		if err != nil {
+			return apierror.InternalError(err, "enabling the webhook")
+		}
+	}
+
+	response.OKReturn(c, webhookResponse(appRef, secret, true))
+	return nil
+}
+
+// WebhookRotate handles the API endpoint POST /namespaces/:namespace/applications/:app/webhook/rotate
+// It replaces the secret of the application's git webhook, enabling the webhook if needed.
+func (hc Controller) WebhookRotate(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	appRef := models.NewAppRef(c.Param("app"), c.Param("namespace"))
+
+	cluster, apierr := webhookCluster(ctx, appRef)
+	if apierr != nil {
+		return apierr
+	}
+
+	secret, err := application.WebhookSecretRotate(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err, "rotating the webhook secret")
+	}
+
+	response.OKReturn(c, webhookResponse(appRef, secret, true))
+	return nil
+}
+
+// GitWebhook handles the API endpoint POST /webhooks/git/:namespace/:app
+// It receives the push notifications of git hosting services. The endpoint is not
+// authenticated. Instead the notifications have to be signed with the secret of the
+// application's webhook. A push to the branch the application is built from imports,
+// stages, and deploys the application in the background.
+func (hc Controller) GitWebhook(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx).WithName("GitWebhook")
+	appRef := models.NewAppRef(c.Param("app"), c.Param("namespace"))
+
+	// Unknown applications and applications without webhook are reported alike, to not
+	// reveal the applications to unauthenticated callers.
+	notFound := apierror.NewNotFoundError("Webhook not found")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	appCR, err := application.Get(ctx, cluster, appRef)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return notFound
+		}
+		return apierror.InternalError(err)
+	}
+
+	secret, enabled, err := application.WebhookSecret(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !enabled {
+		return notFound
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookPayload))
+	if err != nil {
+		return apierror.BadRequest(err, "reading the webhook payload")
+	}
+
+	if err := gitwebhook.Verify(c.Request.Header, body, secret); err != nil {
+		if err == gitwebhook.ErrUnknownProvider {
+			return apierror.BadRequest(err)
+		}
+		return apierror.NewAPIError(err.Error(), "", http.StatusUnauthorized)
+	}
+
+	push, err := gitwebhook.Parse(c.Request.Header, body)
+	if err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	origin, err := application.Origin(appCR)
+	if err != nil {
+		return apierror.InternalError(err, "finding the application origin")
+	}
+
+	reason := webhookIgnoreReason(push, origin)
+	if reason != "" {
+		log.Info("ignored", "app", appRef, "provider", push.Provider, "event", push.Event, "reason", reason)
+		response.OKReturn(c, models.GitWebhookResponse{Reason: reason})
+		return nil
+	}
+
+	log.Info("triggered", "app", appRef, "provider", push.Provider, "ref", push.Ref, "commit", push.Commit)
+
+	// The build outlives the request.
+	buildCtx := requestctx.WithLogger(context.Background(), log)
+	go func() {
+		if err := rebuildFromGit(buildCtx, cluster, appRef, *origin.Git); err != nil {
+			log.Info("build failed", "app", appRef, "commit", push.Commit, "error", err.Error())
+		}
+	}()
+
+	response.OKReturn(c, models.GitWebhookResponse{
+		Triggered: true,
+		Commit:    push.Commit,
+	})
+	return nil
+}
+
+// webhookIgnoreReason returns why the notification does not trigger a build of the
+// application, or the empty string if it does.
+func webhookIgnoreReason(push gitwebhook.Push, origin models.ApplicationOrigin) string {
+	if !push.IsPush() {
+		return fmt.Sprintf("event '%s' is not a push", push.Event)
+	}
+	if origin.Kind != models.OriginGit || origin.Git == nil {
+		return "application is not built from git"
+	}
+
+	sameRepository := false
+	for _, url := range push.Repositories {
+		if gitwebhook.SameRepository(url, origin.Git.URL) {
+			sameRepository = true
+			break
+		}
+	}
+	if !sameRepository {
+		return fmt.Sprintf("push is not for the application repository %s", origin.Git.URL)
+	}
+
+	if push.Deleted() {
+		return "push deleted the ref"
+	}
+
+	branch := push.Branch()
+	if branch == "" {
+		return fmt.Sprintf("push to '%s' is not to a branch", push.Ref)
+	}
+
+	revision := origin.Git.Revision
+	if revision == "" {
+		revision = push.DefaultBranch
+	}
+	if branch != revision {
+		return fmt.Sprintf("push to branch '%s', application is built from '%s'", branch, revision)
+	}
+
+	return ""
+}
+
+// rebuildFromGit imports the revision of the git repository, stages, and deploys the
+// application, like a push of the application from git.
+func rebuildFromGit(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, gitRef models.GitRef) error {
+	log := requestctx.Logger(ctx).WithValues("app", appRef)
+
+	imported, apierr := importGit(ctx, appRef, gitRef, webhookUser, func(string) {})
+	if apierr != nil {
+		return stepError("importing the sources", apierr)
+	}
+	log.Info("imported", "blob", imported.BlobUID, "commit", imported.Commit)
+
+	staged, apierr := stageApp(ctx, models.StageRequest{
+		App:     appRef,
+		BlobUID: imported.BlobUID,
+	}, webhookUser)
+	if apierr != nil {
+		return stepError("staging", apierr)
+	}
+	log.Info("staging", "stage", staged.Stage.ID, "queue-position", staged.QueuePosition)
+
+	if apierr := waitForStaging(ctx, cluster, appRef.Namespace, staged.Stage.ID); apierr != nil {
+		return stepError("staging", apierr)
+	}
+
+	appCR, err := application.Get(ctx, cluster, appRef)
+	if err != nil {
+		return errors.Wrap(err, "deploying")
+	}
+	if err := deploy.UpdateImageURL(ctx, cluster, appCR, staged.ImageURL); err != nil {
+		return errors.Wrap(err, "setting the application image")
+	}
+
+	gitRef.Commit = imported.Commit
+	origin := models.ApplicationOrigin{
+		Kind: models.OriginGit,
+		Git:  &gitRef,
+	}
+
+	_, apierr = deploy.DeployApp(ctx, cluster, appRef, webhookUser, staged.Stage.ID, &origin, nil)
+	if apierr != nil {
+		return stepError("deploying", apierr)
+	}
+
+	log.Info("deployed", "stage", staged.Stage.ID, "commit", imported.Commit)
+	return nil
+}
+
+// webhookCluster returns the cluster, after checking that the application exists.
+func webhookCluster(ctx context.Context, appRef models.AppRef) (*kubernetes.Cluster, apierror.APIErrors) {
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return nil, apierror.InternalError(err)
+	}
+
+	exists, err := application.Exists(ctx, cluster, appRef)
+	if err != nil {
+		return nil, apierror.InternalError(err)
+	}
+	if !exists {
+		return nil, apierror.AppIsNotKnown(appRef.Name)
+	}
+
+	return cluster, nil
+}
+
+func webhookResponse(appRef models.AppRef, secret string, enabled bool) models.AppWebhook {
+	if !enabled {
+		return models.AppWebhook{}
+	}
+	return models.AppWebhook{
+		Enabled: true,
+		// See router.go, WebhookRoutes
+		Path:   fmt.Sprintf("/webhooks/git/%s/%s", appRef.Namespace, appRef.Name),
+		Secret: secret,
+	}
+}
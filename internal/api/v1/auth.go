@@ -28,7 +28,7 @@ func AuthorizationMiddleware(c *gin.Context) {
 	case "admin":
 		authorized = authorizeAdmin(logger)
 	case "user":
-		authorized = authorizeUser(logger, user, path, namespace)
+		authorized = authorizeUser(logger, user, path, c.FullPath(), namespace)
 	}
 
 	logger.Info(fmt.Sprintf("user [%s] with role [%s] authorized [%t] for namespace [%s]", user.Username, user.Role, authorized, namespace))
@@ -45,14 +45,18 @@ func authorizeAdmin(logger logr.Logger) bool {
 	return true
 }
 
-func authorizeUser(logger logr.Logger, user auth.User, path, namespace string) bool {
+func authorizeUser(logger logr.Logger, user auth.User, path, route, namespace string) bool {
 	logger = logger.V(1).WithName("authorizeUser")
 
-	// check if the requested path is restricted
+	// check if the requested path, or the route matching it, is restricted
 	if _, found := AdminRoutes[path]; found {
 		logger.Info(fmt.Sprintf("path [%s] is an admin route, user unauthorized", path))
 		return false
 	}
+	if _, found := AdminRoutes[route]; found {
+		logger.Info(fmt.Sprintf("route [%s] is an admin route, user unauthorized", route))
+		return false
+	}
 
 	// check if the user has permission on the requested namespace
 	if namespace != "" {
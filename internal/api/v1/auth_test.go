@@ -64,6 +64,25 @@ var _ = Describe("Authorization Middleware", func() {
 			})
 		})
 
+		When("the route of the url is restricted", func() {
+			It("returns status code 403", func() {
+				v1.AdminRoutes = map[string]struct{}{
+					"/restricted/:namespace": {},
+				}
+
+				router := gin.New()
+				router.GET("/restricted/:namespace", func(c *gin.Context) {
+					c.Request = c.Request.WithContext(ctx)
+					v1.AuthorizationMiddleware(c)
+				})
+
+				req, err := http.NewRequest(http.MethodGet, "http://url.com/restricted/workspace", nil)
+				Expect(err).ToNot(HaveOccurred())
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusForbidden))
+			})
+		})
+
 		When("url is namespaced", func() {
 			It("returns status code 403 for another namespace", func() {
 				c.Params = []gin.Param{{Key: "namespace", Value: "another-workspace"}}
@@ -0,0 +1,54 @@
+package backup
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/backup"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/registry"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Backup handles the API endpoint GET /namespaces/:namespace/backup
+// It returns the archive of the namespace, a gzipped tar file. With the query parameter
+// images=true the archive contains the images of the applications staged by Epinio.
+func (hc Controller) Backup(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	logger := requestctx.Logger(ctx).WithName("Backup")
+	namespace := c.Param("namespace")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	var client *registry.Client
+	registryNamespace := ""
+	if c.Query("images") == "true" {
+		client, registryNamespace, err = registry.NewEpinioClient(ctx, cluster)
+		if err != nil {
+			return apierror.InternalError(err, "accessing the registry")
+		}
+	}
+
+	archive, err := backup.Capture(ctx, cluster, namespace, client, registryNamespace)
+	if err != nil {
+		return apierror.InternalError(err, "capturing the namespace")
+	}
+
+	c.Header("Content-Type", "application/gzip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.tar.gz"`, namespace))
+	c.Status(http.StatusOK)
+
+	// The response is under way, errors can only cut it short. The archive is then
+	// incomplete, which its readers notice at the missing end of the gzip stream.
+	err = backup.Write(ctx, c.Writer, archive, client, registryNamespace)
+	if err != nil {
+		logger.Error(err, "writing the archive", "namespace", namespace)
+		c.Abort()
+	}
+	return nil
+}
@@ -0,0 +1,7 @@
+// Package backup contains the API handlers to back up namespaces into portable archives,
+// and to restore them.
+package backup
+
+// Controller represents all functionality of the API related to namespace backups
+type Controller struct {
+}
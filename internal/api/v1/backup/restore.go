@@ -0,0 +1,223 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/deploy"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/api/v1/service"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/backup"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/epinio/epinio/internal/namespaces"
+	"github.com/epinio/epinio/internal/registry"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// serviceReadyTimeout limits the wait for the restored services to be ready for binding.
+var serviceReadyTimeout = 3 * time.Minute
+
+// Restore handles the API endpoint POST /admin/namespaces/restore
+// It recreates the namespace of the uploaded archive, or the namespace named by the query
+// parameter `as`, which must not exist yet. Configurations, services and applications are
+// created in this order, then the images of the archive are pushed, the services bound,
+// and the applications deployed. Parts which cannot be restored are reported as warnings.
+func (hc Controller) Restore(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	logger := requestctx.Logger(ctx).WithName("Restore")
+	username := requestctx.User(ctx).Username
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		return apierror.NewBadRequest("the archive is missing", err.Error())
+	}
+	defer file.Close()
+
+	reader, err := backup.NewReader(file)
+	if err != nil {
+		return apierror.NewBadRequest(err.Error())
+	}
+	defer reader.Close()
+	archive := reader.Archive
+
+	namespace := c.Query("as")
+	if namespace == "" {
+		namespace = archive.Namespace
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	exists, err := namespaces.Exists(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if exists {
+		return apierror.NamespaceAlreadyKnown(namespace)
+	}
+
+	logger.Info("restoring namespace", "archive", archive.Namespace, "namespace", namespace)
+
+	if err := namespaces.Create(ctx, cluster, namespace); err != nil {
+		return apierror.InternalError(err)
+	}
+
+	resp := models.NamespaceRestoreResponse{Namespace: namespace}
+	err = backup.Restore(ctx, cluster, archive, namespace, username, &resp)
+	if err != nil {
+		return apierror.InternalError(err, "restoring the namespace")
+	}
+
+	// The registry is needed for the images of the archive, and to check the images
+	// of the applications archived without them.
+	var client *registry.Client
+	registryNamespace := ""
+	registryURL := ""
+	if needsRegistry(archive) {
+		client, registryNamespace, err = registry.NewEpinioClient(ctx, cluster)
+		if err != nil {
+			return apierror.InternalError(err, "accessing the registry")
+		}
+		registryURL, err = publicRegistryURL(ctx, cluster)
+		if err != nil {
+			return apierror.InternalError(err, "accessing the registry")
+		}
+	}
+
+	err = reader.PushImages(ctx, client, func(app string) string {
+		return registry.Repository(registryNamespace, namespace, app)
+	})
+	if err != nil {
+		return apierror.InternalError(err, "pushing the images of the archive")
+	}
+
+	resp.Warnings = append(resp.Warnings, bindServices(ctx, cluster, logger, archive, resp.Services, namespace)...)
+
+	for _, app := range archive.Apps {
+		stageID, imageURL, warning := restoredImage(ctx, client, registryNamespace, registryURL, archive.Namespace, namespace, app)
+		if warning != "" {
+			resp.Warnings = append(resp.Warnings, warning)
+			continue
+		}
+		if imageURL == "" {
+			// Never deployed, nothing to do.
+			continue
+		}
+
+		appRef := models.NewAppRef(app.Name, namespace)
+		if err := backup.SetImage(ctx, cluster, appRef, stageID, imageURL); err != nil {
+			return apierror.InternalError(err, "recording the image of application "+app.Name)
+		}
+		if _, apierr := deploy.DeployApp(ctx, cluster, appRef, username, "", nil, nil); apierr != nil {
+			resp.Warnings = append(resp.Warnings, fmt.Sprintf("application %s not deployed: %s", app.Name, describe(apierr)))
+		}
+	}
+	response.OKReturn(c, resp)
+	return nil
+}
+
+// needsRegistry returns true if the applications of the archive have images in the
+// Epinio registry.
+func needsRegistry(archive backup.Archive) bool {
+	for _, app := range archive.Apps {
+		if app.Image != nil || (app.StageID != "" && app.Origin.Kind != models.OriginContainer) {
+			return true
+		}
+	}
+	return false
+}
+
+// restoredImage returns the stage id and the image to deploy the restored application
+// with. Images of the archive were pushed into the repository of the application in the
+// new namespace. Images staged by Epinio but not archived are used if they are still in
+// the registry, else a warning is returned. Containers are deployed from their image.
+func restoredImage(ctx context.Context, client *registry.Client, registryNamespace, registryURL, oldNamespace, namespace string, app backup.App) (string, string, string) {
+	switch {
+	case app.Image != nil:
+		return app.StageID, fmt.Sprintf("%s/%s-%s:%s", registryURL, namespace, app.Name, app.StageID), ""
+	case app.Origin.Kind == models.OriginContainer || app.StageID == "":
+		return app.StageID, app.ImageURL, ""
+	}
+
+	_, err := client.Manifest(ctx, registry.Repository(registryNamespace, oldNamespace, app.Name), app.StageID)
+	if err != nil {
+		return "", "", fmt.Sprintf("application %s not deployed, its image is neither in the archive nor in the registry", app.Name)
+	}
+	return app.StageID, app.ImageURL, ""
+}
+
+// bindServices waits for the restored services to be ready, and binds them to their
+// applications. Services which do not become ready in time are left unbound.
+func bindServices(ctx context.Context, cluster *kubernetes.Cluster, logger logr.Logger, archive backup.Archive, restored []string, namespace string) []string {
+	warnings := []string{}
+
+	restoredSet := map[string]struct{}{}
+	for _, name := range restored {
+		restoredSet[name] = struct{}{}
+	}
+
+	for _, archived := range archive.Services {
+		if _, ok := restoredSet[archived.Name]; !ok || len(archived.BoundApps) == 0 {
+			continue
+		}
+
+		err := wait.PollImmediate(2*time.Second, serviceReadyTimeout, func() (bool, error) {
+			return service.ValidateService(ctx, cluster, logger, namespace, archived.Name) == nil, nil
+		})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("service %s not ready, not bound to %v", archived.Name, archived.BoundApps))
+			continue
+		}
+
+		for _, appName := range archived.BoundApps {
+			app, err := application.Lookup(ctx, cluster, namespace, appName)
+			if err != nil || app == nil {
+				warnings = append(warnings, fmt.Sprintf("service %s not bound to missing application %s", archived.Name, appName))
+				continue
+			}
+			if apierr := service.BindToApp(ctx, cluster, logger, namespace, archived.Name, *app); apierr != nil {
+				warnings = append(warnings, fmt.Sprintf("service %s not bound to %s: %s", archived.Name, appName, describe(apierr)))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// publicRegistryURL returns the URL of the registry namespace holding the images of the
+// applications, as used by staging.
+func publicRegistryURL(ctx context.Context, cluster *kubernetes.Cluster) (string, error) {
+	details, err := registry.GetConnectionDetails(ctx, cluster, helmchart.Namespace(), registry.CredentialsSecretName)
+	if err != nil {
+		return "", err
+	}
+	publicURL, err := details.PublicRegistryURL()
+	if err != nil {
+		return "", err
+	}
+	if publicURL == "" {
+		return "", errors.New("no public registry URL found")
+	}
+	return fmt.Sprintf("%s/%s", publicURL, details.Namespace), nil
+}
+
+// describe returns the messages of the errors, for warnings.
+func describe(apierr apierror.APIErrors) string {
+	messages := []string{}
+	for _, err := range apierr.Errors() {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, ", ")
+}
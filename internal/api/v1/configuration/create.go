@@ -2,6 +2,7 @@ package configuration
 
 import (
 	"github.com/epinio/epinio/helpers/kubernetes"
+	namespaceapi "github.com/epinio/epinio/internal/api/v1/namespace"
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
 	"github.com/epinio/epinio/internal/configurations"
@@ -48,6 +49,11 @@ func (sc Controller) Create(c *gin.Context) apierror.APIErrors {
 	}
 	// any error here is `configuration not found`, and we can continue
 
+	apierr := namespaceapi.EnforceQuota(ctx, cluster, namespace, models.NamespaceUsage{Configurations: 1})
+	if apierr != nil {
+		return apierr
+	}
+
 	// Create the new configuration. At last.
 	_, err = configurations.CreateConfiguration(ctx, cluster, createRequest.Name, namespace, username, createRequest.Data)
 	if err != nil {
@@ -2,6 +2,8 @@ package configurationbinding
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/internal/api/v1/deploy"
@@ -28,6 +30,13 @@ func DeleteBinding(ctx context.Context, cluster *kubernetes.Cluster, namespace,
 		return apierror.InternalError(err)
 	}
 
+	referencing := application.ReferencingEnvironment(app.Configuration.EnvironmentFrom, configurationName)
+	if len(referencing) > 0 {
+		return apierror.NewBadRequest(
+			fmt.Sprintf("configuration '%s' is referenced by environment variables: %s", configurationName, strings.Join(referencing, ", ")),
+			"unset these variables before unbinding the configuration")
+	}
+
 	err = application.BoundConfigurationsUnset(ctx, cluster, app.Meta, configurationName)
 	if err != nil {
 		return apierror.InternalError(err)
@@ -41,17 +41,30 @@ func DeployApp(ctx context.Context, cluster *kubernetes.Cluster, app models.AppR
 	routes := appObj.Configuration.Routes
 	chartName := appObj.Configuration.AppChart
 
+	// The commit of the sources, recorded with the release. A new origin replaces the
+	// commit of the current one.
+	commit := ""
+	if origin != nil {
+		if origin.Kind == models.OriginGit && origin.Git != nil {
+			commit = origin.Git.Commit
+		}
+	} else if appObj.Origin.Kind == models.OriginGit && appObj.Origin.Git != nil {
+		commit = appObj.Origin.Git.Commit
+	}
+
 	deployParams := helm.ChartParameters{
 		Context:        ctx,
 		Cluster:        cluster,
 		AppRef:         app,
 		Chart:          chartName,
 		Environment:    appObj.Configuration.Environment,
+		EnvFrom:        appObj.Configuration.EnvironmentFrom,
 		Configurations: appObj.Configuration.Configurations,
 		Instances:      *appObj.Configuration.Instances,
 		ImageURL:       imageURL,
 		Username:       username,
 		StageID:        stageID,
+		Commit:         commit,
 		Routes:         routes,
 		Start:          start,
 	}
@@ -52,6 +52,7 @@ type AppCreateResponse struct {
 
 // swagger:route GET /namespaces/{Namespace}/applications/{App} application AppShow
 // Return details of the named `App` in the `Namespace`.
+// The values of environment variables marked as secret are masked, unless `Reveal` is `true`.
 // responses:
 //   200: AppShowResponse
 
@@ -61,6 +62,8 @@ type AppShowParam struct {
 	Namespace string
 	// in: path
 	App string
+	// in: query
+	Reveal bool
 }
 
 // swagger:response AppShowResponse
@@ -125,6 +128,26 @@ type AppLogsParam struct {
 // swagger:response AppLogsResponse
 type AppLogsResponse struct{}
 
+// swagger:route GET /namespaces/{Namespace}/events application AppEvents
+// Return the events of the applications in the `Namespace` streamed over a websocket.
+// The optional `App` restricts the events to the named application.
+// responses:
+//   200: AppEventsResponse
+
+// swagger:parameters AppEvents
+type AppEventsParam struct {
+	// in: path
+	Namespace string
+	// in: query
+	App string
+}
+
+// swagger:response AppEventsResponse
+type AppEventsResponse struct {
+	// in: body
+	Event models.AppEvent
+}
+
 // swagger:route GET /namespaces/{Namespace}/applications/{App}/exec application AppExec
 // Get a shell to the `App` in the `Namespace`.
 // responses:
@@ -196,6 +219,201 @@ type StagingCompleteResponse struct {
 	Body models.Response
 }
 
+// swagger:route GET /namespaces/{Namespace}/staging application StagingStatus
+// Return the running and queued staging processes of the `Namespace`.
+// responses:
+//   200: StagingStatusResponse
+
+// swagger:parameters StagingStatus
+type StagingStatusParam struct {
+	// in: path
+	Namespace string
+}
+
+// swagger:response StagingStatusResponse
+type StagingStatusResponse struct {
+	// in: body
+	Body models.StagingStatusResponse
+}
+
+// swagger:route DELETE /namespaces/{Namespace}/staging/{StageID} application StagingCancel
+// Cancel the staging process identified by `StageID` in the `Namespace`.
+// responses:
+//   200: StagingCancelResponse
+
+// swagger:parameters StagingCancel
+type StagingCancelParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	StageID string
+}
+
+// swagger:response StagingCancelResponse
+type StagingCancelResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route GET /namespaces/{Namespace}/applications/{App}/buildcache application AppBuildCache
+// Return the details of the build cache of the `App` in the `Namespace`.
+// responses:
+//   200: AppBuildCacheResponse
+
+// swagger:parameters AppBuildCache
+type AppBuildCacheParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+}
+
+// swagger:response AppBuildCacheResponse
+type AppBuildCacheResponse struct {
+	// in: body
+	Body models.BuildCache
+}
+
+// swagger:route GET /namespaces/{Namespace}/applications/{App}/sbom application AppSBOM
+// Return the SBOM and provenance of the running image of the `App` in the `Namespace`, or
+// of the image of the staging run named by `Stage`.
+// responses:
+//   200: AppSBOMResponse
+
+// swagger:parameters AppSBOM
+type AppSBOMParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: query
+	Stage string `json:"stage"`
+}
+
+// swagger:response AppSBOMResponse
+type AppSBOMResponse struct {
+	// in: body
+	Body models.AppSBOM
+}
+
+// swagger:route DELETE /namespaces/{Namespace}/applications/{App}/buildcache application AppBuildCachePurge
+// Remove the build cache of the `App` in the `Namespace`.
+// responses:
+//   200: AppBuildCachePurgeResponse
+
+// swagger:parameters AppBuildCachePurge
+type AppBuildCachePurgeParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+}
+
+// swagger:response AppBuildCachePurgeResponse
+type AppBuildCachePurgeResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route GET /namespaces/{Namespace}/applications/{App}/webhook application AppWebhook
+// Return the location and secret of the git webhook of the `App` in the `Namespace`.
+// responses:
+//   200: AppWebhookResponse
+
+// swagger:parameters AppWebhook
+type AppWebhookParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+}
+
+// swagger:response AppWebhookResponse
+type AppWebhookResponse struct {
+	// in: body
+	Body models.AppWebhook
+}
+
+// swagger:route POST /namespaces/{Namespace}/applications/{App}/webhook application AppWebhookEnable
+// Enable the git webhook of the `App` in the `Namespace`.
+// responses:
+//   200: AppWebhookEnableResponse
+
+// swagger:parameters AppWebhookEnable
+type AppWebhookEnableParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+}
+
+// swagger:response AppWebhookEnableResponse
+type AppWebhookEnableResponse struct {
+	// in: body
+	Body models.AppWebhook
+}
+
+// swagger:route POST /namespaces/{Namespace}/applications/{App}/webhook/rotate application AppWebhookRotate
+// Replace the secret of the git webhook of the `App` in the `Namespace`.
+// responses:
+//   200: AppWebhookRotateResponse
+
+// swagger:parameters AppWebhookRotate
+type AppWebhookRotateParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+}
+
+// swagger:response AppWebhookRotateResponse
+type AppWebhookRotateResponse struct {
+	// in: body
+	Body models.AppWebhook
+}
+
+// swagger:route POST /webhooks/git/{Namespace}/{App} application GitWebhook
+// Receive a push notification for the `App` in the `Namespace` from GitHub, GitLab, or Gitea.
+// The notification has to be signed with the secret of the application's webhook.
+// responses:
+//   200: GitWebhookResponse
+
+// swagger:parameters GitWebhook
+type GitWebhookParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+}
+
+// swagger:response GitWebhookResponse
+type GitWebhookResponse struct {
+	// in: body
+	Body models.GitWebhookResponse
+}
+
+// swagger:route GET /blobs/{Blob} application BlobDownload
+// Return the contents of the `Blob`, to the staging jobs of the filesystem blob store.
+// The request has to carry the download token of the blob, and its expiry.
+// responses:
+//   200: BlobDownloadResponse
+
+// swagger:parameters BlobDownload
+type BlobDownloadParam struct {
+	// in: path
+	Blob string
+	// in: query
+	Expires int64 `json:"expires"`
+	// in: query
+	Token string `json:"token"`
+}
+
+// swagger:response BlobDownloadResponse
+type BlobDownloadResponse struct {
+	// in: body
+	Body []byte
+}
+
 // swagger:route DELETE /namespaces/{Namespace}/applications/{App} application AppDelete
 // Delete the named `App` in the `Namespace`.
 // responses:
@@ -215,6 +433,27 @@ type AppDeleteResponse struct {
 	Body models.ApplicationDeleteResponse
 }
 
+// swagger:route PUT /namespaces/{Namespace}/applications/{App}/protection application AppProtection
+// Set or clear the protection of the named `App` in the `Namespace` against deletion.
+// responses:
+//   200: AppProtectionResponse
+
+// swagger:parameters AppProtection
+type AppProtectionParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: body
+	Body models.ProtectionRequest
+}
+
+// swagger:response AppProtectionResponse
+type AppProtectionResponse struct {
+	// in: body
+	Body models.Response
+}
+
 // swagger:route POST /namespaces/{Namespace}/applications/{App}/store application AppUpload
 // Store the named `App` in the `Namespace`.
 // responses:
@@ -226,6 +465,9 @@ type AppUploadParam struct {
 	Namespace string
 	// in: path
 	App string
+	// in: query
+	// Hash of the sources, recorded with the blob.
+	Hash string
 }
 
 // swagger:response AppUploadResponse
@@ -234,6 +476,131 @@ type AppUploadResponse struct {
 	Body models.UploadResponse
 }
 
+// swagger:route POST /namespaces/{Namespace}/applications/{App}/store/match application AppUploadMatch
+// Return the stored sources of the named `App` in the `Namespace` with the requested
+// hash, and the stage built from them with the requested settings, if any.
+// responses:
+//   200: AppUploadMatchResponse
+
+// swagger:parameters AppUploadMatch
+type AppUploadMatchParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: body
+	Match models.UploadMatchRequest
+}
+
+// swagger:response AppUploadMatchResponse
+type AppUploadMatchResponse struct {
+	// in: body
+	Body models.UploadMatchResponse
+}
+
+// swagger:route POST /namespaces/{Namespace}/applications/{App}/store/sessions application AppUploadSessionCreate
+// Start a chunked upload of the sources of the named `App` in the `Namespace`.
+// responses:
+//   200: AppUploadSessionResponse
+
+// swagger:parameters AppUploadSessionCreate
+type AppUploadSessionCreateParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: body
+	Session models.UploadSessionRequest
+}
+
+// swagger:response AppUploadSessionResponse
+type AppUploadSessionResponse struct {
+	// in: body
+	Body models.UploadSession
+}
+
+// swagger:route GET /namespaces/{Namespace}/applications/{App}/store/sessions/{Blob} application AppUploadSessionShow
+// Return the chunked upload of the `Blob`, with the parts stored so far.
+// responses:
+//   200: AppUploadSessionResponse
+
+// swagger:parameters AppUploadSessionShow
+type AppUploadSessionShowParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: path
+	Blob string
+	// in: query
+	Upload string
+}
+
+// swagger:route PUT /namespaces/{Namespace}/applications/{App}/store/sessions/{Blob}/parts/{Part} application AppUploadSessionPart
+// Store the body as the numbered `Part` of the chunked upload of the `Blob`.
+// responses:
+//   200: AppUploadSessionPartResponse
+
+// swagger:parameters AppUploadSessionPart
+type AppUploadSessionPartParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: path
+	Blob string
+	// in: path
+	Part int
+	// in: query
+	Upload string
+}
+
+// swagger:response AppUploadSessionPartResponse
+type AppUploadSessionPartResponse struct {
+	// in: body
+	Body models.UploadPart
+}
+
+// swagger:route POST /namespaces/{Namespace}/applications/{App}/store/sessions/{Blob}/complete application AppUploadSessionComplete
+// Assemble the stored parts of the chunked upload into the `Blob`.
+// responses:
+//   200: AppUploadResponse
+
+// swagger:parameters AppUploadSessionComplete
+type AppUploadSessionCompleteParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: path
+	Blob string
+	// in: query
+	Upload string
+}
+
+// swagger:route DELETE /namespaces/{Namespace}/applications/{App}/store/sessions/{Blob} application AppUploadSessionDelete
+// Abort the chunked upload of the `Blob`, discarding the stored parts.
+// responses:
+//   200: AppUploadSessionDeleteResponse
+
+// swagger:parameters AppUploadSessionDelete
+type AppUploadSessionDeleteParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: path
+	Blob string
+	// in: query
+	Upload string
+}
+
+// swagger:response AppUploadSessionDeleteResponse
+type AppUploadSessionDeleteResponse struct {
+	// in: body
+	Body models.Response
+}
+
 // swagger:route POST /namespaces/{Namespace}/applications/{App}/restart application AppRestart
 // Restart the named `App` in the `Namespace`.
 // responses:
@@ -254,7 +621,8 @@ type AppRestartResponse struct {
 }
 
 // swagger:route POST /namespaces/{Namespace}/applications/{App}/import-git application AppImportGit
-// Store the named `App` from a Git repo in the `Namespace`.
+// Store the named `App` from a Git repo in the `Namespace`. The import runs in the
+// background, follow the returned operation for the id of the stored blob.
 // responses:
 //   200: AppImportGitResponse
 
@@ -265,13 +633,20 @@ type AppImportGitParam struct {
 	// in: path
 	App    string
 	GitUrl string
+	// Branch, tag, or commit SHA. Defaults to the default branch.
 	GitRev string
+	// Name of the secret in the `Namespace` providing access to a private repository.
+	GitCredentials string
+	// Directory of the application sources in the repository.
+	GitSubdir string
+	// Set to `true` to check out the submodules of the repository.
+	GitSubmodules string
 }
 
 // swagger:response AppImportGitResponse
 type AppImportGitResponse struct {
 	// in: body
-	Body models.ImportGitResponse
+	Body models.Operation
 }
 
 // swagger:route POST /namespaces/{Namespace}/applications/{App}/stage application AppStage
@@ -355,3 +730,26 @@ type AppRunningResponse struct {
 	// in: body
 	Body models.Response
 }
+
+// swagger:route GET /namespaces/{Namespace}/applications/{App}/metrics application AppMetrics
+// Return the history of the resource usage of the named `App` in the `Namespace`.
+// responses:
+//   200: AppMetricsResponse
+
+// swagger:parameters AppMetrics
+type AppMetricsParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: query
+	Since string
+	// in: query
+	Step string
+}
+
+// swagger:response AppMetricsResponse
+type AppMetricsResponse struct {
+	// in: body
+	Body models.AppMetricsResponse
+}
@@ -8,6 +8,7 @@ import "github.com/epinio/epinio/pkg/api/core/v1/models"
 
 // swagger:route GET /namespaces/{Namespace}/applications/{App}/environment app-env EnvList
 // Return the environment variable assignments for the `App` in the namespace`.
+// The values of variables marked as secret are masked, unless `Reveal` is `true`.
 // responses:
 //   200: EnvListResponse
 
@@ -17,6 +18,8 @@ type EnvListParams struct {
 	Namespace string
 	// in: path
 	App string
+	// in: query
+	Reveal bool
 }
 
 // swagger:response EnvListResponse
@@ -63,6 +66,7 @@ type EnvMatch0Params struct {
 
 // swagger:route POST /namespaces/{Namespace}/applications/{App}/environment app-env EnvSet
 // Create/modify the posted environment variable assignments for the `App` in the `Namespace`.
+// With `Secret` set to `true` the variables are marked as secret.
 // responses:
 //   200: EnvSetResponse
 
@@ -72,6 +76,8 @@ type EnvSetParams struct {
 	Namespace string
 	// in: path
 	App string
+	// in: query
+	Secret bool
 	// in: body
 	Body models.EnvVariableMap
 }
@@ -82,8 +88,31 @@ type EnvSetResponse struct {
 	Body models.Response
 }
 
+// swagger:route POST /namespaces/{Namespace}/applications/{App}/environmentfrom app-env EnvSetFrom
+// Create/modify the posted environment variables of the `App` in the `Namespace`, sourcing
+// their values from keys of bound configurations.
+// responses:
+//   200: EnvSetFromResponse
+
+// swagger:parameters EnvSetFrom
+type EnvSetFromParams struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: body
+	Body models.EnvReferenceMap
+}
+
+// swagger:response EnvSetFromResponse
+type EnvSetFromResponse struct {
+	// in: body
+	Body models.Response
+}
+
 // swagger:route GET /namespaces/{Namespace}/applications/{App}/environment/{Env} app-env EnvShow
 // Return the named `Env` variable assignment for the `App` in the `Namespace`.
+// The value of a variable marked as secret is masked, unless `Reveal` is `true`.
 // responses:
 //   200: EnvShowResponse
 
@@ -95,6 +124,8 @@ type EnvShowParams struct {
 	App string
 	// in: path
 	Env string
+	// in: query
+	Reveal bool
 }
 
 // swagger:response EnvShowResponse
@@ -104,7 +135,8 @@ type EnvShowResponse struct {
 }
 
 // swagger:route DELETE /namespaces/{Namespace}/applications/{App}/environment/{Env} app-env EnvUnset
-// Remove the named `Env` variable from the `App` in the `Namespace`.
+// Remove the named `Env` variable from the `App` in the `Namespace`. This removes literal
+// values and configuration references alike.
 // responses:
 //   200: EnvUnsetResponse
 
@@ -123,3 +155,25 @@ type EnvUnsetResponse struct {
 	// in: body
 	Body models.Response
 }
+
+// swagger:route DELETE /namespaces/{Namespace}/applications/{App}/stagingenvironment/{Env} app-env EnvStagingUnset
+// Remove the named `Env` variable from the staging environment of the `App` in the
+// `Namespace`. The change takes effect with the next staging of the `App`.
+// responses:
+//   200: EnvStagingUnsetResponse
+
+// swagger:parameters EnvStagingUnset
+type EnvStagingUnsetParams struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: path
+	Env string
+}
+
+// swagger:response EnvStagingUnsetResponse
+type EnvStagingUnsetResponse struct {
+	// in: body
+	Body models.Response
+}
@@ -0,0 +1,29 @@
+package docs
+
+//go:generate swagger generate spec
+
+import "github.com/epinio/epinio/pkg/api/core/v1/models"
+
+// swagger:route GET /admin/gc/blobs gc BlobGC
+// Return the orphaned application source blobs the next garbage collection deletes, and
+// the expired uploads it aborts, without changing anything. Restricted to admins.
+// responses:
+//   200: BlobGCResponse
+
+// swagger:response BlobGCResponse
+type BlobGCResponse struct {
+	// in: body
+	Body models.BlobGCReport
+}
+
+// swagger:route GET /admin/gc/images gc ImageGC
+// Return the application images the next garbage collection deletes from the registry,
+// without deleting them. Restricted to admins.
+// responses:
+//   200: ImageGCResponse
+
+// swagger:response ImageGCResponse
+type ImageGCResponse struct {
+	// in: body
+	Body models.ImageGCReport
+}
@@ -33,7 +33,8 @@ type NamespaceCreateResponse struct {
 }
 
 // swagger:route DELETE /namespaces/{Namespace} namespace NamespaceDelete
-// Delete the named `Namespace`.
+// Delete the named `Namespace`. Fails if the namespace, or any of its applications and
+// services, is protected. A dry run returns a NamespaceDeletePreview instead.
 // responses:
 //   200: NamespaceDeleteResponse
 
@@ -41,6 +42,9 @@ type NamespaceCreateResponse struct {
 type NamespaceDeleteParam struct {
 	// in: path
 	Namespace string
+	// in: query
+	// List what would be deleted, without deleting anything.
+	DryRun bool
 }
 
 // swagger:response NamespaceDeleteResponse
@@ -49,6 +53,25 @@ type NamespaceDeleteResponse struct {
 	Body models.Response
 }
 
+// swagger:route PUT /namespaces/{Namespace}/protection namespace NamespaceProtection
+// Set or clear the protection of the named `Namespace` against deletion.
+// responses:
+//   200: NamespaceProtectionResponse
+
+// swagger:parameters NamespaceProtection
+type NamespaceProtectionParam struct {
+	// in: path
+	Namespace string
+	// in: body
+	Body models.ProtectionRequest
+}
+
+// swagger:response NamespaceProtectionResponse
+type NamespaceProtectionResponse struct {
+	// in: body
+	Body models.Response
+}
+
 // swagger:route GET /namespaces/{Namespace} namespace NamespaceShow
 // Return details of the named `Namespace`.
 // responses:
@@ -92,3 +115,134 @@ type NamespaceMatchResponse struct {
 type NamespaceMatch0Param struct{}
 
 // response: See NamespaceMatch.
+
+// swagger:route GET /namespaces/{Namespace}/registrycredentials namespace RegistryCredentials
+// Return the registry credentials of the `Namespace`, without their passwords.
+// responses:
+//   200: RegistryCredentialsResponse
+
+// swagger:parameters RegistryCredentials
+type RegistryCredentialsParam struct {
+	// in: path
+	Namespace string
+}
+
+// swagger:response RegistryCredentialsResponse
+type RegistryCredentialsResponse struct {
+	// in: body
+	Body models.RegistryCredentialList
+}
+
+// swagger:route POST /namespaces/{Namespace}/registrycredentials namespace RegistryCredentialCreate
+// Add a registry credential to the `Namespace`. The images of its applications are pulled with it.
+// responses:
+//   200: RegistryCredentialCreateResponse
+
+// swagger:parameters RegistryCredentialCreate
+type RegistryCredentialCreateParam struct {
+	// in: path
+	Namespace string
+	// in: body
+	Body models.RegistryCredentialCreateRequest
+}
+
+// swagger:response RegistryCredentialCreateResponse
+type RegistryCredentialCreateResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route DELETE /namespaces/{Namespace}/registrycredentials/{Name} namespace RegistryCredentialDelete
+// Remove the named registry credential from the `Namespace`.
+// responses:
+//   200: RegistryCredentialDeleteResponse
+
+// swagger:parameters RegistryCredentialDelete
+type RegistryCredentialDeleteParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	Name string
+}
+
+// swagger:response RegistryCredentialDeleteResponse
+type RegistryCredentialDeleteResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route GET /namespaces/{Namespace}/quota namespace NamespaceQuota
+// Return the quota of the `Namespace`, and the use of the limited resources.
+// responses:
+//   200: NamespaceQuotaResponse
+
+// swagger:parameters NamespaceQuota
+type NamespaceQuotaParam struct {
+	// in: path
+	Namespace string
+}
+
+// swagger:response NamespaceQuotaResponse
+type NamespaceQuotaResponse struct {
+	// in: body
+	Body models.NamespaceQuotaResponse
+}
+
+// swagger:route PUT /admin/namespaces/{Namespace}/quota namespace NamespaceQuotaSet
+// Replace the quota of the `Namespace`. An empty quota removes it. Restricted to admins.
+// responses:
+//   200: NamespaceQuotaSetResponse
+
+// swagger:parameters NamespaceQuotaSet
+type NamespaceQuotaSetParam struct {
+	// in: path
+	Namespace string
+	// in: body
+	Body models.NamespaceQuota
+}
+
+// swagger:response NamespaceQuotaSetResponse
+type NamespaceQuotaSetResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route GET /namespaces/{Namespace}/backup namespace NamespaceBackup
+// Return the archive of the `Namespace`, a gzipped tar file of its configurations,
+// services and applications, and optionally of the images of the applications.
+// responses:
+//   200: NamespaceBackupResponse
+
+// swagger:parameters NamespaceBackup
+type NamespaceBackupParam struct {
+	// in: path
+	Namespace string
+	// in: query
+	// Include the images of the applications staged by Epinio.
+	Images bool
+}
+
+// swagger:response NamespaceBackupResponse
+type NamespaceBackupResponse struct {
+	// in: body
+	Body []byte
+}
+
+// swagger:route POST /admin/namespaces/restore namespace NamespaceRestore
+// Recreate the namespace of the uploaded archive, and deploy its applications. Restricted
+// to admins.
+// responses:
+//   200: NamespaceRestoreResponse
+
+// swagger:parameters NamespaceRestore
+type NamespaceRestoreParam struct {
+	// in: query
+	// Name of the namespace to create, instead of the namespace of the archive.
+	As string
+}
+
+// swagger:response NamespaceRestoreResponse
+type NamespaceRestoreResponse struct {
+	// in: body
+	Body models.NamespaceRestoreResponse
+}
@@ -0,0 +1,23 @@
+package docs
+
+//go:generate swagger generate spec
+
+import "github.com/epinio/epinio/pkg/api/core/v1/models"
+
+// swagger:route GET /operations/{ID} operations OperationShow
+// Return the state of the long-running operation with the `ID`, and its result once it
+// succeeded.
+// responses:
+//   200: OperationShowResponse
+
+// swagger:parameters OperationShow
+type OperationShowParam struct {
+	// in: path
+	ID string
+}
+
+// swagger:response OperationShowResponse
+type OperationShowResponse struct {
+	// in: body
+	Body models.Operation
+}
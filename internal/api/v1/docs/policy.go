@@ -0,0 +1,58 @@
+package docs
+
+import "github.com/epinio/epinio/pkg/api/core/v1/models"
+
+// swagger:route GET /namespaces/{Namespace}/policies policy Policies
+// Return the global policies and the policies of the `Namespace`.
+// responses:
+//   200: PoliciesResponse
+
+// swagger:parameters Policies
+type PoliciesParam struct {
+	// in: path
+	Namespace string
+}
+
+// swagger:response PoliciesResponse
+type PoliciesResponse struct {
+	// in: body
+	Body models.PolicyList
+}
+
+// swagger:route GET /namespaces/{Namespace}/policies/{Policy} policy PolicyShow
+// Return the named policy applying to the `Namespace`.
+// responses:
+//   200: PolicyShowResponse
+
+// swagger:parameters PolicyShow
+type PolicyShowParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	Policy string
+}
+
+// swagger:response PolicyShowResponse
+type PolicyShowResponse struct {
+	// in: body
+	Body models.Policy
+}
+
+// swagger:route POST /namespaces/{Namespace}/policies/evaluate policy PolicyEvaluate
+// Evaluate the policies applying to the `Namespace` against the application settings, without changing anything.
+// responses:
+//   200: PolicyEvaluateResponse
+
+// swagger:parameters PolicyEvaluate
+type PolicyEvaluateParam struct {
+	// in: path
+	Namespace string
+	// in: body
+	Body models.PolicySubject
+}
+
+// swagger:response PolicyEvaluateResponse
+type PolicyEvaluateResponse struct {
+	// in: body
+	Body models.PolicyEvaluationResponse
+}
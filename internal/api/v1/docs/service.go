@@ -152,6 +152,27 @@ type ServiceDeleteResponse struct {
 	Body models.ServiceDeleteResponse
 }
 
+// swagger:route PUT /namespaces/{Namespace}/services/{Service}/protection service ServiceProtection
+// Set or clear the protection of the named `Service` in the `Namespace` against deletion.
+// responses:
+//   200: ServiceProtectionResponse
+
+// swagger:parameters ServiceProtection
+type ServiceProtectionParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	Service string
+	// in: body
+	Body models.ProtectionRequest
+}
+
+// swagger:response ServiceProtectionResponse
+type ServiceProtectionResponse struct {
+	// in: body
+	Body models.Response
+}
+
 // swagger:route GET /namespaces/{Namespace}/serviceapps service ServiceApps
 // Return map from services in the `Namespace`, to the apps in the same.
 // responses:
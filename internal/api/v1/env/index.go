@@ -13,7 +13,8 @@ import (
 
 // Index handles the API endpoint /namespaces/:namespace/applications/:app/environment
 // It receives the namespace, application name and returns the environment
-// associated with that application
+// associated with that application. The values of variables marked as secret
+// are masked, unless the query parameter `reveal` is set to `true`.
 func (hc Controller) Index(c *gin.Context) apierror.APIErrors {
 	ctx := c.Request.Context()
 	log := requestctx.Logger(ctx)
@@ -44,6 +45,14 @@ func (hc Controller) Index(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err)
 	}
 
+	if c.Query("reveal") != "true" {
+		secrets, err := application.EnvironmentSecretNames(ctx, cluster, app)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+		environment = application.MaskSecrets(environment, secrets)
+	}
+
 	response.OKReturn(c, environment)
 	return nil
 }
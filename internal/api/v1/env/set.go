@@ -6,6 +6,7 @@ import (
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/application"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/policy"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/gin-gonic/gin"
@@ -13,7 +14,8 @@ import (
 
 // Set handles the API endpoint /namespaces/:namespace/applications/:app/environment (POST)
 // It receives the namespace, application name, var name and value,
-// and add/modifies the variable in the  application's environment.
+// and add/modifies the variable in the  application's environment. With the query
+// parameter `secret` set to `true` the variables are marked as secret.
 func (hc Controller) Set(c *gin.Context) apierror.APIErrors {
 	ctx := c.Request.Context()
 	log := requestctx.Logger(ctx)
@@ -44,11 +46,42 @@ func (hc Controller) Set(c *gin.Context) apierror.APIErrors {
 		return apierror.BadRequest(err)
 	}
 
+	names := []string{}
+	for name := range setRequest {
+		names = append(names, name)
+	}
+
+	apierr := policy.Enforce(ctx, cluster, namespaceName, models.PolicySubject{
+		App:         appName,
+		Environment: policy.EnvironmentNames(app.Configuration, names...),
+	})
+	if apierr != nil {
+		return apierr
+	}
+
 	err = application.EnvironmentSet(ctx, cluster, app.Meta, setRequest, false)
 	if err != nil {
 		return apierror.InternalError(err)
 	}
 
+	if c.Query("secret") == "true" {
+		err = application.EnvironmentMarkSecret(ctx, cluster, app.Meta, names)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+	}
+
+	// A literal value replaces a configuration reference of the same name.
+	for name := range setRequest {
+		if _, ok := app.Configuration.EnvironmentFrom[name]; !ok {
+			continue
+		}
+		err = application.EnvironmentFromUnset(ctx, cluster, app.Meta, name)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+	}
+
 	if app.Workload != nil {
 		_, apierr := deploy.DeployApp(ctx, cluster, app.Meta, username, "", nil, nil)
 		if apierr != nil {
@@ -0,0 +1,88 @@
+package env
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/deploy"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/policy"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+)
+
+// SetFrom handles the API endpoint /namespaces/:namespace/applications/:app/environmentfrom (POST)
+// It receives the namespace, application name, var names and the configuration keys they
+// reference, and adds/modifies the variables in the application's environment. The
+// referenced configurations have to be bound to the application.
+func (hc Controller) SetFrom(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
+	username := requestctx.User(ctx).Username
+
+	namespaceName := c.Param("namespace")
+	appName := c.Param("app")
+
+	log.Info("processing environment variable reference assignment",
+		"namespace", namespaceName, "app", appName)
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	app, err := application.Lookup(ctx, cluster, namespaceName, appName)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if app == nil {
+		return apierror.AppIsNotKnown(appName)
+	}
+
+	var setRequest models.EnvReferenceMap
+	err = c.BindJSON(&setRequest)
+	if err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	apierr := application.ValidateEnvironmentFrom(ctx, cluster, namespaceName, setRequest,
+		app.Configuration.Configurations)
+	if apierr != nil {
+		return apierr
+	}
+
+	apierr = policy.Enforce(ctx, cluster, namespaceName, models.PolicySubject{
+		App:         appName,
+		Environment: policy.EnvironmentNames(app.Configuration, setRequest.Names()...),
+	})
+	if apierr != nil {
+		return apierr
+	}
+
+	err = application.EnvironmentFromSet(ctx, cluster, app.Meta, setRequest, false)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	// A configuration reference replaces a literal value of the same name.
+	for name := range setRequest {
+		if _, ok := app.Configuration.Environment[name]; !ok {
+			continue
+		}
+		err = application.EnvironmentUnset(ctx, cluster, app.Meta, name)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+	}
+
+	if app.Workload != nil {
+		_, apierr := deploy.DeployApp(ctx, cluster, app.Meta, username, "", nil, nil)
+		if apierr != nil {
+			return apierr
+		}
+	}
+
+	response.OK(c)
+	return nil
+}
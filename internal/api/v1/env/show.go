@@ -13,7 +13,8 @@ import (
 
 // EnvShow handles the API endpoint /namespaces/:namespace/applications/:app/environment/:env
 // It receives the namespace, application name, var name, and returns
-// the variable's value in the application's environment.
+// the variable's value in the application's environment. The value of a variable
+// marked as secret is masked, unless the query parameter `reveal` is set to `true`.
 func (hc Controller) Show(c *gin.Context) apierror.APIErrors {
 	ctx := c.Request.Context()
 	log := requestctx.Logger(ctx)
@@ -48,6 +49,14 @@ func (hc Controller) Show(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err)
 	}
 
+	if c.Query("reveal") != "true" {
+		secrets, err := application.EnvironmentSecretNames(ctx, cluster, app)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+		environment = application.MaskSecrets(environment, secrets)
+	}
+
 	match := models.EnvVariable{}
 
 	value, ok := environment[varName]
@@ -0,0 +1,47 @@
+package env
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// StagingUnset handles the API endpoint /namespaces/:namespace/applications/:app/stagingenvironment/:env (DELETE)
+// It receives the namespace, application name, var name, and removes the
+// variable from the application's staging environment. The workload is not
+// redeployed, the change takes effect with the next staging.
+func (hc Controller) StagingUnset(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
+
+	namespaceName := c.Param("namespace")
+	appName := c.Param("app")
+	varName := c.Param("env")
+
+	log.Info("processing staging environment variable removal",
+		"namespace", namespaceName, "app", appName, "var", varName)
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	app, err := application.Lookup(ctx, cluster, namespaceName, appName)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if app == nil {
+		return apierror.AppIsNotKnown(appName)
+	}
+
+	err = application.StagingEnvironmentUnset(ctx, cluster, app.Meta, varName)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
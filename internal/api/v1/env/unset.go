@@ -43,6 +43,11 @@ func (hc Controller) Unset(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err)
 	}
 
+	err = application.EnvironmentFromUnset(ctx, cluster, app.Meta, varName)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
 	if app.Workload != nil {
 		_, apierr := deploy.DeployApp(ctx, cluster, app.Meta, username, "", nil, nil)
 		if apierr != nil {
@@ -0,0 +1,37 @@
+package gc
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/blobgc"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// Blobs handles the API endpoint GET /admin/gc/blobs
+// It returns the orphaned source blobs the next collection would delete, and the expired
+// uploads it would abort, without changing anything.
+func (gc Controller) Blobs(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx).WithName("BlobGC")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	grace := viper.GetDuration("blob-gc-grace-period")
+	if grace <= 0 {
+		grace = blobgc.DefaultGracePeriod
+	}
+
+	report, err := blobgc.Collect(ctx, cluster, log, grace, true)
+	if err != nil {
+		return apierror.InternalError(err, "collecting orphaned blobs")
+	}
+
+	response.OKReturn(c, report)
+	return nil
+}
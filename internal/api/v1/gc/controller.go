@@ -0,0 +1,7 @@
+// Package gc contains the API handlers reporting on the garbage collection of stored
+// resources. They are restricted to admins.
+package gc
+
+// Controller represents all functionality of the API related to garbage collection
+type Controller struct {
+}
@@ -0,0 +1,32 @@
+package gc
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/imagegc"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// Images handles the API endpoint GET /admin/gc/images
+// It returns the application images the next collection would delete from the registry,
+// without deleting them.
+func (gc Controller) Images(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx).WithName("ImageGC")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	report, err := imagegc.Collect(ctx, cluster, log, viper.GetInt("image-retention"), true)
+	if err != nil {
+		return apierror.InternalError(err, "collecting old images")
+	}
+
+	response.OKReturn(c, report)
+	return nil
+}
@@ -3,16 +3,22 @@ package namespace
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/appmetrics"
 	"github.com/epinio/epinio/internal/auth"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
 	"github.com/epinio/epinio/internal/configurations"
+	"github.com/epinio/epinio/internal/helmchart"
 	"github.com/epinio/epinio/internal/namespaces"
+	"github.com/epinio/epinio/internal/sbom"
 	"github.com/epinio/epinio/internal/services"
+	"github.com/epinio/epinio/internal/staginglogs"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/gin-gonic/gin"
@@ -21,13 +27,17 @@ import (
 	ants "github.com/panjf2000/ants/v2"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Delete handles the API endpoint /namespaces/:namespace (DELETE).
 // It destroys the namespace specified by its name.
 // This includes all the applications and configurations in it.
+// With the query parameter dryrun=true nothing is deleted. The response lists instead what
+// would be removed, and the protected resources preventing the deletion.
 func (oc Controller) Delete(c *gin.Context) apierror.APIErrors {
 	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
 	namespace := c.Param("namespace")
 
 	cluster, err := kubernetes.GetCluster(ctx)
@@ -35,11 +45,42 @@ func (oc Controller) Delete(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err)
 	}
 
+	preview, err := deletionPreview(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if c.Query("dryrun") == "true" {
+		response.OKReturn(c, preview)
+		return nil
+	}
+
+	if preview.IsBlocked() {
+		return protectionErrors(preview)
+	}
+
 	err = deleteApps(ctx, cluster, namespace)
 	if err != nil {
 		return apierror.InternalError(err)
 	}
 
+	// The applications are gone. The removal of their stored staging logs and SBOMs is
+	// best-effort, a failure must not stop the deletion of the namespace.
+	err = staginglogs.DeleteNamespace(ctx, cluster, namespace)
+	if err != nil {
+		log.Error(err, "deleting the stored staging logs", "namespace", namespace)
+	}
+
+	err = sbom.DeleteNamespace(ctx, cluster, namespace)
+	if err != nil {
+		log.Error(err, "deleting the stored SBOMs", "namespace", namespace)
+	}
+
+	// Drop the collected metrics, recreated applications must not show them.
+	if collector := appmetrics.Running(); collector != nil {
+		collector.ForgetNamespace(namespace)
+	}
+
 	err = deleteServices(ctx, cluster, namespace)
 	if err != nil {
 		return apierror.InternalError(err)
@@ -149,3 +190,87 @@ func deleteNamespaceFromUsers(ctx context.Context, namespace string) error {
 
 	return nil
 }
+
+// deletionPreview lists what the deletion of the namespace removes: its applications with
+// their routes, services, configurations, and the PVCs of the namespace and of the
+// stagings of its applications. It further lists the protected resources.
+func deletionPreview(ctx context.Context, cluster *kubernetes.Cluster, namespace string) (models.NamespaceDeletePreview, error) {
+	preview := models.NamespaceDeletePreview{Namespace: namespace}
+
+	space, err := namespaces.Get(ctx, cluster, namespace)
+	if err != nil {
+		return preview, err
+	}
+	if space != nil {
+		preview.Protected = space.Protected()
+	}
+
+	apps, err := application.List(ctx, cluster, namespace)
+	if err != nil {
+		return preview, errors.Wrap(err, "listing applications")
+	}
+	for _, app := range apps {
+		preview.Apps = append(preview.Apps, app.Meta.Name)
+		preview.Routes = append(preview.Routes, app.Configuration.Routes...)
+		if app.Protected {
+			preview.ProtectedApps = append(preview.ProtectedApps, app.Meta.Name)
+		}
+
+		_, err := cluster.Kubectl.CoreV1().PersistentVolumeClaims(helmchart.Namespace()).
+			Get(ctx, app.Meta.MakePVCName(), metav1.GetOptions{})
+		if err == nil {
+			preview.PVCs = append(preview.PVCs, helmchart.Namespace()+"/"+app.Meta.MakePVCName())
+		} else if !apierrors.IsNotFound(err) {
+			return preview, err
+		}
+	}
+
+	kubeServiceClient, err := services.NewKubernetesServiceClient(cluster)
+	if err != nil {
+		return preview, err
+	}
+	serviceList, err := kubeServiceClient.ListInNamespace(ctx, namespace)
+	if err != nil {
+		return preview, errors.Wrap(err, "listing services")
+	}
+	for _, service := range serviceList {
+		preview.Services = append(preview.Services, service.Meta.Name)
+		if service.Protected {
+			preview.ProtectedServices = append(preview.ProtectedServices, service.Meta.Name)
+		}
+	}
+
+	preview.Configurations, err = namespaceConfigurations(ctx, cluster, namespace)
+	if err != nil {
+		return preview, errors.Wrap(err, "listing configurations")
+	}
+
+	pvcList, err := cluster.Kubectl.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return preview, errors.Wrap(err, "listing PVCs")
+	}
+	for _, pvc := range pvcList.Items {
+		preview.PVCs = append(preview.PVCs, namespace+"/"+pvc.Name)
+	}
+
+	sort.Strings(preview.Routes)
+	sort.Strings(preview.PVCs)
+
+	return preview, nil
+}
+
+// protectionErrors reports the protected resources preventing the deletion of the
+// namespace.
+func protectionErrors(preview models.NamespaceDeletePreview) apierror.APIErrors {
+	issues := []apierror.APIError{}
+	if preview.Protected {
+		issues = append(issues, apierror.NamespaceIsProtected(preview.Namespace))
+	}
+	for _, app := range preview.ProtectedApps {
+		issues = append(issues, apierror.AppIsProtected(app))
+	}
+	for _, service := range preview.ProtectedServices {
+		issues = append(issues, apierror.ServiceIsProtected(service))
+	}
+	return apierror.NewMultiError(issues)
+}
@@ -54,6 +54,7 @@ func (oc Controller) Index(c *gin.Context) apierror.APIErrors {
 			},
 			Apps:           appNamesMap[namespace.Name],
 			Configurations: configNamesMap[namespace.Name],
+			Protected:      namespace.Protected(),
 		})
 	}
 
@@ -0,0 +1,87 @@
+package namespace
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/quota"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Quota handles the API endpoint GET /namespaces/:namespace/quota
+// It returns the quota of the namespace and the use of the limited resources. Namespaces
+// without quota return an empty one.
+func (hc Controller) Quota(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	limits, err := quota.Get(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	usage, err := quota.Usage(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	resp := models.NamespaceQuotaResponse{Usage: usage}
+	if limits != nil {
+		resp.Quota = *limits
+	}
+
+	response.OKReturn(c, resp)
+	return nil
+}
+
+// QuotaSet handles the API endpoint PUT /admin/namespaces/:namespace/quota
+// It replaces the quota of the namespace. An empty quota removes it. Existing resources
+// beyond the new quota are kept, only new ones are rejected.
+func (hc Controller) QuotaSet(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+
+	var request models.NamespaceQuota
+	if err := c.BindJSON(&request); err != nil {
+		return apierror.BadRequest(err)
+	}
+	if err := quota.Validate(request); err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if err := quota.Set(ctx, cluster, namespace, request); err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
+
+// EnforceQuota rejects the additional use of resources of the request, if the quota of
+// the namespace does not allow it.
+func EnforceQuota(ctx context.Context, cluster *kubernetes.Cluster, namespace string, request models.NamespaceUsage) apierror.APIErrors {
+	reasons, err := quota.Check(ctx, cluster, namespace, request)
+	if err != nil {
+		return apierror.InternalError(err, "failed to check the namespace quota")
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	return apierror.NewAPIError("Namespace quota exceeded", strings.Join(reasons, "; "), http.StatusForbidden)
+}
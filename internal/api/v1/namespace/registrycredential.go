@@ -0,0 +1,93 @@
+package namespace
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/namespaces"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegistryCredentials handles the API endpoint GET /namespaces/:namespace/registrycredentials
+// It returns the registry credentials of the namespace, without their passwords.
+func (hc Controller) RegistryCredentials(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	credentials, err := namespaces.RegistryCredentials(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OKReturn(c, credentials)
+	return nil
+}
+
+// RegistryCredentialCreate handles the API endpoint POST /namespaces/:namespace/registrycredentials
+// It adds a registry credential to the namespace. The images of the applications in the
+// namespace are pulled with it.
+func (hc Controller) RegistryCredentialCreate(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+
+	var request models.RegistryCredentialCreateRequest
+	if err := c.BindJSON(&request); err != nil {
+		return apierror.BadRequest(err)
+	}
+	if err := namespaces.ValidateRegistryCredential(request); err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	err = namespaces.AddRegistryCredential(ctx, cluster, namespace, request)
+	if err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return apierror.NewAPIError(
+				fmt.Sprintf("Registry credential '%s' already exists", request.Name),
+				"", http.StatusConflict)
+		}
+		return apierror.InternalError(err)
+	}
+
+	response.Created(c)
+	return nil
+}
+
+// RegistryCredentialDelete handles the API endpoint DELETE /namespaces/:namespace/registrycredentials/:name
+// It removes the named registry credential from the namespace.
+func (hc Controller) RegistryCredentialDelete(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	err = namespaces.RemoveRegistryCredential(ctx, cluster, namespace, name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return apierror.NewNotFoundError(fmt.Sprintf("Registry credential '%s' does not exist", name))
+		}
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/epinio/epinio/internal/application"
 	"github.com/epinio/epinio/internal/configurations"
 	"github.com/epinio/epinio/internal/namespaces"
+	"github.com/epinio/epinio/internal/quota"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 
@@ -40,6 +41,21 @@ func (hc Controller) Show(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err)
 	}
 
+	limits, err := quota.Get(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	// The usage is only of interest against a quota.
+	var usage *models.NamespaceUsage
+	if limits != nil {
+		current, err := quota.Usage(ctx, cluster, namespace)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+		usage = &current
+	}
+
 	response.OKReturn(c, models.Namespace{
 		Meta: models.MetaLite{
 			Name:      namespace,
@@ -47,6 +63,9 @@ func (hc Controller) Show(c *gin.Context) apierror.APIErrors {
 		},
 		Apps:           appNames,
 		Configurations: configurationNames,
+		Quota:          limits,
+		Usage:          usage,
+		Protected:      space.Protected(),
 	})
 	return nil
 }
@@ -0,0 +1,6 @@
+// Package operation contains the API handlers to follow long-running operations.
+package operation
+
+// Controller represents all functionality of the API related to operations
+type Controller struct {
+}
@@ -0,0 +1,50 @@
+package operation
+
+import (
+	"fmt"
+
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/operations"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// Show handles the API endpoint GET /operations/:id
+// It returns the state of the operation. Users see the operations of their namespaces
+// only.
+func (oc Controller) Show(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	pool := operations.Running()
+	if pool == nil {
+		return apierror.InternalError(errors.New("operations are not running"))
+	}
+
+	notFound := apierror.NewNotFoundError(fmt.Sprintf("Operation '%s' does not exist", id))
+
+	operation, ok := pool.Get(id)
+	if !ok {
+		return notFound
+	}
+
+	// Operations of other namespaces are reported as missing, like unknown ones.
+	user := requestctx.User(ctx)
+	if user.Role != "admin" {
+		allowed := false
+		for _, namespace := range user.Namespaces {
+			if namespace == operation.Namespace {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return notFound
+		}
+	}
+
+	response.OKReturn(c, operation)
+	return nil
+}
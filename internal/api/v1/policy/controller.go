@@ -0,0 +1,6 @@
+// Package policy contains the API handlers to inspect and evaluate the admission policies.
+package policy
+
+// Controller represents all functionality of the API related to policies
+type Controller struct {
+}
@@ -0,0 +1,39 @@
+package policy
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/policy"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Evaluate handles the API endpoint POST /namespaces/:namespace/policies/evaluate
+// It evaluates the policies applying to the namespace against the application settings
+// of the request, without changing anything, and returns the violations found.
+func (hc Controller) Evaluate(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+
+	var subject models.PolicySubject
+	if err := c.BindJSON(&subject); err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	violations, err := policy.Check(ctx, cluster, namespace, subject)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OKReturn(c, models.PolicyEvaluationResponse{
+		Allowed:    len(violations) == 0,
+		Violations: violations,
+	})
+	return nil
+}
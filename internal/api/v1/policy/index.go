@@ -0,0 +1,55 @@
+package policy
+
+import (
+	"fmt"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/policy"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Index handles the API endpoint GET /namespaces/:namespace/policies
+// It returns the global policies and the policies of the namespace.
+func (hc Controller) Index(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	policies, err := policy.List(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OKReturn(c, policies)
+	return nil
+}
+
+// Show handles the API endpoint GET /namespaces/:namespace/policies/:policy
+// It returns the named policy applying to the namespace.
+func (hc Controller) Show(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("policy")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	thePolicy, err := policy.Lookup(ctx, cluster, namespace, name)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if thePolicy == nil {
+		return apierror.NewNotFoundError(fmt.Sprintf("Policy '%s' does not exist", name))
+	}
+
+	response.OKReturn(c, thePolicy)
+	return nil
+}
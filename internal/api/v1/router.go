@@ -11,10 +11,14 @@ import (
 	"github.com/epinio/epinio/helpers/routes"
 	"github.com/epinio/epinio/internal/api/v1/appchart"
 	"github.com/epinio/epinio/internal/api/v1/application"
+	"github.com/epinio/epinio/internal/api/v1/backup"
 	"github.com/epinio/epinio/internal/api/v1/configuration"
 	"github.com/epinio/epinio/internal/api/v1/configurationbinding"
 	"github.com/epinio/epinio/internal/api/v1/env"
+	"github.com/epinio/epinio/internal/api/v1/gc"
 	"github.com/epinio/epinio/internal/api/v1/namespace"
+	"github.com/epinio/epinio/internal/api/v1/operation"
+	"github.com/epinio/epinio/internal/api/v1/policy"
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/api/v1/service"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
@@ -69,8 +73,14 @@ func put(path string, h gin.HandlerFunc) routes.Route {
 	return routes.NewRoute("PUT", path, h)
 }
 
-// AdminRoutes is the list of restricted routes, only accessible by admins
-var AdminRoutes map[string]struct{} = map[string]struct{}{}
+// AdminRoutes is the list of restricted routes, only accessible by admins. Routes with
+// parameters are listed with their parameters, i.e. as registered.
+var AdminRoutes map[string]struct{} = map[string]struct{}{
+	Root + "/admin/gc/blobs":                    {},
+	Root + "/admin/gc/images":                   {},
+	Root + "/admin/namespaces/:namespace/quota": {},
+	Root + "/admin/namespaces/restore":          {},
+}
 
 var Routes = routes.NamedRoutes{
 	"Info":      get("/info", errorHandler(Info)),
@@ -78,20 +88,36 @@ var Routes = routes.NamedRoutes{
 
 	// app controller files see application/*.go
 
-	"AllApps":         get("/applications", errorHandler(application.Controller{}.FullIndex)),
-	"Apps":            get("/namespaces/:namespace/applications", errorHandler(application.Controller{}.Index)),
-	"AppCreate":       post("/namespaces/:namespace/applications", errorHandler(application.Controller{}.Create)),
-	"AppShow":         get("/namespaces/:namespace/applications/:app", errorHandler(application.Controller{}.Show)),
-	"StagingComplete": get("/namespaces/:namespace/staging/:stage_id/complete", errorHandler(application.Controller{}.Staged)), // See stage.go
-	"AppDelete":       delete("/namespaces/:namespace/applications/:app", errorHandler(application.Controller{}.Delete)),
-	"AppUpload":       post("/namespaces/:namespace/applications/:app/store", errorHandler(application.Controller{}.Upload)), // See upload.go
-	"AppImportGit":    post("/namespaces/:namespace/applications/:app/import-git", errorHandler(application.Controller{}.ImportGit)),
-	"AppStage":        post("/namespaces/:namespace/applications/:app/stage", errorHandler(application.Controller{}.Stage)), // See stage.go
-	"AppDeploy":       post("/namespaces/:namespace/applications/:app/deploy", errorHandler(application.Controller{}.Deploy)),
-	"AppRestart":      post("/namespaces/:namespace/applications/:app/restart", errorHandler(application.Controller{}.Restart)),
-	"AppUpdate":       patch("/namespaces/:namespace/applications/:app", errorHandler(application.Controller{}.Update)),
-	"AppRunning":      get("/namespaces/:namespace/applications/:app/running", errorHandler(application.Controller{}.Running)),
-	"AppPart":         get("/namespaces/:namespace/applications/:app/part/:part", errorHandler(application.Controller{}.GetPart)),
+	"AllApps":                  get("/applications", errorHandler(application.Controller{}.FullIndex)),
+	"Apps":                     get("/namespaces/:namespace/applications", errorHandler(application.Controller{}.Index)),
+	"AppCreate":                post("/namespaces/:namespace/applications", errorHandler(application.Controller{}.Create)),
+	"AppShow":                  get("/namespaces/:namespace/applications/:app", errorHandler(application.Controller{}.Show)),
+	"StagingComplete":          get("/namespaces/:namespace/staging/:stage_id/complete", errorHandler(application.Controller{}.Staged)), // See stage.go
+	"StagingStatus":            get("/namespaces/:namespace/staging", errorHandler(application.Controller{}.StagingStatus)),             // See stage.go
+	"StagingCancel":            delete("/namespaces/:namespace/staging/:stage_id", errorHandler(application.Controller{}.StageCancel)),  // See stage.go
+	"AppDelete":                delete("/namespaces/:namespace/applications/:app", errorHandler(application.Controller{}.Delete)),
+	"AppProtection":            put("/namespaces/:namespace/applications/:app/protection", errorHandler(application.Controller{}.Protection)),
+	"AppUpload":                post("/namespaces/:namespace/applications/:app/store", errorHandler(application.Controller{}.Upload)), // See upload.go
+	"AppUploadMatch":           post("/namespaces/:namespace/applications/:app/store/match", errorHandler(application.Controller{}.UploadMatch)),
+	"AppUploadSessionCreate":   post("/namespaces/:namespace/applications/:app/store/sessions", errorHandler(application.Controller{}.UploadSessionCreate)), // See upload_session.go
+	"AppUploadSessionShow":     get("/namespaces/:namespace/applications/:app/store/sessions/:blob", errorHandler(application.Controller{}.UploadSessionShow)),
+	"AppUploadSessionPart":     put("/namespaces/:namespace/applications/:app/store/sessions/:blob/parts/:part", errorHandler(application.Controller{}.UploadSessionPart)),
+	"AppUploadSessionComplete": post("/namespaces/:namespace/applications/:app/store/sessions/:blob/complete", errorHandler(application.Controller{}.UploadSessionComplete)),
+	"AppUploadSessionDelete":   delete("/namespaces/:namespace/applications/:app/store/sessions/:blob", errorHandler(application.Controller{}.UploadSessionDelete)),
+	"AppImportGit":             post("/namespaces/:namespace/applications/:app/import-git", errorHandler(application.Controller{}.ImportGit)),
+	"AppStage":                 post("/namespaces/:namespace/applications/:app/stage", errorHandler(application.Controller{}.Stage)), // See stage.go
+	"AppDeploy":                post("/namespaces/:namespace/applications/:app/deploy", errorHandler(application.Controller{}.Deploy)),
+	"AppRestart":               post("/namespaces/:namespace/applications/:app/restart", errorHandler(application.Controller{}.Restart)),
+	"AppUpdate":                patch("/namespaces/:namespace/applications/:app", errorHandler(application.Controller{}.Update)),
+	"AppRunning":               get("/namespaces/:namespace/applications/:app/running", errorHandler(application.Controller{}.Running)),
+	"AppBuildCache":            get("/namespaces/:namespace/applications/:app/buildcache", errorHandler(application.Controller{}.BuildCacheShow)),     // See buildcache.go
+	"AppBuildCachePurge":       delete("/namespaces/:namespace/applications/:app/buildcache", errorHandler(application.Controller{}.BuildCachePurge)), // See buildcache.go
+	"AppSBOM":                  get("/namespaces/:namespace/applications/:app/sbom", errorHandler(application.Controller{}.SBOM)),                     // See sbom.go
+	"AppWebhook":               get("/namespaces/:namespace/applications/:app/webhook", errorHandler(application.Controller{}.WebhookShow)),           // See webhook.go
+	"AppWebhookEnable":         post("/namespaces/:namespace/applications/:app/webhook", errorHandler(application.Controller{}.WebhookEnable)),
+	"AppWebhookRotate":         post("/namespaces/:namespace/applications/:app/webhook/rotate", errorHandler(application.Controller{}.WebhookRotate)),
+	"AppPart":                  get("/namespaces/:namespace/applications/:app/part/:part", errorHandler(application.Controller{}.GetPart)),
+	"AppMetrics":               get("/namespaces/:namespace/applications/:app/metrics", errorHandler(application.Controller{}.Metrics)),
 
 	"AppMatch":  get("/namespaces/:namespace/appsmatches/:pattern", errorHandler(application.Controller{}.Match)),
 	"AppMatch0": get("/namespaces/:namespace/appsmatches", errorHandler(application.Controller{}.Match)),
@@ -103,9 +129,12 @@ var Routes = routes.NamedRoutes{
 	"EnvMatch":  get("/namespaces/:namespace/applications/:app/environmentmatch/:pattern", errorHandler(env.Controller{}.Match)),
 	"EnvMatch0": get("/namespaces/:namespace/applications/:app/environmentmatch", errorHandler(env.Controller{}.Match)),
 
-	"EnvSet":   post("/namespaces/:namespace/applications/:app/environment", errorHandler(env.Controller{}.Set)),
-	"EnvShow":  get("/namespaces/:namespace/applications/:app/environment/:env", errorHandler(env.Controller{}.Show)),
-	"EnvUnset": delete("/namespaces/:namespace/applications/:app/environment/:env", errorHandler(env.Controller{}.Unset)),
+	"EnvSet":     post("/namespaces/:namespace/applications/:app/environment", errorHandler(env.Controller{}.Set)),
+	"EnvSetFrom": post("/namespaces/:namespace/applications/:app/environmentfrom", errorHandler(env.Controller{}.SetFrom)),
+	"EnvShow":    get("/namespaces/:namespace/applications/:app/environment/:env", errorHandler(env.Controller{}.Show)),
+	"EnvUnset":   delete("/namespaces/:namespace/applications/:app/environment/:env", errorHandler(env.Controller{}.Unset)),
+
+	"EnvStagingUnset": delete("/namespaces/:namespace/applications/:app/stagingenvironment/:env", errorHandler(env.Controller{}.StagingUnset)),
 
 	// Bind and unbind configurations to/from applications, by means of configurationbindings in applications
 	"ConfigurationBindingCreate": post("/namespaces/:namespace/applications/:app/configurationbindings",
@@ -119,6 +148,27 @@ var Routes = routes.NamedRoutes{
 	"NamespaceDelete": delete("/namespaces/:namespace", errorHandler(namespace.Controller{}.Delete)),
 	"NamespaceShow":   get("/namespaces/:namespace", errorHandler(namespace.Controller{}.Show)),
 
+	// Protection of namespaces against deletion, see also AppProtection and ServiceProtection
+	"NamespaceProtection": put("/namespaces/:namespace/protection", errorHandler(namespace.Controller{}.Protection)),
+
+	// Registry credentials for pulling the application images of a namespace
+	"RegistryCredentials":      get("/namespaces/:namespace/registrycredentials", errorHandler(namespace.Controller{}.RegistryCredentials)),
+	"RegistryCredentialCreate": post("/namespaces/:namespace/registrycredentials", errorHandler(namespace.Controller{}.RegistryCredentialCreate)),
+	"RegistryCredentialDelete": delete("/namespaces/:namespace/registrycredentials/:name", errorHandler(namespace.Controller{}.RegistryCredentialDelete)),
+
+	// Namespace quotas, see AdminRoutes for setting them
+	"NamespaceQuota":    get("/namespaces/:namespace/quota", errorHandler(namespace.Controller{}.Quota)),
+	"NamespaceQuotaSet": put("/admin/namespaces/:namespace/quota", errorHandler(namespace.Controller{}.QuotaSet)),
+
+	// Namespace backups, see AdminRoutes for restoring them
+	"NamespaceBackup":  get("/namespaces/:namespace/backup", errorHandler(backup.Controller{}.Backup)),
+	"NamespaceRestore": post("/admin/namespaces/restore", errorHandler(backup.Controller{}.Restore)),
+
+	// Admission policies applying to a namespace, and their dry-run evaluation
+	"Policies":       get("/namespaces/:namespace/policies", errorHandler(policy.Controller{}.Index)),
+	"PolicyShow":     get("/namespaces/:namespace/policies/:policy", errorHandler(policy.Controller{}.Show)),
+	"PolicyEvaluate": post("/namespaces/:namespace/policies/evaluate", errorHandler(policy.Controller{}.Evaluate)),
+
 	// Note, the second registration catches calls with an empty pattern!
 	"NamespacesMatch":  get("/namespacematches/:pattern", errorHandler(namespace.Controller{}.Match)),
 	"NamespacesMatch0": get("/namespacematches", errorHandler(namespace.Controller{}.Match)),
@@ -154,6 +204,8 @@ var Routes = routes.NamedRoutes{
 	"ServiceShow":   get("/namespaces/:namespace/services/:service", errorHandler(service.Controller{}.Show)),
 	"ServiceDelete": delete("/namespaces/:namespace/services/:service", errorHandler(service.Controller{}.Delete)),
 
+	"ServiceProtection": put("/namespaces/:namespace/services/:service/protection", errorHandler(service.Controller{}.Protection)),
+
 	"ServiceMatch":  get("/namespaces/:namespace/servicesmatches/:pattern", errorHandler(service.Controller{}.Match)),
 	"ServiceMatch0": get("/namespaces/:namespace/servicesmatches", errorHandler(service.Controller{}.Match)),
 
@@ -167,6 +219,13 @@ var Routes = routes.NamedRoutes{
 		"/namespaces/:namespace/services/:service/unbind",
 		errorHandler(service.Controller{}.Unbind)),
 
+	// Long-running operations
+	"OperationShow": get("/operations/:id", errorHandler(operation.Controller{}.Show)),
+
+	// Garbage collection, see AdminRoutes
+	"BlobGC":  get("/admin/gc/blobs", errorHandler(gc.Controller{}.Blobs)),
+	"ImageGC": get("/admin/gc/images", errorHandler(gc.Controller{}.Images)),
+
 	// App charts
 	"ChartList":   get("/appcharts", errorHandler(appchart.Controller{}.Index)),
 	"ChartMatch":  get("/appchartsmatch/:pattern", errorHandler(appchart.Controller{}.Match)),
@@ -179,6 +238,21 @@ var WsRoutes = routes.NamedRoutes{
 	"AppPortForward": get("/namespaces/:namespace/applications/:app/portforward", errorHandler(application.Controller{}.PortForward)),
 	"AppLogs":        get("/namespaces/:namespace/applications/:app/logs", application.Controller{}.Logs),
 	"StagingLogs":    get("/namespaces/:namespace/staging/:stage_id/logs", application.Controller{}.Logs),
+	"AppEvents":      get("/namespaces/:namespace/events", errorHandler(application.Controller{}.Events)),
+}
+
+// WebhookRoutes are the API endpoints receiving notifications from external services.
+// They are not authenticated by the API server. The handlers authenticate the
+// notifications themselves.
+var WebhookRoutes = routes.NamedRoutes{
+	"GitWebhook": post("/webhooks/git/:namespace/:app", errorHandler(application.Controller{}.GitWebhook)), // See webhook.go
+}
+
+// BlobRoutes are the API endpoints serving the blobs of the filesystem blob store to the
+// staging jobs. They are not authenticated by the API server. The handlers check the
+// download tokens of the requests.
+var BlobRoutes = routes.NamedRoutes{
+	"BlobDownload": get("/blobs/:blob", errorHandler(application.Controller{}.BlobDownload)), // See blob.go
 }
 
 // Lemon extends the specified router with the methods and urls
@@ -196,3 +270,19 @@ func Spice(router *gin.RouterGroup) {
 		router.Handle(r.Method, r.Path, r.Handler)
 	}
 }
+
+// Hook extends the specified router with the methods and urls
+// handling the webhook API endpoints
+func Hook(router *gin.RouterGroup) {
+	for _, r := range WebhookRoutes {
+		router.Handle(r.Method, r.Path, r.Handler)
+	}
+}
+
+// Blob extends the specified router with the methods and urls
+// handling the blob download API endpoints
+func Blob(router *gin.RouterGroup) {
+	for _, r := range BlobRoutes {
+		router.Handle(r.Method, r.Path, r.Handler)
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/epinio/epinio/helpers/kubernetes"
@@ -10,6 +11,7 @@ import (
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
 	"github.com/epinio/epinio/internal/configurations"
 	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
 
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
@@ -49,6 +51,18 @@ func (ctr Controller) Bind(c *gin.Context) apierror.APIErrors {
 		return apiErr
 	}
 
+	apiErr = BindToApp(ctx, cluster, logger, namespace, serviceName, *app)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	response.OK(c)
+	return nil
+}
+
+// BindToApp binds the service to the application. The service has to be ready, see
+// ValidateService.
+func BindToApp(ctx context.Context, cluster *kubernetes.Cluster, logger logr.Logger, namespace, serviceName string, app models.App) apierror.APIErrors {
 	// A service has one or more associated secrets containing its attributes. Adding
 	// a specific set of labels turns these secrets into valid epinio
 	// configurations. These configurations are then bound to the application.
@@ -70,7 +84,7 @@ func (ctr Controller) Bind(c *gin.Context) apierror.APIErrors {
 	logger.Info("binding service configuration")
 
 	_, errors := configurationbinding.CreateConfigurationBinding(
-		ctx, cluster, namespace, *app, configurationNames,
+		ctx, cluster, namespace, app, configurationNames,
 	)
 
 	if errors != nil {
@@ -90,6 +104,5 @@ func (ctr Controller) Bind(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err)
 	}
 
-	response.OK(c)
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/epinio/epinio/helpers/kubernetes"
+	namespaceapi "github.com/epinio/epinio/internal/api/v1/namespace"
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/services"
 	"github.com/gin-gonic/gin"
@@ -42,6 +43,11 @@ func (ctr Controller) Create(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err)
 	}
 
+	apierr := namespaceapi.EnforceQuota(ctx, cluster, namespace, models.NamespaceUsage{Services: 1})
+	if apierr != nil {
+		return apierr
+	}
+
 	err = kubeServiceClient.Create(ctx, namespace, createRequest.Name, *catalogService)
 	if err != nil {
 		return apierror.InternalError(err)
@@ -43,6 +43,19 @@ func (ctr Controller) Delete(c *gin.Context) apierror.APIErrors {
 		return apiErr
 	}
 
+	kubeServiceClient, err := services.NewKubernetesServiceClient(cluster)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	service, err := kubeServiceClient.Get(ctx, namespace, serviceName)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if service != nil && service.Protected {
+		return apierror.ServiceIsProtected(serviceName)
+	}
+
 	// A service has one or more associated secrets containing its attributes.
 	// Binding turned these secrets into configurations and bound them to the
 	// application.  Unbinding simply unbound them.  We may think that this means that
@@ -91,11 +104,6 @@ func (ctr Controller) Delete(c *gin.Context) apierror.APIErrors {
 		}
 	}
 
-	kubeServiceClient, err := services.NewKubernetesServiceClient(cluster)
-	if err != nil {
-		return apierror.InternalError(err)
-	}
-
 	err = kubeServiceClient.Delete(ctx, namespace, serviceName)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
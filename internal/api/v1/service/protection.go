@@ -0,0 +1,50 @@
+package service
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/services"
+	"github.com/gin-gonic/gin"
+
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// Protection handles the API endpoint PUT /namespaces/:namespace/services/:service/protection
+// It sets or clears the protection of the service. A protected service cannot be deleted,
+// nor its namespace.
+func (ctr Controller) Protection(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	serviceName := c.Param("service")
+
+	var request models.ProtectionRequest
+	if err := c.BindJSON(&request); err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	kubeServiceClient, err := services.NewKubernetesServiceClient(cluster)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	service, err := kubeServiceClient.Get(ctx, namespace, serviceName)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if service == nil {
+		return apierror.ServiceIsNotKnown(serviceName)
+	}
+
+	if err := kubeServiceClient.SetProtected(ctx, namespace, serviceName, request.Protected); err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
@@ -0,0 +1,89 @@
+// Package appevents implements the API server's stream of application events. A set of
+// informers watches the kube resources making up applications (App resources, staging
+// jobs, deployments, pods, configuration bindings) and translates their changes into
+// typed events, which a broker fans out to the subscribers of the affected namespace.
+package appevents
+
+import (
+	"sync"
+	"time"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// subscriberBuffer is the number of events buffered per subscriber. Events for a
+// subscriber whose buffer is full are dropped instead of blocking the informers.
+const subscriberBuffer = 64
+
+// Broker distributes published events to the subscribers of the event's namespace.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan models.AppEvent]struct{}
+}
+
+// broker is the memo of the broker run by the API server. See Start.
+var broker *Broker
+
+// NewBroker returns a broker without subscribers.
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: map[string]map[chan models.AppEvent]struct{}{},
+	}
+}
+
+// Subscribe registers a subscriber for the events of the namespace. It returns the
+// channel delivering the events, and a function to cancel the subscription. The
+// channel is closed on cancellation.
+func (b *Broker) Subscribe(namespace string) (<-chan models.AppEvent, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events := make(chan models.AppEvent, subscriberBuffer)
+	if _, ok := b.subscribers[namespace]; !ok {
+		b.subscribers[namespace] = map[chan models.AppEvent]struct{}{}
+	}
+	b.subscribers[namespace][events] = struct{}{}
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			delete(b.subscribers[namespace], events)
+			if len(b.subscribers[namespace]) == 0 {
+				delete(b.subscribers, namespace)
+			}
+			close(events)
+		})
+	}
+
+	return events, cancel
+}
+
+// Publish delivers the events to the subscribers of their namespaces. Events without a
+// time are stamped with the current time.
+func (b *Broker) Publish(events ...models.AppEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, event := range events {
+		if event.Time == "" {
+			event.Time = time.Now().Format(time.RFC3339) // ISO 8601
+		}
+
+		for subscriber := range b.subscribers[event.App.Namespace] {
+			select {
+			case subscriber <- event:
+			default:
+				// Slow subscriber, drop the event
+			}
+		}
+	}
+}
+
+// Running returns the broker started by Start, or nil if the events stream is not
+// available.
+func Running() *Broker {
+	return broker
+}
@@ -0,0 +1,57 @@
+package appevents_test
+
+import (
+	. "github.com/epinio/epinio/internal/appevents"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Broker", func() {
+	var broker *Broker
+
+	BeforeEach(func() {
+		broker = NewBroker()
+	})
+
+	It("delivers events to the subscribers of the namespace only", func() {
+		events, cancel := broker.Subscribe("workspace")
+		defer cancel()
+		others, cancelOthers := broker.Subscribe("other")
+		defer cancelOthers()
+
+		broker.Publish(models.AppEvent{
+			Type: models.AppEventCreated,
+			App:  models.NewAppRef("app", "workspace"),
+		})
+
+		Expect(events).To(HaveLen(1))
+		event := <-events
+		Expect(event.Type).To(Equal(models.AppEventCreated))
+		Expect(event.App.Name).To(Equal("app"))
+		Expect(event.Time).ToNot(BeEmpty())
+		Expect(others).To(BeEmpty())
+	})
+
+	It("closes the channel when the subscription is cancelled", func() {
+		events, cancel := broker.Subscribe("workspace")
+		cancel()
+		cancel()
+
+		broker.Publish(models.AppEvent{App: models.NewAppRef("app", "workspace")})
+
+		Eventually(events).Should(BeClosed())
+	})
+
+	It("drops events for slow subscribers instead of blocking", func() {
+		events, cancel := broker.Subscribe("workspace")
+		defer cancel()
+
+		for i := 0; i < 100; i++ {
+			broker.Publish(models.AppEvent{App: models.NewAppRef("app", "workspace")})
+		}
+
+		Expect(len(events)).To(Equal(cap(events)))
+	})
+})
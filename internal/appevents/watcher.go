@@ -0,0 +1,322 @@
+package appevents
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Start creates the broker of the API server and runs the informers feeding it in the
+// background, until the context is done.
+func Start(ctx context.Context, logger logr.Logger) {
+	broker = NewBroker()
+	go func() {
+		logger := logger.WithName("AppEvents")
+		if err := run(ctx, logger, broker); err != nil {
+			logger.Error(err, "application events unavailable")
+		}
+	}()
+}
+
+// run sets up the informers, and publishes the events they generate to the broker.
+// The objects seen during the initial synchronization of the informers are not
+// reported, only the changes coming after.
+func run(ctx context.Context, logger logr.Logger, b *Broker) error {
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(cluster.RestConfig)
+	if err != nil {
+		return errors.Wrap(err, "error creating dynamic client")
+	}
+
+	var synced atomic.Value
+	synced.Store(false)
+	publish := func(events []models.AppEvent) {
+		if synced.Load().(bool) {
+			b.Publish(events...)
+		}
+	}
+
+	workloads := informers.NewSharedInformerFactoryWithOptions(cluster.Kubectl, 0,
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = "app.kubernetes.io/component=application"
+		}))
+	staging := informers.NewSharedInformerFactoryWithOptions(cluster.Kubectl, 0,
+		informers.WithNamespace(helmchart.Namespace()),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = "app.kubernetes.io/component=staging"
+		}))
+	apps := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0)
+
+	apps.ForResource(schema.GroupVersionResource{
+		Group:    "application.epinio.io",
+		Version:  "v1",
+		Resource: "apps",
+	}).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if app, ok := obj.(metav1.Object); ok {
+				publish(AppEvents(app))
+			}
+		},
+	})
+
+	staging.Batch().V1().Jobs().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if job, ok := obj.(*batchv1.Job); ok {
+				publish(JobEvents(nil, job))
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldJob, ok1 := oldObj.(*batchv1.Job)
+			newJob, ok2 := newObj.(*batchv1.Job)
+			if ok1 && ok2 {
+				publish(JobEvents(oldJob, newJob))
+			}
+		},
+	})
+
+	workloads.Apps().V1().Deployments().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if deployment, ok := obj.(*appsv1.Deployment); ok {
+				publish(DeploymentEvents(nil, deployment))
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldDeployment, ok1 := oldObj.(*appsv1.Deployment)
+			newDeployment, ok2 := newObj.(*appsv1.Deployment)
+			if ok1 && ok2 {
+				publish(DeploymentEvents(oldDeployment, newDeployment))
+			}
+		},
+	})
+
+	workloads.Core().V1().Pods().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldPod, ok1 := oldObj.(*corev1.Pod)
+			newPod, ok2 := newObj.(*corev1.Pod)
+			if ok1 && ok2 {
+				publish(PodEvents(oldPod, newPod))
+			}
+		},
+	})
+
+	workloads.Core().V1().Secrets().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if secret, ok := obj.(*corev1.Secret); ok {
+				publish(ConfigurationEvents(nil, secret))
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldSecret, ok1 := oldObj.(*corev1.Secret)
+			newSecret, ok2 := newObj.(*corev1.Secret)
+			if ok1 && ok2 {
+				publish(ConfigurationEvents(oldSecret, newSecret))
+			}
+		},
+	})
+
+	logger.Info("start")
+
+	apps.Start(ctx.Done())
+	staging.Start(ctx.Done())
+	workloads.Start(ctx.Done())
+
+	for _, ok := range apps.WaitForCacheSync(ctx.Done()) {
+		if !ok {
+			return errors.New("failed to sync the application informer")
+		}
+	}
+	for _, ok := range staging.WaitForCacheSync(ctx.Done()) {
+		if !ok {
+			return errors.New("failed to sync the staging informers")
+		}
+	}
+	for _, ok := range workloads.WaitForCacheSync(ctx.Done()) {
+		if !ok {
+			return errors.New("failed to sync the workload informers")
+		}
+	}
+
+	synced.Store(true)
+	logger.Info("synced")
+
+	<-ctx.Done()
+	logger.Info("stop")
+	return nil
+}
+
+// AppEvents returns the events for the creation of an App resource.
+func AppEvents(app metav1.Object) []models.AppEvent {
+	return []models.AppEvent{{
+		Type: models.AppEventCreated,
+		App:  models.NewAppRef(app.GetName(), app.GetNamespace()),
+	}}
+}
+
+// JobEvents returns the events for the change of a staging job. A nil oldJob indicates
+// the creation of the job.
+func JobEvents(oldJob, newJob *batchv1.Job) []models.AppEvent {
+	event := models.AppEvent{
+		App:     appRefOf(newJob),
+		StageID: newJob.Labels[models.EpinioStageIDLabel],
+	}
+
+	if oldJob == nil {
+		event.Type = models.AppEventStagingStarted
+		return []models.AppEvent{event}
+	}
+
+	oldCondition := jobCondition(oldJob)
+	newCondition := jobCondition(newJob)
+	if oldCondition != nil || newCondition == nil {
+		// Was already done before, or is not done yet.
+		return nil
+	}
+
+	event.Message = newCondition.Message
+	if newCondition.Type == batchv1.JobFailed {
+		event.Type = models.AppEventStagingFailed
+	} else {
+		event.Type = models.AppEventStagingFinished
+	}
+	return []models.AppEvent{event}
+}
+
+// DeploymentEvents returns the events for the change of an application deployment. A
+// nil oldDeployment indicates the creation of the deployment.
+func DeploymentEvents(oldDeployment, newDeployment *appsv1.Deployment) []models.AppEvent {
+	instances := replicas(newDeployment)
+	event := models.AppEvent{
+		App:       appRefOf(newDeployment),
+		StageID:   newDeployment.Spec.Template.Labels[models.EpinioStageIDLabel],
+		Instances: instances,
+	}
+
+	if oldDeployment == nil {
+		event.Type = models.AppEventDeployed
+		return []models.AppEvent{event}
+	}
+
+	result := []models.AppEvent{}
+	if !equality.Semantic.DeepEqual(oldDeployment.Spec.Template, newDeployment.Spec.Template) {
+		event.Type = models.AppEventDeployed
+		result = append(result, event)
+	}
+	if replicas(oldDeployment) != instances {
+		event.Type = models.AppEventScaled
+		result = append(result, event)
+	}
+	return result
+}
+
+// PodEvents returns the events for the change of an application pod, i.e. replica.
+func PodEvents(oldPod, newPod *corev1.Pod) []models.AppEvent {
+	result := []models.AppEvent{}
+	event := models.AppEvent{
+		App:     appRefOf(newPod),
+		Replica: newPod.Name,
+	}
+
+	if !podReady(oldPod) && podReady(newPod) {
+		event.Type = models.AppEventReplicaReady
+		result = append(result, event)
+	}
+
+	restarts := map[string]int32{}
+	for _, status := range oldPod.Status.ContainerStatuses {
+		restarts[status.Name] = status.RestartCount
+	}
+	for _, status := range newPod.Status.ContainerStatuses {
+		if status.RestartCount <= restarts[status.Name] {
+			continue
+		}
+
+		event.Type = models.AppEventReplicaCrashed
+		event.Message = ""
+		if terminated := status.LastTerminationState.Terminated; terminated != nil {
+			event.Message = terminated.Reason
+		}
+		result = append(result, event)
+	}
+
+	return result
+}
+
+// ConfigurationEvents returns the events for the change of an application's secret.
+// Only the secrets holding the application's bound configurations are considered. A nil
+// oldSecret indicates the creation of the secret.
+func ConfigurationEvents(oldSecret, newSecret *corev1.Secret) []models.AppEvent {
+	if newSecret.Labels[application.EpinioApplicationAreaLabel] != "configuration" {
+		return nil
+	}
+
+	result := []models.AppEvent{}
+	for name := range newSecret.Data {
+		if oldSecret != nil {
+			if _, ok := oldSecret.Data[name]; ok {
+				continue
+			}
+		}
+		result = append(result, models.AppEvent{
+			Type:          models.AppEventConfigurationBound,
+			App:           appRefOf(newSecret),
+			Configuration: name,
+		})
+	}
+	return result
+}
+
+// appRefOf returns the reference to the application owning the object, per its labels.
+func appRefOf(obj metav1.Object) models.AppRef {
+	labels := obj.GetLabels()
+	return models.NewAppRef(labels["app.kubernetes.io/name"], labels["app.kubernetes.io/part-of"])
+}
+
+// jobCondition returns the terminal condition of the job, or nil if the job is still
+// running.
+func jobCondition(job *batchv1.Job) *batchv1.JobCondition {
+	for i, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		if condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed {
+			return &job.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func replicas(deployment *appsv1.Deployment) int32 {
+	if deployment.Spec.Replicas == nil {
+		return 1
+	}
+	return *deployment.Spec.Replicas
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package appevents_test
+
+import (
+	. "github.com/epinio/epinio/internal/appevents"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Event translation", func() {
+	meta := func(labels map[string]string) metav1.ObjectMeta {
+		all := map[string]string{
+			"app.kubernetes.io/name":    "app",
+			"app.kubernetes.io/part-of": "workspace",
+		}
+		for k, v := range labels {
+			all[k] = v
+		}
+		return metav1.ObjectMeta{Name: "object", Labels: all}
+	}
+
+	types := func(events []models.AppEvent) []models.AppEventType {
+		result := []models.AppEventType{}
+		for _, event := range events {
+			Expect(event.App.Name).To(Equal("app"))
+			Expect(event.App.Namespace).To(Equal("workspace"))
+			result = append(result, event.Type)
+		}
+		return result
+	}
+
+	Describe("JobEvents", func() {
+		job := func(conditions ...batchv1.JobCondition) *batchv1.Job {
+			return &batchv1.Job{
+				ObjectMeta: meta(map[string]string{models.EpinioStageIDLabel: "s1"}),
+				Status:     batchv1.JobStatus{Conditions: conditions},
+			}
+		}
+		complete := batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}
+		failed := batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "boom"}
+
+		It("reports the start of staging", func() {
+			events := JobEvents(nil, job())
+			Expect(types(events)).To(Equal([]models.AppEventType{models.AppEventStagingStarted}))
+			Expect(events[0].StageID).To(Equal("s1"))
+		})
+
+		It("reports the end of staging once", func() {
+			Expect(types(JobEvents(job(), job(complete)))).To(Equal([]models.AppEventType{models.AppEventStagingFinished}))
+			Expect(JobEvents(job(complete), job(complete))).To(BeEmpty())
+			Expect(JobEvents(job(), job())).To(BeEmpty())
+		})
+
+		It("reports failed staging", func() {
+			events := JobEvents(job(), job(failed))
+			Expect(types(events)).To(Equal([]models.AppEventType{models.AppEventStagingFailed}))
+			Expect(events[0].Message).To(Equal("boom"))
+		})
+	})
+
+	Describe("DeploymentEvents", func() {
+		deployment := func(replicas int32, stageID string) *appsv1.Deployment {
+			d := &appsv1.Deployment{ObjectMeta: meta(nil)}
+			d.Spec.Replicas = &replicas
+			d.Spec.Template.Labels = map[string]string{models.EpinioStageIDLabel: stageID}
+			return d
+		}
+
+		It("reports deployments", func() {
+			Expect(types(DeploymentEvents(nil, deployment(1, "s1")))).To(Equal([]models.AppEventType{models.AppEventDeployed}))
+			Expect(types(DeploymentEvents(deployment(1, "s1"), deployment(1, "s2")))).To(Equal([]models.AppEventType{models.AppEventDeployed}))
+		})
+
+		It("reports scaling", func() {
+			events := DeploymentEvents(deployment(1, "s1"), deployment(3, "s1"))
+			Expect(types(events)).To(Equal([]models.AppEventType{models.AppEventScaled}))
+			Expect(events[0].Instances).To(Equal(int32(3)))
+		})
+
+		It("ignores status changes", func() {
+			changed := deployment(1, "s1")
+			changed.Status.ReadyReplicas = 1
+			Expect(DeploymentEvents(deployment(1, "s1"), changed)).To(BeEmpty())
+		})
+	})
+
+	Describe("PodEvents", func() {
+		pod := func(ready corev1.ConditionStatus, restarts int32) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: meta(nil),
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:         "app",
+						RestartCount: restarts,
+						LastTerminationState: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"},
+						},
+					}},
+				},
+			}
+		}
+
+		It("reports replicas becoming ready", func() {
+			events := PodEvents(pod(corev1.ConditionFalse, 0), pod(corev1.ConditionTrue, 0))
+			Expect(types(events)).To(Equal([]models.AppEventType{models.AppEventReplicaReady}))
+			Expect(events[0].Replica).To(Equal("object"))
+			Expect(PodEvents(pod(corev1.ConditionTrue, 0), pod(corev1.ConditionTrue, 0))).To(BeEmpty())
+		})
+
+		It("reports crashed replicas", func() {
+			events := PodEvents(pod(corev1.ConditionTrue, 0), pod(corev1.ConditionFalse, 1))
+			Expect(types(events)).To(Equal([]models.AppEventType{models.AppEventReplicaCrashed}))
+			Expect(events[0].Message).To(Equal("OOMKilled"))
+		})
+	})
+
+	Describe("ConfigurationEvents", func() {
+		secret := func(area string, names ...string) *corev1.Secret {
+			s := &corev1.Secret{
+				ObjectMeta: meta(map[string]string{application.EpinioApplicationAreaLabel: area}),
+				Data:       map[string][]byte{},
+			}
+			for _, name := range names {
+				s.Data[name] = nil
+			}
+			return s
+		}
+
+		It("reports newly bound configurations", func() {
+			events := ConfigurationEvents(secret("configuration", "a"), secret("configuration", "a", "b"))
+			Expect(types(events)).To(Equal([]models.AppEventType{models.AppEventConfigurationBound}))
+			Expect(events[0].Configuration).To(Equal("b"))
+		})
+
+		It("ignores other application secrets", func() {
+			Expect(ConfigurationEvents(nil, secret("environment", "a"))).To(BeEmpty())
+		})
+	})
+})
@@ -11,12 +11,12 @@ import (
 
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/helpers/kubernetes/tailer"
+	"github.com/epinio/epinio/internal/blobstore"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
 	"github.com/epinio/epinio/internal/duration"
 	"github.com/epinio/epinio/internal/helm"
 	"github.com/epinio/epinio/internal/helmchart"
 	"github.com/epinio/epinio/internal/namespaces"
-	"github.com/epinio/epinio/internal/s3manager"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/pkg/errors"
 
@@ -102,37 +102,60 @@ func CurrentlyStaging(ctx context.Context, cluster *kubernetes.Cluster, namespac
 		return false, err
 	}
 
-	completed := func(condition apibatchv1.JobCondition) bool {
-		return condition.Status == v1.ConditionTrue && condition.Type == apibatchv1.JobComplete
+	for _, job := range jobList.Items {
+		if JobStaging(job) {
+			return true, nil
+		}
 	}
 
-	failed := func(condition apibatchv1.JobCondition) bool {
-		return condition.Status == v1.ConditionTrue && condition.Type == apibatchv1.JobFailed
+	// No staging jobs found
+	return false, nil
+}
+
+// JobStaging returns true if the staging job is active, i.e. neither complete nor failed.
+func JobStaging(job apibatchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status == v1.ConditionTrue &&
+			(condition.Type == apibatchv1.JobComplete || condition.Type == apibatchv1.JobFailed) {
+			// Terminal, not staging
+			return false
+		}
 	}
+	// No terminal condition found on the job, it is actively staging
+	return true
+}
 
-	done := func(condition apibatchv1.JobCondition) bool {
-		return completed(condition) || failed(condition)
+// StagingJob returns the job of the identified staging run for an application of the
+// namespace. The result is nil if there is no such job.
+func StagingJob(ctx context.Context, cluster *kubernetes.Cluster, namespace, stageID string) (*apibatchv1.Job, error) {
+	selector := fmt.Sprintf("app.kubernetes.io/component=staging,app.kubernetes.io/part-of=%s,%s=%s",
+		namespace, models.EpinioStageIDLabel, stageID)
+
+	jobList, err := cluster.ListJobs(ctx, helmchart.Namespace(), selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobList.Items) == 0 {
+		return nil, nil
 	}
 
-	jobStaging := func(job apibatchv1.Job) bool {
-		for _, condition := range job.Status.Conditions {
-			if done(condition) {
-				// Terminal, not staging
-				return false
-			}
-		}
-		// No terminal condition found on the job, it is actively staging
-		return true
+	return &jobList.Items[0], nil
+}
+
+// StagingCancel stops the staging job by deleting it, together with its pod, and the
+// secret holding the job environment.
+func StagingCancel(ctx context.Context, cluster *kubernetes.Cluster, job *apibatchv1.Job) error {
+	err := cluster.DeleteJob(ctx, job.ObjectMeta.Namespace, job.ObjectMeta.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
 	}
 
-	for _, job := range jobList.Items {
-		if jobStaging(job) {
-			return true, nil
-		}
+	err = cluster.DeleteSecret(ctx, job.ObjectMeta.Namespace, job.ObjectMeta.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
 	}
 
-	// No staging jobs found
-	return false, nil
+	return nil
 }
 
 // Lookup locates the named application (and namespace).
@@ -321,18 +344,45 @@ func ImageURL(app *unstructured.Unstructured) (string, error) {
 	return imageURL, nil
 }
 
+// StageHistoryAnnotation is the annotation of the application resource listing the IDs
+// of its last stagings, oldest first. Stage IDs are random, the history orders them.
+const StageHistoryAnnotation = "epinio.io/stage-history"
+
+// maxStageHistory is the number of stage IDs kept in the history.
+const maxStageHistory = 100
+
+// StageHistory returns the IDs of the last stagings of the application, oldest first.
+func StageHistory(app *unstructured.Unstructured) []string {
+	history := app.GetAnnotations()[StageHistoryAnnotation]
+	if history == "" {
+		return []string{}
+	}
+	return strings.Split(history, ",")
+}
+
+// AddStageHistory appends the stage ID to the history of the application resource,
+// dropping the oldest IDs beyond the size of the history. The resource is not saved.
+func AddStageHistory(app *unstructured.Unstructured, stageID string) {
+	history := append(StageHistory(app), stageID)
+	if len(history) > maxStageHistory {
+		history = history[len(history)-maxStageHistory:]
+	}
+
+	annotations := app.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[StageHistoryAnnotation] = strings.Join(history, ",")
+	app.SetAnnotations(annotations)
+}
+
 // Unstage removes staging resources. It deletes either all Jobs of the
 // named application, or all but stageIDCurrent. It also deletes the staged
-// objects from the S3 storage except for the current one.
+// blobs from the blob store except for the current one.
 func Unstage(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, stageIDCurrent string) error {
-	s3ConnectionDetails, err := s3manager.GetConnectionDetails(ctx, cluster,
-		helmchart.Namespace(), helmchart.S3ConnectionDetailsSecretName)
-	if err != nil {
-		return errors.Wrap(err, "fetching the S3 connection details from the Kubernetes secret")
-	}
-	s3m, err := s3manager.New(s3ConnectionDetails)
+	store, err := blobstore.New(ctx, cluster)
 	if err != nil {
-		return errors.Wrap(err, "creating an S3 manager")
+		return err
 	}
 
 	jobs, err := cluster.ListJobs(ctx, helmchart.Namespace(),
@@ -364,14 +414,14 @@ func Unstage(ctx context.Context, cluster *kubernetes.Cluster, appRef models.App
 		}
 	}
 
-	// Cleanup s3 objects
+	// Cleanup blobs
 	for _, job := range jobs.Items {
 		// skip prs with the same blob as the current one (including the current one)
 		if currentJob != nil && job.Labels[models.EpinioStageBlobUIDLabel] == currentJob.Labels[models.EpinioStageBlobUIDLabel] {
 			continue
 		}
 
-		if err = s3m.DeleteObject(ctx, job.ObjectMeta.Labels[models.EpinioStageBlobUIDLabel]); err != nil {
+		if err = store.DeleteObject(ctx, job.ObjectMeta.Labels[models.EpinioStageBlobUIDLabel]); err != nil {
 			return err
 		}
 	}
@@ -473,6 +523,16 @@ func fetch(ctx context.Context, cluster *kubernetes.Cluster, app *models.App) er
 		return errors.Wrap(err, "finding env")
 	}
 
+	environmentFrom, err := EnvironmentFrom(ctx, cluster, app.Meta)
+	if err != nil {
+		return errors.Wrap(err, "finding env references")
+	}
+
+	stagingEnvironment, err := StagingEnvironment(ctx, cluster, app.Meta)
+	if err != nil {
+		return errors.Wrap(err, "finding staging env")
+	}
+
 	instances, err := Scaling(ctx, cluster, app.Meta)
 	if err != nil {
 		return errors.Wrap(err, "finding scaling")
@@ -503,11 +563,16 @@ func fetch(ctx context.Context, cluster *kubernetes.Cluster, app *models.App) er
 	app.Configuration.Instances = &instances
 	app.Configuration.Configurations = configurations
 	app.Configuration.Environment = environment
+	app.Configuration.EnvironmentFrom = environmentFrom
+	if len(stagingEnvironment) > 0 {
+		app.Configuration.StagingEnvironment = &stagingEnvironment
+	}
 	app.Configuration.Routes = desiredRoutes
 	app.Configuration.AppChart = chartName
 	app.Origin = origin
 	app.StageID = stageID
 	app.ImageURL = imageURL
+	app.Protected = Protected(applicationCR)
 
 	// Check if app is active, and if yes, fill the associated parts.
 	// May have to straighten the workload structure a bit further.
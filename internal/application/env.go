@@ -2,14 +2,25 @@ package application
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/configurations"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/retry"
 )
 
+// EpinioSecretEnvAnnotation is the annotation of the environment secret listing the
+// names of the variables whose values are secret, as a JSON array.
+const EpinioSecretEnvAnnotation = "epinio.io/secret-env"
+
 // EnvironmentNames returns the names of all environment variables which are set on the named application by users.
 // It does not return values.
 func EnvironmentNames(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) ([]string, error) {
@@ -47,7 +58,7 @@ func Environment(ctx context.Context, cluster *kubernetes.Cluster, appRef models
 // workload is restarted to update it to the new settings. The
 // function will __not__ wait on this to complete.
 func EnvironmentSet(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, assignments models.EnvVariableMap, replace bool) error {
-	return envUpdate(ctx, cluster, appRef, func(evSecret *v1.Secret) {
+	return envUpdate(ctx, cluster, appRef, envLoad, func(evSecret *v1.Secret) {
 		// Replacement is adding to a clear structure
 		if replace {
 			evSecret.Data = make(map[string][]byte)
@@ -55,6 +66,38 @@ func EnvironmentSet(ctx context.Context, cluster *kubernetes.Cluster, appRef mod
 		for name, value := range assignments {
 			evSecret.Data[name] = []byte(value)
 		}
+		if replace {
+			// Drop the marks of variables which are gone.
+			secrets := secretNames(evSecret)
+			for name := range secrets {
+				if _, ok := evSecret.Data[name]; !ok {
+					delete(secrets, name)
+				}
+			}
+			setSecretNames(evSecret, secrets)
+		}
+	})
+}
+
+// EnvironmentSecretNames returns the set of names of the environment variables of the
+// named application which are marked as secret.
+func EnvironmentSecretNames(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (map[string]struct{}, error) {
+	evSecret, err := envLoad(ctx, cluster, appRef)
+	if err != nil {
+		return nil, err
+	}
+	return secretNames(evSecret), nil
+}
+
+// EnvironmentMarkSecret marks the specified environment variables of the named
+// application as secret, i.e. as variables whose values are masked when listed.
+func EnvironmentMarkSecret(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, names []string) error {
+	return envUpdate(ctx, cluster, appRef, envLoad, func(evSecret *v1.Secret) {
+		secrets := secretNames(evSecret)
+		for _, name := range names {
+			secrets[name] = struct{}{}
+		}
+		setSecretNames(evSecret, secrets)
 	})
 }
 
@@ -64,20 +107,239 @@ func EnvironmentSet(ctx context.Context, cluster *kubernetes.Cluster, appRef mod
 // update it to the new settings. The function will __not__ wait on
 // this to complete.
 func EnvironmentUnset(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, varName string) error {
-	return envUpdate(ctx, cluster, appRef, func(evSecret *v1.Secret) {
+	return envUpdate(ctx, cluster, appRef, envLoad, func(evSecret *v1.Secret) {
+		delete(evSecret.Data, varName)
+
+		secrets := secretNames(evSecret)
+		if _, ok := secrets[varName]; ok {
+			delete(secrets, varName)
+			setSecretNames(evSecret, secrets)
+		}
+	})
+}
+
+// MaskSecrets returns a copy of the environment where the values of the variables marked
+// as secret are replaced with models.EnvMaskedValue.
+func MaskSecrets(environment models.EnvVariableMap, secrets map[string]struct{}) models.EnvVariableMap {
+	result := models.EnvVariableMap{}
+	for name, value := range environment {
+		if _, ok := secrets[name]; ok {
+			value = models.EnvMaskedValue
+		}
+		result[name] = value
+	}
+	return result
+}
+
+// MaskAppSecrets masks the values of the environment variables of the application which
+// are marked as secret, see MaskSecrets. A missing environment secret is not created.
+func MaskAppSecrets(ctx context.Context, cluster *kubernetes.Cluster, app *models.App) error {
+	evSecret, err := loadSecret(ctx, cluster, app.Meta.Namespace, app.Meta.MakeEnvSecretName())
+	if err != nil {
+		return err
+	}
+
+	app.Configuration.Environment = MaskSecrets(app.Configuration.Environment, secretNames(evSecret))
+	return nil
+}
+
+// EnvironmentFrom returns the environment variables which are set on the named
+// application by users as references to keys of configurations.
+func EnvironmentFrom(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (models.EnvReferenceMap, error) {
+	evSecret, err := envFromLoad(ctx, cluster, appRef)
+	if err != nil {
+		return nil, err
+	}
+
+	result := models.EnvReferenceMap{}
+	for name, value := range evSecret.Data {
+		var ref models.EnvConfigurationRef
+		if err := json.Unmarshal(value, &ref); err != nil {
+			return nil, errors.Wrapf(err, "bad configuration reference for environment variable %s", name)
+		}
+		result[name] = ref
+	}
+
+	return result, nil
+}
+
+// EnvironmentFromSet adds or modifies the specified configuration references for the
+// named application. See EnvironmentSet for the handling of the workload.
+func EnvironmentFromSet(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, references models.EnvReferenceMap, replace bool) error {
+	encoded := map[string][]byte{}
+	for name, ref := range references {
+		value, err := json.Marshal(ref)
+		if err != nil {
+			return err
+		}
+		encoded[name] = value
+	}
+
+	return envUpdate(ctx, cluster, appRef, envFromLoad, func(evSecret *v1.Secret) {
+		// Replacement is adding to a clear structure
+		if replace {
+			evSecret.Data = make(map[string][]byte)
+		}
+		for name, value := range encoded {
+			evSecret.Data[name] = value
+		}
+	})
+}
+
+// EnvironmentFromUnset removes the specified configuration reference from the named
+// application. See EnvironmentUnset for the handling of the workload.
+func EnvironmentFromUnset(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, varName string) error {
+	return envUpdate(ctx, cluster, appRef, envFromLoad, func(evSecret *v1.Secret) {
+		delete(evSecret.Data, varName)
+	})
+}
+
+// StagingEnvironment returns the environment variables which are set on the named
+// application by users for staging only. They are given to the builder, and not to the
+// running application. A missing secret is an empty environment, it is not created.
+func StagingEnvironment(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (models.EnvVariableMap, error) {
+	evSecret, err := loadSecret(ctx, cluster, appRef.Namespace, appRef.MakeStagingEnvSecretName())
+	if err != nil {
+		return nil, err
+	}
+
+	return secretEnvironment(evSecret), nil
+}
+
+// BuildEnvironment returns the environment given to the builder of the named
+// application. This is the staging environment. Without one the buildpack settings of
+// the runtime environment, i.e. the `BP_` variables, are used instead, as the builds of
+// applications predating the staging environment received the runtime environment.
+func BuildEnvironment(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (models.EnvVariableMap, error) {
+	environment, err := StagingEnvironment(ctx, cluster, appRef)
+	if err != nil {
+		return nil, err
+	}
+	if len(environment) > 0 {
+		return environment, nil
+	}
+
+	evSecret, err := loadSecret(ctx, cluster, appRef.Namespace, appRef.MakeEnvSecretName())
+	if err != nil {
+		return nil, err
+	}
+
+	return buildpackSettings(secretEnvironment(evSecret)), nil
+}
+
+// buildpackSettings returns the variables of the environment configuring buildpacks.
+func buildpackSettings(environment models.EnvVariableMap) models.EnvVariableMap {
+	result := models.EnvVariableMap{}
+	for name, value := range environment {
+		if strings.HasPrefix(name, "BP_") {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+// secretEnvironment returns the variables stored in the secret.
+func secretEnvironment(evSecret *v1.Secret) models.EnvVariableMap {
+	result := models.EnvVariableMap{}
+	for name, value := range evSecret.Data {
+		result[name] = string(value)
+	}
+	return result
+}
+
+// StagingEnvironmentSet adds or modifies the specified staging environment variables for
+// the named application. The change takes effect with the next staging of the
+// application. The workload is not touched.
+func StagingEnvironmentSet(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, assignments models.EnvVariableMap, replace bool) error {
+	return envUpdate(ctx, cluster, appRef, stagingEnvLoad, func(evSecret *v1.Secret) {
+		// Replacement is adding to a clear structure
+		if replace {
+			evSecret.Data = make(map[string][]byte)
+		}
+		for name, value := range assignments {
+			evSecret.Data[name] = []byte(value)
+		}
+	})
+}
+
+// StagingEnvironmentUnset removes the specified variable from the staging environment of
+// the named application. The change takes effect with the next staging of the
+// application. The workload is not touched.
+func StagingEnvironmentUnset(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, varName string) error {
+	return envUpdate(ctx, cluster, appRef, stagingEnvLoad, func(evSecret *v1.Secret) {
 		delete(evSecret.Data, varName)
 	})
 }
 
+// ValidateEnvironmentFrom checks that the configuration references point to existing
+// keys of configurations in the set of bound configurations. All bad references are
+// reported.
+func ValidateEnvironmentFrom(ctx context.Context, cluster *kubernetes.Cluster, namespace string,
+	references models.EnvReferenceMap, bound []string) apierror.APIErrors {
+
+	boundSet := map[string]struct{}{}
+	for _, name := range bound {
+		boundSet[name] = struct{}{}
+	}
+
+	issues := []apierror.APIError{}
+	for _, name := range references.Names() {
+		ref := references[name]
+
+		if _, ok := boundSet[ref.Configuration]; !ok {
+			issues = append(issues, apierror.NewBadRequest(
+				fmt.Sprintf("environment variable '%s' references configuration '%s', which is not bound", name, ref.Configuration)))
+			continue
+		}
+
+		configuration, err := configurations.Lookup(ctx, cluster, namespace, ref.Configuration)
+		if err != nil {
+			if err.Error() == "configuration not found" {
+				issues = append(issues, apierror.ConfigurationIsNotKnown(ref.Configuration))
+				continue
+			}
+			return apierror.InternalError(err)
+		}
+
+		secret, err := configuration.GetSecret(ctx)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+		if _, ok := secret.Data[ref.Key]; !ok {
+			issues = append(issues, apierror.NewBadRequest(
+				fmt.Sprintf("environment variable '%s' references key '%s', which configuration '%s' does not have", name, ref.Key, ref.Configuration)))
+		}
+	}
+
+	if len(issues) > 0 {
+		return apierror.NewMultiError(issues)
+	}
+	return nil
+}
+
+// ReferencingEnvironment returns the sorted names of the environment variables referencing
+// the configuration.
+func ReferencingEnvironment(references models.EnvReferenceMap, configurationName string) []string {
+	result := []string{}
+	for _, name := range references.Names() {
+		if references[name].Configuration == configurationName {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
 // envUpdate is the helper for the public function encapsulating the
 // read/modify/write cycle necessary to update the application's kube
 // resource holding the application's environment, and the logic to
 // restart the workload so that it may gain the changed settings.
-func envUpdate(ctx context.Context, cluster *kubernetes.Cluster,
-	appRef models.AppRef, modifyEnvironment func(*v1.Secret)) error {
+// The loader selects the resource, i.e. literal values or configuration references.
+func envUpdate(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef,
+	load func(context.Context, *kubernetes.Cluster, models.AppRef) (*v1.Secret, error),
+	modifyEnvironment func(*v1.Secret)) error {
 
 	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		evSecret, err := envLoad(ctx, cluster, appRef)
+		evSecret, err := load(ctx, cluster, appRef)
 		if err != nil {
 			return err
 		}
@@ -101,3 +363,53 @@ func envLoad(ctx context.Context, cluster *kubernetes.Cluster, appRef models.App
 	secretName := appRef.MakeEnvSecretName()
 	return loadOrCreateSecret(ctx, cluster, appRef, secretName, "environment")
 }
+
+// envFromLoad locates and returns the kube secret storing the referenced
+// application's configuration references. If necessary it creates that secret.
+func envFromLoad(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (*v1.Secret, error) {
+	secretName := appRef.MakeEnvFromSecretName()
+	return loadOrCreateSecret(ctx, cluster, appRef, secretName, "environment-from")
+}
+
+// stagingEnvLoad locates and returns the kube secret storing the referenced
+// application's staging environment. If necessary it creates that secret.
+func stagingEnvLoad(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (*v1.Secret, error) {
+	secretName := appRef.MakeStagingEnvSecretName()
+	return loadOrCreateSecret(ctx, cluster, appRef, secretName, "staging-environment")
+}
+
+// secretNames decodes the set of secret variable names from the annotation of the
+// environment secret. A bad annotation is treated as an empty set.
+func secretNames(evSecret *v1.Secret) map[string]struct{} {
+	result := map[string]struct{}{}
+
+	var names []string
+	if err := json.Unmarshal([]byte(evSecret.Annotations[EpinioSecretEnvAnnotation]), &names); err != nil {
+		return result
+	}
+	for _, name := range names {
+		result[name] = struct{}{}
+	}
+	return result
+}
+
+// setSecretNames encodes the set of secret variable names into the annotation of the
+// environment secret.
+func setSecretNames(evSecret *v1.Secret, secrets map[string]struct{}) {
+	if len(secrets) == 0 {
+		delete(evSecret.Annotations, EpinioSecretEnvAnnotation)
+		return
+	}
+
+	names := []string{}
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	encoded, _ := json.Marshal(names)
+	if evSecret.Annotations == nil {
+		evSecret.Annotations = map[string]string{}
+	}
+	evSecret.Annotations[EpinioSecretEnvAnnotation] = string(encoded)
+}
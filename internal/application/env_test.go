@@ -0,0 +1,54 @@
+package application
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Environment sourced from configurations", func() {
+	references := models.EnvReferenceMap{
+		"DATABASE_URL":  {Configuration: "mydb", Key: "url"},
+		"DATABASE_USER": {Configuration: "mydb", Key: "username"},
+		"CACHE_HOST":    {Configuration: "cache", Key: "host"},
+	}
+
+	It("finds the variables referencing a configuration", func() {
+		Expect(ReferencingEnvironment(references, "mydb")).To(Equal([]string{"DATABASE_URL", "DATABASE_USER"}))
+		Expect(ReferencingEnvironment(references, "other")).To(BeEmpty())
+	})
+
+	It("renders the references as secret key references", func() {
+		Expect(references.Assignments()).To(Equal([]string{
+			`{"name":"CACHE_HOST","valueFrom":{"secretKeyRef":{"name":"cache","key":"host"}}}`,
+			`{"name":"DATABASE_URL","valueFrom":{"secretKeyRef":{"name":"mydb","key":"url"}}}`,
+			`{"name":"DATABASE_USER","valueFrom":{"secretKeyRef":{"name":"mydb","key":"username"}}}`,
+		}))
+	})
+})
+
+var _ = Describe("Build environment", func() {
+	It("takes the buildpack settings from the runtime environment", func() {
+		Expect(buildpackSettings(models.EnvVariableMap{
+			"BP_JVM_VERSION": "17",
+			"BPL_DEBUG":      "true",
+			"DATABASE_URL":   "postgres://db",
+		})).To(Equal(models.EnvVariableMap{"BP_JVM_VERSION": "17"}))
+	})
+})
+
+var _ = Describe("Secret environment variables", func() {
+	It("masks the values of the secret variables", func() {
+		Expect(MaskSecrets(models.EnvVariableMap{
+			"DATABASE_PASSWORD": "hunter2",
+			"PORT":              "8080",
+		}, map[string]struct{}{
+			"DATABASE_PASSWORD": {},
+			"GONE":              {},
+		})).To(Equal(models.EnvVariableMap{
+			"DATABASE_PASSWORD": models.EnvMaskedValue,
+			"PORT":              "8080",
+		}))
+	})
+})
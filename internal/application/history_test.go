@@ -0,0 +1,32 @@
+package application
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Stage history", func() {
+	It("records the stage IDs in order", func() {
+		app := &unstructured.Unstructured{}
+		Expect(StageHistory(app)).To(BeEmpty())
+
+		AddStageHistory(app, "b2")
+		AddStageHistory(app, "a1")
+		Expect(StageHistory(app)).To(Equal([]string{"b2", "a1"}))
+	})
+
+	It("drops the oldest stage IDs beyond its size", func() {
+		app := &unstructured.Unstructured{}
+		for i := 0; i < maxStageHistory+2; i++ {
+			AddStageHistory(app, fmt.Sprintf("s%d", i))
+		}
+
+		history := StageHistory(app)
+		Expect(history).To(HaveLen(maxStageHistory))
+		Expect(history[0]).To(Equal("s2"))
+		Expect(history[maxStageHistory-1]).To(Equal(fmt.Sprintf("s%d", maxStageHistory+1)))
+	})
+})
@@ -12,6 +12,19 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 )
 
+// OriginGitAnnotation is the annotation of the application resource holding the import
+// settings of a git origin, and the commit imported, as JSON. The schema of the origin in
+// the resource's spec has only the repository and revision, further fields are pruned.
+const OriginGitAnnotation = "epinio.io/origin-git"
+
+// originGit is the content of the OriginGitAnnotation.
+type originGit struct {
+	Commit       string `json:"commit,omitempty"`
+	Credentials  string `json:"credentials,omitempty"`
+	Subdirectory string `json:"subdirectory,omitempty"`
+	Submodules   bool   `json:"submodules,omitempty"`
+}
+
 // Origin returns the origin of the specified application. The data is
 // constructed from the stored information on the Application Custom
 // Resource.
@@ -86,6 +99,18 @@ func Origin(app *unstructured.Unstructured) (models.ApplicationOrigin, error) {
 			result.Git.Revision = revision
 		}
 
+		// And the commit recorded at import, and the optional import settings, if any.
+		if settings := app.GetAnnotations()[OriginGitAnnotation]; settings != "" {
+			var git originGit
+			if err := json.Unmarshal([]byte(settings), &git); err != nil {
+				return result, errors.Wrap(err, "bad git origin settings")
+			}
+			result.Git.Commit = git.Commit
+			result.Git.Credentials = git.Credentials
+			result.Git.Subdirectory = git.Subdirectory
+			result.Git.Submodules = git.Submodules
+		}
+
 		result.Kind = models.OriginGit
 		result.Git.URL = repository
 		return result, nil
@@ -98,7 +123,8 @@ func Origin(app *unstructured.Unstructured) (models.ApplicationOrigin, error) {
 	return result, nil
 }
 
-// SetOrigin patches the new origin information into the specified application.
+// SetOrigin patches the new origin information into the specified application. The
+// import settings of a git origin go into the OriginGitAnnotation.
 func SetOrigin(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef, origin models.ApplicationOrigin) error {
 	client, err := cluster.ClientApp()
 	if err != nil {
@@ -115,11 +141,33 @@ func SetOrigin(ctx context.Context, cluster *kubernetes.Cluster, app models.AppR
 		types.JSONPatchType,
 		patch,
 		metav1.PatchOptions{})
+	if err != nil {
+		return err
+	}
+
+	patch, err = buildAnnotationPatch(origin)
+	if err != nil {
+		return errors.Wrap(err, "error building annotation patch")
+	}
+
+	_, err = client.Namespace(app.Namespace).Patch(ctx,
+		app.Name,
+		types.MergePatchType,
+		patch,
+		metav1.PatchOptions{})
 
 	return err
 }
 
 func buildBodyPatch(origin models.ApplicationOrigin) ([]byte, error) {
+	// The spec keeps only the repository and revision of a git origin.
+	if origin.Git != nil {
+		origin.Git = &models.GitRef{
+			URL:      origin.Git.URL,
+			Revision: origin.Git.Revision,
+		}
+	}
+
 	operations := []PatchOperation{{
 		Op:    "replace",
 		Path:  "/spec/origin",
@@ -129,6 +177,36 @@ func buildBodyPatch(origin models.ApplicationOrigin) ([]byte, error) {
 	return json.Marshal(operations)
 }
 
+// buildAnnotationPatch returns the merge patch setting the OriginGitAnnotation to the
+// import settings of a git origin, or removing it for other origins.
+func buildAnnotationPatch(origin models.ApplicationOrigin) ([]byte, error) {
+	var settings interface{} // nil removes the annotation
+
+	if origin.Kind == models.OriginGit && origin.Git != nil {
+		git := originGit{
+			Commit:       origin.Git.Commit,
+			Credentials:  origin.Git.Credentials,
+			Subdirectory: origin.Git.Subdirectory,
+			Submodules:   origin.Git.Submodules,
+		}
+		if git != (originGit{}) {
+			encoded, err := json.Marshal(git)
+			if err != nil {
+				return nil, err
+			}
+			settings = string(encoded)
+		}
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				OriginGitAnnotation: settings,
+			},
+		},
+	})
+}
+
 type PatchOperation struct {
 	Op    string                   `json:"op"`
 	Path  string                   `json:"path"`
@@ -2,6 +2,7 @@ package application
 
 import (
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -95,6 +96,79 @@ var _ = Describe("Build body for SetOrigin PATCH", func() {
 					Expect(string(body)).To(MatchJSON(`[{"op":"replace","path":"/spec/origin","value":{"Kind":2,"git":{"repository":"git@repo","revision":"revision_1"}}}]`))
 				})
 			})
+
+			Context("with import settings", func() {
+				var gitOriginImport models.ApplicationOrigin
+
+				BeforeEach(func() {
+					gitOriginImport = models.ApplicationOrigin{
+						Kind: models.OriginGit,
+						Git: &models.GitRef{
+							URL:          "git@repo",
+							Revision:     "revision_1",
+							Commit:       "abc",
+							Credentials:  "deploy-key",
+							Subdirectory: "services/api",
+							Submodules:   true,
+						},
+					}
+				})
+
+				It("keeps the import settings out of the spec", func() {
+					body, err := buildBodyPatch(gitOriginImport)
+
+					Expect(err).ToNot(HaveOccurred())
+					Expect(string(body)).To(MatchJSON(`[{"op":"replace","path":"/spec/origin","value":{"Kind":2,"git":{"repository":"git@repo","revision":"revision_1"}}}]`))
+					Expect(gitOriginImport.Git.Credentials).To(Equal("deploy-key"))
+				})
+
+				It("puts the import settings into the annotation", func() {
+					body, err := buildAnnotationPatch(gitOriginImport)
+
+					Expect(err).ToNot(HaveOccurred())
+					Expect(string(body)).To(MatchJSON(`{"metadata":{"annotations":{"epinio.io/origin-git":` +
+						`"{\"commit\":\"abc\",\"credentials\":\"deploy-key\",\"subdirectory\":\"services/api\",\"submodules\":true}"}}}`))
+				})
+			})
+
+			It("removes the annotation without import settings", func() {
+				body, err := buildAnnotationPatch(gitOriginRev)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(body)).To(MatchJSON(`{"metadata":{"annotations":{"epinio.io/origin-git":null}}}`))
+			})
 		})
 	})
 })
+
+var _ = Describe("Origin", func() {
+	It("reads the import settings of a git origin from the annotation", func() {
+		app := &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]interface{}{
+					OriginGitAnnotation: `{"commit":"abc","credentials":"deploy-key","subdirectory":"services/api","submodules":true}`,
+				},
+			},
+			"spec": map[string]interface{}{
+				"origin": map[string]interface{}{
+					"git": map[string]interface{}{
+						"repository": "git@repo",
+						"revision":   "revision_1",
+					},
+				},
+			},
+		}}
+
+		origin, err := Origin(app)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(origin.Kind).To(Equal(models.OriginGit))
+		Expect(*origin.Git).To(Equal(models.GitRef{
+			URL:          "git@repo",
+			Revision:     "revision_1",
+			Commit:       "abc",
+			Credentials:  "deploy-key",
+			Subdirectory: "services/api",
+			Submodules:   true,
+		}))
+	})
+})
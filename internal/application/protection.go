@@ -0,0 +1,33 @@
+package application
+
+import (
+	"context"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/namespaces"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Protected returns true if the application resource is protected against deletion.
+func Protected(app *unstructured.Unstructured) bool {
+	return namespaces.IsProtected(app.GetAnnotations())
+}
+
+// SetProtected sets or clears the protection of the application against deletion.
+func SetProtected(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, protected bool) error {
+	client, err := cluster.ClientApp()
+	if err != nil {
+		return err
+	}
+
+	patch, err := namespaces.ProtectionPatch(protected)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Namespace(appRef.Namespace).Patch(ctx, appRef.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
@@ -26,6 +26,19 @@ func loadOrCreateSecret(ctx context.Context, cluster *kubernetes.Cluster, appRef
 	return secret, nil
 }
 
+// loadSecret returns the named secret. A missing secret is returned as an empty secret,
+// without creating it.
+func loadSecret(ctx context.Context, cluster *kubernetes.Cluster, namespace, secretName string) (*v1.Secret, error) {
+	secret, err := cluster.GetSecret(ctx, namespace, secretName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return &v1.Secret{}, nil
+		}
+		return nil, errors.Wrapf(err, "error getting secret %s", secretName)
+	}
+	return secret, nil
+}
+
 // createSecret will create the secret in the cluster
 func createSecret(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, secretName, areaLabel string) (*v1.Secret, error) {
 	app, err := Get(ctx, cluster, appRef)
@@ -0,0 +1,57 @@
+package application
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// StagingAnnotation is the annotation of the application resource holding the settings
+// of its last staging, as JSON. The schema of the resource's spec has no place for them,
+// they would be pruned.
+const StagingAnnotation = "epinio.io/staging"
+
+// StagingSettings are the settings of the last staging of an application. The next
+// staging uses them when it is not given any. Environment is the hash of the build
+// environment, if there was one.
+type StagingSettings struct {
+	Strategy    string            `json:"strategy"`
+	Dockerfile  string            `json:"dockerfile,omitempty"`
+	Target      string            `json:"target,omitempty"`
+	BuildArgs   map[string]string `json:"buildargs,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+}
+
+// Staging returns the settings of the last staging of the application. They are empty if
+// the application was not staged yet.
+func Staging(app *unstructured.Unstructured) (StagingSettings, error) {
+	settings := StagingSettings{}
+
+	encoded := app.GetAnnotations()[StagingAnnotation]
+	if encoded == "" {
+		return settings, nil
+	}
+
+	if err := json.Unmarshal([]byte(encoded), &settings); err != nil {
+		return settings, errors.Wrap(err, "bad staging settings")
+	}
+	return settings, nil
+}
+
+// SetStaging records the settings of a staging in the application resource. The resource
+// is not saved.
+func SetStaging(app *unstructured.Unstructured, settings StagingSettings) error {
+	encoded, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	annotations := app.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[StagingAnnotation] = string(encoded)
+	app.SetAnnotations(annotations)
+	return nil
+}
@@ -0,0 +1,34 @@
+package application
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Staging settings", func() {
+	It("are empty for an application which was not staged", func() {
+		settings, err := Staging(&unstructured.Unstructured{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings).To(Equal(StagingSettings{}))
+	})
+
+	It("are recorded in an annotation", func() {
+		app := &unstructured.Unstructured{}
+		app.SetAnnotations(map[string]string{StageHistoryAnnotation: "s1"})
+
+		recorded := StagingSettings{
+			Strategy:    "dockerfile",
+			Dockerfile:  "build/Dockerfile",
+			Target:      "runtime",
+			BuildArgs:   map[string]string{"VERSION": "1.2"},
+			Environment: "abc",
+		}
+		Expect(SetStaging(app, recorded)).To(Succeed())
+		Expect(app.GetAnnotations()).To(HaveKey(StageHistoryAnnotation))
+
+		settings, err := Staging(app)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings).To(Equal(recorded))
+	})
+})
@@ -0,0 +1,67 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// webhookSecretKey is the key of the signing secret in the kube secret of the webhook.
+const webhookSecretKey = "secret"
+
+// WebhookSecret returns the signing secret of the git webhook of the referenced
+// application. The boolean result is false if the webhook is not enabled.
+func WebhookSecret(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (string, bool, error) {
+	secret, err := cluster.GetSecret(ctx, appRef.Namespace, appRef.MakeWebhookSecretName())
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	value, ok := secret.Data[webhookSecretKey]
+	if !ok || len(value) == 0 {
+		return "", false, nil
+	}
+
+	return string(value), true, nil
+}
+
+// WebhookSecretRotate enables the git webhook of the referenced application with a new,
+// random signing secret, replacing any previous secret. It returns the new secret.
+func WebhookSecretRotate(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (string, error) {
+	randBytes := make([]byte, 32)
+	if _, err := rand.Read(randBytes); err != nil {
+		return "", errors.Wrap(err, "generating the webhook secret")
+	}
+	value := hex.EncodeToString(randBytes)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := loadOrCreateSecret(ctx, cluster, appRef, appRef.MakeWebhookSecretName(), "webhook")
+		if err != nil {
+			return err
+		}
+
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		secret.Data[webhookSecretKey] = []byte(value)
+
+		_, err = cluster.Kubectl.CoreV1().Secrets(appRef.Namespace).Update(
+			ctx, secret, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
@@ -0,0 +1,304 @@
+// Package appmetrics implements the API server's collector of historical application
+// resource usage. It periodically samples the metrics-server for the pods of all
+// applications and keeps the results in bounded, in-memory ring buffers, one per
+// application.
+package appmetrics
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	resource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metrics "k8s.io/metrics/pkg/client/clientset/versioned"
+)
+
+// Sample is a single measurement of the resources used by all the instances of an
+// application at a point in time.
+type Sample struct {
+	Time        time.Time
+	MilliCPUs   int64
+	MemoryBytes int64
+	Instances   int
+}
+
+// ring is a fixed-capacity buffer of samples. When full, the oldest sample is
+// overwritten by the newest.
+type ring struct {
+	samples []Sample
+	next    int
+	full    bool
+}
+
+func newRing(capacity int) *ring {
+	return &ring{samples: make([]Sample, capacity)}
+}
+
+func (r *ring) add(s Sample) {
+	r.samples[r.next] = s
+	r.next = (r.next + 1) % len(r.samples)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// ordered returns the samples held by the ring, oldest first.
+func (r *ring) ordered() []Sample {
+	if !r.full {
+		return append([]Sample{}, r.samples[:r.next]...)
+	}
+	return append(append([]Sample{}, r.samples[r.next:]...), r.samples[:r.next]...)
+}
+
+// last returns the newest sample in the ring, if any.
+func (r *ring) last() (Sample, bool) {
+	if !r.full && r.next == 0 {
+		return Sample{}, false
+	}
+	return r.samples[(r.next-1+len(r.samples))%len(r.samples)], true
+}
+
+// Collector keeps the sampled history of all applications.
+type Collector struct {
+	mu       sync.RWMutex
+	interval time.Duration
+	history  time.Duration
+	capacity int
+	rings    map[models.AppRef]*ring
+}
+
+// collector is the memo of the collector run by the API server. See Start.
+var collector *Collector
+
+// NewCollector returns a collector sampling every interval, and remembering samples
+// for the specified history.
+func NewCollector(interval, history time.Duration) *Collector {
+	capacity := int(history / interval)
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &Collector{
+		interval: interval,
+		history:  history,
+		capacity: capacity,
+		rings:    map[models.AppRef]*ring{},
+	}
+}
+
+// Start creates the collector of the API server and runs it in the background until the
+// context is done. A non-positive interval disables collection.
+func Start(ctx context.Context, logger logr.Logger, interval, history time.Duration) {
+	if interval <= 0 {
+		logger.Info("application metrics collection disabled")
+		return
+	}
+
+	collector = NewCollector(interval, history)
+	go collector.Run(ctx, logger)
+}
+
+// Running returns the collector started by Start, or nil if collection is disabled.
+func Running() *Collector {
+	return collector
+}
+
+// Run samples the application metrics every interval, until the context is done.
+func (c *Collector) Run(ctx context.Context, logger logr.Logger) {
+	logger = logger.WithName("AppMetrics")
+	logger.Info("start", "interval", c.interval, "history", c.history)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.collect(ctx); err != nil {
+			logger.V(1).Info("sampling failed", "error", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("stop")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// collect retrieves the current metrics of all application pods in the cluster and
+// records them per application.
+func (c *Collector) collect(ctx context.Context) error {
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	metricsClient, err := metrics.NewForConfig(cluster.RestConfig)
+	if err != nil {
+		return err
+	}
+
+	podMetrics, err := metricsClient.MetricsV1beta1().PodMetricses("").List(ctx, metav1.ListOptions{
+		LabelSelector: "app.kubernetes.io/component=application",
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing pod metrics")
+	}
+
+	now := time.Now()
+	samples := map[models.AppRef]*Sample{}
+
+	for _, podMetric := range podMetrics.Items {
+		app := models.NewAppRef(podMetric.Labels["app.kubernetes.io/name"],
+			podMetric.Labels["app.kubernetes.io/part-of"])
+		if app.Name == "" || app.Namespace == "" {
+			continue
+		}
+
+		cpuUsage := resource.NewQuantity(0, resource.DecimalSI)
+		memUsage := resource.NewQuantity(0, resource.BinarySI)
+		for _, container := range podMetric.Containers {
+			cpuUsage.Add(*container.Usage.Cpu())
+			memUsage.Add(*container.Usage.Memory())
+		}
+
+		sample, ok := samples[app]
+		if !ok {
+			sample = &Sample{Time: now}
+			samples[app] = sample
+		}
+
+		// cpu * 1000 -> milliCPUs (rounded)
+		sample.MilliCPUs += int64(math.Round(cpuUsage.ToDec().AsApproximateFloat64() * 1000))
+		if mem, ok := memUsage.AsInt64(); ok {
+			sample.MemoryBytes += mem
+		}
+		sample.Instances++
+	}
+
+	for app, sample := range samples {
+		c.Record(app, *sample)
+	}
+
+	c.expire(now)
+	return nil
+}
+
+// Record adds the sample to the history of the referenced application.
+func (c *Collector) Record(app models.AppRef, s Sample) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r, ok := c.rings[app]
+	if !ok {
+		r = newRing(c.capacity)
+		c.rings[app] = r
+	}
+	r.add(s)
+}
+
+// Forget drops the history of the referenced application.
+func (c *Collector) Forget(app models.AppRef) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.rings, app)
+}
+
+// ForgetNamespace drops the history of all applications in the namespace.
+func (c *Collector) ForgetNamespace(namespace string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for app := range c.rings {
+		if app.Namespace == namespace {
+			delete(c.rings, app)
+		}
+	}
+}
+
+// expire drops the history of all applications which were not seen for longer than the
+// history of the collector. These are deleted, or scaled to zero.
+func (c *Collector) expire(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for app, r := range c.rings {
+		last, ok := r.last()
+		if !ok || now.Sub(last.Time) > c.history {
+			delete(c.rings, app)
+		}
+	}
+}
+
+// Query returns the history of the referenced application reaching back for the
+// specified duration. The samples are aggregated into buckets of size step, each
+// bucket holding the averages of the samples falling into it. Buckets without samples
+// are left out.
+func (c *Collector) Query(app models.AppRef, since, step time.Duration, now time.Time) []Sample {
+	c.mu.RLock()
+	r, ok := c.rings[app]
+	var samples []Sample
+	if ok {
+		samples = r.ordered()
+	}
+	c.mu.RUnlock()
+
+	result := []Sample{}
+	if step <= 0 {
+		return result
+	}
+
+	start := now.Add(-since).Truncate(step)
+
+	var (
+		bucket    time.Time
+		count     int64
+		aggregate Sample
+	)
+
+	flush := func() {
+		if count == 0 {
+			return
+		}
+		result = append(result, Sample{
+			Time:        bucket,
+			MilliCPUs:   aggregate.MilliCPUs / count,
+			MemoryBytes: aggregate.MemoryBytes / count,
+			Instances:   int(int64(aggregate.Instances) / count),
+		})
+		count = 0
+		aggregate = Sample{}
+	}
+
+	for _, s := range samples {
+		if s.Time.Before(start) || s.Time.After(now) {
+			continue
+		}
+
+		b := s.Time.Truncate(step)
+		if !b.Equal(bucket) {
+			flush()
+			bucket = b
+		}
+
+		aggregate.MilliCPUs += s.MilliCPUs
+		aggregate.MemoryBytes += s.MemoryBytes
+		aggregate.Instances += s.Instances
+		count++
+	}
+	flush()
+
+	return result
+}
+
+// History returns the maximal duration the collector remembers samples for.
+func (c *Collector) History() time.Duration {
+	return c.history
+}
@@ -0,0 +1,102 @@
+package appmetrics_test
+
+import (
+	"time"
+
+	. "github.com/epinio/epinio/internal/appmetrics"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Collector", func() {
+	var (
+		collector *Collector
+		app       models.AppRef
+		now       time.Time
+	)
+
+	BeforeEach(func() {
+		collector = NewCollector(time.Minute, 5*time.Minute)
+		app = models.NewAppRef("app", "workspace")
+		now = time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	})
+
+	record := func(ago time.Duration, cpu, mem int64) {
+		collector.Record(app, Sample{
+			Time:        now.Add(-ago),
+			MilliCPUs:   cpu,
+			MemoryBytes: mem,
+			Instances:   1,
+		})
+	}
+
+	Describe("Query", func() {
+		It("returns nothing for unknown applications", func() {
+			Expect(collector.Query(app, time.Hour, time.Minute, now)).To(BeEmpty())
+		})
+
+		It("returns the samples oldest first", func() {
+			record(3*time.Minute, 10, 100)
+			record(2*time.Minute, 20, 200)
+			record(1*time.Minute, 30, 300)
+
+			samples := collector.Query(app, time.Hour, time.Minute, now)
+			Expect(samples).To(HaveLen(3))
+			Expect(samples[0].MilliCPUs).To(Equal(int64(10)))
+			Expect(samples[2].MilliCPUs).To(Equal(int64(30)))
+		})
+
+		It("keeps only as many samples as the history allows", func() {
+			for i := 10; i > 0; i-- {
+				record(time.Duration(i)*time.Minute, int64(i), 0)
+			}
+
+			samples := collector.Query(app, time.Hour, time.Minute, now)
+			Expect(samples).To(HaveLen(5))
+			Expect(samples[0].MilliCPUs).To(Equal(int64(5)))
+			Expect(samples[4].MilliCPUs).To(Equal(int64(1)))
+		})
+
+		It("averages the samples falling into the same step", func() {
+			record(4*time.Minute, 10, 100)
+			record(3*time.Minute, 30, 300)
+
+			samples := collector.Query(app, time.Hour, 5*time.Minute, now)
+			Expect(samples).To(HaveLen(1))
+			Expect(samples[0].MilliCPUs).To(Equal(int64(20)))
+			Expect(samples[0].MemoryBytes).To(Equal(int64(200)))
+		})
+
+		It("ignores samples older than requested", func() {
+			record(4*time.Minute, 10, 100)
+			record(1*time.Minute, 30, 300)
+
+			samples := collector.Query(app, 2*time.Minute, time.Minute, now)
+			Expect(samples).To(HaveLen(1))
+			Expect(samples[0].MilliCPUs).To(Equal(int64(30)))
+		})
+	})
+
+	Describe("Forget", func() {
+		It("drops the history of the application", func() {
+			record(1*time.Minute, 30, 300)
+			collector.Forget(app)
+
+			Expect(collector.Query(app, time.Hour, time.Minute, now)).To(BeEmpty())
+		})
+	})
+
+	Describe("ForgetNamespace", func() {
+		It("drops the history of the applications in the namespace only", func() {
+			other := models.NewAppRef("app", "elsewhere")
+			record(1*time.Minute, 30, 300)
+			collector.Record(other, Sample{Time: now.Add(-time.Minute), MilliCPUs: 10, Instances: 1})
+			collector.ForgetNamespace("workspace")
+
+			Expect(collector.Query(app, time.Hour, time.Minute, now)).To(BeEmpty())
+			Expect(collector.Query(other, time.Hour, time.Minute, now)).To(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,265 @@
+// Package backup implements the portable archives of namespaces. An archive is a gzipped
+// tar file. Its first entry, backup.json, describes the configurations, services and
+// applications of the namespace. When images are included the entries of the image of
+// each application follow, its blobs first, then its manifest.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/configurations"
+	"github.com/epinio/epinio/internal/registry"
+	"github.com/epinio/epinio/internal/services"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Version is the version of the archive format written by this package.
+const Version = 1
+
+const (
+	manifestEntry = "backup.json"
+	imagesPrefix  = "images/"
+)
+
+// Archive describes the contents of a namespace archive.
+type Archive struct {
+	Version        int             `json:"version"`
+	Namespace      string          `json:"namespace"`
+	CreatedAt      metav1.Time     `json:"createdAt"`
+	Configurations []Configuration `json:"configurations,omitempty"`
+	Services       []Service       `json:"services,omitempty"`
+	Apps           []App           `json:"apps,omitempty"`
+}
+
+// Configuration is a configuration created by a user. The configurations of services
+// are not archived, they are recreated with the services.
+type Configuration struct {
+	Name string            `json:"name"`
+	Data map[string]string `json:"data"`
+}
+
+// Service is a service instance, with the catalog service and values it was created
+// with, and the applications bound to it.
+type Service struct {
+	Name           string   `json:"name"`
+	CatalogService string   `json:"catalogService"`
+	Values         string   `json:"values,omitempty"`
+	BoundApps      []string `json:"boundApps,omitempty"`
+}
+
+// App is an application. Its configuration lists only the configurations created by
+// users, see Service for the others. Image is set when the image of the application is
+// part of the archive.
+type App struct {
+	Name              string                          `json:"name"`
+	Configuration     models.ApplicationUpdateRequest `json:"configuration"`
+	SecretEnvironment []string                        `json:"secretEnvironment,omitempty"`
+	Origin            models.ApplicationOrigin        `json:"origin"`
+	StageID           string                          `json:"stageID,omitempty"`
+	ImageURL          string                          `json:"imageURL,omitempty"`
+	Image             *Image                          `json:"image,omitempty"`
+}
+
+// Image is the image of an application, as found in the Epinio registry.
+type Image struct {
+	Digest    string           `json:"digest"`
+	MediaType string           `json:"mediaType"`
+	Blobs     map[string]int64 `json:"blobs"`
+}
+
+// Capture describes the namespace as an archive. When a registry client is given the
+// images of the applications staged by Epinio are included. Images missing from the
+// registry, or which are not single images, are left out, and the applications are
+// archived without them.
+func Capture(ctx context.Context, cluster *kubernetes.Cluster, namespace string, client *registry.Client, registryNamespace string) (*Archive, error) {
+	archive := &Archive{
+		Version:   Version,
+		Namespace: namespace,
+		CreatedAt: metav1.NewTime(time.Now().UTC()),
+	}
+
+	configurationList, err := configurations.List(ctx, cluster, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing configurations")
+	}
+
+	userConfigurations := map[string]struct{}{}
+	for _, configuration := range configurationList {
+		if configuration.Origin != "" {
+			continue
+		}
+		data, err := configuration.Details(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading configuration %s", configuration.Name)
+		}
+		userConfigurations[configuration.Name] = struct{}{}
+		archive.Configurations = append(archive.Configurations, Configuration{
+			Name: configuration.Name,
+			Data: data,
+		})
+	}
+
+	serviceClient, err := services.NewKubernetesServiceClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+	serviceList, err := serviceClient.ListInNamespace(ctx, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing services")
+	}
+	boundApps, err := application.ServicesBoundAppsNames(ctx, cluster, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing service bindings")
+	}
+
+	for _, service := range serviceList {
+		values, err := serviceClient.Values(ctx, namespace, service.Meta.Name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading the values of service %s", service.Meta.Name)
+		}
+		apps := boundApps[service.Meta.Name]
+		sort.Strings(apps)
+		archive.Services = append(archive.Services, Service{
+			Name:           service.Meta.Name,
+			CatalogService: strings.TrimPrefix(service.CatalogService, "[Missing] "),
+			Values:         values,
+			BoundApps:      apps,
+		})
+	}
+
+	apps, err := application.List(ctx, cluster, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing applications")
+	}
+
+	for _, app := range apps {
+		secrets, err := application.EnvironmentSecretNames(ctx, cluster, app.Meta)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading the environment of application %s", app.Meta.Name)
+		}
+
+		archived := App{
+			Name:          app.Meta.Name,
+			Configuration: app.Configuration,
+			Origin:        app.Origin,
+			StageID:       app.StageID,
+			ImageURL:      app.ImageURL,
+		}
+		archived.Configuration.Configurations = []string{}
+		for _, name := range app.Configuration.Configurations {
+			if _, ok := userConfigurations[name]; ok {
+				archived.Configuration.Configurations = append(archived.Configuration.Configurations, name)
+			}
+		}
+		for name := range secrets {
+			archived.SecretEnvironment = append(archived.SecretEnvironment, name)
+		}
+		sort.Strings(archived.SecretEnvironment)
+
+		if client != nil && app.StageID != "" && app.Origin.Kind != models.OriginContainer {
+			repository := registry.Repository(registryNamespace, namespace, app.Meta.Name)
+			manifest, err := client.Manifest(ctx, repository, app.StageID)
+			if err == nil && manifest.Config != "" {
+				archived.Image = &Image{
+					Digest:    manifest.Digest,
+					MediaType: manifest.MediaType,
+					Blobs:     manifest.Blobs,
+				}
+			}
+		}
+
+		archive.Apps = append(archive.Apps, archived)
+	}
+
+	return archive, nil
+}
+
+// Write writes the archive. The images are copied from the registry of the client, which
+// is only needed when the archive has images.
+func Write(ctx context.Context, w io.Writer, archive *Archive, client *registry.Client, registryNamespace string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	description, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeEntry(tw, manifestEntry, int64(len(description)), bytes.NewReader(description)); err != nil {
+		return err
+	}
+
+	for _, app := range archive.Apps {
+		if app.Image == nil {
+			continue
+		}
+		repository := registry.Repository(registryNamespace, archive.Namespace, app.Name)
+
+		digests := make([]string, 0, len(app.Image.Blobs))
+		for digest := range app.Image.Blobs {
+			digests = append(digests, digest)
+		}
+		sort.Strings(digests)
+
+		for _, digest := range digests {
+			blob, err := client.Blob(ctx, repository, digest)
+			if err != nil {
+				return err
+			}
+			err = writeEntry(tw, blobEntry(app.Name, digest), app.Image.Blobs[digest], blob)
+			blob.Close()
+			if err != nil {
+				return err
+			}
+		}
+
+		manifest, err := client.Manifest(ctx, repository, app.Image.Digest)
+		if err != nil {
+			return err
+		}
+		err = writeEntry(tw, manifestOf(app.Name), int64(len(manifest.Content)), bytes.NewReader(manifest.Content))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeEntry(tw *tar.Writer, name string, size int64, content io.Reader) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0600,
+		Size:     size,
+		Typeflag: tar.TypeReg,
+		ModTime:  time.Now(),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "writing header of %s", name)
+	}
+	_, err = io.Copy(tw, content)
+	return errors.Wrapf(err, "writing %s", name)
+}
+
+func blobEntry(app, digest string) string {
+	return fmt.Sprintf("%s%s/blobs/%s", imagesPrefix, app, digest)
+}
+
+func manifestOf(app string) string {
+	return fmt.Sprintf("%s%s/manifest.json", imagesPrefix, app)
+}
@@ -0,0 +1,164 @@
+package backup_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/epinio/epinio/internal/backup"
+	"github.com/epinio/epinio/internal/registry"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Archive", func() {
+	var (
+		server    *httptest.Server
+		client    *registry.Client
+		uploads   map[string]string
+		manifests map[string]string
+		archive   *backup.Archive
+	)
+
+	const manifest = `{"config":{"digest":"sha256:c","size":6},"layers":[{"digest":"sha256:known","size":5}]}`
+
+	BeforeEach(func() {
+		uploads = map[string]string{}
+		manifests = map[string]string{}
+
+		mux := http.NewServeMux()
+		server = httptest.NewServer(mux)
+
+		var err error
+		client, err = registry.NewClient(registry.RegistryCredentials{URL: server.URL}, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		mux.HandleFunc("/v2/apps/ns-app/manifests/sha256:m", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+			w.Header().Set("Docker-Content-Digest", "sha256:m")
+			fmt.Fprint(w, manifest)
+		})
+		mux.HandleFunc("/v2/apps/ns-app/blobs/", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, map[string]string{
+				"/v2/apps/ns-app/blobs/sha256:c":     "config",
+				"/v2/apps/ns-app/blobs/sha256:known": "layer",
+			}[r.URL.Path])
+		})
+		mux.HandleFunc("/v2/apps/restored-app/blobs/uploads/", func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost:
+				w.Header().Set("Location", server.URL+"/v2/apps/restored-app/blobs/uploads/u1")
+				w.WriteHeader(http.StatusAccepted)
+			case http.MethodPut:
+				data, err := io.ReadAll(r.Body)
+				Expect(err).ToNot(HaveOccurred())
+				uploads[r.URL.Query().Get("digest")] = string(data)
+				w.WriteHeader(http.StatusCreated)
+			}
+		})
+		mux.HandleFunc("/v2/apps/restored-app/blobs/", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v2/apps/restored-app/blobs/sha256:known" {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+		mux.HandleFunc("/v2/apps/restored-app/manifests/", func(w http.ResponseWriter, r *http.Request) {
+			data, err := io.ReadAll(r.Body)
+			Expect(err).ToNot(HaveOccurred())
+			manifests[r.URL.Path] = r.Header.Get("Content-Type") + " " + string(data)
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		instances := int32(2)
+		archive = &backup.Archive{
+			Version:   backup.Version,
+			Namespace: "ns",
+			Configurations: []backup.Configuration{
+				{Name: "settings", Data: map[string]string{"user": "admin"}},
+			},
+			Services: []backup.Service{
+				{Name: "db", CatalogService: "mysql-dev", Values: "auth:\n  database: app\n", BoundApps: []string{"app"}},
+			},
+			Apps: []backup.App{
+				{
+					Name: "app",
+					Configuration: models.ApplicationUpdateRequest{
+						Instances:      &instances,
+						Configurations: []string{"settings"},
+						Environment:    models.EnvVariableMap{"TOKEN": "t0k3n"},
+						Routes:         []string{"app.example.com"},
+					},
+					SecretEnvironment: []string{"TOKEN"},
+					StageID:           "s1",
+					ImageURL:          "registry.example.com/apps/ns-app:s1",
+					Image: &backup.Image{
+						Digest:    "sha256:m",
+						MediaType: "application/vnd.oci.image.manifest.v1+json",
+						Blobs:     map[string]int64{"sha256:c": 6, "sha256:known": 5},
+					},
+				},
+				{
+					Name:     "web",
+					Origin:   models.ApplicationOrigin{Kind: models.OriginContainer, Container: "nginx"},
+					ImageURL: "nginx",
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("reads the archives it writes", func() {
+		var buffer bytes.Buffer
+		Expect(backup.Write(context.Background(), &buffer, archive, client, "apps")).To(Succeed())
+
+		reader, err := backup.NewReader(&buffer)
+		Expect(err).ToNot(HaveOccurred())
+		defer reader.Close()
+
+		Expect(reader.Archive.Namespace).To(Equal("ns"))
+		Expect(reader.Archive.Configurations).To(Equal(archive.Configurations))
+		Expect(reader.Archive.Services).To(Equal(archive.Services))
+		Expect(reader.Archive.Apps).To(Equal(archive.Apps))
+	})
+
+	It("pushes the images into the new repositories", func() {
+		var buffer bytes.Buffer
+		Expect(backup.Write(context.Background(), &buffer, archive, client, "apps")).To(Succeed())
+
+		reader, err := backup.NewReader(&buffer)
+		Expect(err).ToNot(HaveOccurred())
+		defer reader.Close()
+
+		err = reader.PushImages(context.Background(), client, func(app string) string {
+			return registry.Repository("apps", "restored", app)
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(uploads).To(Equal(map[string]string{"sha256:c": "config"}))
+		Expect(manifests).To(Equal(map[string]string{
+			"/v2/apps/restored-app/manifests/s1": "application/vnd.oci.image.manifest.v1+json " + manifest,
+		}))
+	})
+
+	It("rejects files which are not archives", func() {
+		_, err := backup.NewReader(strings.NewReader("not an archive"))
+		Expect(err).To(MatchError(ContainSubstring("not a namespace archive")))
+	})
+
+	It("rejects archives of unknown versions", func() {
+		archive.Version = backup.Version + 1
+
+		var buffer bytes.Buffer
+		Expect(backup.Write(context.Background(), &buffer, archive, client, "apps")).To(Succeed())
+
+		_, err := backup.NewReader(&buffer)
+		Expect(err).To(MatchError(ContainSubstring("unsupported archive version")))
+	})
+})
@@ -0,0 +1,245 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/configurations"
+	"github.com/epinio/epinio/internal/registry"
+	"github.com/epinio/epinio/internal/services"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Reader reads an archive. The description of the archive is read on creation, the
+// images, if any, by PushImages.
+type Reader struct {
+	Archive Archive
+	gz      *gzip.Reader
+	tar     *tar.Reader
+}
+
+// NewReader returns a reader of the archive, with its description.
+func NewReader(r io.Reader) (*Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "not a namespace archive")
+	}
+	reader := &Reader{gz: gz, tar: tar.NewReader(gz)}
+
+	header, err := reader.tar.Next()
+	if err != nil {
+		return nil, errors.Wrap(err, "not a namespace archive")
+	}
+	if header.Name != manifestEntry {
+		return nil, errors.Errorf("not a namespace archive, found %s instead of %s", header.Name, manifestEntry)
+	}
+	if err := json.NewDecoder(reader.tar).Decode(&reader.Archive); err != nil {
+		return nil, errors.Wrapf(err, "reading %s", manifestEntry)
+	}
+	if reader.Archive.Version != Version {
+		return nil, errors.Errorf("unsupported archive version %d, expected %d", reader.Archive.Version, Version)
+	}
+
+	return reader, nil
+}
+
+// Close closes the reader. It does not close the underlying reader.
+func (r *Reader) Close() error {
+	return r.gz.Close()
+}
+
+// PushImages pushes the images of the archive to the registry of the client, into the
+// repositories returned by repository for the applications. Images are tagged with the
+// stage ids of their applications. Blobs already in a repository are not pushed again.
+// The whole archive is read, which detects truncated archives. The client is not used
+// by archives without images.
+func (r *Reader) PushImages(ctx context.Context, client *registry.Client, repository func(app string) string) error {
+	apps := map[string]App{}
+	for _, app := range r.Archive.Apps {
+		if app.Image != nil {
+			apps[app.Name] = app
+		}
+	}
+
+	for {
+		header, err := r.tar.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "reading the archive")
+		}
+
+		name, rest, ok := imageEntry(header.Name)
+		if !ok {
+			return errors.Errorf("unexpected archive entry %s", header.Name)
+		}
+		app, ok := apps[name]
+		if !ok {
+			return errors.Errorf("archive entry %s belongs to no application", header.Name)
+		}
+		target := repository(app.Name)
+
+		if digest := strings.TrimPrefix(rest, "blobs/"); digest != rest {
+			exists, err := client.BlobExists(ctx, target, digest)
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+			if err := client.PutBlob(ctx, target, digest, header.Size, r.tar); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if rest != "manifest.json" {
+			return errors.Errorf("unexpected archive entry %s", header.Name)
+		}
+		content, err := io.ReadAll(r.tar)
+		if err != nil {
+			return errors.Wrapf(err, "reading %s", header.Name)
+		}
+		if err := client.PutManifest(ctx, target, app.StageID, app.Image.MediaType, content); err != nil {
+			return err
+		}
+	}
+}
+
+// imageEntry splits the name of an image entry into the application and the rest.
+func imageEntry(name string) (string, string, bool) {
+	if !strings.HasPrefix(name, imagesPrefix) {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(name, imagesPrefix), "/", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// Restore recreates the configurations, services and applications of the archive in the
+// namespace, which has to exist, and records them in the result. Applications are created
+// with their settings, but neither bound to services nor deployed. Services whose
+// catalog service is gone are skipped, and reported in the warnings of the result.
+func Restore(ctx context.Context, cluster *kubernetes.Cluster, archive Archive, namespace, username string, result *models.NamespaceRestoreResponse) error {
+	for _, configuration := range archive.Configurations {
+		_, err := configurations.CreateConfiguration(ctx, cluster, configuration.Name, namespace, username, configuration.Data)
+		if err != nil {
+			return errors.Wrapf(err, "creating configuration %s", configuration.Name)
+		}
+		result.Configurations = append(result.Configurations, configuration.Name)
+	}
+
+	serviceClient, err := services.NewKubernetesServiceClient(cluster)
+	if err != nil {
+		return err
+	}
+	for _, service := range archive.Services {
+		catalogService, err := serviceClient.GetCatalogService(ctx, service.CatalogService)
+		if err != nil {
+			if apierrors.IsNotFound(errors.Cause(err)) {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("service %s not restored, catalog service %s not found",
+					service.Name, service.CatalogService))
+				continue
+			}
+			return err
+		}
+		catalogService.Values = service.Values
+
+		if err := serviceClient.Create(ctx, namespace, service.Name, *catalogService); err != nil {
+			return errors.Wrapf(err, "creating service %s", service.Name)
+		}
+		result.Services = append(result.Services, service.Name)
+	}
+
+	for _, app := range archive.Apps {
+		if err := restoreApp(ctx, cluster, app, namespace, username); err != nil {
+			return errors.Wrapf(err, "creating application %s", app.Name)
+		}
+		result.Apps = append(result.Apps, app.Name)
+	}
+
+	return nil
+}
+
+func restoreApp(ctx context.Context, cluster *kubernetes.Cluster, app App, namespace, username string) error {
+	appRef := models.NewAppRef(app.Name, namespace)
+	settings := app.Configuration
+
+	if err := application.Create(ctx, cluster, appRef, username, settings.Routes, settings.AppChart); err != nil {
+		return err
+	}
+
+	if settings.Instances != nil {
+		if err := application.ScalingSet(ctx, cluster, appRef, *settings.Instances); err != nil {
+			return err
+		}
+	}
+	if len(settings.Environment) > 0 {
+		if err := application.EnvironmentSet(ctx, cluster, appRef, settings.Environment, true); err != nil {
+			return err
+		}
+	}
+	if len(app.SecretEnvironment) > 0 {
+		if err := application.EnvironmentMarkSecret(ctx, cluster, appRef, app.SecretEnvironment); err != nil {
+			return err
+		}
+	}
+	if len(settings.EnvironmentFrom) > 0 {
+		if err := application.EnvironmentFromSet(ctx, cluster, appRef, settings.EnvironmentFrom, true); err != nil {
+			return err
+		}
+	}
+	if settings.StagingEnvironment != nil && len(*settings.StagingEnvironment) > 0 {
+		if err := application.StagingEnvironmentSet(ctx, cluster, appRef, *settings.StagingEnvironment, true); err != nil {
+			return err
+		}
+	}
+	if len(settings.Configurations) > 0 {
+		if err := application.BoundConfigurationsSet(ctx, cluster, appRef, settings.Configurations, true); err != nil {
+			return err
+		}
+	}
+	if app.Origin.Kind != models.OriginNone {
+		if err := application.SetOrigin(ctx, cluster, appRef, app.Origin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetImage records the stage id and the image of the application, as staging does.
+func SetImage(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, stageID, imageURL string) error {
+	client, err := cluster.ClientApp()
+	if err != nil {
+		return err
+	}
+
+	app, err := application.Get(ctx, cluster, appRef)
+	if err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedField(app.Object, stageID, "spec", "stageid"); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedField(app.Object, imageURL, "spec", "imageurl"); err != nil {
+		return err
+	}
+
+	_, err = client.Namespace(appRef.Namespace).Update(ctx, app, metav1.UpdateOptions{})
+	return err
+}
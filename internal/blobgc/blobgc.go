@@ -0,0 +1,230 @@
+// Package blobgc removes the orphaned blobs of application sources from the blob store.
+// Every upload and git import stores a new blob, yet only the blobs of replaced stages
+// are deleted. Blobs of failed stagings, abandoned uploads, and deleted applications
+// remain. A blob is orphaned when neither an application nor a staging job references
+// it. Orphans are deleted once they are older than a grace period, which protects blobs
+// uploaded but not staged yet. Chunked uploads which were never completed are aborted
+// once they were initiated longer than the grace period ago, discarding their parts.
+package blobgc
+
+import (
+	"context"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/blobstore"
+	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// DefaultInterval is the time between collections, if the server does not specify
+	// one.
+	DefaultInterval = time.Hour
+
+	// DefaultGracePeriod is the age of unreferenced blobs before they are deleted, if
+	// the server does not specify one.
+	DefaultGracePeriod = 24 * time.Hour
+)
+
+// References returns the UIDs of the blobs referenced by applications, as their current
+// sources, and by staging jobs.
+func References(ctx context.Context, cluster *kubernetes.Cluster) (map[string]struct{}, error) {
+	references := map[string]struct{}{}
+
+	client, err := cluster.ClientApp()
+	if err != nil {
+		return nil, err
+	}
+	apps, err := client.Namespace("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing applications")
+	}
+	for _, app := range apps.Items {
+		blobUID, _, err := unstructured.NestedString(app.UnstructuredContent(), "spec", "blobuid")
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading the blob of application %s/%s", app.GetNamespace(), app.GetName())
+		}
+		if blobUID != "" {
+			references[blobUID] = struct{}{}
+		}
+	}
+
+	// Note: Queued stagings are suspended jobs, and are found here as well.
+	jobs, err := cluster.ListJobs(ctx, helmchart.Namespace(), models.EpinioStageBlobUIDLabel)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing staging jobs")
+	}
+	for _, job := range jobs.Items {
+		if blobUID := job.Labels[models.EpinioStageBlobUIDLabel]; blobUID != "" {
+			references[blobUID] = struct{}{}
+		}
+	}
+
+	return references, nil
+}
+
+// Orphans returns the blobs which are not referenced, and were last modified longer than
+// the grace period before now. The second result is the number of unreferenced blobs
+// within the grace period.
+func Orphans(blobs []blobstore.Blob, references map[string]struct{}, grace time.Duration, now time.Time) ([]blobstore.Blob, int) {
+	orphans := []blobstore.Blob{}
+	recent := 0
+	for _, blob := range blobs {
+		if _, ok := references[blob.UID]; ok {
+			continue
+		}
+		if now.Sub(blob.LastModified) <= grace {
+			recent++
+			continue
+		}
+		orphans = append(orphans, blob)
+	}
+	return orphans, recent
+}
+
+// ExpiredUploads returns the uploads initiated longer than the grace period before now.
+func ExpiredUploads(uploads []blobstore.Upload, grace time.Duration, now time.Time) []blobstore.Upload {
+	expired := []blobstore.Upload{}
+	for _, upload := range uploads {
+		if now.Sub(upload.Initiated) > grace {
+			expired = append(expired, upload)
+		}
+	}
+	return expired
+}
+
+// Collect finds the orphaned blobs and expired uploads and, unless dryRun, deletes
+// respectively aborts them. The report lists the orphans and uploads found.
+func Collect(ctx context.Context, cluster *kubernetes.Cluster, logger logr.Logger, grace time.Duration, dryRun bool) (models.BlobGCReport, error) {
+	report := models.BlobGCReport{
+		DryRun:      dryRun,
+		GracePeriod: grace.String(),
+	}
+
+	store, err := blobstore.New(ctx, cluster)
+	if err != nil {
+		return report, err
+	}
+
+	// The references are read before the blobs. A blob stored in between is not
+	// referenced yet, but is within the grace period.
+	references, err := References(ctx, cluster)
+	if err != nil {
+		return report, err
+	}
+	blobs, err := store.ListBlobs(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	orphans, recent := Orphans(blobs, references, grace, time.Now())
+	report.Blobs = len(blobs)
+	report.Referenced = len(blobs) - len(orphans) - recent
+	report.Recent = recent
+
+	for _, blob := range orphans {
+		orphan := models.OrphanBlob{
+			BlobUID:      blob.UID,
+			Size:         blob.Size,
+			LastModified: blob.LastModified.UTC().Format(time.RFC3339),
+		}
+
+		meta, err := store.Meta(ctx, blob.UID)
+		if err != nil && !blobstore.IsNotFound(err) {
+			return report, errors.Wrapf(err, "reading the meta data of blob %s", blob.UID)
+		}
+		orphan.Namespace = blobstore.MetaValue(meta, "namespace")
+		orphan.App = blobstore.MetaValue(meta, "app")
+
+		if !dryRun {
+			if err := store.DeleteObject(ctx, blob.UID); err != nil {
+				return report, errors.Wrapf(err, "deleting blob %s", blob.UID)
+			}
+			orphan.Deleted = true
+			report.Reclaimed += blob.Size
+
+			logger.Info("deleted orphaned blob", "blob", blob.UID, "app", orphan.App, "namespace", orphan.Namespace, "size", blob.Size)
+		}
+
+		report.Orphans = append(report.Orphans, orphan)
+	}
+
+	uploads, err := store.ListUploads(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	for _, upload := range ExpiredUploads(uploads, grace, time.Now()) {
+		expired := models.ExpiredUpload{
+			BlobUID:   upload.BlobUID,
+			Initiated: upload.Initiated.UTC().Format(time.RFC3339),
+		}
+
+		meta, err := store.UploadMeta(ctx, upload.BlobUID, upload.UploadID)
+		if err != nil && !blobstore.IsNotFound(err) {
+			return report, errors.Wrapf(err, "reading the meta data of the upload of blob %s", upload.BlobUID)
+		}
+		expired.Namespace = blobstore.MetaValue(meta, "namespace")
+		expired.App = blobstore.MetaValue(meta, "app")
+
+		if !dryRun {
+			err := store.AbortUpload(ctx, upload.BlobUID, upload.UploadID)
+			if err != nil && !blobstore.IsNotFound(err) {
+				return report, errors.Wrapf(err, "aborting the upload of blob %s", upload.BlobUID)
+			}
+			expired.Aborted = true
+
+			logger.Info("aborted expired upload", "blob", upload.BlobUID, "app", expired.App, "namespace", expired.Namespace)
+		}
+
+		report.Uploads = append(report.Uploads, expired)
+	}
+
+	return report, nil
+}
+
+// Start runs the collection of orphaned blobs in the background, every interval, until
+// the context is done. Without an interval no collection is run.
+func Start(ctx context.Context, logger logr.Logger, interval, grace time.Duration) {
+	logger = logger.WithName("BlobGC")
+	if interval <= 0 {
+		logger.Info("disabled")
+		return
+	}
+	if grace <= 0 {
+		grace = DefaultGracePeriod
+	}
+
+	go func() {
+		logger.Info("start", "interval", interval.String(), "grace", grace.String())
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Info("stop")
+				return
+			case <-ticker.C:
+			}
+
+			cluster, err := kubernetes.GetCluster(ctx)
+			if err != nil {
+				logger.Info("collection failed", "error", err.Error())
+				continue
+			}
+			report, err := Collect(ctx, cluster, logger, grace, false)
+			if err != nil {
+				logger.Info("collection failed", "error", err.Error())
+				continue
+			}
+			logger.Info("collected", "blobs", report.Blobs, "deleted", len(report.Orphans), "aborted", len(report.Uploads), "reclaimed", report.Reclaimed)
+		}
+	}()
+}
@@ -0,0 +1,62 @@
+package blobgc_test
+
+import (
+	"time"
+
+	"github.com/epinio/epinio/internal/blobgc"
+	"github.com/epinio/epinio/internal/blobstore"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Orphans", func() {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	grace := 24 * time.Hour
+
+	blob := func(uid string, age time.Duration) blobstore.Blob {
+		return blobstore.Blob{UID: uid, Size: 100, LastModified: now.Add(-age)}
+	}
+
+	It("returns the unreferenced blobs older than the grace period", func() {
+		blobs := []blobstore.Blob{
+			blob("referenced-old", 48*time.Hour),
+			blob("referenced-new", time.Hour),
+			blob("orphan-old", 48*time.Hour),
+			blob("orphan-new", time.Hour),
+		}
+		references := map[string]struct{}{
+			"referenced-old": {},
+			"referenced-new": {},
+		}
+
+		orphans, recent := blobgc.Orphans(blobs, references, grace, now)
+		Expect(orphans).To(Equal([]blobstore.Blob{blob("orphan-old", 48*time.Hour)}))
+		Expect(recent).To(Equal(1))
+	})
+
+	It("keeps blobs exactly at the grace period", func() {
+		orphans, recent := blobgc.Orphans([]blobstore.Blob{blob("orphan", grace)}, nil, grace, now)
+		Expect(orphans).To(BeEmpty())
+		Expect(recent).To(Equal(1))
+	})
+})
+
+var _ = Describe("ExpiredUploads", func() {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	grace := 24 * time.Hour
+
+	upload := func(uid string, age time.Duration) blobstore.Upload {
+		return blobstore.Upload{BlobUID: uid, UploadID: "upload-" + uid, Initiated: now.Add(-age)}
+	}
+
+	It("returns the uploads initiated longer than the grace period ago", func() {
+		uploads := []blobstore.Upload{
+			upload("abandoned", 48*time.Hour),
+			upload("active", time.Hour),
+			upload("boundary", grace),
+		}
+
+		Expect(blobgc.ExpiredUploads(uploads, grace, now)).To(Equal([]blobstore.Upload{upload("abandoned", 48*time.Hour)}))
+	})
+})
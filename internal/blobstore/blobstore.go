@@ -0,0 +1,189 @@
+// Package blobstore stores the blobs of application sources, and the other objects of
+// the API server, like staging logs. The store is either the S3 bucket of the connection
+// details, or a directory of the API server, usually on a PVC. The latter removes the
+// need for an S3 service on small installs. Staging jobs cannot reach that directory,
+// and fetch their blob from the API server instead, see DownloadToken.
+package blobstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/epinio/epinio/internal/s3manager"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+const (
+	// BackendS3 stores the blobs in the S3 bucket of the connection details.
+	BackendS3 = "s3"
+
+	// BackendFilesystem stores the blobs in a directory of the API server.
+	BackendFilesystem = "filesystem"
+
+	// DefaultPath is the directory of the filesystem backend, if the server does not
+	// specify one.
+	DefaultPath = "/var/lib/epinio/blobs"
+
+	// DownloadTokenLifetime is how long the download token of a staging job stays
+	// good. It covers the wait of the job in the staging queue, and its run.
+	DownloadTokenLifetime = 24 * time.Hour
+)
+
+// ErrNotFound is returned, wrapped, for missing objects and uploads.
+var ErrNotFound = errors.New("not found")
+
+// IsNotFound returns true if the error reports a missing object or upload.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// Blob describes a stored blob of application sources.
+type Blob struct {
+	UID          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Part is an uploaded part of a multipart upload.
+type Part struct {
+	Number int
+	Size   int64
+	ETag   string
+}
+
+// Upload describes an incomplete multipart upload, see Store.InitiateUpload.
+type Upload struct {
+	BlobUID   string
+	UploadID  string
+	Initiated time.Time
+}
+
+// Store is a storage of blobs and objects. Blobs are the application sources, named by a
+// UUID. Objects are stored under a name of the caller. Meta data keys are returned in
+// canonical form, use MetaValue to look them up.
+type Store interface {
+	// Upload stores the file as a new blob, and returns the blobUID.
+	Upload(ctx context.Context, filepath string, metadata map[string]string) (string, error)
+	// UploadStream stores the data as a new blob, and returns the blobUID.
+	UploadStream(ctx context.Context, data io.Reader, size int64, metadata map[string]string) (string, error)
+	// Meta returns the meta data of the blob or object.
+	Meta(ctx context.Context, name string) (map[string]string, error)
+	// ListBlobs returns the blobs of application sources, without the other objects.
+	ListBlobs(ctx context.Context) ([]Blob, error)
+
+	// InitiateUpload starts a multipart upload of a new blob. It returns the blobUID
+	// and the ID of the upload.
+	InitiateUpload(ctx context.Context, metadata map[string]string) (string, string, error)
+	// UploadMeta returns the meta data the upload was started with.
+	UploadMeta(ctx context.Context, blobUID, uploadID string) (map[string]string, error)
+	// UploadPart stores the numbered part of the upload. Uploading a part again
+	// replaces it.
+	UploadPart(ctx context.Context, blobUID, uploadID string, number int, data io.Reader, size int64) (Part, error)
+	// ListParts returns the parts uploaded so far, ordered by their number.
+	ListParts(ctx context.Context, blobUID, uploadID string) ([]Part, error)
+	// CompleteUpload assembles the parts, numbered from 1 without gaps, into the blob.
+	CompleteUpload(ctx context.Context, blobUID, uploadID string) error
+	// AbortUpload discards the upload and its parts.
+	AbortUpload(ctx context.Context, blobUID, uploadID string) error
+	// ListUploads returns the incomplete uploads, abandoned ones included.
+	ListUploads(ctx context.Context) ([]Upload, error)
+
+	// PutObject stores the data under the name, replacing any existing object.
+	PutObject(ctx context.Context, name string, data io.Reader, size int64, contentType string, metadata map[string]string) error
+	// GetObject returns the contents of the named object.
+	GetObject(ctx context.Context, name string) ([]byte, error)
+	// ListObjects returns the names of all objects whose name starts with the prefix.
+	ListObjects(ctx context.Context, prefix string) ([]string, error)
+	// DeleteObject deletes the named blob or object. Missing objects are ignored.
+	DeleteObject(ctx context.Context, name string) error
+	// DeletePrefix deletes all objects whose name starts with the prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// Backend returns the backend of the store, as configured for the server.
+func Backend() string {
+	if backend := viper.GetString("blob-store"); backend != "" {
+		return backend
+	}
+	return BackendS3
+}
+
+// New returns the store of the backend configured for the server.
+func New(ctx context.Context, cluster *kubernetes.Cluster) (Store, error) {
+	switch backend := Backend(); backend {
+	case BackendS3:
+		connectionDetails, err := s3manager.GetConnectionDetails(ctx, cluster,
+			helmchart.Namespace(), helmchart.S3ConnectionDetailsSecretName)
+		if err != nil {
+			return nil, errors.Wrap(err, "fetching the S3 connection details from the Kubernetes secret")
+		}
+		manager, err := s3manager.New(connectionDetails)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating an S3 manager")
+		}
+		return &s3Store{Manager: manager}, nil
+	case BackendFilesystem:
+		return NewFilesystem(Path())
+	default:
+		return nil, errors.Errorf("unknown blob store '%s'", backend)
+	}
+}
+
+// Path returns the directory of the filesystem backend.
+func Path() string {
+	if path := viper.GetString("blob-store-path"); path != "" {
+		return path
+	}
+	return DefaultPath
+}
+
+// DownloadURL returns the URL staging jobs fetch the blob from, for the filesystem
+// backend. The URL carries the download token of the blob, and its expiry.
+func DownloadURL(blobUID string) string {
+	base := viper.GetString("blob-store-url")
+	if base == "" {
+		base = fmt.Sprintf("http://epinio-server.%s.svc.cluster.local", helmchart.Namespace())
+	}
+	expires := time.Now().Add(DownloadTokenLifetime).Unix()
+	return fmt.Sprintf("%s/api/v1/blobs/%s?expires=%d&token=%s", strings.TrimSuffix(base, "/"),
+		blobUID, expires, DownloadToken(blobUID, expires))
+}
+
+// DownloadToken returns the token authorizing the download of the blob from the API
+// server, until the expiry, in seconds since the epoch. It is derived from the session
+// key of the server, and is good for this blob only.
+func DownloadToken(blobUID string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(os.Getenv("SESSION_KEY")))
+	mac.Write([]byte(fmt.Sprintf("blob:%s:%d", blobUID, expires)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidDownloadToken returns true if the token authorizes the download of the blob
+// until the expiry, and the expiry is not reached at the given time.
+func ValidDownloadToken(blobUID, token string, expires int64, now time.Time) bool {
+	if now.Unix() >= expires {
+		return false
+	}
+	return hmac.Equal([]byte(token), []byte(DownloadToken(blobUID, expires)))
+}
+
+// MetaValue returns the value of the key in the meta data returned by Meta. The store may
+// have changed the case of the key.
+func MetaValue(meta map[string]string, key string) string {
+	for k, v := range meta {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,499 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/md5" // nolint:gosec // ETag of a part, not security relevant
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Filesystem is the filesystem backend. It keeps the objects below a root directory:
+//
+//	objects/<name>         contents of the object
+//	meta/<name>.json       meta data of the object
+//	uploads/<id>/info.json blob and meta data of a multipart upload
+//	uploads/<id>/<number>  uploaded part
+//	tmp/                   objects and parts being written
+//
+// Contents are written to tmp/ first, and moved into place when complete.
+type Filesystem struct {
+	root string
+}
+
+var _ Store = &Filesystem{}
+
+// upload is the information about a multipart upload.
+type upload struct {
+	BlobUID  string            `json:"blobuid"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// NewFilesystem returns the filesystem backend storing below the root directory, and
+// creates its directories.
+func NewFilesystem(root string) (*Filesystem, error) {
+	store := &Filesystem{root: root}
+	for _, dir := range []string{"objects", "meta", "uploads", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0700); err != nil {
+			return nil, errors.Wrap(err, "creating the blob store directories")
+		}
+	}
+	return store, nil
+}
+
+// Upload stores the file as a new blob, and returns the blobUID.
+func (s *Filesystem) Upload(ctx context.Context, path string, metadata map[string]string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	return s.UploadStream(ctx, file, info.Size(), metadata)
+}
+
+// UploadStream stores the data as a new blob, and returns the blobUID.
+func (s *Filesystem) UploadStream(ctx context.Context, data io.Reader, size int64, metadata map[string]string) (string, error) {
+	blobUID := uuid.New().String()
+	if err := s.PutObject(ctx, blobUID, data, size, "application/tar", metadata); err != nil {
+		return "", err
+	}
+	return blobUID, nil
+}
+
+// Meta returns the meta data of the blob or object.
+func (s *Filesystem) Meta(ctx context.Context, name string) (map[string]string, error) {
+	path, err := s.path("meta", name+".json")
+	if err != nil {
+		return map[string]string{}, err
+	}
+
+	meta := map[string]string{}
+	if err := readJSON(path, &meta); err != nil {
+		return map[string]string{}, errors.Wrap(err, "reading the object meta data")
+	}
+	return meta, nil
+}
+
+// ListBlobs returns the blobs of application sources. These are the objects at the top of
+// the store named by a UUID. Other objects, like staging logs, are not returned.
+func (s *Filesystem) ListBlobs(ctx context.Context) ([]Blob, error) {
+	entries, err := os.ReadDir(filepath.Join(s.root, "objects"))
+	if err != nil {
+		return nil, errors.Wrap(err, "listing objects")
+	}
+
+	blobs := []Blob{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if _, err := uuid.Parse(entry.Name()); err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue // Deleted meanwhile
+			}
+			return nil, err
+		}
+		blobs = append(blobs, Blob{
+			UID:          entry.Name(),
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+	}
+
+	return blobs, nil
+}
+
+// InitiateUpload starts a multipart upload of a new blob. It returns the blobUID and the
+// ID of the upload.
+func (s *Filesystem) InitiateUpload(ctx context.Context, metadata map[string]string) (string, string, error) {
+	blobUID := uuid.New().String()
+	uploadID := uuid.New().String()
+
+	dir := filepath.Join(s.root, "uploads", uploadID)
+	if err := os.Mkdir(dir, 0700); err != nil {
+		return "", "", errors.Wrap(err, "starting the multipart upload")
+	}
+
+	info := upload{BlobUID: blobUID, Metadata: canonicalMeta(metadata)}
+	if err := writeJSON(filepath.Join(dir, "info.json"), info); err != nil {
+		return "", "", errors.Wrap(err, "starting the multipart upload")
+	}
+
+	return blobUID, uploadID, nil
+}
+
+// UploadMeta returns the meta data the upload was started with.
+func (s *Filesystem) UploadMeta(ctx context.Context, blobUID, uploadID string) (map[string]string, error) {
+	_, info, err := s.upload(blobUID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	return info.Metadata, nil
+}
+
+// UploadPart stores the numbered part of the upload. Uploading a part again replaces it.
+func (s *Filesystem) UploadPart(ctx context.Context, blobUID, uploadID string, number int, data io.Reader, size int64) (Part, error) {
+	dir, _, err := s.upload(blobUID, uploadID)
+	if err != nil {
+		return Part{}, err
+	}
+
+	hash := md5.New() // nolint:gosec // ETag only
+	written, err := s.write(filepath.Join(dir, strconv.Itoa(number)), io.TeeReader(data, hash), size)
+	if err != nil {
+		return Part{}, errors.Wrapf(err, "writing part %d", number)
+	}
+
+	return Part{Number: number, Size: written, ETag: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// ListParts returns the parts uploaded so far, ordered by their number.
+func (s *Filesystem) ListParts(ctx context.Context, blobUID, uploadID string) ([]Part, error) {
+	dir, _, err := s.upload(blobUID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing the uploaded parts")
+	}
+
+	parts := []Part{}
+	for _, entry := range entries {
+		number, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue // info.json
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, errors.Wrap(err, "listing the uploaded parts")
+		}
+		parts = append(parts, Part{Number: number, Size: info.Size()})
+	}
+
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Number < parts[j].Number })
+	return parts, nil
+}
+
+// CompleteUpload assembles the parts, numbered from 1 without gaps, into the blob.
+func (s *Filesystem) CompleteUpload(ctx context.Context, blobUID, uploadID string) error {
+	dir, info, err := s.upload(blobUID, uploadID)
+	if err != nil {
+		return err
+	}
+
+	parts, err := s.ListParts(ctx, blobUID, uploadID)
+	if err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return errors.New("no parts uploaded")
+	}
+
+	readers := []io.Reader{}
+	size := int64(0)
+	for i, part := range parts {
+		if part.Number != i+1 {
+			return errors.Errorf("part %d is missing", i+1)
+		}
+
+		file, err := os.Open(filepath.Join(dir, strconv.Itoa(part.Number)))
+		if err != nil {
+			return errors.Wrap(err, "completing the multipart upload")
+		}
+		defer file.Close()
+
+		readers = append(readers, file)
+		size += part.Size
+	}
+
+	err = s.PutObject(ctx, info.BlobUID, io.MultiReader(readers...), size, "application/tar", info.Metadata)
+	if err != nil {
+		return errors.Wrap(err, "completing the multipart upload")
+	}
+
+	return os.RemoveAll(dir)
+}
+
+// AbortUpload discards the upload and its parts.
+func (s *Filesystem) AbortUpload(ctx context.Context, blobUID, uploadID string) error {
+	dir, _, err := s.upload(blobUID, uploadID)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// ListUploads returns the incomplete uploads, abandoned ones included. An upload is
+// initiated when its information was written.
+func (s *Filesystem) ListUploads(ctx context.Context) ([]Upload, error) {
+	entries, err := os.ReadDir(filepath.Join(s.root, "uploads"))
+	if err != nil {
+		return nil, errors.Wrap(err, "listing uploads")
+	}
+
+	uploads := []Upload{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(s.root, "uploads", entry.Name(), "info.json")
+		stat, err := os.Stat(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue // Completed or aborted meanwhile
+			}
+			return nil, err
+		}
+		info := upload{}
+		if err := readJSON(path, &info); err != nil {
+			if IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		uploads = append(uploads, Upload{
+			BlobUID:   info.BlobUID,
+			UploadID:  entry.Name(),
+			Initiated: stat.ModTime(),
+		})
+	}
+
+	return uploads, nil
+}
+
+// PutObject stores the data under the name, replacing any existing object. The content
+// type is not kept.
+func (s *Filesystem) PutObject(ctx context.Context, name string, data io.Reader, size int64, contentType string, metadata map[string]string) error {
+	objectPath, err := s.path("objects", name)
+	if err != nil {
+		return err
+	}
+	metaPath, err := s.path("meta", name+".json")
+	if err != nil {
+		return err
+	}
+
+	// The meta data is written first. A listed object always has its meta data.
+	for _, dir := range []string{filepath.Dir(objectPath), filepath.Dir(metaPath)} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return errors.Wrap(err, "writing the object")
+		}
+	}
+	if err := writeJSON(metaPath, canonicalMeta(metadata)); err != nil {
+		return errors.Wrap(err, "writing the object meta data")
+	}
+	if _, err := s.write(objectPath, data, size); err != nil {
+		return errors.Wrap(err, "writing the object")
+	}
+
+	return nil
+}
+
+// GetObject returns the contents of the named object.
+func (s *Filesystem) GetObject(ctx context.Context, name string) ([]byte, error) {
+	path, err := s.path("objects", name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return data, nil
+}
+
+// Open returns a reader of the contents of the named object, and its size.
+func (s *Filesystem) Open(name string) (io.ReadCloser, int64, error) {
+	path, err := s.path("objects", name)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, notFound(err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+	return file, info.Size(), nil
+}
+
+// ListObjects returns the names of all objects whose name starts with the prefix.
+func (s *Filesystem) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	root := filepath.Join(s.root, "objects")
+
+	names := []string{}
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil // Deleted meanwhile
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		name, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		name = filepath.ToSlash(name)
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing objects")
+	}
+
+	return names, nil
+}
+
+// DeleteObject deletes the named blob or object. Missing objects are ignored.
+func (s *Filesystem) DeleteObject(ctx context.Context, name string) error {
+	objectPath, err := s.path("objects", name)
+	if err != nil {
+		return err
+	}
+	metaPath, err := s.path("meta", name+".json")
+	if err != nil {
+		return err
+	}
+
+	for _, path := range []string{objectPath, metaPath} {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeletePrefix deletes all objects whose name starts with the prefix.
+func (s *Filesystem) DeletePrefix(ctx context.Context, prefix string) error {
+	names, err := s.ListObjects(ctx, prefix)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := s.DeleteObject(ctx, name); err != nil {
+			return errors.Wrapf(err, "deleting object %s", name)
+		}
+	}
+
+	return nil
+}
+
+// path returns the path of the named object in the area of the store. Names escaping
+// the area are rejected.
+func (s *Filesystem) path(area, name string) (string, error) {
+	clean := filepath.Clean("/" + name)
+	if name == "" || clean != "/"+name {
+		return "", errors.Errorf("bad object name '%s'", name)
+	}
+	return filepath.Join(s.root, area, clean), nil
+}
+
+// upload returns the directory and information of the multipart upload of the blob.
+func (s *Filesystem) upload(blobUID, uploadID string) (string, upload, error) {
+	info := upload{}
+	if _, err := uuid.Parse(uploadID); err != nil {
+		return "", info, errors.Wrap(ErrNotFound, "no such upload")
+	}
+
+	dir := filepath.Join(s.root, "uploads", uploadID)
+	if err := readJSON(filepath.Join(dir, "info.json"), &info); err != nil {
+		return "", info, err
+	}
+	if info.BlobUID != blobUID {
+		return "", info, errors.Wrap(ErrNotFound, "no such upload")
+	}
+
+	return dir, info, nil
+}
+
+// write stores size bytes of the data at the path, through a temporary file. It returns
+// the number of bytes written.
+func (s *Filesystem) write(path string, data io.Reader, size int64) (int64, error) {
+	tmp, err := os.CreateTemp(filepath.Join(s.root, "tmp"), "write-")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	var written int64
+	if size < 0 {
+		written, err = io.Copy(tmp, data)
+	} else {
+		written, err = io.CopyN(tmp, data, size)
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return written, os.Rename(tmp.Name(), path)
+}
+
+// canonicalMeta returns the meta data with the keys in canonical form, as S3 does.
+func canonicalMeta(metadata map[string]string) map[string]string {
+	meta := map[string]string{}
+	for key, value := range metadata {
+		meta[http.CanonicalHeaderKey(key)] = value
+	}
+	return meta
+}
+
+func readJSON(path string, into interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return notFound(err)
+	}
+	return json.Unmarshal(data, into)
+}
+
+func writeJSON(path string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// notFound marks errors of missing files with ErrNotFound.
+func notFound(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return errors.Wrap(ErrNotFound, err.Error())
+	}
+	return err
+}
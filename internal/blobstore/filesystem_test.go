@@ -0,0 +1,186 @@
+package blobstore_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/epinio/epinio/internal/blobstore"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Filesystem", func() {
+	var (
+		ctx   context.Context
+		root  string
+		store *blobstore.Filesystem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		var err error
+		root, err = os.MkdirTemp("", "epinio-blobstore")
+		Expect(err).ToNot(HaveOccurred())
+		store, err = blobstore.NewFilesystem(root)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(root)).To(Succeed())
+	})
+
+	It("stores blobs with their meta data", func() {
+		blobUID, err := store.UploadStream(ctx, strings.NewReader("sources"), 7, map[string]string{
+			"app": "sample", "namespace": "workspace",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		meta, err := store.Meta(ctx, blobUID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(meta).To(Equal(map[string]string{"App": "sample", "Namespace": "workspace"}))
+		Expect(blobstore.MetaValue(meta, "app")).To(Equal("sample"))
+
+		data, err := store.GetObject(ctx, blobUID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("sources"))
+	})
+
+	It("lists the blobs without the other objects", func() {
+		blobUID, err := store.UploadStream(ctx, strings.NewReader("sources"), 7, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(store.PutObject(ctx, "staging-logs/workspace/sample/1", strings.NewReader("log"), 3, "text/plain", nil)).To(Succeed())
+
+		blobs, err := store.ListBlobs(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(blobs).To(HaveLen(1))
+		Expect(blobs[0].UID).To(Equal(blobUID))
+		Expect(blobs[0].Size).To(Equal(int64(7)))
+	})
+
+	It("lists and deletes objects by prefix", func() {
+		for _, name := range []string{"logs/a/1", "logs/a/2", "logs/b/1"} {
+			Expect(store.PutObject(ctx, name, strings.NewReader("log"), 3, "text/plain", nil)).To(Succeed())
+		}
+
+		names, err := store.ListObjects(ctx, "logs/a/")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(names).To(ConsistOf("logs/a/1", "logs/a/2"))
+
+		Expect(store.DeletePrefix(ctx, "logs/a/")).To(Succeed())
+		names, err = store.ListObjects(ctx, "logs/")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(names).To(ConsistOf("logs/b/1"))
+	})
+
+	It("reports missing objects", func() {
+		_, err := store.GetObject(ctx, "missing")
+		Expect(blobstore.IsNotFound(err)).To(BeTrue())
+		_, err = store.Meta(ctx, "missing")
+		Expect(blobstore.IsNotFound(err)).To(BeTrue())
+		Expect(store.DeleteObject(ctx, "missing")).To(Succeed())
+	})
+
+	It("rejects names outside of the store", func() {
+		err := store.PutObject(ctx, "../escape", strings.NewReader("x"), 1, "text/plain", nil)
+		Expect(err).To(MatchError(ContainSubstring("bad object name")))
+	})
+
+	It("assembles multipart uploads", func() {
+		blobUID, uploadID, err := store.InitiateUpload(ctx, map[string]string{"app": "sample"})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = store.UploadPart(ctx, blobUID, uploadID, 2, strings.NewReader("world"), 5)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(store.CompleteUpload(ctx, blobUID, uploadID)).To(MatchError("part 1 is missing"))
+
+		part, err := store.UploadPart(ctx, blobUID, uploadID, 1, strings.NewReader("hello "), 6)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(part.Size).To(Equal(int64(6)))
+
+		parts, err := store.ListParts(ctx, blobUID, uploadID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parts).To(HaveLen(2))
+		Expect(parts[0].Number).To(Equal(1))
+
+		Expect(store.CompleteUpload(ctx, blobUID, uploadID)).To(Succeed())
+
+		data, err := store.GetObject(ctx, blobUID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("hello world"))
+		meta, err := store.Meta(ctx, blobUID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(blobstore.MetaValue(meta, "app")).To(Equal("sample"))
+
+		_, err = store.ListParts(ctx, blobUID, uploadID)
+		Expect(blobstore.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("discards aborted uploads", func() {
+		blobUID, uploadID, err := store.InitiateUpload(ctx, nil)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = store.UploadPart(ctx, blobUID, uploadID, 1, bytes.NewReader([]byte("x")), 1)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(store.AbortUpload(ctx, blobUID, uploadID)).To(Succeed())
+		_, err = store.UploadPart(ctx, blobUID, uploadID, 1, bytes.NewReader([]byte("x")), 1)
+		Expect(blobstore.IsNotFound(err)).To(BeTrue())
+		Expect(blobstore.IsNotFound(store.AbortUpload(ctx, "other", uploadID))).To(BeTrue())
+	})
+
+	It("lists the incomplete uploads", func() {
+		blobUID, uploadID, err := store.InitiateUpload(ctx, nil)
+		Expect(err).ToNot(HaveOccurred())
+		done, doneID, err := store.InitiateUpload(ctx, nil)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = store.UploadPart(ctx, done, doneID, 1, strings.NewReader("x"), 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(store.CompleteUpload(ctx, done, doneID)).To(Succeed())
+
+		uploads, err := store.ListUploads(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(uploads).To(HaveLen(1))
+		Expect(uploads[0].BlobUID).To(Equal(blobUID))
+		Expect(uploads[0].UploadID).To(Equal(uploadID))
+		Expect(uploads[0].Initiated).To(BeTemporally("~", time.Now(), time.Minute))
+
+		Expect(store.AbortUpload(ctx, blobUID, uploadID)).To(Succeed())
+		uploads, err = store.ListUploads(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(uploads).To(BeEmpty())
+	})
+
+	It("returns the meta data uploads were started with", func() {
+		blobUID, uploadID, err := store.InitiateUpload(ctx, map[string]string{"namespace": "workspace", "app": "sample"})
+		Expect(err).ToNot(HaveOccurred())
+
+		meta, err := store.UploadMeta(ctx, blobUID, uploadID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(blobstore.MetaValue(meta, "namespace")).To(Equal("workspace"))
+		Expect(blobstore.MetaValue(meta, "app")).To(Equal("sample"))
+
+		_, err = store.UploadMeta(ctx, "other", uploadID)
+		Expect(blobstore.IsNotFound(err)).To(BeTrue())
+	})
+})
+
+var _ = Describe("DownloadToken", func() {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(time.Hour).Unix()
+
+	It("authorizes the download of its blob only", func() {
+		token := blobstore.DownloadToken("blob-a", expires)
+		Expect(blobstore.ValidDownloadToken("blob-a", token, expires, now)).To(BeTrue())
+		Expect(blobstore.ValidDownloadToken("blob-b", token, expires, now)).To(BeFalse())
+	})
+
+	It("authorizes the download until its expiry only", func() {
+		token := blobstore.DownloadToken("blob-a", expires)
+		Expect(blobstore.ValidDownloadToken("blob-a", token, expires, now.Add(time.Hour))).To(BeFalse())
+		Expect(blobstore.ValidDownloadToken("blob-a", token, expires+3600, now)).To(BeFalse())
+	})
+})
@@ -0,0 +1,146 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/epinio/epinio/internal/s3manager"
+	"github.com/pkg/errors"
+)
+
+// s3Store is the S3 backend. It adapts the types and errors of the S3 manager.
+type s3Store struct {
+	*s3manager.Manager
+}
+
+var _ Store = &s3Store{}
+
+// s3UploadsPrefix is the prefix of the records of the multipart uploads. S3 does not
+// return the meta data of an upload before its completion, the record keeps it.
+const s3UploadsPrefix = "uploads/"
+
+// s3Upload is the record of a multipart upload, stored under the blobUID.
+type s3Upload struct {
+	UploadID string            `json:"uploadID"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+func (s *s3Store) InitiateUpload(ctx context.Context, metadata map[string]string) (string, string, error) {
+	blobUID, uploadID, err := s.Manager.InitiateUpload(ctx, metadata)
+	if err != nil {
+		return "", "", err
+	}
+
+	record, err := json.Marshal(s3Upload{UploadID: uploadID, Metadata: canonicalMeta(metadata)})
+	if err == nil {
+		err = s.Manager.PutObject(ctx, s3UploadsPrefix+blobUID, bytes.NewReader(record),
+			int64(len(record)), "application/json", nil)
+	}
+	if err != nil {
+		_ = s.Manager.AbortUpload(ctx, blobUID, uploadID)
+		return "", "", errors.Wrap(err, "recording the multipart upload")
+	}
+
+	return blobUID, uploadID, nil
+}
+
+func (s *s3Store) UploadMeta(ctx context.Context, blobUID, uploadID string) (map[string]string, error) {
+	data, err := s.Manager.GetObject(ctx, s3UploadsPrefix+blobUID)
+	if err != nil {
+		return nil, s3Error(err)
+	}
+
+	record := s3Upload{}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, errors.Wrap(err, "reading the record of the multipart upload")
+	}
+	if record.UploadID != uploadID {
+		return nil, errors.Wrap(ErrNotFound, "no such upload")
+	}
+
+	return record.Metadata, nil
+}
+
+func (s *s3Store) Meta(ctx context.Context, name string) (map[string]string, error) {
+	meta, err := s.Manager.Meta(ctx, name)
+	return meta, s3Error(err)
+}
+
+func (s *s3Store) ListBlobs(ctx context.Context) ([]Blob, error) {
+	objects, err := s.Manager.ListBlobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	blobs := []Blob{}
+	for _, object := range objects {
+		blobs = append(blobs, Blob{UID: object.UID, Size: object.Size, LastModified: object.LastModified})
+	}
+	return blobs, nil
+}
+
+func (s *s3Store) UploadPart(ctx context.Context, blobUID, uploadID string, number int, data io.Reader, size int64) (Part, error) {
+	part, err := s.Manager.UploadPart(ctx, blobUID, uploadID, number, data, size)
+	if err != nil {
+		return Part{}, s3Error(err)
+	}
+	return Part{Number: part.Number, Size: part.Size, ETag: part.ETag}, nil
+}
+
+func (s *s3Store) ListParts(ctx context.Context, blobUID, uploadID string) ([]Part, error) {
+	uploaded, err := s.Manager.ListParts(ctx, blobUID, uploadID)
+	if err != nil {
+		return nil, s3Error(err)
+	}
+
+	parts := []Part{}
+	for _, part := range uploaded {
+		parts = append(parts, Part{Number: part.Number, Size: part.Size, ETag: part.ETag})
+	}
+	return parts, nil
+}
+
+func (s *s3Store) CompleteUpload(ctx context.Context, blobUID, uploadID string) error {
+	if err := s.Manager.CompleteUpload(ctx, blobUID, uploadID); err != nil {
+		return s3Error(err)
+	}
+	// Note: A leftover record is harmless, its upload is gone.
+	_ = s.Manager.DeleteObject(ctx, s3UploadsPrefix+blobUID)
+	return nil
+}
+
+func (s *s3Store) AbortUpload(ctx context.Context, blobUID, uploadID string) error {
+	if err := s.Manager.AbortUpload(ctx, blobUID, uploadID); err != nil {
+		return s3Error(err)
+	}
+	_ = s.Manager.DeleteObject(ctx, s3UploadsPrefix+blobUID)
+	return nil
+}
+
+func (s *s3Store) ListUploads(ctx context.Context) ([]Upload, error) {
+	incomplete, err := s.Manager.ListUploads(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uploads := []Upload{}
+	for _, upload := range incomplete {
+		uploads = append(uploads, Upload{BlobUID: upload.BlobUID, UploadID: upload.UploadID, Initiated: upload.Initiated})
+	}
+	return uploads, nil
+}
+
+func (s *s3Store) GetObject(ctx context.Context, name string) ([]byte, error) {
+	data, err := s.Manager.GetObject(ctx, name)
+	return data, s3Error(err)
+}
+
+// s3Error marks the errors of S3 reporting missing objects and uploads with ErrNotFound.
+func s3Error(err error) error {
+	if err != nil && s3manager.IsNotFound(err) {
+		return errors.Wrap(ErrNotFound, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,252 @@
+// Package buildcache manages the per-application PVCs holding the build caches of
+// staging. A cache is created on first staging of its application, records the time of
+// its last use, and is removed with the application. It can be purged on request, to
+// wipe a corrupted cache, and caches unused for longer than a TTL are removed by the API
+// server in the background. A purged or removed cache is created anew by the next
+// staging of its application.
+package buildcache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// LastUsedAnnotation records the time of the last staging using the cache.
+	LastUsedAnnotation = "epinio.io/last-used"
+
+	// DefaultSize is the size of new caches, if the server does not specify one.
+	DefaultSize = "1Gi"
+
+	// component is the value of the `app.kubernetes.io/component` label of caches.
+	component = "buildcache"
+
+	// interval is the time between the checks for expired caches.
+	interval = time.Hour
+)
+
+// ErrStaging is returned by Purge for applications which are staging.
+var ErrStaging = errors.New("application is staging, its build cache is in use")
+
+// Settings configure the caches created by Ensure.
+type Settings struct {
+	Size         string
+	StorageClass string
+}
+
+// Ensure creates the cache of the application if it does not exist yet, and records
+// its use.
+func Ensure(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef, settings Settings) error {
+	size := settings.Size
+	if size == "" {
+		size = DefaultSize
+	}
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return errors.Wrapf(err, "bad build cache size '%s'", size)
+	}
+
+	pvcs := cluster.Kubectl.CoreV1().PersistentVolumeClaims(helmchart.Namespace())
+
+	_, err = pvcs.Get(ctx, app.MakePVCName(), metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) { // Unknown error, irrelevant to non-existence
+		return err
+	}
+	if err == nil { // pvc already exists
+		return MarkUsed(ctx, cluster, app, time.Now())
+	}
+
+	// From here on, only if the PVC is missing
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        app.MakePVCName(),
+			Namespace:   helmchart.Namespace(),
+			Labels:      labels(app),
+			Annotations: map[string]string{LastUsedAnnotation: time.Now().UTC().Format(time.RFC3339)},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: map[corev1.ResourceName]resource.Quantity{
+					corev1.ResourceStorage: quantity,
+				},
+			},
+		},
+	}
+	if settings.StorageClass != "" {
+		claim.Spec.StorageClassName = &settings.StorageClass
+	}
+
+	_, err = pvcs.Create(ctx, claim, metav1.CreateOptions{})
+	return err
+}
+
+// MarkUsed records the time as the last use of the cache of the application. Caches
+// created before the tracking of use are labeled as caches as well.
+func MarkUsed(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef, when time.Time) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      labels(app),
+			"annotations": map[string]string{LastUsedAnnotation: when.UTC().Format(time.RFC3339)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = cluster.Kubectl.CoreV1().PersistentVolumeClaims(helmchart.Namespace()).
+		Patch(ctx, app.MakePVCName(), types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// Get returns the details of the cache of the application, or nil if the application
+// has no cache.
+func Get(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef) (*models.BuildCache, error) {
+	pvc, err := cluster.Kubectl.CoreV1().PersistentVolumeClaims(helmchart.Namespace()).
+		Get(ctx, app.MakePVCName(), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	cache := &models.BuildCache{
+		App:       app,
+		Name:      pvc.Name,
+		Status:    string(pvc.Status.Phase),
+		CreatedAt: pvc.CreationTimestamp.Format(time.RFC3339),
+		LastUsed:  pvc.Annotations[LastUsedAnnotation],
+	}
+	if request, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		cache.Size = request.String()
+	}
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		cache.Size = capacity.String()
+	}
+	if pvc.Spec.StorageClassName != nil {
+		cache.StorageClass = *pvc.Spec.StorageClassName
+	}
+
+	return cache, nil
+}
+
+// Purge removes the cache of the application. It is an error to purge the cache while
+// the application is staging. Purging a missing cache is not an error.
+func Purge(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef) error {
+	staging, err := application.CurrentlyStaging(ctx, cluster, app.Namespace, app.Name)
+	if err != nil {
+		return err
+	}
+	if staging {
+		return ErrStaging
+	}
+
+	err = cluster.Kubectl.CoreV1().PersistentVolumeClaims(helmchart.Namespace()).
+		Delete(ctx, app.MakePVCName(), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Expired returns true if the cache was last used longer than the TTL before now.
+// Caches without recorded use count from their creation.
+func Expired(pvc corev1.PersistentVolumeClaim, ttl time.Duration, now time.Time) bool {
+	lastUsed := pvc.CreationTimestamp.Time
+	if stamp, ok := pvc.Annotations[LastUsedAnnotation]; ok {
+		if parsed, err := time.Parse(time.RFC3339, stamp); err == nil {
+			lastUsed = parsed
+		}
+	}
+	return now.Sub(lastUsed) > ttl
+}
+
+// Cleanup removes the caches unused for longer than the TTL. Caches of applications
+// which are staging are kept. A cache which cannot be removed is logged and left for the
+// next run, it does not stop the removal of the others.
+func Cleanup(ctx context.Context, cluster *kubernetes.Cluster, logger logr.Logger, ttl time.Duration) error {
+	pvcList, err := cluster.Kubectl.CoreV1().PersistentVolumeClaims(helmchart.Namespace()).
+		List(ctx, metav1.ListOptions{LabelSelector: "app.kubernetes.io/component=" + component})
+	if err != nil {
+		return errors.Wrap(err, "listing build caches")
+	}
+
+	now := time.Now()
+	for _, pvc := range pvcList.Items {
+		if !Expired(pvc, ttl, now) {
+			continue
+		}
+
+		app := models.NewAppRef(pvc.Labels["app.kubernetes.io/name"], pvc.Labels["app.kubernetes.io/part-of"])
+		err := Purge(ctx, cluster, app)
+		if err == ErrStaging {
+			continue
+		}
+		if err != nil {
+			logger.Info("removing expired build cache failed", "cache", pvc.Name, "error", err.Error())
+			continue
+		}
+
+		logger.Info("removed expired build cache", "app", app.Name, "namespace", app.Namespace)
+	}
+
+	return nil
+}
+
+// Start runs the cleanup of expired caches in the background, until the context is
+// done. Without a TTL caches are kept until their application is deleted.
+func Start(ctx context.Context, logger logr.Logger, ttl time.Duration) {
+	logger = logger.WithName("BuildCacheCleanup")
+	if ttl <= 0 {
+		logger.Info("disabled")
+		return
+	}
+
+	go func() {
+		logger.Info("start", "ttl", ttl.String())
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			cluster, err := kubernetes.GetCluster(ctx)
+			if err == nil {
+				err = Cleanup(ctx, cluster, logger, ttl)
+			}
+			if err != nil {
+				logger.Info("cleanup failed", "error", err.Error())
+			}
+
+			select {
+			case <-ctx.Done():
+				logger.Info("stop")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func labels(app models.AppRef) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       app.Name,
+		"app.kubernetes.io/part-of":    app.Namespace,
+		"app.kubernetes.io/managed-by": "epinio",
+		"app.kubernetes.io/component":  component,
+	}
+}
@@ -0,0 +1,49 @@
+package buildcache_test
+
+import (
+	"time"
+
+	"github.com/epinio/epinio/internal/buildcache"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Expired", func() {
+	now := time.Date(2022, 6, 15, 12, 0, 0, 0, time.UTC)
+	ttl := 7 * 24 * time.Hour
+
+	cache := func(created time.Time, lastUsed string) corev1.PersistentVolumeClaim {
+		pvc := corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: metav1.NewTime(created),
+				Annotations:       map[string]string{},
+			},
+		}
+		if lastUsed != "" {
+			pvc.Annotations[buildcache.LastUsedAnnotation] = lastUsed
+		}
+		return pvc
+	}
+
+	It("keeps caches used within the TTL", func() {
+		pvc := cache(now.Add(-30*24*time.Hour), now.Add(-24*time.Hour).Format(time.RFC3339))
+		Expect(buildcache.Expired(pvc, ttl, now)).To(BeFalse())
+	})
+
+	It("expires caches not used within the TTL", func() {
+		pvc := cache(now.Add(-30*24*time.Hour), now.Add(-8*24*time.Hour).Format(time.RFC3339))
+		Expect(buildcache.Expired(pvc, ttl, now)).To(BeTrue())
+	})
+
+	It("counts from creation for caches without recorded use", func() {
+		Expect(buildcache.Expired(cache(now.Add(-24*time.Hour), ""), ttl, now)).To(BeFalse())
+		Expect(buildcache.Expired(cache(now.Add(-8*24*time.Hour), ""), ttl, now)).To(BeTrue())
+	})
+
+	It("counts from creation for caches with a bad record of use", func() {
+		Expect(buildcache.Expired(cache(now.Add(-24*time.Hour), "yesterday"), ttl, now)).To(BeFalse())
+	})
+})
@@ -20,9 +20,12 @@ var CmdApp = &cobra.Command{
 
 func init() {
 	CmdAppList.Flags().Bool("all", false, "list all applications")
+	CmdAppShow.Flags().Bool("watch", false, "keep showing the application as it changes")
 	CmdAppLogs.Flags().Bool("follow", false, "follow the logs of the application")
 	CmdAppLogs.Flags().Bool("staging", false, "show the staging logs of the application")
 	CmdAppExec.Flags().StringP("instance", "i", "", "The name of the instance to shell to")
+	CmdAppMetrics.Flags().String("since", "1h", "how far back to show the resource usage")
+	CmdAppMetrics.Flags().String("step", "1m", "interval to average the resource usage over")
 	CmdAppPortForward.Flags().StringSliceVar(&portForwardAddress, "address", []string{"localhost"}, "Addresses to listen on (comma separated). Only accepts IP addresses or localhost as a value. When localhost is supplied, kubectl will try to bind on both 127.0.0.1 and ::1 and will fail if neither of these addresses are available to bind.")
 	CmdAppPortForward.Flags().StringVarP(&portForwardInstance, "instance", "i", "", "The name of the instance to shell to")
 
@@ -32,6 +35,8 @@ func init() {
 	bindOption(CmdAppUpdate)
 	envOption(CmdAppCreate)
 	envOption(CmdAppUpdate)
+	stagingEnvOption(CmdAppCreate)
+	stagingEnvOption(CmdAppUpdate)
 	instancesOption(CmdAppCreate)
 	instancesOption(CmdAppUpdate)
 
@@ -48,12 +53,34 @@ func init() {
 
 	CmdApp.AddCommand(CmdAppManifest)
 	CmdApp.AddCommand(CmdAppShow)
+	CmdApp.AddCommand(CmdAppMetrics)
 	CmdApp.AddCommand(CmdAppExport)
 	CmdApp.AddCommand(CmdAppUpdate)
 	CmdApp.AddCommand(CmdAppDelete)
+	CmdApp.AddCommand(CmdAppProtect)
+	CmdApp.AddCommand(CmdAppUnprotect)
 	CmdApp.AddCommand(CmdAppPush) // See push.go for implementation
 	CmdApp.AddCommand(CmdAppRestart)
 	CmdApp.AddCommand(CmdAppRestage)
+	CmdApp.AddCommand(CmdAppStage)
+
+	CmdAppStage.AddCommand(CmdAppStageCancel)
+	CmdAppStage.AddCommand(CmdAppStageStatus)
+
+	CmdApp.AddCommand(CmdAppBuildCache)
+
+	CmdAppBuildCache.AddCommand(CmdAppBuildCacheShow)
+	CmdAppBuildCache.AddCommand(CmdAppBuildCachePurge)
+
+	CmdApp.AddCommand(CmdAppWebhook)
+
+	CmdAppWebhook.AddCommand(CmdAppWebhookEnable)
+	CmdAppWebhook.AddCommand(CmdAppWebhookShow)
+	CmdAppWebhook.AddCommand(CmdAppWebhookRotate)
+
+	CmdAppSBOM.Flags().String("stage", "", "ID of the staging run whose image to show, instead of the running image")
+	CmdAppSBOM.Flags().StringP("output", "o", "", "Directory to write the SBOM documents and the provenance record to")
+	CmdApp.AddCommand(CmdAppSBOM)
 }
 
 // CmdAppList implements the command: epinio app list
@@ -111,6 +138,11 @@ var CmdAppCreate = &cobra.Command{
 			return err
 		}
 
+		// An empty staging environment in the manifest clears the saved one.
+		if m.Staging.Environment != nil {
+			m.Configuration.StagingEnvironment = &m.Staging.Environment
+		}
+
 		err = client.AppCreate(args[0], m.Configuration)
 		// Note: errors.Wrap (nil, "...") == nil
 		return errors.Wrap(err, "error creating app")
@@ -132,12 +164,52 @@ var CmdAppShow = &cobra.Command{
 			return errors.Wrap(err, "error initializing cli")
 		}
 
-		err = client.AppShow(args[0])
+		watch, err := cmd.Flags().GetBool("watch")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --watch")
+		}
+
+		if watch {
+			err = client.AppWatch(args[0])
+		} else {
+			err = client.AppShow(args[0])
+		}
 		// Note: errors.Wrap (nil, "...") == nil
 		return errors.Wrap(err, "error showing app")
 	},
 }
 
+// CmdAppMetrics implements the command: epinio apps metrics
+var CmdAppMetrics = &cobra.Command{
+	Use:               "metrics NAME",
+	Short:             "Show the resource usage history of the named application",
+	Long:              "Show the history of CPU and memory usage, and of the number of instances, of the named application, as recorded by the server",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		since, err := cmd.Flags().GetString("since")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --since")
+		}
+
+		step, err := cmd.Flags().GetString("step")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --step")
+		}
+
+		err = client.AppMetrics(args[0], since, step)
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error showing app metrics")
+	},
+}
+
 // CmdAppExport implements the command: epinio apps export
 var CmdAppExport = &cobra.Command{
 	Use:               "export NAME DIRECTORY",
@@ -280,6 +352,11 @@ var CmdAppUpdate = &cobra.Command{
 			return errors.Wrap(err, "unable to update domains")
 		}
 
+		// An empty staging environment in the manifest clears the saved one.
+		if m.Staging.Environment != nil {
+			m.Configuration.StagingEnvironment = &m.Staging.Environment
+		}
+
 		err = client.AppUpdate(args[0], m.Configuration)
 		// Note: errors.Wrap (nil, "...") == nil
 		return errors.Wrap(err, "error updating the app")
@@ -346,3 +423,240 @@ var CmdAppRestage = &cobra.Command{
 		return errors.Wrap(err, "error restaging app")
 	},
 }
+
+// CmdAppProtect implements the command: epinio app protect
+var CmdAppProtect = &cobra.Command{
+	Use:               "protect NAME",
+	Short:             "Protect the application against deletion",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppProtect(args[0], true)
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error protecting app")
+	},
+}
+
+// CmdAppUnprotect implements the command: epinio app unprotect
+var CmdAppUnprotect = &cobra.Command{
+	Use:               "unprotect NAME",
+	Short:             "Clear the protection of the application against deletion",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppProtect(args[0], false)
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error unprotecting app")
+	},
+}
+
+// CmdAppStage implements the command: epinio app stage
+var CmdAppStage = &cobra.Command{
+	Use:   "stage",
+	Short: "Epinio application staging",
+	Long:  "Manage the staging of epinio applications",
+}
+
+// CmdAppStageCancel implements the command: epinio app stage cancel
+var CmdAppStageCancel = &cobra.Command{
+	Use:               "cancel NAME",
+	Short:             "Cancel the staging of the application",
+	Long:              "Stop the active staging run of the application, and clean up its resources",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppStageCancel(args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error cancelling app staging")
+	},
+}
+
+// CmdAppStageStatus implements the command: epinio app stage status
+var CmdAppStageStatus = &cobra.Command{
+	Use:   "status",
+	Short: "Show running and queued staging",
+	Long:  "Show the running and queued staging runs of the targeted namespace",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppStageStatus()
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error showing app staging")
+	},
+}
+
+// CmdAppBuildCache implements the command: epinio app buildcache
+var CmdAppBuildCache = &cobra.Command{
+	Use:   "buildcache",
+	Short: "Epinio application build cache",
+	Long:  "Manage the build caches of epinio applications",
+}
+
+// CmdAppBuildCacheShow implements the command: epinio app buildcache show
+var CmdAppBuildCacheShow = &cobra.Command{
+	Use:               "show NAME",
+	Short:             "Show the build cache of the application",
+	Long:              "Show the size, storage class, and last use of the build cache of the application",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppBuildCacheShow(cmd.Context(), args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error showing app build cache")
+	},
+}
+
+// CmdAppBuildCachePurge implements the command: epinio app buildcache purge
+var CmdAppBuildCachePurge = &cobra.Command{
+	Use:               "purge NAME",
+	Short:             "Purge the build cache of the application",
+	Long:              "Remove the build cache of the application. The next staging starts with an empty cache.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppBuildCachePurge(cmd.Context(), args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error purging app build cache")
+	},
+}
+
+// CmdAppSBOM implements the command: epinio app sbom
+var CmdAppSBOM = &cobra.Command{
+	Use:               "sbom NAME",
+	Short:             "Show the SBOM and provenance of the application image",
+	Long:              "Show the software bill of materials and the provenance of the running image of the application, as recorded at staging",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		stageID, err := cmd.Flags().GetString("stage")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --stage")
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --output")
+		}
+
+		err = client.AppSBOM(cmd.Context(), args[0], stageID, output)
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error showing app SBOM")
+	},
+}
+
+// CmdAppWebhook implements the command: epinio app webhook
+var CmdAppWebhook = &cobra.Command{
+	Use:   "webhook",
+	Short: "Epinio application git webhook",
+	Long:  "Manage the git webhooks restaging and deploying epinio applications built from git on push",
+}
+
+// CmdAppWebhookEnable implements the command: epinio app webhook enable
+var CmdAppWebhookEnable = &cobra.Command{
+	Use:               "enable NAME",
+	Short:             "Enable the git webhook of the application",
+	Long:              "Enable the git webhook of the application, and show its URL and secret",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppWebhookEnable(cmd.Context(), args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error enabling app webhook")
+	},
+}
+
+// CmdAppWebhookShow implements the command: epinio app webhook show
+var CmdAppWebhookShow = &cobra.Command{
+	Use:               "show NAME",
+	Short:             "Show the git webhook of the application",
+	Long:              "Show the URL and secret of the git webhook of the application",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppWebhookShow(cmd.Context(), args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error showing app webhook")
+	},
+}
+
+// CmdAppWebhookRotate implements the command: epinio app webhook rotate
+var CmdAppWebhookRotate = &cobra.Command{
+	Use:               "rotate NAME",
+	Short:             "Replace the secret of the git webhook of the application",
+	Long:              "Replace the secret of the git webhook of the application with a new one",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppWebhookRotate(cmd.Context(), args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error rotating app webhook secret")
+	},
+}
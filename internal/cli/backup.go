@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	CmdNamespaceBackup.Flags().Bool("images", false, "include the images of the applications staged by epinio")
+	CmdNamespaceRestore.Flags().String("as", "", "name of the namespace to create, instead of the name in the archive")
+}
+
+// CmdNamespaceBackup implements the command: epinio namespace backup
+var CmdNamespaceBackup = &cobra.Command{
+	Use:               "backup NAME FILE",
+	Short:             "Back up a namespace",
+	Long:              "Store the configurations, services and applications of the namespace in a portable archive file, optionally with the images of the applications.",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: matchingNamespaceFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		images, err := cmd.Flags().GetBool("images")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --images")
+		}
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.NamespaceBackup(args[0], args[1], images)
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error backing up namespace")
+	},
+}
+
+// CmdNamespaceRestore implements the command: epinio namespace restore
+var CmdNamespaceRestore = &cobra.Command{
+	Use:   "restore FILE",
+	Short: "Restore a namespace from a backup",
+	Long:  "Recreate the namespace of the archive file, with its configurations, services and applications, and deploy the applications. The namespace must not exist. Requires admin rights.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		newName, err := cmd.Flags().GetString("as")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --as")
+		}
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.NamespaceRestore(args[0], newName)
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error restoring namespace")
+	},
+}
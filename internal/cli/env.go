@@ -2,8 +2,10 @@ package cli
 
 import (
 	"context"
+	"strings"
 
 	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/epinio/epinio/internal/envfile"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -16,6 +18,18 @@ var CmdAppEnv = &cobra.Command{
 }
 
 func init() {
+	CmdEnvList.Flags().Bool("reveal", false, "show the values of secret variables")
+	CmdEnvShow.Flags().Bool("reveal", false, "show the value of a secret variable")
+	CmdEnvSet.Flags().String("from-configuration", "", "take the value from the KEY of the bound CONFIGURATION, given as CONFIGURATION:KEY")
+	CmdEnvSet.Flags().Bool("secret", false, "mark the variable as secret, masking its value in listings")
+	CmdEnvUnset.Flags().Bool("staging", false, "remove the variable from the staging environment")
+	CmdEnvImport.Flags().Bool("replace", false, "replace the entire environment with the contents of the file")
+	CmdEnvImport.Flags().Bool("secret", false, "mark the imported variables as secret")
+	CmdEnvImport.Flags().String("format", "", "file format (dotenv, json, yaml), derived from the file extension by default")
+	CmdEnvExport.Flags().String("format", envfile.FormatDotenv, "output format (dotenv, json, yaml)")
+
+	CmdAppEnv.AddCommand(CmdEnvExport)
+	CmdAppEnv.AddCommand(CmdEnvImport)
 	CmdAppEnv.AddCommand(CmdEnvList)
 	CmdAppEnv.AddCommand(CmdEnvSet)
 	CmdAppEnv.AddCommand(CmdEnvShow)
@@ -32,12 +46,17 @@ var CmdEnvList = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
 
+		reveal, err := cmd.Flags().GetBool("reveal")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --reveal")
+		}
+
 		client, err := usercmd.New()
 		if err != nil {
 			return errors.Wrap(err, "error initializing cli")
 		}
 
-		err = client.EnvList(cmd.Context(), args[0])
+		err = client.EnvList(cmd.Context(), args[0], reveal)
 		if err != nil {
 			return errors.Wrap(err, "error listing app environment")
 		}
@@ -48,20 +67,46 @@ var CmdEnvList = &cobra.Command{
 
 // CmdEnvSet implements the command: epinio app env set
 var CmdEnvSet = &cobra.Command{
-	Use:   "set APPNAME NAME VALUE",
+	Use:   "set APPNAME NAME (VALUE | --from-configuration CONFIGURATION:KEY)",
 	Short: "Extend application environment",
-	Long:  "Add or change environment variable of named application",
-	Args:  cobra.ExactArgs(3),
+	Long:  "Add or change environment variable of named application. The value is either given literally, or taken from a key of a configuration bound to the application.",
+	Args:  cobra.RangeArgs(2, 3),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
 
+		from, err := cmd.Flags().GetString("from-configuration")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --from-configuration")
+		}
+
+		secret, err := cmd.Flags().GetBool("secret")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --secret")
+		}
+
+		if from == "" && len(args) != 3 {
+			return errors.New("either a value or --from-configuration is required")
+		}
+		if from != "" && len(args) != 2 {
+			return errors.New("a value cannot be used together with --from-configuration")
+		}
+
 		client, err := usercmd.New()
 
 		if err != nil {
 			return errors.Wrap(err, "error initializing cli")
 		}
 
-		err = client.EnvSet(cmd.Context(), args[0], args[1], args[2])
+		if from != "" {
+			pieces := strings.SplitN(from, ":", 2)
+			if len(pieces) != 2 || pieces[0] == "" || pieces[1] == "" {
+				return errors.New("bad --from-configuration, expected CONFIGURATION:KEY")
+			}
+
+			err = client.EnvSetFrom(cmd.Context(), args[0], args[1], pieces[0], pieces[1])
+		} else {
+			err = client.EnvSet(cmd.Context(), args[0], args[1], args[2], secret)
+		}
 		if err != nil {
 			return errors.Wrap(err, "error setting into app environment")
 		}
@@ -87,6 +132,90 @@ var CmdEnvSet = &cobra.Command{
 	},
 }
 
+// CmdEnvImport implements the command: epinio app env import
+var CmdEnvImport = &cobra.Command{
+	Use:   "import APPNAME FILE",
+	Short: "Import application environment",
+	Long:  "Add the environment variables of a dotenv, JSON, or YAML file to the named application, or replace its environment with them.",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		replace, err := cmd.Flags().GetBool("replace")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --replace")
+		}
+		secret, err := cmd.Flags().GetBool("secret")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --secret")
+		}
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --format")
+		}
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.EnvImport(cmd.Context(), args[0], args[1], format, replace, secret)
+		if err != nil {
+			return errors.Wrap(err, "error importing app environment")
+		}
+
+		return nil
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		// #args == 1: the file, complete with file names.
+		if len(args) == 1 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+		if len(args) > 1 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		app, err := usercmd.New()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		// #args == 0: application name.
+		matches := app.AppsMatching(toComplete)
+
+		return matches, cobra.ShellCompDirectiveNoFileComp
+	},
+}
+
+// CmdEnvExport implements the command: epinio app env export
+var CmdEnvExport = &cobra.Command{
+	Use:               "export APPNAME",
+	Short:             "Export application environment",
+	Long:              "Write the environment variables of the named application to stdout, as dotenv, JSON, or YAML. Secret values are written in the clear.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --format")
+		}
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.EnvExport(cmd.Context(), args[0], format)
+		if err != nil {
+			return errors.Wrap(err, "error exporting app environment")
+		}
+
+		return nil
+	},
+}
+
 // CmdEnvShow implements the command: epinio app env show
 var CmdEnvShow = &cobra.Command{
 	Use:   "show APPNAME NAME",
@@ -95,13 +224,18 @@ var CmdEnvShow = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
 
+		reveal, err := cmd.Flags().GetBool("reveal")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --reveal")
+		}
+
 		client, err := usercmd.New()
 
 		if err != nil {
 			return errors.Wrap(err, "error initializing cli")
 		}
 
-		err = client.EnvShow(cmd.Context(), args[0], args[1])
+		err = client.EnvShow(cmd.Context(), args[0], args[1], reveal)
 		if err != nil {
 			return errors.Wrap(err, "error accessing app environment")
 		}
@@ -141,13 +275,18 @@ var CmdEnvUnset = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
 
+		staging, err := cmd.Flags().GetBool("staging")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --staging")
+		}
+
 		client, err := usercmd.New()
 
 		if err != nil {
 			return errors.Wrap(err, "error initializing cli")
 		}
 
-		err = client.EnvUnset(cmd.Context(), args[0], args[1])
+		err = client.EnvUnset(cmd.Context(), args[0], args[1], staging)
 		if err != nil {
 			return errors.Wrap(err, "error removing from app environment")
 		}
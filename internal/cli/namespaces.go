@@ -36,11 +36,17 @@ func init() {
 
 	flags := CmdNamespaceDelete.Flags()
 	flags.BoolVarP(&force, "force", "f", false, "force namespace deletion")
+	flags.Bool("dry-run", false, "list what would be deleted, without deleting anything")
 
 	CmdNamespace.AddCommand(CmdNamespaceCreate)
 	CmdNamespace.AddCommand(CmdNamespaceList)
 	CmdNamespace.AddCommand(CmdNamespaceDelete)
 	CmdNamespace.AddCommand(CmdNamespaceShow)
+	CmdNamespace.AddCommand(CmdNamespaceProtect)
+	CmdNamespace.AddCommand(CmdNamespaceUnprotect)
+	CmdNamespace.AddCommand(CmdNamespaceQuota)
+	CmdNamespace.AddCommand(CmdNamespaceBackup)
+	CmdNamespace.AddCommand(CmdNamespaceRestore)
 }
 
 // CmdNamespaces implements the command: epinio namespace list
@@ -97,7 +103,11 @@ var CmdNamespaceDelete = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		if !force {
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+		if !force && !dryRun {
 			cmd.Printf("You are about to delete namespace %s and everything it includes, i.e. applications, configurations, etc. Are you sure? (y/n): ", args[0])
 			if !askConfirmation(cmd) {
 				return errors.New("Cancelled by user")
@@ -114,6 +124,17 @@ var CmdNamespaceDelete = &cobra.Command{
 			return errors.Wrap(err, "error initializing cli")
 		}
 
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --dry-run")
+		}
+
+		if dryRun {
+			err = client.DeleteNamespacePreview(args[0])
+			// Note: errors.Wrap (nil, "...") == nil
+			return errors.Wrap(err, "error previewing deletion of epinio-controlled namespace")
+		}
+
 		err = client.DeleteNamespace(args[0])
 		if err != nil {
 			return errors.Wrap(err, "error deleting epinio-controlled namespace")
@@ -123,6 +144,46 @@ var CmdNamespaceDelete = &cobra.Command{
 	},
 }
 
+// CmdNamespaceProtect implements the command: epinio namespace protect
+var CmdNamespaceProtect = &cobra.Command{
+	Use:               "protect NAME",
+	Short:             "Protects an epinio-controlled namespace against deletion",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingNamespaceFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.ProtectNamespace(args[0], true)
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error protecting epinio-controlled namespace")
+	},
+}
+
+// CmdNamespaceUnprotect implements the command: epinio namespace unprotect
+var CmdNamespaceUnprotect = &cobra.Command{
+	Use:               "unprotect NAME",
+	Short:             "Clears the protection of an epinio-controlled namespace against deletion",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingNamespaceFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.ProtectNamespace(args[0], false)
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error unprotecting epinio-controlled namespace")
+	},
+}
+
 // CmdNamespaceShow implements the command: epinio namespace show
 var CmdNamespaceShow = &cobra.Command{
 	Use:               "show NAME",
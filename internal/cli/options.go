@@ -66,3 +66,8 @@ func bindOption(cmd *cobra.Command) {
 func envOption(cmd *cobra.Command) {
 	cmd.Flags().StringSliceP("env", "e", []string{}, "environment variables to be used")
 }
+
+// stagingEnvOption initializes the --staging-env option for the provided command
+func stagingEnvOption(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("staging-env", []string{}, "environment variables to be used by staging only")
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	CmdPolicy.AddCommand(CmdPolicyList)
+	CmdPolicy.AddCommand(CmdPolicyShow)
+}
+
+// CmdPolicy implements the command: epinio policy
+var CmdPolicy = &cobra.Command{
+	Use:           "policy",
+	Aliases:       []string{"policies"},
+	Short:         "Epinio policies",
+	Long:          `Inspect the admission policies applying to the applications of the targeted namespace`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Usage(); err != nil {
+			return err
+		}
+		return fmt.Errorf(`Unknown method "%s"`, args[0])
+	},
+}
+
+// CmdPolicyList implements the command: epinio policy list
+var CmdPolicyList = &cobra.Command{
+	Use:   "list",
+	Short: "List the policies applying to the namespace",
+	Long:  "List the global policies and the policies of the targeted namespace",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.Policies(cmd.Context())
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error listing policies")
+	},
+}
+
+// CmdPolicyShow implements the command: epinio policy show
+var CmdPolicyShow = &cobra.Command{
+	Use:   "show NAME",
+	Short: "Show the details of a policy",
+	Long:  "Show the rules of the named policy applying to the targeted namespace",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.PolicyShow(cmd.Context(), args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error showing policy")
+	},
+}
@@ -21,10 +21,13 @@ func init() {
 	CmdAppPush.Flags().StringP("path", "p", "", "Path to application sources.")
 	CmdAppPush.Flags().String("builder-image", "", "Paketo builder image to use for staging")
 	CmdAppPush.Flags().String("app-chart", "", "App chart to use for deployment")
+	CmdAppPush.Flags().Bool("force-build", false, "Stage the application even if its sources and staging settings are unchanged")
+	CmdAppPush.Flags().Bool("dry-run-files", false, "List the files which would be uploaded, and their total size, without pushing")
 
 	routeOption(CmdAppPush)
 	bindOption(CmdAppPush)
 	envOption(CmdAppPush)
+	stagingEnvOption(CmdAppPush)
 	instancesOption(CmdAppPush)
 }
 
@@ -100,8 +103,22 @@ var CmdAppPush = &cobra.Command{
 			}
 		}
 
+		forceBuild, err := cmd.Flags().GetBool("force-build")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --force-build")
+		}
+
 		params := usercmd.PushParams{
 			ApplicationManifest: m,
+			ForceBuild:          forceBuild,
+		}
+
+		dryRunFiles, err := cmd.Flags().GetBool("dry-run-files")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --dry-run-files")
+		}
+		if dryRunFiles {
+			return client.PushFiles(params)
 		}
 
 		err = client.Push(cmd.Context(), params)
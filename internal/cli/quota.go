@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	flags := CmdNamespaceQuotaSet.Flags()
+	flags.Int32("apps", 0, "maximum number of applications, 0 for no limit")
+	flags.Int32("instances", 0, "maximum number of instances of all applications, 0 for no limit")
+	flags.Int32("services", 0, "maximum number of services, 0 for no limit")
+	flags.Int32("configurations", 0, "maximum number of configurations, 0 for no limit")
+	flags.String("memory", "", "maximum memory requested by all workloads, e.g. 4Gi, empty for no limit")
+	flags.String("cpu", "", "maximum CPU requested by all workloads, e.g. 2 or 500m, empty for no limit")
+
+	CmdNamespaceQuota.AddCommand(CmdNamespaceQuotaSet)
+	CmdNamespaceQuota.AddCommand(CmdNamespaceQuotaShow)
+}
+
+// CmdNamespaceQuota implements the command: epinio namespace quota
+var CmdNamespaceQuota = &cobra.Command{
+	Use:           "quota",
+	Short:         "Namespace quotas",
+	Long:          `Manage the quotas limiting the resources of epinio-controlled namespaces`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Usage(); err != nil {
+			return err
+		}
+		return fmt.Errorf(`Unknown method "%s"`, args[0])
+	},
+}
+
+// CmdNamespaceQuotaSet implements the command: epinio namespace quota set
+var CmdNamespaceQuotaSet = &cobra.Command{
+	Use:               "set NAME",
+	Short:             "Set the quota of a namespace",
+	Long:              "Change the limits of the quota of the namespace given by the options. Limits not given are kept. Requires admin rights.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingNamespaceFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		changes := usercmd.NamespaceQuotaChanges{}
+		flags := cmd.Flags()
+		for name, field := range map[string]**int32{
+			"apps":           &changes.Apps,
+			"instances":      &changes.Instances,
+			"services":       &changes.Services,
+			"configurations": &changes.Configurations,
+		} {
+			if !flags.Changed(name) {
+				continue
+			}
+			value, err := flags.GetInt32(name)
+			if err != nil {
+				return errors.Wrap(err, "error reading option --"+name)
+			}
+			*field = &value
+		}
+		for name, field := range map[string]**string{
+			"memory": &changes.Memory,
+			"cpu":    &changes.CPU,
+		} {
+			if !flags.Changed(name) {
+				continue
+			}
+			value, err := flags.GetString(name)
+			if err != nil {
+				return errors.Wrap(err, "error reading option --"+name)
+			}
+			*field = &value
+		}
+
+		err = client.NamespaceQuotaSet(args[0], changes)
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error setting namespace quota")
+	},
+}
+
+// CmdNamespaceQuotaShow implements the command: epinio namespace quota show
+var CmdNamespaceQuotaShow = &cobra.Command{
+	Use:               "show NAME",
+	Short:             "Show the quota of a namespace",
+	Long:              "Show the limits of the quota of the namespace, and the use of the limited resources",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingNamespaceFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.NamespaceQuotaShow(args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error showing namespace quota")
+	},
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	CmdRegistryCredentialAdd.Flags().StringP("username", "u", "", "username for the registry")
+	CmdRegistryCredentialAdd.Flags().StringP("password", "p", "", "password for the registry")
+
+	CmdRegistryCredential.AddCommand(CmdRegistryCredentialAdd)
+	CmdRegistryCredential.AddCommand(CmdRegistryCredentialList)
+	CmdRegistryCredential.AddCommand(CmdRegistryCredentialRemove)
+}
+
+// CmdRegistryCredential implements the command: epinio registry-credential
+var CmdRegistryCredential = &cobra.Command{
+	Use:           "registry-credential",
+	Aliases:       []string{"registry-credentials"},
+	Short:         "Epinio registry credentials",
+	Long:          `Manage the credentials of private container registries, used to pull the application images of the targeted namespace`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Usage(); err != nil {
+			return err
+		}
+		return fmt.Errorf(`Unknown method "%s"`, args[0])
+	},
+}
+
+// CmdRegistryCredentialAdd implements the command: epinio registry-credential add
+var CmdRegistryCredentialAdd = &cobra.Command{
+	Use:   "add NAME URL",
+	Short: "Add a registry credential to the namespace",
+	Long:  "Add a credential of the private container registry at URL to the targeted namespace. The application images of the namespace are pulled with it.",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		username, err := cmd.Flags().GetString("username")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --username")
+		}
+		password, err := cmd.Flags().GetString("password")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --password")
+		}
+
+		err = client.RegistryCredentialAdd(cmd.Context(), args[0], args[1], username, password)
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error adding registry credential")
+	},
+}
+
+// CmdRegistryCredentialList implements the command: epinio registry-credential list
+var CmdRegistryCredentialList = &cobra.Command{
+	Use:   "list",
+	Short: "List the registry credentials of the namespace",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.RegistryCredentials(cmd.Context())
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error listing registry credentials")
+	},
+}
+
+// CmdRegistryCredentialRemove implements the command: epinio registry-credential remove
+var CmdRegistryCredentialRemove = &cobra.Command{
+	Use:   "remove NAME",
+	Short: "Remove a registry credential from the namespace",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.RegistryCredentialRemove(cmd.Context(), args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error removing registry credential")
+	},
+}
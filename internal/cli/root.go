@@ -89,6 +89,8 @@ func init() {
 	rootCmd.AddCommand(CmdSettings)
 	rootCmd.AddCommand(CmdInfo)
 	rootCmd.AddCommand(CmdNamespace)
+	rootCmd.AddCommand(CmdRegistryCredential)
+	rootCmd.AddCommand(CmdPolicy)
 	rootCmd.AddCommand(CmdAppPush) // shorthand access to `app push`.
 	rootCmd.AddCommand(CmdApp)
 	rootCmd.AddCommand(CmdTarget)
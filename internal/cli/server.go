@@ -14,7 +14,15 @@ import (
 
 	"github.com/epinio/epinio/helpers/termui"
 	"github.com/epinio/epinio/helpers/tracelog"
+	"github.com/epinio/epinio/internal/appevents"
+	"github.com/epinio/epinio/internal/appmetrics"
+	"github.com/epinio/epinio/internal/blobgc"
+	"github.com/epinio/epinio/internal/blobstore"
+	"github.com/epinio/epinio/internal/buildcache"
 	"github.com/epinio/epinio/internal/cli/server"
+	"github.com/epinio/epinio/internal/imagegc"
+	"github.com/epinio/epinio/internal/operations"
+	"github.com/epinio/epinio/internal/stagingqueue"
 	"github.com/epinio/epinio/internal/version"
 	"github.com/gin-gonic/gin"
 
@@ -57,6 +65,74 @@ func init() {
 	flags.String("ingress-class-name", "", "(INGRESS_CLASS_NAME) Name of the ingress class to use for apps. Leave empty to add no ingressClassName to the ingress.")
 	viper.BindPFlag("ingress-class-name", flags.Lookup("ingress-class-name"))
 	viper.BindEnv("ingress-class-name", "INGRESS_CLASS_NAME")
+
+	flags.Duration("metrics-interval", time.Minute, "(METRICS_INTERVAL) Interval between samples of application resource usage. Set to 0 to disable the collection of metrics history.")
+	viper.BindPFlag("metrics-interval", flags.Lookup("metrics-interval"))
+	viper.BindEnv("metrics-interval", "METRICS_INTERVAL")
+
+	flags.Duration("metrics-history", 24*time.Hour, "(METRICS_HISTORY) How long to keep the sampled application resource usage")
+	viper.BindPFlag("metrics-history", flags.Lookup("metrics-history"))
+	viper.BindEnv("metrics-history", "METRICS_HISTORY")
+
+	flags.Duration("staging-timeout", 0, "(STAGING_TIMEOUT) Maximum duration of a staging run. Set to 0 to use the default application build timeout.")
+	viper.BindPFlag("staging-timeout", flags.Lookup("staging-timeout"))
+	viper.BindEnv("staging-timeout", "STAGING_TIMEOUT")
+
+	flags.Int("staging-max-concurrent", 0, "(STAGING_MAX_CONCURRENT) Maximum number of staging runs in the cluster at the same time. Further runs are queued. Set to 0 for no limit.")
+	viper.BindPFlag("staging-max-concurrent", flags.Lookup("staging-max-concurrent"))
+	viper.BindEnv("staging-max-concurrent", "STAGING_MAX_CONCURRENT")
+
+	flags.Int("staging-max-concurrent-namespace", 0, "(STAGING_MAX_CONCURRENT_NAMESPACE) Maximum number of staging runs per namespace at the same time. Further runs are queued. Set to 0 for no limit.")
+	viper.BindPFlag("staging-max-concurrent-namespace", flags.Lookup("staging-max-concurrent-namespace"))
+	viper.BindEnv("staging-max-concurrent-namespace", "STAGING_MAX_CONCURRENT_NAMESPACE")
+
+	flags.String("staging-cache-size", buildcache.DefaultSize, "(STAGING_CACHE_SIZE) Size of new application build caches.")
+	viper.BindPFlag("staging-cache-size", flags.Lookup("staging-cache-size"))
+	viper.BindEnv("staging-cache-size", "STAGING_CACHE_SIZE")
+
+	flags.String("staging-cache-storage-class", "", "(STAGING_CACHE_STORAGE_CLASS) Storage class of new application build caches. Empty for the cluster default.")
+	viper.BindPFlag("staging-cache-storage-class", flags.Lookup("staging-cache-storage-class"))
+	viper.BindEnv("staging-cache-storage-class", "STAGING_CACHE_STORAGE_CLASS")
+
+	flags.Duration("staging-cache-ttl", 0, "(STAGING_CACHE_TTL) Remove the build caches of applications not staged for this long. Set to 0 to keep caches until their application is deleted.")
+	viper.BindPFlag("staging-cache-ttl", flags.Lookup("staging-cache-ttl"))
+	viper.BindEnv("staging-cache-ttl", "STAGING_CACHE_TTL")
+
+	flags.Int("operation-workers", operations.DefaultWorkers, "(OPERATION_WORKERS) Number of long-running operations, like git imports, run at the same time. Further operations wait. Operations are kept in memory, and require a single replica of the API server.")
+	viper.BindPFlag("operation-workers", flags.Lookup("operation-workers"))
+	viper.BindEnv("operation-workers", "OPERATION_WORKERS")
+
+	flags.Duration("operation-retention", operations.DefaultRetention, "(OPERATION_RETENTION) How long to keep the state of finished long-running operations")
+	viper.BindPFlag("operation-retention", flags.Lookup("operation-retention"))
+	viper.BindEnv("operation-retention", "OPERATION_RETENTION")
+
+	flags.String("blob-store", blobstore.BackendS3, "(BLOB_STORE) Storage of the application source blobs [s3,filesystem]. The filesystem store keeps them in a directory of the server, usually on a PVC, which has to be ReadWriteMany for more than one server replica.")
+	viper.BindPFlag("blob-store", flags.Lookup("blob-store"))
+	viper.BindEnv("blob-store", "BLOB_STORE")
+
+	flags.String("blob-store-path", blobstore.DefaultPath, "(BLOB_STORE_PATH) Directory of the filesystem blob store")
+	viper.BindPFlag("blob-store-path", flags.Lookup("blob-store-path"))
+	viper.BindEnv("blob-store-path", "BLOB_STORE_PATH")
+
+	flags.String("blob-store-url", "", "(BLOB_STORE_URL) URL of the server, as reached by the staging jobs fetching the blobs of the filesystem blob store. Leave empty to use the in-cluster service of the server.")
+	viper.BindPFlag("blob-store-url", flags.Lookup("blob-store-url"))
+	viper.BindEnv("blob-store-url", "BLOB_STORE_URL")
+
+	flags.Duration("blob-gc-interval", blobgc.DefaultInterval, "(BLOB_GC_INTERVAL) Time between the removals of orphaned application source blobs. Set to 0 to disable the removal.")
+	viper.BindPFlag("blob-gc-interval", flags.Lookup("blob-gc-interval"))
+	viper.BindEnv("blob-gc-interval", "BLOB_GC_INTERVAL")
+
+	flags.Duration("blob-gc-grace-period", blobgc.DefaultGracePeriod, "(BLOB_GC_GRACE_PERIOD) Age of orphaned application source blobs before they are removed")
+	viper.BindPFlag("blob-gc-grace-period", flags.Lookup("blob-gc-grace-period"))
+	viper.BindEnv("blob-gc-grace-period", "BLOB_GC_GRACE_PERIOD")
+
+	flags.Duration("image-gc-interval", imagegc.DefaultInterval, "(IMAGE_GC_INTERVAL) Time between the removals of old application images from the registry. Set to 0 to disable the removal.")
+	viper.BindPFlag("image-gc-interval", flags.Lookup("image-gc-interval"))
+	viper.BindEnv("image-gc-interval", "IMAGE_GC_INTERVAL")
+
+	flags.Int("image-retention", 0, "(IMAGE_RETENTION) Number of images kept per application in the registry, besides those of recent releases. 0 keeps all images. Namespaces override it with the annotation "+imagegc.RetentionAnnotation+". The registry has to allow deletions.")
+	viper.BindPFlag("image-retention", flags.Lookup("image-retention"))
+	viper.BindEnv("image-retention", "IMAGE_RETENTION")
 }
 
 // CmdServer implements the command: epinio server
@@ -84,6 +160,21 @@ var CmdServer = &cobra.Command{
 		listeningPort := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
 		ui.Normal().Msg("listening on localhost on port " + listeningPort)
 
+		// Background subsystems run until the server shuts down.
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		appmetrics.Start(ctx, logger, viper.GetDuration("metrics-interval"), viper.GetDuration("metrics-history"))
+		appevents.Start(ctx, logger)
+		stagingqueue.Start(ctx, logger, stagingqueue.Limits{
+			Global:    viper.GetInt("staging-max-concurrent"),
+			Namespace: viper.GetInt("staging-max-concurrent-namespace"),
+		})
+		buildcache.Start(ctx, logger, viper.GetDuration("staging-cache-ttl"))
+		operations.Start(ctx, logger, viper.GetInt("operation-workers"), viper.GetDuration("operation-retention"))
+		blobgc.Start(ctx, logger, viper.GetDuration("blob-gc-interval"), viper.GetDuration("blob-gc-grace-period"))
+		imagegc.Start(ctx, logger, viper.GetDuration("image-gc-interval"), viper.GetInt("image-retention"))
+
 		return startServerGracefully(listener, handler)
 	},
 }
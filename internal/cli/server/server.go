@@ -43,6 +43,8 @@ func NewHandler(logger logr.Logger) (*gin.Engine, error) {
 	// | Path              | Notes      | Logging
 	// | ---               | ---        | ----
 	// | <Root>/...        | API        | Via "<Root>" Group
+	// | <Root>/webhooks/... | Webhooks | ditto, no user authentication
+	// | <Root>/blobs/...  | Blobs      | ditto, no user authentication
 	// | /ready            | L/R Probes |
 	// | /namespaces/target/:namespace | ditto      | ditto
 
@@ -112,6 +114,20 @@ func NewHandler(logger logr.Logger) (*gin.Engine, error) {
 		apiv1.Lemon(apiRoutesGroup)
 	}
 
+	// Register webhook routes. No user authentication, the handlers check the
+	// signatures of the notifications.
+	{
+		hookRoutesGroup := router.Group(apiv1.Root)
+		apiv1.Hook(hookRoutesGroup)
+	}
+
+	// Register blob routes. No user authentication, the handlers check the download
+	// tokens of the requests.
+	{
+		blobRoutesGroup := router.Group(apiv1.Root)
+		apiv1.Blob(blobRoutesGroup)
+	}
+
 	// Register web socket routes
 	{
 		wapiRoutesGroup := router.Group(apiv1.WsRoot,
@@ -26,6 +26,8 @@ func init() {
 	CmdServices.AddCommand(CmdServiceUnbind)
 	CmdServices.AddCommand(CmdServiceShow)
 	CmdServices.AddCommand(CmdServiceDelete)
+	CmdServices.AddCommand(CmdServiceProtect)
+	CmdServices.AddCommand(CmdServiceUnprotect)
 	CmdServices.AddCommand(CmdServiceList)
 
 	CmdServiceList.Flags().Bool("all", false, "list all services")
@@ -100,6 +102,42 @@ var CmdServiceShow = &cobra.Command{
 	},
 }
 
+var CmdServiceProtect = &cobra.Command{
+	Use:               "protect SERVICENAME",
+	Short:             "Protect service SERVICENAME against deletion",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingServiceFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.ServiceProtect(args[0], true)
+		return errors.Wrap(err, "error protecting service")
+	},
+}
+
+var CmdServiceUnprotect = &cobra.Command{
+	Use:               "unprotect SERVICENAME",
+	Short:             "Clear the protection of service SERVICENAME against deletion",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingServiceFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.ServiceProtect(args[0], false)
+		return errors.Wrap(err, "error unprotecting service")
+	},
+}
+
 var CmdServiceDelete = &cobra.Command{
 	Use:               "delete SERVICENAME",
 	Short:             "Delete service SERVICENAME",
@@ -17,6 +17,7 @@ import (
 
 	"github.com/epinio/epinio/helpers/bytes"
 	"github.com/epinio/epinio/helpers/kubernetes/tailer"
+	"github.com/epinio/epinio/helpers/termui"
 	"github.com/epinio/epinio/internal/cli/logprinter"
 	"github.com/epinio/epinio/pkg/api/core/v1/client"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
@@ -204,6 +205,122 @@ func (c *EpinioClient) AppShow(appName string) error {
 	return c.printReplicaDetails(app)
 }
 
+// AppWatch displays the information of the named app, in the targeted namespace, and
+// then redisplays it whenever the server reports an event for the app.
+func (c *EpinioClient) AppWatch(appName string) error {
+	log := c.Log.WithName("AppWatch").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	if err := c.AppShow(appName); err != nil {
+		return err
+	}
+
+	c.ui.Normal().Msg("Watching for changes, press Ctrl+C to stop")
+
+	return c.API.AppEvents(c.Settings.Namespace, appName, func(event models.AppEvent) {
+		msg := c.ui.Note().WithStringValue("Event", string(event.Type))
+		if event.StageID != "" {
+			msg = msg.WithStringValue("StageId", event.StageID)
+		}
+		if event.Replica != "" {
+			msg = msg.WithStringValue("Instance", event.Replica)
+		}
+		if event.Configuration != "" {
+			msg = msg.WithStringValue("Configuration", event.Configuration)
+		}
+		if event.Message != "" {
+			msg = msg.WithStringValue("Details", event.Message)
+		}
+		msg.Msg(event.Time)
+
+		app, err := c.API.AppShow(c.Settings.Namespace, appName)
+		if err == nil {
+			err = c.printAppDetails(app)
+		}
+		if err == nil {
+			err = c.printReplicaDetails(app)
+		}
+		if err != nil {
+			// Keep watching, the next event may show the app again.
+			c.ui.Problem().Msg(err.Error())
+		}
+	})
+}
+
+// AppMetrics displays the history of resource usage of the named app, in the targeted namespace
+func (c *EpinioClient) AppMetrics(appName, since, step string) error {
+	log := c.Log.WithName("AppMetrics").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		WithStringValue("Since", since).
+		WithStringValue("Step", step).
+		Msg("Show application metrics")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	details.Info("show application metrics")
+
+	metrics, err := c.API.AppMetrics(c.Settings.Namespace, appName, since, step)
+	if err != nil {
+		return err
+	}
+
+	if len(metrics.Samples) == 0 {
+		c.ui.Exclamation().Msg("No metrics recorded for this application yet")
+		return nil
+	}
+
+	cpu := make([]int64, len(metrics.Samples))
+	memory := make([]int64, len(metrics.Samples))
+	instances := make([]int64, len(metrics.Samples))
+	for i, s := range metrics.Samples {
+		cpu[i] = s.MilliCPUs
+		memory[i] = s.MemoryBytes
+		instances[i] = int64(s.Instances)
+	}
+
+	itoa := func(v int64) string { return strconv.FormatInt(v, 10) }
+
+	msg := c.ui.Success().WithTable("Metric", "Min", "Avg", "Max", "Last", "History")
+	msg = metricsRow(msg, "MilliCPUs", cpu, itoa)
+	msg = metricsRow(msg, "Memory", memory, bytes.ByteCountIEC)
+	msg = metricsRow(msg, "Instances", instances, itoa)
+	msg.Msg(fmt.Sprintf("Metrics from %s to %s:",
+		metrics.Samples[0].Timestamp,
+		metrics.Samples[len(metrics.Samples)-1].Timestamp))
+
+	return nil
+}
+
+// metricsRow adds a row summarizing the series of values to the table of the message.
+func metricsRow(msg *termui.Message, name string, values []int64, format func(int64) string) *termui.Message {
+	min, max, sum := values[0], values[0], int64(0)
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+		sum += v
+	}
+
+	return msg.WithTableRow(name,
+		format(min),
+		format(sum/int64(len(values))),
+		format(max),
+		format(values[len(values)-1]),
+		termui.Sparkline(values))
+}
+
 // AppExport saves the named app, in the targeted namespace, to the directory.
 func (c *EpinioClient) AppExport(appName string, directory string) error {
 	log := c.Log.WithName("Apps").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
@@ -260,7 +377,8 @@ func (c *EpinioClient) AppManifest(appName, manifestPath string) error {
 
 	details.Info("show application")
 
-	app, err := c.API.AppShow(c.Settings.Namespace, appName)
+	// The manifest carries the values of secret variables.
+	app, err := c.API.AppShowRevealed(c.Settings.Namespace, appName)
 	if err != nil {
 		return err
 	}
@@ -269,6 +387,9 @@ func (c *EpinioClient) AppManifest(appName, manifestPath string) error {
 	m.Name = appName
 	m.Configuration = app.Configuration
 	m.Origin = app.Origin
+	if app.Configuration.StagingEnvironment != nil {
+		m.Staging.Environment = *app.Configuration.StagingEnvironment
+	}
 
 	yaml, err := yaml.Marshal(m)
 	if err != nil {
@@ -305,6 +426,31 @@ func (c *EpinioClient) AppRestart(appName string) error {
 	return c.API.AppRestart(c.Settings.Namespace, appName)
 }
 
+// AppProtect sets or clears the protection of the named app against deletion
+func (c *EpinioClient) AppProtect(appName string, protected bool) error {
+	log := c.Log.WithName("AppProtect").WithValues("Namespace", c.Settings.Namespace, "Application", appName, "Protected", protected)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		Msg(protectionNote(protected, "application"))
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	_, err := c.API.AppProtect(c.Settings.Namespace, appName, protected)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg(protectionDone(protected, "Application"))
+
+	return nil
+}
+
 // AppStageID returns the last stage id of the named app, in the targeted namespace
 func (c *EpinioClient) AppStageID(appName string) (string, error) {
 	log := c.Log.WithName("Apps").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
@@ -490,8 +636,19 @@ func (c *EpinioClient) Delete(ctx context.Context, appname string) error {
 
 func (c *EpinioClient) printAppDetails(app models.App) error {
 	msg := c.ui.Success().WithTable("Key", "Value").
-		WithTableRow("Origin", app.Origin.String()).
-		WithTableRow("Created", app.Meta.CreatedAt.String())
+		WithTableRow("Origin", app.Origin.String())
+	if app.Origin.Kind == models.OriginGit && app.Origin.Git != nil {
+		if app.Origin.Git.Subdirectory != "" {
+			msg = msg.WithTableRow("Subdirectory", app.Origin.Git.Subdirectory)
+		}
+		if app.Origin.Git.Commit != "" {
+			msg = msg.WithTableRow("Commit", app.Origin.Git.Commit)
+		}
+	}
+	msg = msg.WithTableRow("Created", app.Meta.CreatedAt.String())
+	if app.Protected {
+		msg = msg.WithTableRow("Protected", "true")
+	}
 
 	var createdAt time.Time
 	var err error
@@ -540,6 +697,16 @@ func (c *EpinioClient) printAppDetails(app models.App) error {
 			msg = msg.WithTableRow("  - "+ev.Name, ev.Value)
 		}
 	}
+	for _, name := range app.Configuration.EnvironmentFrom.Names() {
+		msg = msg.WithTableRow("  - "+name, envReference(app.Configuration.EnvironmentFrom[name]))
+	}
+
+	msg = msg.WithTableRow("Staging Environment", "")
+	if app.Configuration.StagingEnvironment != nil {
+		for _, ev := range app.Configuration.StagingEnvironment.List() {
+			msg = msg.WithTableRow("  - "+ev.Name, ev.Value)
+		}
+	}
 
 	msg.Msg("Details:")
 
@@ -608,6 +775,7 @@ func (c *EpinioClient) AppRestage(appName string) error {
 
 	log.V(3).Info("stage response", "response", stageResponse)
 	stageID := stageResponse.Stage.ID
+	c.reportQueued(stageResponse)
 
 	log.V(1).Info("start tailing logs", "StageID", stageID)
 	c.stageLogs(app.Meta, stageID)
@@ -617,3 +785,88 @@ func (c *EpinioClient) AppRestage(appName string) error {
 	_, err = c.API.StagingComplete(app.Meta.Namespace, stageID)
 	return errors.Wrap(err, "waiting for staging failed")
 }
+
+// AppStageCancel stops the active staging run of an application
+func (c *EpinioClient) AppStageCancel(appName string) error {
+	log := c.Log.WithName("AppStageCancel").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		Msg("Cancel application staging")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	app, err := c.API.AppShow(c.Settings.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	if app.StageID == "" {
+		c.ui.Exclamation().Msg("Application was never staged, nothing to cancel")
+		return nil
+	}
+
+	log.V(1).Info("cancel staging", "StageID", app.StageID)
+
+	_, err = c.API.StagingCancel(app.Meta.Namespace, app.StageID)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().WithStringValue("Stage ID", app.StageID).Msg("Staging cancelled")
+	return nil
+}
+
+// AppStageStatus shows the running and queued staging runs of the targeted namespace
+func (c *EpinioClient) AppStageStatus() error {
+	log := c.Log.WithName("AppStageStatus").WithValues("Namespace", c.Settings.Namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		Msg("Show application staging")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	status, err := c.API.StagingStatus(c.Settings.Namespace)
+	if err != nil {
+		return err
+	}
+
+	limit := func(value int) string {
+		if value <= 0 {
+			return "unlimited"
+		}
+		return strconv.Itoa(value)
+	}
+
+	c.ui.Note().
+		WithStringValue("Cluster limit", limit(status.MaxConcurrent)).
+		WithStringValue("Namespace limit", limit(status.MaxConcurrentNamespace)).
+		Msg("Concurrent builds")
+
+	if len(status.Builds) == 0 {
+		c.ui.Exclamation().Msg("No builds running or queued")
+		return nil
+	}
+
+	msg := c.ui.Success().WithTable("Application", "Stage ID", "Status", "Queue Position", "Created")
+	for _, build := range status.Builds {
+		position := ""
+		if build.Position > 0 {
+			position = strconv.Itoa(build.Position)
+		}
+		msg = msg.WithTableRow(build.App.Name, build.Stage.ID, build.Status, position, build.CreatedAt)
+	}
+	msg.Msg("Builds")
+
+	return nil
+}
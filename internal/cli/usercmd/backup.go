@@ -0,0 +1,64 @@
+package usercmd
+
+import (
+	"strings"
+)
+
+// NamespaceBackup stores the archive of the named namespace in the file
+func (c *EpinioClient) NamespaceBackup(namespace, file string, images bool) error {
+	log := c.Log.WithName("NamespaceBackup").WithValues("Namespace", namespace, "File", file)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", namespace).
+		WithStringValue("File", file).
+		WithBoolValue("Images", images).
+		Msg("Backing up namespace...")
+
+	if err := c.API.NamespaceBackup(namespace, file, images); err != nil {
+		return err
+	}
+
+	c.ui.Success().
+		WithStringValue("File", file).
+		Msg("Namespace backed up.")
+
+	return nil
+}
+
+// NamespaceRestore recreates the namespace of the archive file, under the new name, if
+// not empty
+func (c *EpinioClient) NamespaceRestore(file, newName string) error {
+	log := c.Log.WithName("NamespaceRestore").WithValues("File", file, "As", newName)
+	log.Info("start")
+	defer log.Info("return")
+
+	msg := c.ui.Note().WithStringValue("File", file)
+	if newName != "" {
+		msg = msg.WithStringValue("As", newName)
+	}
+	msg.Msg("Restoring namespace...")
+
+	resp, err := c.API.NamespaceRestore(file, newName)
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Warnings) > 0 {
+		warnings := c.ui.Exclamation().WithTable("Warnings")
+		for _, warning := range resp.Warnings {
+			warnings = warnings.WithTableRow(warning)
+		}
+		warnings.Msg("Parts of the archive were not restored")
+	}
+
+	c.ui.Success().
+		WithStringValue("Namespace", resp.Namespace).
+		WithStringValue("Applications", strings.Join(resp.Apps, ", ")).
+		WithStringValue("Configurations", strings.Join(resp.Configurations, ", ")).
+		WithStringValue("Services", strings.Join(resp.Services, ", ")).
+		Msg("Namespace restored.")
+
+	return nil
+}
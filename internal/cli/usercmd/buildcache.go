@@ -0,0 +1,66 @@
+package usercmd
+
+import (
+	"context"
+)
+
+// AppBuildCacheShow displays the details of the build cache of the named application
+func (c *EpinioClient) AppBuildCacheShow(ctx context.Context, appName string) error {
+	log := c.Log.WithName("AppBuildCacheShow").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		Msg("Show application build cache")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	cache, err := c.API.AppBuildCache(c.Settings.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	lastUsed := cache.LastUsed
+	if lastUsed == "" {
+		lastUsed = "unknown"
+	}
+
+	c.ui.Success().WithTable("Key", "Value").
+		WithTableRow("Volume", cache.Name).
+		WithTableRow("Size", cache.Size).
+		WithTableRow("Storage Class", cache.StorageClass).
+		WithTableRow("Status", cache.Status).
+		WithTableRow("Created", cache.CreatedAt).
+		WithTableRow("Last Used", lastUsed).
+		Msg("Details:")
+
+	return nil
+}
+
+// AppBuildCachePurge removes the build cache of the named application
+func (c *EpinioClient) AppBuildCachePurge(ctx context.Context, appName string) error {
+	log := c.Log.WithName("AppBuildCachePurge").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		Msg("Purge application build cache")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	if _, err := c.API.AppBuildCachePurge(c.Settings.Namespace, appName); err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Build cache purged. The next staging starts with an empty cache.")
+
+	return nil
+}
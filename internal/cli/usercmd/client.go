@@ -34,14 +34,28 @@ type APIClient interface {
 	Apps(namespace string) (models.AppList, error)
 	AllApps() (models.AppList, error)
 	AppShow(namespace string, appName string) (models.App, error)
+	AppShowRevealed(namespace string, appName string) (models.App, error)
+	AppMetrics(namespace string, appName string, since string, step string) (models.AppMetricsResponse, error)
 	AppUpdate(req models.ApplicationUpdateRequest, namespace string, appName string) (models.Response, error)
 	AppDelete(namespace string, name string) (models.ApplicationDeleteResponse, error)
-	AppUpload(namespace string, name string, tarball string) (models.UploadResponse, error)
-	AppImportGit(app models.AppRef, gitRef models.GitRef) (*models.ImportGitResponse, error)
+	AppProtect(namespace string, name string, protected bool) (models.Response, error)
+	AppUpload(namespace string, name string, tarball string, sourceHash string) (models.UploadResponse, error)
+	AppUploadChunked(namespace string, name string, tarball string, sourceHash string, progress epinioapi.UploadProgress) (models.UploadResponse, error)
+	AppUploadMatch(app models.AppRef, req models.UploadMatchRequest) (models.UploadMatchResponse, error)
+	AppImportGit(app models.AppRef, gitRef models.GitRef) (*models.Operation, error)
 	AppStage(req models.StageRequest) (*models.StageResponse, error)
 	AppDeploy(req models.DeployRequest) (*models.DeployResponse, error)
 	AppLogs(namespace, appName, stageID string, follow bool, callback func(tailer.ContainerLogLine)) error
+	AppEvents(namespace, appName string, callback func(models.AppEvent)) error
 	StagingComplete(namespace string, id string) (models.Response, error)
+	StagingCancel(namespace string, id string) (models.Response, error)
+	StagingStatus(namespace string) (models.StagingStatusResponse, error)
+	AppBuildCache(namespace string, appName string) (models.BuildCache, error)
+	AppBuildCachePurge(namespace string, appName string) (models.Response, error)
+	AppSBOM(namespace string, appName string, stageID string) (models.AppSBOM, error)
+	AppWebhook(namespace string, appName string) (models.AppWebhook, error)
+	AppWebhookEnable(namespace string, appName string) (models.AppWebhook, error)
+	AppWebhookRotate(namespace string, appName string) (models.AppWebhook, error)
 	AppRunning(app models.AppRef) (models.Response, error)
 	AppExec(namespace string, appName, instance string, tty kubectlterm.TTY) error
 	AppPortForward(namespace string, appName, instance string, opts *epinioapi.PortForwardOpts) error
@@ -51,20 +65,41 @@ type APIClient interface {
 
 	// env
 	EnvList(namespace string, appName string) (models.EnvVariableMap, error)
+	EnvListRevealed(namespace string, appName string) (models.EnvVariableMap, error)
 	EnvSet(req models.EnvVariableMap, namespace string, appName string) (models.Response, error)
+	EnvSetSecret(req models.EnvVariableMap, namespace string, appName string) (models.Response, error)
+	EnvSetFrom(req models.EnvReferenceMap, namespace string, appName string) (models.Response, error)
 	EnvShow(namespace string, appName string, envName string) (models.EnvVariable, error)
+	EnvShowRevealed(namespace string, appName string, envName string) (models.EnvVariable, error)
 	EnvUnset(namespace string, appName string, envName string) (models.Response, error)
+	EnvStagingUnset(namespace string, appName string, envName string) (models.Response, error)
 	EnvMatch(namespace string, appName string, prefix string) (models.EnvMatchResponse, error)
 
 	// info
 	Info() (models.InfoResponse, error)
 
+	// operations
+	Operation(id string) (models.Operation, error)
+
 	// namespaces
 	NamespaceCreate(req models.NamespaceCreateRequest) (models.Response, error)
 	NamespaceDelete(namespace string) (models.Response, error)
+	NamespaceDeletePreview(namespace string) (models.NamespaceDeletePreview, error)
+	NamespaceProtect(namespace string, protected bool) (models.Response, error)
 	NamespaceShow(namespace string) (models.Namespace, error)
 	NamespacesMatch(prefix string) (models.NamespacesMatchResponse, error)
 	Namespaces() (models.NamespaceList, error)
+	RegistryCredentials(namespace string) (models.RegistryCredentialList, error)
+	RegistryCredentialCreate(req models.RegistryCredentialCreateRequest, namespace string) (models.Response, error)
+	RegistryCredentialDelete(namespace string, name string) (models.Response, error)
+	NamespaceQuota(namespace string) (models.NamespaceQuotaResponse, error)
+	NamespaceQuotaSet(req models.NamespaceQuota, namespace string) (models.Response, error)
+	NamespaceBackup(namespace, destinationPath string, images bool) error
+	NamespaceRestore(archivePath, newName string) (models.NamespaceRestoreResponse, error)
+
+	// policies
+	Policies(namespace string) (models.PolicyList, error)
+	PolicyShow(namespace string, name string) (models.Policy, error)
 
 	// configurations
 	Configurations(namespace string) (models.ConfigurationResponseList, error)
@@ -89,6 +124,7 @@ type APIClient interface {
 	ServiceBind(req *models.ServiceBindRequest, namespace, name string) error
 	ServiceUnbind(req *models.ServiceUnbindRequest, namespace, name string) error
 	ServiceDelete(req models.ServiceDeleteRequest, namespace string, name string, f epinioapi.ErrorFunc) (models.ServiceDeleteResponse, error)
+	ServiceProtect(namespace string, name string, protected bool) (models.Response, error)
 	ServiceList(namespace string) (models.ServiceList, error)
 	ServiceMatch(namespace, prefix string) (models.ServiceMatchResponse, error)
 
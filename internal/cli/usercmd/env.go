@@ -2,13 +2,18 @@ package usercmd
 
 import (
 	"context"
+	"fmt"
+	"os"
 
+	"github.com/epinio/epinio/internal/envfile"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
 )
 
 // EnvList displays a table of all environment variables and their
-// values for the named application.
-func (c *EpinioClient) EnvList(ctx context.Context, appName string) error {
+// values for the named application. The values of secret variables
+// are masked, unless reveal is set.
+func (c *EpinioClient) EnvList(ctx context.Context, appName string, reveal bool) error {
 	log := c.Log.WithName("EnvList")
 	log.Info("start")
 	defer log.Info("return")
@@ -22,7 +27,18 @@ func (c *EpinioClient) EnvList(ctx context.Context, appName string) error {
 		return err
 	}
 
-	eVariables, err := c.API.EnvList(c.Settings.Namespace, appName)
+	var eVariables models.EnvVariableMap
+	var err error
+	if reveal {
+		eVariables, err = c.API.EnvListRevealed(c.Settings.Namespace, appName)
+	} else {
+		eVariables, err = c.API.EnvList(c.Settings.Namespace, appName)
+	}
+	if err != nil {
+		return err
+	}
+
+	app, err := c.API.AppShow(c.Settings.Namespace, appName)
 	if err != nil {
 		return err
 	}
@@ -32,6 +48,9 @@ func (c *EpinioClient) EnvList(ctx context.Context, appName string) error {
 	for _, ev := range eVariables.List() {
 		msg = msg.WithTableRow(ev.Name, ev.Value)
 	}
+	for _, name := range app.Configuration.EnvironmentFrom.Names() {
+		msg = msg.WithTableRow(name, envReference(app.Configuration.EnvironmentFrom[name]))
+	}
 
 	msg.Msg("Ok")
 	return nil
@@ -39,16 +58,22 @@ func (c *EpinioClient) EnvList(ctx context.Context, appName string) error {
 
 // EnvSet adds or modifies the specified environment variable in the
 // named application, with the given value. A workload is restarted.
-func (c *EpinioClient) EnvSet(ctx context.Context, appName, envName, envValue string) error {
+// With secret set the variable is marked as secret.
+func (c *EpinioClient) EnvSet(ctx context.Context, appName, envName, envValue string, secret bool) error {
 	log := c.Log.WithName("Env")
 	log.Info("start")
 	defer log.Info("return")
 
+	shownValue := envValue
+	if secret {
+		shownValue = models.EnvMaskedValue
+	}
+
 	c.ui.Note().
 		WithStringValue("Namespace", c.Settings.Namespace).
 		WithStringValue("Application", appName).
 		WithStringValue("Variable", envName).
-		WithStringValue("Value", envValue).
+		WithStringValue("Value", shownValue).
 		Msg("Extend or modify application environment")
 
 	if err := c.TargetOk(); err != nil {
@@ -58,7 +83,136 @@ func (c *EpinioClient) EnvSet(ctx context.Context, appName, envName, envValue st
 	request := models.EnvVariableMap{}
 	request[envName] = envValue
 
-	_, err := c.API.EnvSet(request, c.Settings.Namespace, appName)
+	var err error
+	if secret {
+		_, err = c.API.EnvSetSecret(request, c.Settings.Namespace, appName)
+	} else {
+		_, err = c.API.EnvSet(request, c.Settings.Namespace, appName)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("OK")
+	return nil
+}
+
+// EnvImport adds the environment variables found in the file to the named application.
+// The format is derived from the file extension when not specified. With replace set the
+// file replaces the entire environment. With secret set the imported variables are
+// marked as secret. A workload is restarted.
+func (c *EpinioClient) EnvImport(ctx context.Context, appName, path, format string, replace, secret bool) error {
+	log := c.Log.WithName("EnvImport")
+	log.Info("start")
+	defer log.Info("return")
+
+	if format == "" {
+		format = envfile.FormatOf(path)
+	}
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		WithStringValue("File", path).
+		WithStringValue("Format", format).
+		WithBoolValue("Replace", replace).
+		Msg("Import application environment")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "reading environment file")
+	}
+
+	environment, err := envfile.Parse(data, format)
+	if err != nil {
+		return errors.Wrapf(err, "parsing environment file %s", path)
+	}
+	if len(environment) == 0 {
+		if replace {
+			return errors.New("refusing to replace the environment with an empty file")
+		}
+		c.ui.Exclamation().Msg("No variables found, nothing to import")
+		return nil
+	}
+
+	if replace {
+		request := models.ApplicationUpdateRequest{
+			Environment: environment,
+		}
+		_, err = c.API.AppUpdate(request, c.Settings.Namespace, appName)
+		if err == nil && secret {
+			_, err = c.API.EnvSetSecret(environment, c.Settings.Namespace, appName)
+		}
+	} else if secret {
+		_, err = c.API.EnvSetSecret(environment, c.Settings.Namespace, appName)
+	} else {
+		_, err = c.API.EnvSet(environment, c.Settings.Namespace, appName)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().WithIntValue("Variables", len(environment)).Msg("OK")
+	return nil
+}
+
+// EnvExport writes the environment variables of the named application to stdout, in the
+// specified format. Secret values are written in the clear. Configuration references are
+// not exported, as they have no value of their own.
+func (c *EpinioClient) EnvExport(ctx context.Context, appName, format string) error {
+	log := c.Log.WithName("EnvExport")
+	log.Info("start")
+	defer log.Info("return")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	eVariables, err := c.API.EnvListRevealed(c.Settings.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	out, err := envfile.Format(eVariables, format)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(out)
+	return err
+}
+
+// EnvSetFrom adds or modifies the specified environment variable in the named
+// application, sourcing its value from the key of a bound configuration. A workload is
+// restarted.
+func (c *EpinioClient) EnvSetFrom(ctx context.Context, appName, envName, configurationName, key string) error {
+	log := c.Log.WithName("Env")
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		WithStringValue("Variable", envName).
+		WithStringValue("Configuration", configurationName).
+		WithStringValue("Key", key).
+		Msg("Extend or modify application environment")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	request := models.EnvReferenceMap{}
+	request[envName] = models.EnvConfigurationRef{
+		Configuration: configurationName,
+		Key:           key,
+	}
+
+	_, err := c.API.EnvSetFrom(request, c.Settings.Namespace, appName)
 	if err != nil {
 		return err
 	}
@@ -68,8 +222,9 @@ func (c *EpinioClient) EnvSet(ctx context.Context, appName, envName, envValue st
 }
 
 // EnvShow shows the value of the specified environment variable in
-// the named application.
-func (c *EpinioClient) EnvShow(ctx context.Context, appName, envName string) error {
+// the named application. The value of a secret variable is masked,
+// unless reveal is set.
+func (c *EpinioClient) EnvShow(ctx context.Context, appName, envName string, reveal bool) error {
 	log := c.Log.WithName("Env")
 	log.Info("start")
 	defer log.Info("return")
@@ -84,7 +239,13 @@ func (c *EpinioClient) EnvShow(ctx context.Context, appName, envName string) err
 		return err
 	}
 
-	eVariable, err := c.API.EnvShow(c.Settings.Namespace, appName, envName)
+	var eVariable models.EnvVariable
+	var err error
+	if reveal {
+		eVariable, err = c.API.EnvShowRevealed(c.Settings.Namespace, appName, envName)
+	} else {
+		eVariable, err = c.API.EnvShow(c.Settings.Namespace, appName, envName)
+	}
 	if err != nil {
 		return err
 	}
@@ -98,22 +259,32 @@ func (c *EpinioClient) EnvShow(ctx context.Context, appName, envName string) err
 
 // EnvUnset removes the specified environment variable from the named
 // application. A workload is restarted.
-func (c *EpinioClient) EnvUnset(ctx context.Context, appName, envName string) error {
+func (c *EpinioClient) EnvUnset(ctx context.Context, appName, envName string, staging bool) error {
 	log := c.Log.WithName("Env")
 	log.Info("start")
 	defer log.Info("return")
 
+	what := "application environment"
+	if staging {
+		what = "application staging environment"
+	}
+
 	c.ui.Note().
 		WithStringValue("Namespace", c.Settings.Namespace).
 		WithStringValue("Application", appName).
 		WithStringValue("Variable", envName).
-		Msg("Remove from application environment")
+		Msg("Remove from " + what)
 
 	if err := c.TargetOk(); err != nil {
 		return err
 	}
 
-	_, err := c.API.EnvUnset(c.Settings.Namespace, appName, envName)
+	var err error
+	if staging {
+		_, err = c.API.EnvStagingUnset(c.Settings.Namespace, appName, envName)
+	} else {
+		_, err = c.API.EnvUnset(c.Settings.Namespace, appName, envName)
+	}
 	if err != nil {
 		return err
 	}
@@ -138,3 +309,9 @@ func (c *EpinioClient) EnvMatching(ctx context.Context, appName, prefix string)
 
 	return resp.Names
 }
+
+// envReference returns the display form of an environment variable's configuration
+// reference.
+func envReference(ref models.EnvConfigurationRef) string {
+	return fmt.Sprintf("from configuration %s, key %s", ref.Configuration, ref.Key)
+}
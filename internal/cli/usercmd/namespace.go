@@ -152,6 +152,85 @@ func (c *EpinioClient) DeleteNamespace(namespace string) error {
 	return nil
 }
 
+// DeleteNamespacePreview shows what the deletion of the namespace would remove, and the
+// protected resources preventing it
+func (c *EpinioClient) DeleteNamespacePreview(namespace string) error {
+	log := c.Log.WithName("DeleteNamespacePreview").WithValues("Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", namespace).
+		Msg("Previewing deletion of namespace...")
+
+	preview, err := c.API.NamespaceDeletePreview(namespace)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Normal().WithTable("Kind", "Removed").
+		WithTableRow("Applications", strings.Join(preview.Apps, "\n")).
+		WithTableRow("Services", strings.Join(preview.Services, "\n")).
+		WithTableRow("Configurations", strings.Join(preview.Configurations, "\n")).
+		WithTableRow("PVCs", strings.Join(preview.PVCs, "\n")).
+		WithTableRow("Routes", strings.Join(preview.Routes, "\n")).
+		Msg("Dry run, nothing deleted:")
+
+	if !preview.IsBlocked() {
+		c.ui.Success().Msg("The namespace can be deleted.")
+		return nil
+	}
+
+	msg := c.ui.Exclamation().WithTable("Kind", "Protected")
+	if preview.Protected {
+		msg = msg.WithTableRow("Namespace", preview.Namespace)
+	}
+	msg.
+		WithTableRow("Applications", strings.Join(preview.ProtectedApps, "\n")).
+		WithTableRow("Services", strings.Join(preview.ProtectedServices, "\n")).
+		Msg("The deletion is blocked by protected resources. Clear their protection first.")
+
+	return nil
+}
+
+// ProtectNamespace sets or clears the protection of a Namespace against deletion
+func (c *EpinioClient) ProtectNamespace(namespace string, protected bool) error {
+	log := c.Log.WithName("ProtectNamespace").WithValues("Namespace", namespace, "Protected", protected)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", namespace).
+		Msg(protectionNote(protected, "namespace"))
+
+	_, err := c.API.NamespaceProtect(namespace, protected)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg(protectionDone(protected, "Namespace"))
+
+	return nil
+}
+
+// protectionNote returns the note shown when setting or clearing the protection of a
+// resource of the kind
+func protectionNote(protected bool, kind string) string {
+	if protected {
+		return fmt.Sprintf("Protecting %s...", kind)
+	}
+	return fmt.Sprintf("Unprotecting %s...", kind)
+}
+
+// protectionDone returns the message shown after setting or clearing the protection of a
+// resource of the kind
+func protectionDone(protected bool, kind string) string {
+	if protected {
+		return fmt.Sprintf("%s protected.", kind)
+	}
+	return fmt.Sprintf("%s unprotected.", kind)
+}
+
 // ShowNamepsace shows a Namespace
 func (c *EpinioClient) ShowNamespace(namespace string) error {
 	log := c.Log.WithName("ShowNamespace").WithValues("Namespace", namespace)
@@ -175,10 +254,15 @@ func (c *EpinioClient) ShowNamespace(namespace string) error {
 	msg = msg.
 		WithTableRow("Name", space.Meta.Name).
 		WithTableRow("Created", space.Meta.CreatedAt.String()).
+		WithTableRow("Protected", fmt.Sprintf("%t", space.Protected)).
 		WithTableRow("Applications", strings.Join(space.Apps, "\n")).
 		WithTableRow("Configurations", strings.Join(space.Configurations, "\n"))
 
 	msg.Msg("Details:")
 
+	if space.Quota != nil && space.Usage != nil {
+		c.showQuota(*space.Quota, *space.Usage)
+	}
+
 	return nil
 }
@@ -0,0 +1,112 @@
+package usercmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Policies lists the policies applying to the targeted namespace
+func (c *EpinioClient) Policies(ctx context.Context) error {
+	log := c.Log.WithName("Policies").WithValues("Namespace", c.Settings.Namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		Msg("Listing policies")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	policies, err := c.API.Policies(c.Settings.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if len(policies) == 0 {
+		c.ui.Normal().Msg("No policies found")
+		return nil
+	}
+
+	msg := c.ui.Success().WithTable("Name", "Scope", "Rules")
+	for _, policy := range policies {
+		scope := "global"
+		if policy.Namespace != "" {
+			scope = policy.Namespace
+		}
+
+		rules := []string{}
+		if len(policy.AllowedRegistries) > 0 {
+			rules = append(rules, "allowed registries")
+		}
+		if len(policy.AllowedBuilders) > 0 {
+			rules = append(rules, "allowed builders")
+		}
+		if policy.MaxInstances > 0 {
+			rules = append(rules, "max instances")
+		}
+		if policy.RequireHealthCheck {
+			rules = append(rules, "health check")
+		}
+		if len(policy.ForbiddenEnv) > 0 {
+			rules = append(rules, "forbidden env")
+		}
+
+		msg = msg.WithTableRow(policy.Name, scope, strings.Join(rules, ", "))
+	}
+	msg.Msg("Policies:")
+
+	return nil
+}
+
+// PolicyShow shows the details of the named policy applying to the targeted namespace
+func (c *EpinioClient) PolicyShow(ctx context.Context, name string) error {
+	log := c.Log.WithName("PolicyShow").WithValues("Namespace", c.Settings.Namespace, "Name", name)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Name", name).
+		Msg("Show policy details")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	policy, err := c.API.PolicyShow(c.Settings.Namespace, name)
+	if err != nil {
+		return err
+	}
+
+	scope := "global"
+	if policy.Namespace != "" {
+		scope = policy.Namespace
+	}
+	maxInstances := "unlimited"
+	if policy.MaxInstances > 0 {
+		maxInstances = fmt.Sprintf("%d", policy.MaxInstances)
+	}
+
+	c.ui.Success().WithTable("Key", "Value").
+		WithTableRow("Name", policy.Name).
+		WithTableRow("Scope", scope).
+		WithTableRow("Allowed Registries", listOrAny(policy.AllowedRegistries)).
+		WithTableRow("Allowed Builders", listOrAny(policy.AllowedBuilders)).
+		WithTableRow("Max Instances", maxInstances).
+		WithTableRow("Require Health Check", fmt.Sprintf("%t", policy.RequireHealthCheck)).
+		WithTableRow("Forbidden Environment", strings.Join(policy.ForbiddenEnv, ", ")).
+		Msg("Details:")
+
+	return nil
+}
+
+// listOrAny returns the comma separated entries of an allow list. An empty list allows anything.
+func listOrAny(entries []string) string {
+	if len(entries) == 0 {
+		return "any"
+	}
+	return strings.Join(entries, ", ")
+}
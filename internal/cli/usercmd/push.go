@@ -8,17 +8,25 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/epinio/epinio/helpers"
+	"github.com/epinio/epinio/helpers/bytes"
 	"github.com/epinio/epinio/internal/duration"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 )
 
+// operationPollInterval is the time between checks of a running operation.
+var operationPollInterval = 2 * time.Second
+
 type PushParams struct {
 	models.ApplicationManifest
+	// ForceBuild stages the application even if its sources and staging settings are
+	// unchanged.
+	ForceBuild bool
 }
 
 // Push pushes an app
@@ -36,6 +44,12 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 		params.Configuration.AppChart = c.Settings.AppChart
 	}
 
+	// The staging environment is saved with the application. An empty staging
+	// environment in the manifest clears the saved one.
+	if params.Staging.Environment != nil {
+		params.Configuration.StagingEnvironment = &params.Staging.Environment
+	}
+
 	source := params.Origin.String()
 	appRef := models.AppRef{
 		Meta: models.Meta{
@@ -72,6 +86,19 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 		params.Staging.Builder != "" {
 		msg = msg.WithStringValue("Builder", params.Staging.Builder)
 	}
+	if params.Origin.Kind != models.OriginContainer &&
+		params.Staging.Strategy != "" {
+		msg = msg.WithStringValue("Staging Strategy", params.Staging.Strategy)
+	}
+	if params.Origin.Kind != models.OriginContainer &&
+		params.Staging.Dockerfile != nil && params.Staging.Dockerfile.Path != "" {
+		msg = msg.WithStringValue("Dockerfile", params.Staging.Dockerfile.Path)
+	}
+	if params.Origin.Kind != models.OriginContainer {
+		for _, ev := range params.Staging.Environment.List() {
+			msg = msg.WithStringValue(fmt.Sprintf("Staging Environment '%s'", ev.Name), ev.Value)
+		}
+	}
 
 	if params.Configuration.Instances != nil {
 		msg = msg.WithStringValue("Instances",
@@ -131,12 +158,42 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 
 	// AppUpload / AppImportGit
 	var blobUID string
+	// A stage built from unchanged sources with unchanged staging settings is redeployed
+	// without staging.
+	var reuse *models.UploadMatchResponse
 	switch params.Origin.Kind {
 	case models.OriginNone:
 		return fmt.Errorf("%s", "No application origin")
 	case models.OriginPath:
 		c.ui.Normal().Msg("Collecting the application sources ...")
 
+		sourceHash, err := helpers.SourceHash(source)
+		if err != nil {
+			return err
+		}
+
+		details.Info("match sources", "Hash", sourceHash)
+		match, err := c.API.AppUploadMatch(appRef, models.UploadMatchRequest{
+			Hash:         sourceHash,
+			BuilderImage: params.Staging.Builder,
+			Strategy:     params.Staging.Strategy,
+			Dockerfile:   params.Staging.Dockerfile,
+		})
+		if err != nil {
+			return err
+		}
+		log.V(3).Info("match response", "response", match)
+
+		if match.BlobUID != "" {
+			c.ui.Normal().Msg("Application sources unchanged, skipping the upload ...")
+
+			blobUID = match.BlobUID
+			if match.StageID != "" && !params.ForceBuild {
+				reuse = &match
+			}
+			break
+		}
+
 		tmpDir, tarball, err := helpers.Tar(source)
 		defer func() {
 			if tmpDir != "" {
@@ -150,7 +207,9 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 		c.ui.Normal().Msg("Uploading application code ...")
 
 		details.Info("upload code")
-		upload, err := c.API.AppUpload(appRef.Namespace, appRef.Name, tarball)
+		bar := c.ui.ByteProgress("Uploading")
+		upload, err := c.API.AppUploadChunked(appRef.Namespace, appRef.Name, tarball, sourceHash, bar.Update)
+		bar.Stop()
 		if err != nil {
 			return err
 		}
@@ -166,12 +225,19 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 			return errors.New("git origin is nil")
 		}
 
-		response, err := c.API.AppImportGit(appRef, *gitOrigin)
+		operation, err := c.API.AppImportGit(appRef, *gitOrigin)
 		if err != nil {
 			return errors.Wrap(err, "importing git remote")
 		}
 
-		blobUID = response.BlobUID
+		details.Info("import operation", "ID", operation.ID)
+		imported, err := c.waitForImport(*operation)
+		if err != nil {
+			return errors.Wrap(err, "importing git remote")
+		}
+
+		blobUID = imported.BlobUID
+		gitOrigin.Commit = imported.Commit
 
 	case models.OriginContainer:
 		// Nothing to upload (nor stage)
@@ -179,8 +245,15 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 
 	// AppStage
 	stageID := ""
-	var stageResponse *models.StageResponse
-	if params.Origin.Kind != models.OriginContainer {
+	imageURL := ""
+	if reuse != nil {
+		c.ui.Note().
+			WithStringValue("Stage", reuse.StageID).
+			Msg("Sources and staging settings unchanged, skipping staging (use --force-build to stage anyway)")
+
+		stageID = reuse.StageID
+		imageURL = reuse.ImageURL
+	} else if params.Origin.Kind != models.OriginContainer {
 		c.ui.Normal().Msg("Staging application with code...")
 		c.ui.ProgressNote().Msg("Running staging")
 
@@ -188,21 +261,25 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 			App:          appRef,
 			BlobUID:      blobUID,
 			BuilderImage: params.Staging.Builder,
+			Strategy:     params.Staging.Strategy,
+			Dockerfile:   params.Staging.Dockerfile,
 		}
 		details.Info("staging code", "Blob", blobUID)
-		stageResponse, err = c.API.AppStage(req)
+		stageResponse, err := c.API.AppStage(req)
 		if err != nil {
 			return err
 		}
 		stageID = stageResponse.Stage.ID
+		imageURL = stageResponse.ImageURL
 		log.V(3).Info("stage response", "response", stageResponse)
+		c.reportQueued(stageResponse)
 
 		details.Info("start tailing logs", "StageID", stageResponse.Stage.ID)
 		c.stageLogs(appRef, stageResponse.Stage.ID)
 
 		details.Info("wait for job", "StageID", stageID)
 		// blocking function that wait until the staging is done
-		_, err := c.API.StagingComplete(appRef.Namespace, stageID)
+		_, err = c.API.StagingComplete(appRef.Namespace, stageID)
 		if err != nil {
 			c.ui.Note().Msgf(
 				"You can access the staging logs at any time, either in the UI or with the CLI using this command:\n\nepinio app logs --staging %s",
@@ -222,7 +299,7 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 	if params.Origin.Kind == models.OriginContainer {
 		deployRequest.ImageURL = params.Origin.Container
 	} else {
-		deployRequest.ImageURL = stageResponse.ImageURL
+		deployRequest.ImageURL = imageURL
 		deployRequest.Stage = models.StageRef{ID: stageID}
 	}
 
@@ -251,6 +328,15 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 	return nil
 }
 
+// reportQueued tells the user about the place of a queued staging run in the build queue.
+func (c *EpinioClient) reportQueued(stageResponse *models.StageResponse) {
+	if stageResponse.QueuePosition > 0 {
+		c.ui.Note().
+			WithIntValue("Position", stageResponse.QueuePosition).
+			Msg("Staging is queued, waiting for running builds to finish")
+	}
+}
+
 func (c *EpinioClient) stageLogs(appRef models.AppRef, stageID string) {
 	go func() {
 		err := c.AppLogs(appRef.Name, stageID, true)
@@ -275,3 +361,67 @@ func (c *EpinioClient) reportOK(appRef models.AppRef, builder string, routes []s
 	}
 	msg.Msg("App is online.")
 }
+
+// waitForImport polls the operation importing the application sources until it is
+// finished, showing its progress. It returns the result of a successful import.
+func (c *EpinioClient) waitForImport(operation models.Operation) (*models.ImportGitResponse, error) {
+	s := c.ui.Progress("Waiting for the import to start")
+	defer s.Stop()
+
+	var err error
+	for !operation.Finished() {
+		if operation.Step != "" {
+			s.ChangeMessagef("Importing: %s", operation.Step)
+		}
+
+		time.Sleep(operationPollInterval)
+
+		operation, err = c.API.Operation(operation.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "checking the import")
+		}
+	}
+
+	if operation.State == models.OperationFailed {
+		return nil, errors.New(operation.Error)
+	}
+
+	result := &models.ImportGitResponse{}
+	if err := operation.DecodeResult(result); err != nil {
+		return nil, errors.Wrap(err, "reading the import result")
+	}
+
+	return result, nil
+}
+
+// PushFiles lists the files a push of the application sources would upload, and their
+// total size. Nothing is pushed.
+func (c *EpinioClient) PushFiles(params PushParams) error {
+	if params.Origin.Kind != models.OriginPath {
+		return errors.New("listing the files to upload requires application sources from a local path")
+	}
+
+	log := c.Log.WithName("PushFiles").WithValues("Sources", params.Origin.Path)
+	log.Info("start")
+	defer log.Info("return")
+
+	files, err := helpers.SourceFiles(params.Origin.Path)
+	if err != nil {
+		return err
+	}
+
+	msg := c.ui.Success().WithTable("File", "Size")
+	count := 0
+	var total int64
+	for _, file := range files {
+		if file.Info.IsDir() {
+			continue
+		}
+		count++
+		total += file.Info.Size()
+		msg = msg.WithTableRow(file.Path, bytes.ByteCountIEC(file.Info.Size()))
+	}
+	msg.Msg(fmt.Sprintf("%d files, %s, would be uploaded from %s", count, bytes.ByteCountIEC(total), params.Origin.Path))
+
+	return nil
+}
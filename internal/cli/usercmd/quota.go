@@ -0,0 +1,119 @@
+package usercmd
+
+import (
+	"fmt"
+
+	"github.com/epinio/epinio/internal/quota"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// NamespaceQuotaChanges are the limits of a namespace quota to change. Nil fields keep
+// their limit, zero values and empty strings remove it.
+type NamespaceQuotaChanges struct {
+	Apps           *int32
+	Instances      *int32
+	Services       *int32
+	Configurations *int32
+	Memory         *string
+	CPU            *string
+}
+
+// NamespaceQuotaShow shows the quota of the named namespace, and the use of the limited
+// resources
+func (c *EpinioClient) NamespaceQuotaShow(namespace string) error {
+	log := c.Log.WithName("NamespaceQuotaShow").WithValues("Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", namespace).
+		Msg("Showing namespace quota...")
+
+	resp, err := c.API.NamespaceQuota(namespace)
+	if err != nil {
+		return err
+	}
+
+	if resp.Quota.IsEmpty() {
+		c.ui.Normal().Msg("The namespace has no quota")
+	}
+	c.showQuota(resp.Quota, resp.Usage)
+
+	return nil
+}
+
+// NamespaceQuotaSet changes the quota of the named namespace
+func (c *EpinioClient) NamespaceQuotaSet(namespace string, changes NamespaceQuotaChanges) error {
+	log := c.Log.WithName("NamespaceQuotaSet").WithValues("Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", namespace).
+		Msg("Setting namespace quota...")
+
+	current, err := c.API.NamespaceQuota(namespace)
+	if err != nil {
+		return err
+	}
+
+	limits := current.Quota
+	if changes.Apps != nil {
+		limits.Apps = *changes.Apps
+	}
+	if changes.Instances != nil {
+		limits.Instances = *changes.Instances
+	}
+	if changes.Services != nil {
+		limits.Services = *changes.Services
+	}
+	if changes.Configurations != nil {
+		limits.Configurations = *changes.Configurations
+	}
+	if changes.Memory != nil {
+		limits.Memory = *changes.Memory
+	}
+	if changes.CPU != nil {
+		limits.CPU = *changes.CPU
+	}
+
+	if err := quota.Validate(limits); err != nil {
+		return err
+	}
+
+	if _, err := c.API.NamespaceQuotaSet(limits, namespace); err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Namespace quota set.")
+
+	return nil
+}
+
+// showQuota prints the limits of the quota next to the use of the resources.
+func (c *EpinioClient) showQuota(limits models.NamespaceQuota, usage models.NamespaceUsage) {
+	count := func(used, max int32) string {
+		if max == 0 {
+			return fmt.Sprintf("%d", used)
+		}
+		return fmt.Sprintf("%d / %d", used, max)
+	}
+	quantity := func(used, max string) string {
+		if max == "" {
+			return used
+		}
+		if used == "" {
+			used = "0"
+		}
+		return fmt.Sprintf("%s / %s", used, max)
+	}
+
+	c.ui.Success().WithTable("Resource", "Used / Limit").
+		WithTableRow("Applications", count(usage.Apps, limits.Apps)).
+		WithTableRow("Instances", count(usage.Instances, limits.Instances)).
+		WithTableRow("Services", count(usage.Services, limits.Services)).
+		WithTableRow("Configurations", count(usage.Configurations, limits.Configurations)).
+		WithTableRow("Memory", quantity(usage.Memory, limits.Memory)).
+		WithTableRow("CPU", quantity(usage.CPU, limits.CPU)).
+		Msg("Quota:")
+}
@@ -0,0 +1,102 @@
+package usercmd
+
+import (
+	"context"
+
+	"github.com/epinio/epinio/internal/namespaces"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// RegistryCredentials lists the registry credentials of the targeted namespace
+func (c *EpinioClient) RegistryCredentials(ctx context.Context) error {
+	log := c.Log.WithName("RegistryCredentials").WithValues("Namespace", c.Settings.Namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		Msg("Listing registry credentials")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	credentials, err := c.API.RegistryCredentials(c.Settings.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if len(credentials) == 0 {
+		c.ui.Normal().Msg("No registry credentials found")
+		return nil
+	}
+
+	msg := c.ui.Success().WithTable("Name", "URL", "Username")
+	for _, credential := range credentials {
+		msg = msg.WithTableRow(credential.Name, credential.URL, credential.Username)
+	}
+	msg.Msg("Registry credentials:")
+
+	return nil
+}
+
+// RegistryCredentialAdd adds a registry credential to the targeted namespace. The images
+// of the applications in the namespace are pulled with it.
+func (c *EpinioClient) RegistryCredentialAdd(ctx context.Context, name, url, username, password string) error {
+	log := c.Log.WithName("RegistryCredentialAdd").WithValues("Namespace", c.Settings.Namespace, "Name", name)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Name", name).
+		WithStringValue("URL", url).
+		WithStringValue("Username", username).
+		Msg("Adding registry credential...")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	request := models.RegistryCredentialCreateRequest{
+		Name:     name,
+		URL:      url,
+		Username: username,
+		Password: password,
+	}
+	if err := namespaces.ValidateRegistryCredential(request); err != nil {
+		return err
+	}
+
+	if _, err := c.API.RegistryCredentialCreate(request, c.Settings.Namespace); err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Registry credential added. Images from the registry can be deployed with `epinio push --container-image-url`.")
+
+	return nil
+}
+
+// RegistryCredentialRemove removes the named registry credential from the targeted namespace
+func (c *EpinioClient) RegistryCredentialRemove(ctx context.Context, name string) error {
+	log := c.Log.WithName("RegistryCredentialRemove").WithValues("Namespace", c.Settings.Namespace, "Name", name)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Name", name).
+		Msg("Removing registry credential...")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	if _, err := c.API.RegistryCredentialDelete(c.Settings.Namespace, name); err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Registry credential removed.")
+
+	return nil
+}
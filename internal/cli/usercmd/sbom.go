@@ -0,0 +1,96 @@
+package usercmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// AppSBOM displays the provenance and SBOM documents of the running image of the named
+// application, or of the image of the identified staging run. With an output directory
+// the documents and the provenance record are written to it.
+func (c *EpinioClient) AppSBOM(ctx context.Context, appName, stageID, outputDir string) error {
+	log := c.Log.WithName("AppSBOM").WithValues("Namespace", c.Settings.Namespace, "Application", appName, "StageID", stageID)
+	log.Info("start")
+	defer log.Info("return")
+
+	msg := c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName)
+	if stageID != "" {
+		msg = msg.WithStringValue("Stage", stageID)
+	}
+	msg.Msg("Show application SBOM")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	sbom, err := c.API.AppSBOM(c.Settings.Namespace, appName, stageID)
+	if err != nil {
+		return err
+	}
+
+	provenance := sbom.Provenance
+	c.ui.Success().WithTable("Key", "Value").
+		WithTableRow("Stage", provenance.StageID).
+		WithTableRow("Image", provenance.ImageURL).
+		WithTableRow("Digest", provenance.ImageDigest).
+		WithTableRow("Strategy", provenance.Strategy).
+		WithTableRow("Builder", provenance.BuilderImage).
+		WithTableRow("Source Blob", provenance.BlobUID).
+		WithTableRow("Git Repository", provenance.GitURL).
+		WithTableRow("Git Commit", provenance.GitCommit).
+		WithTableRow("Staged By", provenance.Username).
+		WithTableRow("Staged At", provenance.CreatedAt.String()).
+		Msg("Provenance:")
+
+	if len(sbom.Documents) == 0 {
+		c.ui.Exclamation().Msg("The image has no SBOM. Only images built by buildpacks carry one.")
+	} else {
+		msg := c.ui.Success().WithTable("Path", "Format", "Size")
+		for _, document := range sbom.Documents {
+			msg = msg.WithTableRow(document.Path, document.Format, fmt.Sprintf("%d", len(document.Content)))
+		}
+		msg.Msg("SBOM documents:")
+	}
+
+	if outputDir == "" {
+		return nil
+	}
+
+	for _, document := range sbom.Documents {
+		if err := writeSBOMFile(outputDir, document.Path, document.Content); err != nil {
+			return err
+		}
+	}
+	data, err := json.MarshalIndent(provenance, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeSBOMFile(outputDir, "provenance.json", data); err != nil {
+		return err
+	}
+
+	c.ui.Success().WithStringValue("Directory", outputDir).Msg("SBOM written.")
+
+	return nil
+}
+
+// writeSBOMFile writes the data to the file at the relative path below the directory.
+func writeSBOMFile(dir, path string, data []byte) error {
+	target := filepath.Join(dir, filepath.FromSlash(path))
+	if rel, err := filepath.Rel(dir, target); err != nil || strings.HasPrefix(rel, "..") {
+		return errors.Errorf("bad SBOM document path '%s'", path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return errors.Wrapf(err, "creating directory for %s", path)
+	}
+	return errors.Wrapf(os.WriteFile(target, data, 0644), "writing %s", path)
+}
@@ -2,6 +2,7 @@ package usercmd
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"sort"
 	"strings"
@@ -118,6 +119,7 @@ func (c *EpinioClient) ServiceShow(serviceName string) error {
 		WithTableRow("Version", service.CatalogServiceVersion).
 		WithTableRow("Status", service.Status.String()).
 		WithTableRow("Used-By", strings.Join(boundApps, ", ")).
+		WithTableRow("Protected", fmt.Sprintf("%t", service.Protected)).
 		Msg("Details:")
 
 	return nil
@@ -191,6 +193,31 @@ func (c *EpinioClient) ServiceDelete(name string, unbind bool) error {
 	return nil
 }
 
+// ServiceProtect sets or clears the protection of a service against deletion
+func (c *EpinioClient) ServiceProtect(name string, protected bool) error {
+	log := c.Log.WithName("ServiceProtect")
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", name).
+		WithStringValue("Namespace", c.Settings.Namespace).
+		Msg(protectionNote(protected, "service"))
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	_, err := c.API.ServiceProtect(c.Settings.Namespace, name, protected)
+	if err != nil {
+		return errors.Wrap(err, "service protection failed")
+	}
+
+	c.ui.Success().Msg(protectionDone(protected, "Service"))
+
+	return nil
+}
+
 // ServiceBind binds a service to an application
 func (c *EpinioClient) ServiceBind(name, appName string) error {
 	log := c.Log.WithName("ServiceBind")
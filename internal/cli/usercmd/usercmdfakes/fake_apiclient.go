@@ -49,6 +49,34 @@ type FakeAPIClient struct {
 		result1 models.ServiceList
 		result2 error
 	}
+	AppBuildCacheStub        func(string, string) (models.BuildCache, error)
+	appBuildCacheMutex       sync.RWMutex
+	appBuildCacheArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	appBuildCacheReturns struct {
+		result1 models.BuildCache
+		result2 error
+	}
+	appBuildCacheReturnsOnCall map[int]struct {
+		result1 models.BuildCache
+		result2 error
+	}
+	AppBuildCachePurgeStub        func(string, string) (models.Response, error)
+	appBuildCachePurgeMutex       sync.RWMutex
+	appBuildCachePurgeArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	appBuildCachePurgeReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	appBuildCachePurgeReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
 	AppCreateStub        func(models.ApplicationCreateRequest, string) (models.Response, error)
 	appCreateMutex       sync.RWMutex
 	appCreateArgsForCall []struct {
@@ -90,6 +118,19 @@ type FakeAPIClient struct {
 		result1 *models.DeployResponse
 		result2 error
 	}
+	AppEventsStub        func(string, string, func(models.AppEvent)) error
+	appEventsMutex       sync.RWMutex
+	appEventsArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 func(models.AppEvent)
+	}
+	appEventsReturns struct {
+		result1 error
+	}
+	appEventsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	AppExecStub        func(string, string, string, term.TTY) error
 	appExecMutex       sync.RWMutex
 	appExecArgsForCall []struct {
@@ -118,18 +159,18 @@ type FakeAPIClient struct {
 	appGetPartReturnsOnCall map[int]struct {
 		result1 error
 	}
-	AppImportGitStub        func(models.AppRef, models.GitRef) (*models.ImportGitResponse, error)
+	AppImportGitStub        func(models.AppRef, models.GitRef) (*models.Operation, error)
 	appImportGitMutex       sync.RWMutex
 	appImportGitArgsForCall []struct {
 		arg1 models.AppRef
 		arg2 models.GitRef
 	}
 	appImportGitReturns struct {
-		result1 *models.ImportGitResponse
+		result1 *models.Operation
 		result2 error
 	}
 	appImportGitReturnsOnCall map[int]struct {
-		result1 *models.ImportGitResponse
+		result1 *models.Operation
 		result2 error
 	}
 	AppLogsStub        func(string, string, string, bool, func(tailer.ContainerLogLine)) error
@@ -161,6 +202,22 @@ type FakeAPIClient struct {
 		result1 models.AppMatchResponse
 		result2 error
 	}
+	AppMetricsStub        func(string, string, string, string) (models.AppMetricsResponse, error)
+	appMetricsMutex       sync.RWMutex
+	appMetricsArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	appMetricsReturns struct {
+		result1 models.AppMetricsResponse
+		result2 error
+	}
+	appMetricsReturnsOnCall map[int]struct {
+		result1 models.AppMetricsResponse
+		result2 error
+	}
 	AppPortForwardStub        func(string, string, string, *client.PortForwardOpts) error
 	appPortForwardMutex       sync.RWMutex
 	appPortForwardArgsForCall []struct {
@@ -175,6 +232,21 @@ type FakeAPIClient struct {
 	appPortForwardReturnsOnCall map[int]struct {
 		result1 error
 	}
+	AppProtectStub        func(string, string, bool) (models.Response, error)
+	appProtectMutex       sync.RWMutex
+	appProtectArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 bool
+	}
+	appProtectReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	appProtectReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
 	AppRestartStub        func(string, string) error
 	appRestartMutex       sync.RWMutex
 	appRestartArgsForCall []struct {
@@ -200,6 +272,21 @@ type FakeAPIClient struct {
 		result1 models.Response
 		result2 error
 	}
+	AppSBOMStub        func(string, string, string) (models.AppSBOM, error)
+	appSBOMMutex       sync.RWMutex
+	appSBOMArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	appSBOMReturns struct {
+		result1 models.AppSBOM
+		result2 error
+	}
+	appSBOMReturnsOnCall map[int]struct {
+		result1 models.AppSBOM
+		result2 error
+	}
 	AppShowStub        func(string, string) (models.App, error)
 	appShowMutex       sync.RWMutex
 	appShowArgsForCall []struct {
@@ -214,6 +301,20 @@ type FakeAPIClient struct {
 		result1 models.App
 		result2 error
 	}
+	AppShowRevealedStub        func(string, string) (models.App, error)
+	appShowRevealedMutex       sync.RWMutex
+	appShowRevealedArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	appShowRevealedReturns struct {
+		result1 models.App
+		result2 error
+	}
+	appShowRevealedReturnsOnCall map[int]struct {
+		result1 models.App
+		result2 error
+	}
 	AppStageStub        func(models.StageRequest) (*models.StageResponse, error)
 	appStageMutex       sync.RWMutex
 	appStageArgsForCall []struct {
@@ -242,12 +343,13 @@ type FakeAPIClient struct {
 		result1 models.Response
 		result2 error
 	}
-	AppUploadStub        func(string, string, string) (models.UploadResponse, error)
+	AppUploadStub        func(string, string, string, string) (models.UploadResponse, error)
 	appUploadMutex       sync.RWMutex
 	appUploadArgsForCall []struct {
 		arg1 string
 		arg2 string
 		arg3 string
+		arg4 string
 	}
 	appUploadReturns struct {
 		result1 models.UploadResponse
@@ -257,6 +359,79 @@ type FakeAPIClient struct {
 		result1 models.UploadResponse
 		result2 error
 	}
+	AppUploadChunkedStub        func(string, string, string, string, client.UploadProgress) (models.UploadResponse, error)
+	appUploadChunkedMutex       sync.RWMutex
+	appUploadChunkedArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 client.UploadProgress
+	}
+	appUploadChunkedReturns struct {
+		result1 models.UploadResponse
+		result2 error
+	}
+	appUploadChunkedReturnsOnCall map[int]struct {
+		result1 models.UploadResponse
+		result2 error
+	}
+	AppUploadMatchStub        func(models.AppRef, models.UploadMatchRequest) (models.UploadMatchResponse, error)
+	appUploadMatchMutex       sync.RWMutex
+	appUploadMatchArgsForCall []struct {
+		arg1 models.AppRef
+		arg2 models.UploadMatchRequest
+	}
+	appUploadMatchReturns struct {
+		result1 models.UploadMatchResponse
+		result2 error
+	}
+	appUploadMatchReturnsOnCall map[int]struct {
+		result1 models.UploadMatchResponse
+		result2 error
+	}
+	AppWebhookStub        func(string, string) (models.AppWebhook, error)
+	appWebhookMutex       sync.RWMutex
+	appWebhookArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	appWebhookReturns struct {
+		result1 models.AppWebhook
+		result2 error
+	}
+	appWebhookReturnsOnCall map[int]struct {
+		result1 models.AppWebhook
+		result2 error
+	}
+	AppWebhookEnableStub        func(string, string) (models.AppWebhook, error)
+	appWebhookEnableMutex       sync.RWMutex
+	appWebhookEnableArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	appWebhookEnableReturns struct {
+		result1 models.AppWebhook
+		result2 error
+	}
+	appWebhookEnableReturnsOnCall map[int]struct {
+		result1 models.AppWebhook
+		result2 error
+	}
+	AppWebhookRotateStub        func(string, string) (models.AppWebhook, error)
+	appWebhookRotateMutex       sync.RWMutex
+	appWebhookRotateArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	appWebhookRotateReturns struct {
+		result1 models.AppWebhook
+		result2 error
+	}
+	appWebhookRotateReturnsOnCall map[int]struct {
+		result1 models.AppWebhook
+		result2 error
+	}
 	AppsStub        func(string) (models.AppList, error)
 	appsMutex       sync.RWMutex
 	appsArgsForCall []struct {
@@ -463,6 +638,20 @@ type FakeAPIClient struct {
 		result1 models.EnvVariableMap
 		result2 error
 	}
+	EnvListRevealedStub        func(string, string) (models.EnvVariableMap, error)
+	envListRevealedMutex       sync.RWMutex
+	envListRevealedArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	envListRevealedReturns struct {
+		result1 models.EnvVariableMap
+		result2 error
+	}
+	envListRevealedReturnsOnCall map[int]struct {
+		result1 models.EnvVariableMap
+		result2 error
+	}
 	EnvMatchStub        func(string, string, string) (models.EnvMatchResponse, error)
 	envMatchMutex       sync.RWMutex
 	envMatchArgsForCall []struct {
@@ -493,6 +682,36 @@ type FakeAPIClient struct {
 		result1 models.Response
 		result2 error
 	}
+	EnvSetFromStub        func(models.EnvReferenceMap, string, string) (models.Response, error)
+	envSetFromMutex       sync.RWMutex
+	envSetFromArgsForCall []struct {
+		arg1 models.EnvReferenceMap
+		arg2 string
+		arg3 string
+	}
+	envSetFromReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	envSetFromReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
+	EnvSetSecretStub        func(models.EnvVariableMap, string, string) (models.Response, error)
+	envSetSecretMutex       sync.RWMutex
+	envSetSecretArgsForCall []struct {
+		arg1 models.EnvVariableMap
+		arg2 string
+		arg3 string
+	}
+	envSetSecretReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	envSetSecretReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
 	EnvShowStub        func(string, string, string) (models.EnvVariable, error)
 	envShowMutex       sync.RWMutex
 	envShowArgsForCall []struct {
@@ -508,6 +727,36 @@ type FakeAPIClient struct {
 		result1 models.EnvVariable
 		result2 error
 	}
+	EnvShowRevealedStub        func(string, string, string) (models.EnvVariable, error)
+	envShowRevealedMutex       sync.RWMutex
+	envShowRevealedArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	envShowRevealedReturns struct {
+		result1 models.EnvVariable
+		result2 error
+	}
+	envShowRevealedReturnsOnCall map[int]struct {
+		result1 models.EnvVariable
+		result2 error
+	}
+	EnvStagingUnsetStub        func(string, string, string) (models.Response, error)
+	envStagingUnsetMutex       sync.RWMutex
+	envStagingUnsetArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	envStagingUnsetReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	envStagingUnsetReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
 	EnvUnsetStub        func(string, string, string) (models.Response, error)
 	envUnsetMutex       sync.RWMutex
 	envUnsetArgsForCall []struct {
@@ -535,6 +784,19 @@ type FakeAPIClient struct {
 		result1 models.InfoResponse
 		result2 error
 	}
+	NamespaceBackupStub        func(string, string, bool) error
+	namespaceBackupMutex       sync.RWMutex
+	namespaceBackupArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 bool
+	}
+	namespaceBackupReturns struct {
+		result1 error
+	}
+	namespaceBackupReturnsOnCall map[int]struct {
+		result1 error
+	}
 	NamespaceCreateStub        func(models.NamespaceCreateRequest) (models.Response, error)
 	namespaceCreateMutex       sync.RWMutex
 	namespaceCreateArgsForCall []struct {
@@ -561,6 +823,74 @@ type FakeAPIClient struct {
 		result1 models.Response
 		result2 error
 	}
+	NamespaceDeletePreviewStub        func(string) (models.NamespaceDeletePreview, error)
+	namespaceDeletePreviewMutex       sync.RWMutex
+	namespaceDeletePreviewArgsForCall []struct {
+		arg1 string
+	}
+	namespaceDeletePreviewReturns struct {
+		result1 models.NamespaceDeletePreview
+		result2 error
+	}
+	namespaceDeletePreviewReturnsOnCall map[int]struct {
+		result1 models.NamespaceDeletePreview
+		result2 error
+	}
+	NamespaceProtectStub        func(string, bool) (models.Response, error)
+	namespaceProtectMutex       sync.RWMutex
+	namespaceProtectArgsForCall []struct {
+		arg1 string
+		arg2 bool
+	}
+	namespaceProtectReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	namespaceProtectReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
+	NamespaceQuotaStub        func(string) (models.NamespaceQuotaResponse, error)
+	namespaceQuotaMutex       sync.RWMutex
+	namespaceQuotaArgsForCall []struct {
+		arg1 string
+	}
+	namespaceQuotaReturns struct {
+		result1 models.NamespaceQuotaResponse
+		result2 error
+	}
+	namespaceQuotaReturnsOnCall map[int]struct {
+		result1 models.NamespaceQuotaResponse
+		result2 error
+	}
+	NamespaceQuotaSetStub        func(models.NamespaceQuota, string) (models.Response, error)
+	namespaceQuotaSetMutex       sync.RWMutex
+	namespaceQuotaSetArgsForCall []struct {
+		arg1 models.NamespaceQuota
+		arg2 string
+	}
+	namespaceQuotaSetReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	namespaceQuotaSetReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
+	NamespaceRestoreStub        func(string, string) (models.NamespaceRestoreResponse, error)
+	namespaceRestoreMutex       sync.RWMutex
+	namespaceRestoreArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	namespaceRestoreReturns struct {
+		result1 models.NamespaceRestoreResponse
+		result2 error
+	}
+	namespaceRestoreReturnsOnCall map[int]struct {
+		result1 models.NamespaceRestoreResponse
+		result2 error
+	}
 	NamespaceShowStub        func(string) (models.Namespace, error)
 	namespaceShowMutex       sync.RWMutex
 	namespaceShowArgsForCall []struct {
@@ -599,6 +929,87 @@ type FakeAPIClient struct {
 		result1 models.NamespacesMatchResponse
 		result2 error
 	}
+	OperationStub        func(string) (models.Operation, error)
+	operationMutex       sync.RWMutex
+	operationArgsForCall []struct {
+		arg1 string
+	}
+	operationReturns struct {
+		result1 models.Operation
+		result2 error
+	}
+	operationReturnsOnCall map[int]struct {
+		result1 models.Operation
+		result2 error
+	}
+	PoliciesStub        func(string) (models.PolicyList, error)
+	policiesMutex       sync.RWMutex
+	policiesArgsForCall []struct {
+		arg1 string
+	}
+	policiesReturns struct {
+		result1 models.PolicyList
+		result2 error
+	}
+	policiesReturnsOnCall map[int]struct {
+		result1 models.PolicyList
+		result2 error
+	}
+	PolicyShowStub        func(string, string) (models.Policy, error)
+	policyShowMutex       sync.RWMutex
+	policyShowArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	policyShowReturns struct {
+		result1 models.Policy
+		result2 error
+	}
+	policyShowReturnsOnCall map[int]struct {
+		result1 models.Policy
+		result2 error
+	}
+	RegistryCredentialCreateStub        func(models.RegistryCredentialCreateRequest, string) (models.Response, error)
+	registryCredentialCreateMutex       sync.RWMutex
+	registryCredentialCreateArgsForCall []struct {
+		arg1 models.RegistryCredentialCreateRequest
+		arg2 string
+	}
+	registryCredentialCreateReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	registryCredentialCreateReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
+	RegistryCredentialDeleteStub        func(string, string) (models.Response, error)
+	registryCredentialDeleteMutex       sync.RWMutex
+	registryCredentialDeleteArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	registryCredentialDeleteReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	registryCredentialDeleteReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
+	RegistryCredentialsStub        func(string) (models.RegistryCredentialList, error)
+	registryCredentialsMutex       sync.RWMutex
+	registryCredentialsArgsForCall []struct {
+		arg1 string
+	}
+	registryCredentialsReturns struct {
+		result1 models.RegistryCredentialList
+		result2 error
+	}
+	registryCredentialsReturnsOnCall map[int]struct {
+		result1 models.RegistryCredentialList
+		result2 error
+	}
 	ServiceBindStub        func(*models.ServiceBindRequest, string, string) error
 	serviceBindMutex       sync.RWMutex
 	serviceBindArgsForCall []struct {
@@ -705,6 +1116,21 @@ type FakeAPIClient struct {
 		result1 models.ServiceMatchResponse
 		result2 error
 	}
+	ServiceProtectStub        func(string, string, bool) (models.Response, error)
+	serviceProtectMutex       sync.RWMutex
+	serviceProtectArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 bool
+	}
+	serviceProtectReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	serviceProtectReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
 	ServiceShowStub        func(*models.ServiceShowRequest, string) (*models.Service, error)
 	serviceShowMutex       sync.RWMutex
 	serviceShowArgsForCall []struct {
@@ -732,6 +1158,20 @@ type FakeAPIClient struct {
 	serviceUnbindReturnsOnCall map[int]struct {
 		result1 error
 	}
+	StagingCancelStub        func(string, string) (models.Response, error)
+	stagingCancelMutex       sync.RWMutex
+	stagingCancelArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	stagingCancelReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	stagingCancelReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
 	StagingCompleteStub        func(string, string) (models.Response, error)
 	stagingCompleteMutex       sync.RWMutex
 	stagingCompleteArgsForCall []struct {
@@ -746,6 +1186,19 @@ type FakeAPIClient struct {
 		result1 models.Response
 		result2 error
 	}
+	StagingStatusStub        func(string) (models.StagingStatusResponse, error)
+	stagingStatusMutex       sync.RWMutex
+	stagingStatusArgsForCall []struct {
+		arg1 string
+	}
+	stagingStatusReturns struct {
+		result1 models.StagingStatusResponse
+		result2 error
+	}
+	stagingStatusReturnsOnCall map[int]struct {
+		result1 models.StagingStatusResponse
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -918,9 +1371,139 @@ func (fake *FakeAPIClient) AllServicesReturnsOnCall(i int, result1 models.Servic
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) AppCreate(arg1 models.ApplicationCreateRequest, arg2 string) (models.Response, error) {
-	fake.appCreateMutex.Lock()
-	ret, specificReturn := fake.appCreateReturnsOnCall[len(fake.appCreateArgsForCall)]
+func (fake *FakeAPIClient) AppBuildCache(arg1 string, arg2 string) (models.BuildCache, error) {
+	fake.appBuildCacheMutex.Lock()
+	ret, specificReturn := fake.appBuildCacheReturnsOnCall[len(fake.appBuildCacheArgsForCall)]
+	fake.appBuildCacheArgsForCall = append(fake.appBuildCacheArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AppBuildCacheStub
+	fakeReturns := fake.appBuildCacheReturns
+	fake.recordInvocation("AppBuildCache", []interface{}{arg1, arg2})
+	fake.appBuildCacheMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppBuildCacheCallCount() int {
+	fake.appBuildCacheMutex.RLock()
+	defer fake.appBuildCacheMutex.RUnlock()
+	return len(fake.appBuildCacheArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppBuildCacheCalls(stub func(string, string) (models.BuildCache, error)) {
+	fake.appBuildCacheMutex.Lock()
+	defer fake.appBuildCacheMutex.Unlock()
+	fake.AppBuildCacheStub = stub
+}
+
+func (fake *FakeAPIClient) AppBuildCacheArgsForCall(i int) (string, string) {
+	fake.appBuildCacheMutex.RLock()
+	defer fake.appBuildCacheMutex.RUnlock()
+	argsForCall := fake.appBuildCacheArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) AppBuildCacheReturns(result1 models.BuildCache, result2 error) {
+	fake.appBuildCacheMutex.Lock()
+	defer fake.appBuildCacheMutex.Unlock()
+	fake.AppBuildCacheStub = nil
+	fake.appBuildCacheReturns = struct {
+		result1 models.BuildCache
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppBuildCacheReturnsOnCall(i int, result1 models.BuildCache, result2 error) {
+	fake.appBuildCacheMutex.Lock()
+	defer fake.appBuildCacheMutex.Unlock()
+	fake.AppBuildCacheStub = nil
+	if fake.appBuildCacheReturnsOnCall == nil {
+		fake.appBuildCacheReturnsOnCall = make(map[int]struct {
+			result1 models.BuildCache
+			result2 error
+		})
+	}
+	fake.appBuildCacheReturnsOnCall[i] = struct {
+		result1 models.BuildCache
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppBuildCachePurge(arg1 string, arg2 string) (models.Response, error) {
+	fake.appBuildCachePurgeMutex.Lock()
+	ret, specificReturn := fake.appBuildCachePurgeReturnsOnCall[len(fake.appBuildCachePurgeArgsForCall)]
+	fake.appBuildCachePurgeArgsForCall = append(fake.appBuildCachePurgeArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AppBuildCachePurgeStub
+	fakeReturns := fake.appBuildCachePurgeReturns
+	fake.recordInvocation("AppBuildCachePurge", []interface{}{arg1, arg2})
+	fake.appBuildCachePurgeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppBuildCachePurgeCallCount() int {
+	fake.appBuildCachePurgeMutex.RLock()
+	defer fake.appBuildCachePurgeMutex.RUnlock()
+	return len(fake.appBuildCachePurgeArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppBuildCachePurgeCalls(stub func(string, string) (models.Response, error)) {
+	fake.appBuildCachePurgeMutex.Lock()
+	defer fake.appBuildCachePurgeMutex.Unlock()
+	fake.AppBuildCachePurgeStub = stub
+}
+
+func (fake *FakeAPIClient) AppBuildCachePurgeArgsForCall(i int) (string, string) {
+	fake.appBuildCachePurgeMutex.RLock()
+	defer fake.appBuildCachePurgeMutex.RUnlock()
+	argsForCall := fake.appBuildCachePurgeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) AppBuildCachePurgeReturns(result1 models.Response, result2 error) {
+	fake.appBuildCachePurgeMutex.Lock()
+	defer fake.appBuildCachePurgeMutex.Unlock()
+	fake.AppBuildCachePurgeStub = nil
+	fake.appBuildCachePurgeReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppBuildCachePurgeReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.appBuildCachePurgeMutex.Lock()
+	defer fake.appBuildCachePurgeMutex.Unlock()
+	fake.AppBuildCachePurgeStub = nil
+	if fake.appBuildCachePurgeReturnsOnCall == nil {
+		fake.appBuildCachePurgeReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.appBuildCachePurgeReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppCreate(arg1 models.ApplicationCreateRequest, arg2 string) (models.Response, error) {
+	fake.appCreateMutex.Lock()
+	ret, specificReturn := fake.appCreateReturnsOnCall[len(fake.appCreateArgsForCall)]
 	fake.appCreateArgsForCall = append(fake.appCreateArgsForCall, struct {
 		arg1 models.ApplicationCreateRequest
 		arg2 string
@@ -1112,6 +1695,69 @@ func (fake *FakeAPIClient) AppDeployReturnsOnCall(i int, result1 *models.DeployR
 	}{result1, result2}
 }
 
+func (fake *FakeAPIClient) AppEvents(arg1 string, arg2 string, arg3 func(models.AppEvent)) error {
+	fake.appEventsMutex.Lock()
+	ret, specificReturn := fake.appEventsReturnsOnCall[len(fake.appEventsArgsForCall)]
+	fake.appEventsArgsForCall = append(fake.appEventsArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 func(models.AppEvent)
+	}{arg1, arg2, arg3})
+	stub := fake.AppEventsStub
+	fakeReturns := fake.appEventsReturns
+	fake.recordInvocation("AppEvents", []interface{}{arg1, arg2, arg3})
+	fake.appEventsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAPIClient) AppEventsCallCount() int {
+	fake.appEventsMutex.RLock()
+	defer fake.appEventsMutex.RUnlock()
+	return len(fake.appEventsArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppEventsCalls(stub func(string, string, func(models.AppEvent)) error) {
+	fake.appEventsMutex.Lock()
+	defer fake.appEventsMutex.Unlock()
+	fake.AppEventsStub = stub
+}
+
+func (fake *FakeAPIClient) AppEventsArgsForCall(i int) (string, string, func(models.AppEvent)) {
+	fake.appEventsMutex.RLock()
+	defer fake.appEventsMutex.RUnlock()
+	argsForCall := fake.appEventsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAPIClient) AppEventsReturns(result1 error) {
+	fake.appEventsMutex.Lock()
+	defer fake.appEventsMutex.Unlock()
+	fake.AppEventsStub = nil
+	fake.appEventsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAPIClient) AppEventsReturnsOnCall(i int, result1 error) {
+	fake.appEventsMutex.Lock()
+	defer fake.appEventsMutex.Unlock()
+	fake.AppEventsStub = nil
+	if fake.appEventsReturnsOnCall == nil {
+		fake.appEventsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.appEventsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeAPIClient) AppExec(arg1 string, arg2 string, arg3 string, arg4 term.TTY) error {
 	fake.appExecMutex.Lock()
 	ret, specificReturn := fake.appExecReturnsOnCall[len(fake.appExecArgsForCall)]
@@ -1240,7 +1886,7 @@ func (fake *FakeAPIClient) AppGetPartReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeAPIClient) AppImportGit(arg1 models.AppRef, arg2 models.GitRef) (*models.ImportGitResponse, error) {
+func (fake *FakeAPIClient) AppImportGit(arg1 models.AppRef, arg2 models.GitRef) (*models.Operation, error) {
 	fake.appImportGitMutex.Lock()
 	ret, specificReturn := fake.appImportGitReturnsOnCall[len(fake.appImportGitArgsForCall)]
 	fake.appImportGitArgsForCall = append(fake.appImportGitArgsForCall, struct {
@@ -1266,7 +1912,7 @@ func (fake *FakeAPIClient) AppImportGitCallCount() int {
 	return len(fake.appImportGitArgsForCall)
 }
 
-func (fake *FakeAPIClient) AppImportGitCalls(stub func(models.AppRef, models.GitRef) (*models.ImportGitResponse, error)) {
+func (fake *FakeAPIClient) AppImportGitCalls(stub func(models.AppRef, models.GitRef) (*models.Operation, error)) {
 	fake.appImportGitMutex.Lock()
 	defer fake.appImportGitMutex.Unlock()
 	fake.AppImportGitStub = stub
@@ -1279,28 +1925,28 @@ func (fake *FakeAPIClient) AppImportGitArgsForCall(i int) (models.AppRef, models
 	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeAPIClient) AppImportGitReturns(result1 *models.ImportGitResponse, result2 error) {
+func (fake *FakeAPIClient) AppImportGitReturns(result1 *models.Operation, result2 error) {
 	fake.appImportGitMutex.Lock()
 	defer fake.appImportGitMutex.Unlock()
 	fake.AppImportGitStub = nil
 	fake.appImportGitReturns = struct {
-		result1 *models.ImportGitResponse
+		result1 *models.Operation
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) AppImportGitReturnsOnCall(i int, result1 *models.ImportGitResponse, result2 error) {
+func (fake *FakeAPIClient) AppImportGitReturnsOnCall(i int, result1 *models.Operation, result2 error) {
 	fake.appImportGitMutex.Lock()
 	defer fake.appImportGitMutex.Unlock()
 	fake.AppImportGitStub = nil
 	if fake.appImportGitReturnsOnCall == nil {
 		fake.appImportGitReturnsOnCall = make(map[int]struct {
-			result1 *models.ImportGitResponse
+			result1 *models.Operation
 			result2 error
 		})
 	}
 	fake.appImportGitReturnsOnCall[i] = struct {
-		result1 *models.ImportGitResponse
+		result1 *models.Operation
 		result2 error
 	}{result1, result2}
 }
@@ -1435,6 +2081,73 @@ func (fake *FakeAPIClient) AppMatchReturnsOnCall(i int, result1 models.AppMatchR
 	}{result1, result2}
 }
 
+func (fake *FakeAPIClient) AppMetrics(arg1 string, arg2 string, arg3 string, arg4 string) (models.AppMetricsResponse, error) {
+	fake.appMetricsMutex.Lock()
+	ret, specificReturn := fake.appMetricsReturnsOnCall[len(fake.appMetricsArgsForCall)]
+	fake.appMetricsArgsForCall = append(fake.appMetricsArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.AppMetricsStub
+	fakeReturns := fake.appMetricsReturns
+	fake.recordInvocation("AppMetrics", []interface{}{arg1, arg2, arg3, arg4})
+	fake.appMetricsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppMetricsCallCount() int {
+	fake.appMetricsMutex.RLock()
+	defer fake.appMetricsMutex.RUnlock()
+	return len(fake.appMetricsArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppMetricsCalls(stub func(string, string, string, string) (models.AppMetricsResponse, error)) {
+	fake.appMetricsMutex.Lock()
+	defer fake.appMetricsMutex.Unlock()
+	fake.AppMetricsStub = stub
+}
+
+func (fake *FakeAPIClient) AppMetricsArgsForCall(i int) (string, string, string, string) {
+	fake.appMetricsMutex.RLock()
+	defer fake.appMetricsMutex.RUnlock()
+	argsForCall := fake.appMetricsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeAPIClient) AppMetricsReturns(result1 models.AppMetricsResponse, result2 error) {
+	fake.appMetricsMutex.Lock()
+	defer fake.appMetricsMutex.Unlock()
+	fake.AppMetricsStub = nil
+	fake.appMetricsReturns = struct {
+		result1 models.AppMetricsResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppMetricsReturnsOnCall(i int, result1 models.AppMetricsResponse, result2 error) {
+	fake.appMetricsMutex.Lock()
+	defer fake.appMetricsMutex.Unlock()
+	fake.AppMetricsStub = nil
+	if fake.appMetricsReturnsOnCall == nil {
+		fake.appMetricsReturnsOnCall = make(map[int]struct {
+			result1 models.AppMetricsResponse
+			result2 error
+		})
+	}
+	fake.appMetricsReturnsOnCall[i] = struct {
+		result1 models.AppMetricsResponse
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeAPIClient) AppPortForward(arg1 string, arg2 string, arg3 string, arg4 *client.PortForwardOpts) error {
 	fake.appPortForwardMutex.Lock()
 	ret, specificReturn := fake.appPortForwardReturnsOnCall[len(fake.appPortForwardArgsForCall)]
@@ -1499,6 +2212,72 @@ func (fake *FakeAPIClient) AppPortForwardReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeAPIClient) AppProtect(arg1 string, arg2 string, arg3 bool) (models.Response, error) {
+	fake.appProtectMutex.Lock()
+	ret, specificReturn := fake.appProtectReturnsOnCall[len(fake.appProtectArgsForCall)]
+	fake.appProtectArgsForCall = append(fake.appProtectArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 bool
+	}{arg1, arg2, arg3})
+	stub := fake.AppProtectStub
+	fakeReturns := fake.appProtectReturns
+	fake.recordInvocation("AppProtect", []interface{}{arg1, arg2, arg3})
+	fake.appProtectMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppProtectCallCount() int {
+	fake.appProtectMutex.RLock()
+	defer fake.appProtectMutex.RUnlock()
+	return len(fake.appProtectArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppProtectCalls(stub func(string, string, bool) (models.Response, error)) {
+	fake.appProtectMutex.Lock()
+	defer fake.appProtectMutex.Unlock()
+	fake.AppProtectStub = stub
+}
+
+func (fake *FakeAPIClient) AppProtectArgsForCall(i int) (string, string, bool) {
+	fake.appProtectMutex.RLock()
+	defer fake.appProtectMutex.RUnlock()
+	argsForCall := fake.appProtectArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAPIClient) AppProtectReturns(result1 models.Response, result2 error) {
+	fake.appProtectMutex.Lock()
+	defer fake.appProtectMutex.Unlock()
+	fake.AppProtectStub = nil
+	fake.appProtectReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppProtectReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.appProtectMutex.Lock()
+	defer fake.appProtectMutex.Unlock()
+	fake.AppProtectStub = nil
+	if fake.appProtectReturnsOnCall == nil {
+		fake.appProtectReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.appProtectReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeAPIClient) AppRestart(arg1 string, arg2 string) error {
 	fake.appRestartMutex.Lock()
 	ret, specificReturn := fake.appRestartReturnsOnCall[len(fake.appRestartArgsForCall)]
@@ -1625,6 +2404,72 @@ func (fake *FakeAPIClient) AppRunningReturnsOnCall(i int, result1 models.Respons
 	}{result1, result2}
 }
 
+func (fake *FakeAPIClient) AppSBOM(arg1 string, arg2 string, arg3 string) (models.AppSBOM, error) {
+	fake.appSBOMMutex.Lock()
+	ret, specificReturn := fake.appSBOMReturnsOnCall[len(fake.appSBOMArgsForCall)]
+	fake.appSBOMArgsForCall = append(fake.appSBOMArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.AppSBOMStub
+	fakeReturns := fake.appSBOMReturns
+	fake.recordInvocation("AppSBOM", []interface{}{arg1, arg2, arg3})
+	fake.appSBOMMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppSBOMCallCount() int {
+	fake.appSBOMMutex.RLock()
+	defer fake.appSBOMMutex.RUnlock()
+	return len(fake.appSBOMArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppSBOMCalls(stub func(string, string, string) (models.AppSBOM, error)) {
+	fake.appSBOMMutex.Lock()
+	defer fake.appSBOMMutex.Unlock()
+	fake.AppSBOMStub = stub
+}
+
+func (fake *FakeAPIClient) AppSBOMArgsForCall(i int) (string, string, string) {
+	fake.appSBOMMutex.RLock()
+	defer fake.appSBOMMutex.RUnlock()
+	argsForCall := fake.appSBOMArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAPIClient) AppSBOMReturns(result1 models.AppSBOM, result2 error) {
+	fake.appSBOMMutex.Lock()
+	defer fake.appSBOMMutex.Unlock()
+	fake.AppSBOMStub = nil
+	fake.appSBOMReturns = struct {
+		result1 models.AppSBOM
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppSBOMReturnsOnCall(i int, result1 models.AppSBOM, result2 error) {
+	fake.appSBOMMutex.Lock()
+	defer fake.appSBOMMutex.Unlock()
+	fake.AppSBOMStub = nil
+	if fake.appSBOMReturnsOnCall == nil {
+		fake.appSBOMReturnsOnCall = make(map[int]struct {
+			result1 models.AppSBOM
+			result2 error
+		})
+	}
+	fake.appSBOMReturnsOnCall[i] = struct {
+		result1 models.AppSBOM
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeAPIClient) AppShow(arg1 string, arg2 string) (models.App, error) {
 	fake.appShowMutex.Lock()
 	ret, specificReturn := fake.appShowReturnsOnCall[len(fake.appShowArgsForCall)]
@@ -1690,6 +2535,71 @@ func (fake *FakeAPIClient) AppShowReturnsOnCall(i int, result1 models.App, resul
 	}{result1, result2}
 }
 
+func (fake *FakeAPIClient) AppShowRevealed(arg1 string, arg2 string) (models.App, error) {
+	fake.appShowRevealedMutex.Lock()
+	ret, specificReturn := fake.appShowRevealedReturnsOnCall[len(fake.appShowRevealedArgsForCall)]
+	fake.appShowRevealedArgsForCall = append(fake.appShowRevealedArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AppShowRevealedStub
+	fakeReturns := fake.appShowRevealedReturns
+	fake.recordInvocation("AppShowRevealed", []interface{}{arg1, arg2})
+	fake.appShowRevealedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppShowRevealedCallCount() int {
+	fake.appShowRevealedMutex.RLock()
+	defer fake.appShowRevealedMutex.RUnlock()
+	return len(fake.appShowRevealedArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppShowRevealedCalls(stub func(string, string) (models.App, error)) {
+	fake.appShowRevealedMutex.Lock()
+	defer fake.appShowRevealedMutex.Unlock()
+	fake.AppShowRevealedStub = stub
+}
+
+func (fake *FakeAPIClient) AppShowRevealedArgsForCall(i int) (string, string) {
+	fake.appShowRevealedMutex.RLock()
+	defer fake.appShowRevealedMutex.RUnlock()
+	argsForCall := fake.appShowRevealedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) AppShowRevealedReturns(result1 models.App, result2 error) {
+	fake.appShowRevealedMutex.Lock()
+	defer fake.appShowRevealedMutex.Unlock()
+	fake.AppShowRevealedStub = nil
+	fake.appShowRevealedReturns = struct {
+		result1 models.App
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppShowRevealedReturnsOnCall(i int, result1 models.App, result2 error) {
+	fake.appShowRevealedMutex.Lock()
+	defer fake.appShowRevealedMutex.Unlock()
+	fake.AppShowRevealedStub = nil
+	if fake.appShowRevealedReturnsOnCall == nil {
+		fake.appShowRevealedReturnsOnCall = make(map[int]struct {
+			result1 models.App
+			result2 error
+		})
+	}
+	fake.appShowRevealedReturnsOnCall[i] = struct {
+		result1 models.App
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeAPIClient) AppStage(arg1 models.StageRequest) (*models.StageResponse, error) {
 	fake.appStageMutex.Lock()
 	ret, specificReturn := fake.appStageReturnsOnCall[len(fake.appStageArgsForCall)]
@@ -1820,20 +2730,21 @@ func (fake *FakeAPIClient) AppUpdateReturnsOnCall(i int, result1 models.Response
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) AppUpload(arg1 string, arg2 string, arg3 string) (models.UploadResponse, error) {
+func (fake *FakeAPIClient) AppUpload(arg1 string, arg2 string, arg3 string, arg4 string) (models.UploadResponse, error) {
 	fake.appUploadMutex.Lock()
 	ret, specificReturn := fake.appUploadReturnsOnCall[len(fake.appUploadArgsForCall)]
 	fake.appUploadArgsForCall = append(fake.appUploadArgsForCall, struct {
 		arg1 string
 		arg2 string
 		arg3 string
-	}{arg1, arg2, arg3})
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.AppUploadStub
 	fakeReturns := fake.appUploadReturns
-	fake.recordInvocation("AppUpload", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("AppUpload", []interface{}{arg1, arg2, arg3, arg4})
 	fake.appUploadMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -1847,17 +2758,17 @@ func (fake *FakeAPIClient) AppUploadCallCount() int {
 	return len(fake.appUploadArgsForCall)
 }
 
-func (fake *FakeAPIClient) AppUploadCalls(stub func(string, string, string) (models.UploadResponse, error)) {
+func (fake *FakeAPIClient) AppUploadCalls(stub func(string, string, string, string) (models.UploadResponse, error)) {
 	fake.appUploadMutex.Lock()
 	defer fake.appUploadMutex.Unlock()
 	fake.AppUploadStub = stub
 }
 
-func (fake *FakeAPIClient) AppUploadArgsForCall(i int) (string, string, string) {
+func (fake *FakeAPIClient) AppUploadArgsForCall(i int) (string, string, string, string) {
 	fake.appUploadMutex.RLock()
 	defer fake.appUploadMutex.RUnlock()
 	argsForCall := fake.appUploadArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeAPIClient) AppUploadReturns(result1 models.UploadResponse, result2 error) {
@@ -1886,18 +2797,22 @@ func (fake *FakeAPIClient) AppUploadReturnsOnCall(i int, result1 models.UploadRe
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) Apps(arg1 string) (models.AppList, error) {
-	fake.appsMutex.Lock()
-	ret, specificReturn := fake.appsReturnsOnCall[len(fake.appsArgsForCall)]
-	fake.appsArgsForCall = append(fake.appsArgsForCall, struct {
+func (fake *FakeAPIClient) AppUploadChunked(arg1 string, arg2 string, arg3 string, arg4 string, arg5 client.UploadProgress) (models.UploadResponse, error) {
+	fake.appUploadChunkedMutex.Lock()
+	ret, specificReturn := fake.appUploadChunkedReturnsOnCall[len(fake.appUploadChunkedArgsForCall)]
+	fake.appUploadChunkedArgsForCall = append(fake.appUploadChunkedArgsForCall, struct {
 		arg1 string
-	}{arg1})
-	stub := fake.AppsStub
-	fakeReturns := fake.appsReturns
-	fake.recordInvocation("Apps", []interface{}{arg1})
-	fake.appsMutex.Unlock()
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 client.UploadProgress
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.AppUploadChunkedStub
+	fakeReturns := fake.appUploadChunkedReturns
+	fake.recordInvocation("AppUploadChunked", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.appUploadChunkedMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -1905,19 +2820,343 @@ func (fake *FakeAPIClient) Apps(arg1 string) (models.AppList, error) {
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeAPIClient) AppsCallCount() int {
-	fake.appsMutex.RLock()
-	defer fake.appsMutex.RUnlock()
-	return len(fake.appsArgsForCall)
+func (fake *FakeAPIClient) AppUploadChunkedCallCount() int {
+	fake.appUploadChunkedMutex.RLock()
+	defer fake.appUploadChunkedMutex.RUnlock()
+	return len(fake.appUploadChunkedArgsForCall)
 }
 
-func (fake *FakeAPIClient) AppsCalls(stub func(string) (models.AppList, error)) {
-	fake.appsMutex.Lock()
-	defer fake.appsMutex.Unlock()
-	fake.AppsStub = stub
+func (fake *FakeAPIClient) AppUploadChunkedCalls(stub func(string, string, string, string, client.UploadProgress) (models.UploadResponse, error)) {
+	fake.appUploadChunkedMutex.Lock()
+	defer fake.appUploadChunkedMutex.Unlock()
+	fake.AppUploadChunkedStub = stub
 }
 
-func (fake *FakeAPIClient) AppsArgsForCall(i int) string {
+func (fake *FakeAPIClient) AppUploadChunkedArgsForCall(i int) (string, string, string, string, client.UploadProgress) {
+	fake.appUploadChunkedMutex.RLock()
+	defer fake.appUploadChunkedMutex.RUnlock()
+	argsForCall := fake.appUploadChunkedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeAPIClient) AppUploadChunkedReturns(result1 models.UploadResponse, result2 error) {
+	fake.appUploadChunkedMutex.Lock()
+	defer fake.appUploadChunkedMutex.Unlock()
+	fake.AppUploadChunkedStub = nil
+	fake.appUploadChunkedReturns = struct {
+		result1 models.UploadResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppUploadChunkedReturnsOnCall(i int, result1 models.UploadResponse, result2 error) {
+	fake.appUploadChunkedMutex.Lock()
+	defer fake.appUploadChunkedMutex.Unlock()
+	fake.AppUploadChunkedStub = nil
+	if fake.appUploadChunkedReturnsOnCall == nil {
+		fake.appUploadChunkedReturnsOnCall = make(map[int]struct {
+			result1 models.UploadResponse
+			result2 error
+		})
+	}
+	fake.appUploadChunkedReturnsOnCall[i] = struct {
+		result1 models.UploadResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppUploadMatch(arg1 models.AppRef, arg2 models.UploadMatchRequest) (models.UploadMatchResponse, error) {
+	fake.appUploadMatchMutex.Lock()
+	ret, specificReturn := fake.appUploadMatchReturnsOnCall[len(fake.appUploadMatchArgsForCall)]
+	fake.appUploadMatchArgsForCall = append(fake.appUploadMatchArgsForCall, struct {
+		arg1 models.AppRef
+		arg2 models.UploadMatchRequest
+	}{arg1, arg2})
+	stub := fake.AppUploadMatchStub
+	fakeReturns := fake.appUploadMatchReturns
+	fake.recordInvocation("AppUploadMatch", []interface{}{arg1, arg2})
+	fake.appUploadMatchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppUploadMatchCallCount() int {
+	fake.appUploadMatchMutex.RLock()
+	defer fake.appUploadMatchMutex.RUnlock()
+	return len(fake.appUploadMatchArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppUploadMatchCalls(stub func(models.AppRef, models.UploadMatchRequest) (models.UploadMatchResponse, error)) {
+	fake.appUploadMatchMutex.Lock()
+	defer fake.appUploadMatchMutex.Unlock()
+	fake.AppUploadMatchStub = stub
+}
+
+func (fake *FakeAPIClient) AppUploadMatchArgsForCall(i int) (models.AppRef, models.UploadMatchRequest) {
+	fake.appUploadMatchMutex.RLock()
+	defer fake.appUploadMatchMutex.RUnlock()
+	argsForCall := fake.appUploadMatchArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) AppUploadMatchReturns(result1 models.UploadMatchResponse, result2 error) {
+	fake.appUploadMatchMutex.Lock()
+	defer fake.appUploadMatchMutex.Unlock()
+	fake.AppUploadMatchStub = nil
+	fake.appUploadMatchReturns = struct {
+		result1 models.UploadMatchResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppUploadMatchReturnsOnCall(i int, result1 models.UploadMatchResponse, result2 error) {
+	fake.appUploadMatchMutex.Lock()
+	defer fake.appUploadMatchMutex.Unlock()
+	fake.AppUploadMatchStub = nil
+	if fake.appUploadMatchReturnsOnCall == nil {
+		fake.appUploadMatchReturnsOnCall = make(map[int]struct {
+			result1 models.UploadMatchResponse
+			result2 error
+		})
+	}
+	fake.appUploadMatchReturnsOnCall[i] = struct {
+		result1 models.UploadMatchResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppWebhook(arg1 string, arg2 string) (models.AppWebhook, error) {
+	fake.appWebhookMutex.Lock()
+	ret, specificReturn := fake.appWebhookReturnsOnCall[len(fake.appWebhookArgsForCall)]
+	fake.appWebhookArgsForCall = append(fake.appWebhookArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AppWebhookStub
+	fakeReturns := fake.appWebhookReturns
+	fake.recordInvocation("AppWebhook", []interface{}{arg1, arg2})
+	fake.appWebhookMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppWebhookCallCount() int {
+	fake.appWebhookMutex.RLock()
+	defer fake.appWebhookMutex.RUnlock()
+	return len(fake.appWebhookArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppWebhookCalls(stub func(string, string) (models.AppWebhook, error)) {
+	fake.appWebhookMutex.Lock()
+	defer fake.appWebhookMutex.Unlock()
+	fake.AppWebhookStub = stub
+}
+
+func (fake *FakeAPIClient) AppWebhookArgsForCall(i int) (string, string) {
+	fake.appWebhookMutex.RLock()
+	defer fake.appWebhookMutex.RUnlock()
+	argsForCall := fake.appWebhookArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) AppWebhookReturns(result1 models.AppWebhook, result2 error) {
+	fake.appWebhookMutex.Lock()
+	defer fake.appWebhookMutex.Unlock()
+	fake.AppWebhookStub = nil
+	fake.appWebhookReturns = struct {
+		result1 models.AppWebhook
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppWebhookReturnsOnCall(i int, result1 models.AppWebhook, result2 error) {
+	fake.appWebhookMutex.Lock()
+	defer fake.appWebhookMutex.Unlock()
+	fake.AppWebhookStub = nil
+	if fake.appWebhookReturnsOnCall == nil {
+		fake.appWebhookReturnsOnCall = make(map[int]struct {
+			result1 models.AppWebhook
+			result2 error
+		})
+	}
+	fake.appWebhookReturnsOnCall[i] = struct {
+		result1 models.AppWebhook
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppWebhookEnable(arg1 string, arg2 string) (models.AppWebhook, error) {
+	fake.appWebhookEnableMutex.Lock()
+	ret, specificReturn := fake.appWebhookEnableReturnsOnCall[len(fake.appWebhookEnableArgsForCall)]
+	fake.appWebhookEnableArgsForCall = append(fake.appWebhookEnableArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AppWebhookEnableStub
+	fakeReturns := fake.appWebhookEnableReturns
+	fake.recordInvocation("AppWebhookEnable", []interface{}{arg1, arg2})
+	fake.appWebhookEnableMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppWebhookEnableCallCount() int {
+	fake.appWebhookEnableMutex.RLock()
+	defer fake.appWebhookEnableMutex.RUnlock()
+	return len(fake.appWebhookEnableArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppWebhookEnableCalls(stub func(string, string) (models.AppWebhook, error)) {
+	fake.appWebhookEnableMutex.Lock()
+	defer fake.appWebhookEnableMutex.Unlock()
+	fake.AppWebhookEnableStub = stub
+}
+
+func (fake *FakeAPIClient) AppWebhookEnableArgsForCall(i int) (string, string) {
+	fake.appWebhookEnableMutex.RLock()
+	defer fake.appWebhookEnableMutex.RUnlock()
+	argsForCall := fake.appWebhookEnableArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) AppWebhookEnableReturns(result1 models.AppWebhook, result2 error) {
+	fake.appWebhookEnableMutex.Lock()
+	defer fake.appWebhookEnableMutex.Unlock()
+	fake.AppWebhookEnableStub = nil
+	fake.appWebhookEnableReturns = struct {
+		result1 models.AppWebhook
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppWebhookEnableReturnsOnCall(i int, result1 models.AppWebhook, result2 error) {
+	fake.appWebhookEnableMutex.Lock()
+	defer fake.appWebhookEnableMutex.Unlock()
+	fake.AppWebhookEnableStub = nil
+	if fake.appWebhookEnableReturnsOnCall == nil {
+		fake.appWebhookEnableReturnsOnCall = make(map[int]struct {
+			result1 models.AppWebhook
+			result2 error
+		})
+	}
+	fake.appWebhookEnableReturnsOnCall[i] = struct {
+		result1 models.AppWebhook
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppWebhookRotate(arg1 string, arg2 string) (models.AppWebhook, error) {
+	fake.appWebhookRotateMutex.Lock()
+	ret, specificReturn := fake.appWebhookRotateReturnsOnCall[len(fake.appWebhookRotateArgsForCall)]
+	fake.appWebhookRotateArgsForCall = append(fake.appWebhookRotateArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AppWebhookRotateStub
+	fakeReturns := fake.appWebhookRotateReturns
+	fake.recordInvocation("AppWebhookRotate", []interface{}{arg1, arg2})
+	fake.appWebhookRotateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppWebhookRotateCallCount() int {
+	fake.appWebhookRotateMutex.RLock()
+	defer fake.appWebhookRotateMutex.RUnlock()
+	return len(fake.appWebhookRotateArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppWebhookRotateCalls(stub func(string, string) (models.AppWebhook, error)) {
+	fake.appWebhookRotateMutex.Lock()
+	defer fake.appWebhookRotateMutex.Unlock()
+	fake.AppWebhookRotateStub = stub
+}
+
+func (fake *FakeAPIClient) AppWebhookRotateArgsForCall(i int) (string, string) {
+	fake.appWebhookRotateMutex.RLock()
+	defer fake.appWebhookRotateMutex.RUnlock()
+	argsForCall := fake.appWebhookRotateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) AppWebhookRotateReturns(result1 models.AppWebhook, result2 error) {
+	fake.appWebhookRotateMutex.Lock()
+	defer fake.appWebhookRotateMutex.Unlock()
+	fake.AppWebhookRotateStub = nil
+	fake.appWebhookRotateReturns = struct {
+		result1 models.AppWebhook
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppWebhookRotateReturnsOnCall(i int, result1 models.AppWebhook, result2 error) {
+	fake.appWebhookRotateMutex.Lock()
+	defer fake.appWebhookRotateMutex.Unlock()
+	fake.AppWebhookRotateStub = nil
+	if fake.appWebhookRotateReturnsOnCall == nil {
+		fake.appWebhookRotateReturnsOnCall = make(map[int]struct {
+			result1 models.AppWebhook
+			result2 error
+		})
+	}
+	fake.appWebhookRotateReturnsOnCall[i] = struct {
+		result1 models.AppWebhook
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) Apps(arg1 string) (models.AppList, error) {
+	fake.appsMutex.Lock()
+	ret, specificReturn := fake.appsReturnsOnCall[len(fake.appsArgsForCall)]
+	fake.appsArgsForCall = append(fake.appsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.AppsStub
+	fakeReturns := fake.appsReturns
+	fake.recordInvocation("Apps", []interface{}{arg1})
+	fake.appsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppsCallCount() int {
+	fake.appsMutex.RLock()
+	defer fake.appsMutex.RUnlock()
+	return len(fake.appsArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppsCalls(stub func(string) (models.AppList, error)) {
+	fake.appsMutex.Lock()
+	defer fake.appsMutex.Unlock()
+	fake.AppsStub = stub
+}
+
+func (fake *FakeAPIClient) AppsArgsForCall(i int) string {
 	fake.appsMutex.RLock()
 	defer fake.appsMutex.RUnlock()
 	argsForCall := fake.appsArgsForCall[i]
@@ -2843,6 +4082,71 @@ func (fake *FakeAPIClient) EnvListReturnsOnCall(i int, result1 models.EnvVariabl
 	}{result1, result2}
 }
 
+func (fake *FakeAPIClient) EnvListRevealed(arg1 string, arg2 string) (models.EnvVariableMap, error) {
+	fake.envListRevealedMutex.Lock()
+	ret, specificReturn := fake.envListRevealedReturnsOnCall[len(fake.envListRevealedArgsForCall)]
+	fake.envListRevealedArgsForCall = append(fake.envListRevealedArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.EnvListRevealedStub
+	fakeReturns := fake.envListRevealedReturns
+	fake.recordInvocation("EnvListRevealed", []interface{}{arg1, arg2})
+	fake.envListRevealedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) EnvListRevealedCallCount() int {
+	fake.envListRevealedMutex.RLock()
+	defer fake.envListRevealedMutex.RUnlock()
+	return len(fake.envListRevealedArgsForCall)
+}
+
+func (fake *FakeAPIClient) EnvListRevealedCalls(stub func(string, string) (models.EnvVariableMap, error)) {
+	fake.envListRevealedMutex.Lock()
+	defer fake.envListRevealedMutex.Unlock()
+	fake.EnvListRevealedStub = stub
+}
+
+func (fake *FakeAPIClient) EnvListRevealedArgsForCall(i int) (string, string) {
+	fake.envListRevealedMutex.RLock()
+	defer fake.envListRevealedMutex.RUnlock()
+	argsForCall := fake.envListRevealedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) EnvListRevealedReturns(result1 models.EnvVariableMap, result2 error) {
+	fake.envListRevealedMutex.Lock()
+	defer fake.envListRevealedMutex.Unlock()
+	fake.EnvListRevealedStub = nil
+	fake.envListRevealedReturns = struct {
+		result1 models.EnvVariableMap
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) EnvListRevealedReturnsOnCall(i int, result1 models.EnvVariableMap, result2 error) {
+	fake.envListRevealedMutex.Lock()
+	defer fake.envListRevealedMutex.Unlock()
+	fake.EnvListRevealedStub = nil
+	if fake.envListRevealedReturnsOnCall == nil {
+		fake.envListRevealedReturnsOnCall = make(map[int]struct {
+			result1 models.EnvVariableMap
+			result2 error
+		})
+	}
+	fake.envListRevealedReturnsOnCall[i] = struct {
+		result1 models.EnvVariableMap
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeAPIClient) EnvMatch(arg1 string, arg2 string, arg3 string) (models.EnvMatchResponse, error) {
 	fake.envMatchMutex.Lock()
 	ret, specificReturn := fake.envMatchReturnsOnCall[len(fake.envMatchArgsForCall)]
@@ -2975,18 +4279,18 @@ func (fake *FakeAPIClient) EnvSetReturnsOnCall(i int, result1 models.Response, r
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) EnvShow(arg1 string, arg2 string, arg3 string) (models.EnvVariable, error) {
-	fake.envShowMutex.Lock()
-	ret, specificReturn := fake.envShowReturnsOnCall[len(fake.envShowArgsForCall)]
-	fake.envShowArgsForCall = append(fake.envShowArgsForCall, struct {
-		arg1 string
+func (fake *FakeAPIClient) EnvSetFrom(arg1 models.EnvReferenceMap, arg2 string, arg3 string) (models.Response, error) {
+	fake.envSetFromMutex.Lock()
+	ret, specificReturn := fake.envSetFromReturnsOnCall[len(fake.envSetFromArgsForCall)]
+	fake.envSetFromArgsForCall = append(fake.envSetFromArgsForCall, struct {
+		arg1 models.EnvReferenceMap
 		arg2 string
 		arg3 string
 	}{arg1, arg2, arg3})
-	stub := fake.EnvShowStub
-	fakeReturns := fake.envShowReturns
-	fake.recordInvocation("EnvShow", []interface{}{arg1, arg2, arg3})
-	fake.envShowMutex.Unlock()
+	stub := fake.EnvSetFromStub
+	fakeReturns := fake.envSetFromReturns
+	fake.recordInvocation("EnvSetFrom", []interface{}{arg1, arg2, arg3})
+	fake.envSetFromMutex.Unlock()
 	if stub != nil {
 		return stub(arg1, arg2, arg3)
 	}
@@ -2996,63 +4300,63 @@ func (fake *FakeAPIClient) EnvShow(arg1 string, arg2 string, arg3 string) (model
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeAPIClient) EnvShowCallCount() int {
-	fake.envShowMutex.RLock()
-	defer fake.envShowMutex.RUnlock()
-	return len(fake.envShowArgsForCall)
+func (fake *FakeAPIClient) EnvSetFromCallCount() int {
+	fake.envSetFromMutex.RLock()
+	defer fake.envSetFromMutex.RUnlock()
+	return len(fake.envSetFromArgsForCall)
 }
 
-func (fake *FakeAPIClient) EnvShowCalls(stub func(string, string, string) (models.EnvVariable, error)) {
-	fake.envShowMutex.Lock()
-	defer fake.envShowMutex.Unlock()
-	fake.EnvShowStub = stub
+func (fake *FakeAPIClient) EnvSetFromCalls(stub func(models.EnvReferenceMap, string, string) (models.Response, error)) {
+	fake.envSetFromMutex.Lock()
+	defer fake.envSetFromMutex.Unlock()
+	fake.EnvSetFromStub = stub
 }
 
-func (fake *FakeAPIClient) EnvShowArgsForCall(i int) (string, string, string) {
-	fake.envShowMutex.RLock()
-	defer fake.envShowMutex.RUnlock()
-	argsForCall := fake.envShowArgsForCall[i]
+func (fake *FakeAPIClient) EnvSetFromArgsForCall(i int) (models.EnvReferenceMap, string, string) {
+	fake.envSetFromMutex.RLock()
+	defer fake.envSetFromMutex.RUnlock()
+	argsForCall := fake.envSetFromArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeAPIClient) EnvShowReturns(result1 models.EnvVariable, result2 error) {
-	fake.envShowMutex.Lock()
-	defer fake.envShowMutex.Unlock()
-	fake.EnvShowStub = nil
-	fake.envShowReturns = struct {
-		result1 models.EnvVariable
+func (fake *FakeAPIClient) EnvSetFromReturns(result1 models.Response, result2 error) {
+	fake.envSetFromMutex.Lock()
+	defer fake.envSetFromMutex.Unlock()
+	fake.EnvSetFromStub = nil
+	fake.envSetFromReturns = struct {
+		result1 models.Response
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) EnvShowReturnsOnCall(i int, result1 models.EnvVariable, result2 error) {
-	fake.envShowMutex.Lock()
-	defer fake.envShowMutex.Unlock()
-	fake.EnvShowStub = nil
-	if fake.envShowReturnsOnCall == nil {
-		fake.envShowReturnsOnCall = make(map[int]struct {
-			result1 models.EnvVariable
+func (fake *FakeAPIClient) EnvSetFromReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.envSetFromMutex.Lock()
+	defer fake.envSetFromMutex.Unlock()
+	fake.EnvSetFromStub = nil
+	if fake.envSetFromReturnsOnCall == nil {
+		fake.envSetFromReturnsOnCall = make(map[int]struct {
+			result1 models.Response
 			result2 error
 		})
 	}
-	fake.envShowReturnsOnCall[i] = struct {
-		result1 models.EnvVariable
+	fake.envSetFromReturnsOnCall[i] = struct {
+		result1 models.Response
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) EnvUnset(arg1 string, arg2 string, arg3 string) (models.Response, error) {
-	fake.envUnsetMutex.Lock()
-	ret, specificReturn := fake.envUnsetReturnsOnCall[len(fake.envUnsetArgsForCall)]
-	fake.envUnsetArgsForCall = append(fake.envUnsetArgsForCall, struct {
-		arg1 string
+func (fake *FakeAPIClient) EnvSetSecret(arg1 models.EnvVariableMap, arg2 string, arg3 string) (models.Response, error) {
+	fake.envSetSecretMutex.Lock()
+	ret, specificReturn := fake.envSetSecretReturnsOnCall[len(fake.envSetSecretArgsForCall)]
+	fake.envSetSecretArgsForCall = append(fake.envSetSecretArgsForCall, struct {
+		arg1 models.EnvVariableMap
 		arg2 string
 		arg3 string
 	}{arg1, arg2, arg3})
-	stub := fake.EnvUnsetStub
-	fakeReturns := fake.envUnsetReturns
-	fake.recordInvocation("EnvUnset", []interface{}{arg1, arg2, arg3})
-	fake.envUnsetMutex.Unlock()
+	stub := fake.EnvSetSecretStub
+	fakeReturns := fake.envSetSecretReturns
+	fake.recordInvocation("EnvSetSecret", []interface{}{arg1, arg2, arg3})
+	fake.envSetSecretMutex.Unlock()
 	if stub != nil {
 		return stub(arg1, arg2, arg3)
 	}
@@ -3062,14 +4366,278 @@ func (fake *FakeAPIClient) EnvUnset(arg1 string, arg2 string, arg3 string) (mode
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeAPIClient) EnvUnsetCallCount() int {
-	fake.envUnsetMutex.RLock()
-	defer fake.envUnsetMutex.RUnlock()
-	return len(fake.envUnsetArgsForCall)
+func (fake *FakeAPIClient) EnvSetSecretCallCount() int {
+	fake.envSetSecretMutex.RLock()
+	defer fake.envSetSecretMutex.RUnlock()
+	return len(fake.envSetSecretArgsForCall)
 }
 
-func (fake *FakeAPIClient) EnvUnsetCalls(stub func(string, string, string) (models.Response, error)) {
-	fake.envUnsetMutex.Lock()
+func (fake *FakeAPIClient) EnvSetSecretCalls(stub func(models.EnvVariableMap, string, string) (models.Response, error)) {
+	fake.envSetSecretMutex.Lock()
+	defer fake.envSetSecretMutex.Unlock()
+	fake.EnvSetSecretStub = stub
+}
+
+func (fake *FakeAPIClient) EnvSetSecretArgsForCall(i int) (models.EnvVariableMap, string, string) {
+	fake.envSetSecretMutex.RLock()
+	defer fake.envSetSecretMutex.RUnlock()
+	argsForCall := fake.envSetSecretArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAPIClient) EnvSetSecretReturns(result1 models.Response, result2 error) {
+	fake.envSetSecretMutex.Lock()
+	defer fake.envSetSecretMutex.Unlock()
+	fake.EnvSetSecretStub = nil
+	fake.envSetSecretReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) EnvSetSecretReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.envSetSecretMutex.Lock()
+	defer fake.envSetSecretMutex.Unlock()
+	fake.EnvSetSecretStub = nil
+	if fake.envSetSecretReturnsOnCall == nil {
+		fake.envSetSecretReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.envSetSecretReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) EnvShow(arg1 string, arg2 string, arg3 string) (models.EnvVariable, error) {
+	fake.envShowMutex.Lock()
+	ret, specificReturn := fake.envShowReturnsOnCall[len(fake.envShowArgsForCall)]
+	fake.envShowArgsForCall = append(fake.envShowArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.EnvShowStub
+	fakeReturns := fake.envShowReturns
+	fake.recordInvocation("EnvShow", []interface{}{arg1, arg2, arg3})
+	fake.envShowMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) EnvShowCallCount() int {
+	fake.envShowMutex.RLock()
+	defer fake.envShowMutex.RUnlock()
+	return len(fake.envShowArgsForCall)
+}
+
+func (fake *FakeAPIClient) EnvShowCalls(stub func(string, string, string) (models.EnvVariable, error)) {
+	fake.envShowMutex.Lock()
+	defer fake.envShowMutex.Unlock()
+	fake.EnvShowStub = stub
+}
+
+func (fake *FakeAPIClient) EnvShowArgsForCall(i int) (string, string, string) {
+	fake.envShowMutex.RLock()
+	defer fake.envShowMutex.RUnlock()
+	argsForCall := fake.envShowArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAPIClient) EnvShowReturns(result1 models.EnvVariable, result2 error) {
+	fake.envShowMutex.Lock()
+	defer fake.envShowMutex.Unlock()
+	fake.EnvShowStub = nil
+	fake.envShowReturns = struct {
+		result1 models.EnvVariable
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) EnvShowReturnsOnCall(i int, result1 models.EnvVariable, result2 error) {
+	fake.envShowMutex.Lock()
+	defer fake.envShowMutex.Unlock()
+	fake.EnvShowStub = nil
+	if fake.envShowReturnsOnCall == nil {
+		fake.envShowReturnsOnCall = make(map[int]struct {
+			result1 models.EnvVariable
+			result2 error
+		})
+	}
+	fake.envShowReturnsOnCall[i] = struct {
+		result1 models.EnvVariable
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) EnvShowRevealed(arg1 string, arg2 string, arg3 string) (models.EnvVariable, error) {
+	fake.envShowRevealedMutex.Lock()
+	ret, specificReturn := fake.envShowRevealedReturnsOnCall[len(fake.envShowRevealedArgsForCall)]
+	fake.envShowRevealedArgsForCall = append(fake.envShowRevealedArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.EnvShowRevealedStub
+	fakeReturns := fake.envShowRevealedReturns
+	fake.recordInvocation("EnvShowRevealed", []interface{}{arg1, arg2, arg3})
+	fake.envShowRevealedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) EnvShowRevealedCallCount() int {
+	fake.envShowRevealedMutex.RLock()
+	defer fake.envShowRevealedMutex.RUnlock()
+	return len(fake.envShowRevealedArgsForCall)
+}
+
+func (fake *FakeAPIClient) EnvShowRevealedCalls(stub func(string, string, string) (models.EnvVariable, error)) {
+	fake.envShowRevealedMutex.Lock()
+	defer fake.envShowRevealedMutex.Unlock()
+	fake.EnvShowRevealedStub = stub
+}
+
+func (fake *FakeAPIClient) EnvShowRevealedArgsForCall(i int) (string, string, string) {
+	fake.envShowRevealedMutex.RLock()
+	defer fake.envShowRevealedMutex.RUnlock()
+	argsForCall := fake.envShowRevealedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAPIClient) EnvShowRevealedReturns(result1 models.EnvVariable, result2 error) {
+	fake.envShowRevealedMutex.Lock()
+	defer fake.envShowRevealedMutex.Unlock()
+	fake.EnvShowRevealedStub = nil
+	fake.envShowRevealedReturns = struct {
+		result1 models.EnvVariable
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) EnvShowRevealedReturnsOnCall(i int, result1 models.EnvVariable, result2 error) {
+	fake.envShowRevealedMutex.Lock()
+	defer fake.envShowRevealedMutex.Unlock()
+	fake.EnvShowRevealedStub = nil
+	if fake.envShowRevealedReturnsOnCall == nil {
+		fake.envShowRevealedReturnsOnCall = make(map[int]struct {
+			result1 models.EnvVariable
+			result2 error
+		})
+	}
+	fake.envShowRevealedReturnsOnCall[i] = struct {
+		result1 models.EnvVariable
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) EnvStagingUnset(arg1 string, arg2 string, arg3 string) (models.Response, error) {
+	fake.envStagingUnsetMutex.Lock()
+	ret, specificReturn := fake.envStagingUnsetReturnsOnCall[len(fake.envStagingUnsetArgsForCall)]
+	fake.envStagingUnsetArgsForCall = append(fake.envStagingUnsetArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.EnvStagingUnsetStub
+	fakeReturns := fake.envStagingUnsetReturns
+	fake.recordInvocation("EnvStagingUnset", []interface{}{arg1, arg2, arg3})
+	fake.envStagingUnsetMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) EnvStagingUnsetCallCount() int {
+	fake.envStagingUnsetMutex.RLock()
+	defer fake.envStagingUnsetMutex.RUnlock()
+	return len(fake.envStagingUnsetArgsForCall)
+}
+
+func (fake *FakeAPIClient) EnvStagingUnsetCalls(stub func(string, string, string) (models.Response, error)) {
+	fake.envStagingUnsetMutex.Lock()
+	defer fake.envStagingUnsetMutex.Unlock()
+	fake.EnvStagingUnsetStub = stub
+}
+
+func (fake *FakeAPIClient) EnvStagingUnsetArgsForCall(i int) (string, string, string) {
+	fake.envStagingUnsetMutex.RLock()
+	defer fake.envStagingUnsetMutex.RUnlock()
+	argsForCall := fake.envStagingUnsetArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAPIClient) EnvStagingUnsetReturns(result1 models.Response, result2 error) {
+	fake.envStagingUnsetMutex.Lock()
+	defer fake.envStagingUnsetMutex.Unlock()
+	fake.EnvStagingUnsetStub = nil
+	fake.envStagingUnsetReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) EnvStagingUnsetReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.envStagingUnsetMutex.Lock()
+	defer fake.envStagingUnsetMutex.Unlock()
+	fake.EnvStagingUnsetStub = nil
+	if fake.envStagingUnsetReturnsOnCall == nil {
+		fake.envStagingUnsetReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.envStagingUnsetReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) EnvUnset(arg1 string, arg2 string, arg3 string) (models.Response, error) {
+	fake.envUnsetMutex.Lock()
+	ret, specificReturn := fake.envUnsetReturnsOnCall[len(fake.envUnsetArgsForCall)]
+	fake.envUnsetArgsForCall = append(fake.envUnsetArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.EnvUnsetStub
+	fakeReturns := fake.envUnsetReturns
+	fake.recordInvocation("EnvUnset", []interface{}{arg1, arg2, arg3})
+	fake.envUnsetMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) EnvUnsetCallCount() int {
+	fake.envUnsetMutex.RLock()
+	defer fake.envUnsetMutex.RUnlock()
+	return len(fake.envUnsetArgsForCall)
+}
+
+func (fake *FakeAPIClient) EnvUnsetCalls(stub func(string, string, string) (models.Response, error)) {
+	fake.envUnsetMutex.Lock()
 	defer fake.envUnsetMutex.Unlock()
 	fake.EnvUnsetStub = stub
 }
@@ -3163,6 +4731,69 @@ func (fake *FakeAPIClient) InfoReturnsOnCall(i int, result1 models.InfoResponse,
 	}{result1, result2}
 }
 
+func (fake *FakeAPIClient) NamespaceBackup(arg1 string, arg2 string, arg3 bool) error {
+	fake.namespaceBackupMutex.Lock()
+	ret, specificReturn := fake.namespaceBackupReturnsOnCall[len(fake.namespaceBackupArgsForCall)]
+	fake.namespaceBackupArgsForCall = append(fake.namespaceBackupArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 bool
+	}{arg1, arg2, arg3})
+	stub := fake.NamespaceBackupStub
+	fakeReturns := fake.namespaceBackupReturns
+	fake.recordInvocation("NamespaceBackup", []interface{}{arg1, arg2, arg3})
+	fake.namespaceBackupMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAPIClient) NamespaceBackupCallCount() int {
+	fake.namespaceBackupMutex.RLock()
+	defer fake.namespaceBackupMutex.RUnlock()
+	return len(fake.namespaceBackupArgsForCall)
+}
+
+func (fake *FakeAPIClient) NamespaceBackupCalls(stub func(string, string, bool) error) {
+	fake.namespaceBackupMutex.Lock()
+	defer fake.namespaceBackupMutex.Unlock()
+	fake.NamespaceBackupStub = stub
+}
+
+func (fake *FakeAPIClient) NamespaceBackupArgsForCall(i int) (string, string, bool) {
+	fake.namespaceBackupMutex.RLock()
+	defer fake.namespaceBackupMutex.RUnlock()
+	argsForCall := fake.namespaceBackupArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAPIClient) NamespaceBackupReturns(result1 error) {
+	fake.namespaceBackupMutex.Lock()
+	defer fake.namespaceBackupMutex.Unlock()
+	fake.NamespaceBackupStub = nil
+	fake.namespaceBackupReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAPIClient) NamespaceBackupReturnsOnCall(i int, result1 error) {
+	fake.namespaceBackupMutex.Lock()
+	defer fake.namespaceBackupMutex.Unlock()
+	fake.NamespaceBackupStub = nil
+	if fake.namespaceBackupReturnsOnCall == nil {
+		fake.namespaceBackupReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.namespaceBackupReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeAPIClient) NamespaceCreate(arg1 models.NamespaceCreateRequest) (models.Response, error) {
 	fake.namespaceCreateMutex.Lock()
 	ret, specificReturn := fake.namespaceCreateReturnsOnCall[len(fake.namespaceCreateArgsForCall)]
@@ -3238,7 +4869,707 @@ func (fake *FakeAPIClient) NamespaceDelete(arg1 string) (models.Response, error)
 	fake.recordInvocation("NamespaceDelete", []interface{}{arg1})
 	fake.namespaceDeleteMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) NamespaceDeleteCallCount() int {
+	fake.namespaceDeleteMutex.RLock()
+	defer fake.namespaceDeleteMutex.RUnlock()
+	return len(fake.namespaceDeleteArgsForCall)
+}
+
+func (fake *FakeAPIClient) NamespaceDeleteCalls(stub func(string) (models.Response, error)) {
+	fake.namespaceDeleteMutex.Lock()
+	defer fake.namespaceDeleteMutex.Unlock()
+	fake.NamespaceDeleteStub = stub
+}
+
+func (fake *FakeAPIClient) NamespaceDeleteArgsForCall(i int) string {
+	fake.namespaceDeleteMutex.RLock()
+	defer fake.namespaceDeleteMutex.RUnlock()
+	argsForCall := fake.namespaceDeleteArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAPIClient) NamespaceDeleteReturns(result1 models.Response, result2 error) {
+	fake.namespaceDeleteMutex.Lock()
+	defer fake.namespaceDeleteMutex.Unlock()
+	fake.NamespaceDeleteStub = nil
+	fake.namespaceDeleteReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespaceDeleteReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.namespaceDeleteMutex.Lock()
+	defer fake.namespaceDeleteMutex.Unlock()
+	fake.NamespaceDeleteStub = nil
+	if fake.namespaceDeleteReturnsOnCall == nil {
+		fake.namespaceDeleteReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.namespaceDeleteReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespaceDeletePreview(arg1 string) (models.NamespaceDeletePreview, error) {
+	fake.namespaceDeletePreviewMutex.Lock()
+	ret, specificReturn := fake.namespaceDeletePreviewReturnsOnCall[len(fake.namespaceDeletePreviewArgsForCall)]
+	fake.namespaceDeletePreviewArgsForCall = append(fake.namespaceDeletePreviewArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.NamespaceDeletePreviewStub
+	fakeReturns := fake.namespaceDeletePreviewReturns
+	fake.recordInvocation("NamespaceDeletePreview", []interface{}{arg1})
+	fake.namespaceDeletePreviewMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) NamespaceDeletePreviewCallCount() int {
+	fake.namespaceDeletePreviewMutex.RLock()
+	defer fake.namespaceDeletePreviewMutex.RUnlock()
+	return len(fake.namespaceDeletePreviewArgsForCall)
+}
+
+func (fake *FakeAPIClient) NamespaceDeletePreviewCalls(stub func(string) (models.NamespaceDeletePreview, error)) {
+	fake.namespaceDeletePreviewMutex.Lock()
+	defer fake.namespaceDeletePreviewMutex.Unlock()
+	fake.NamespaceDeletePreviewStub = stub
+}
+
+func (fake *FakeAPIClient) NamespaceDeletePreviewArgsForCall(i int) string {
+	fake.namespaceDeletePreviewMutex.RLock()
+	defer fake.namespaceDeletePreviewMutex.RUnlock()
+	argsForCall := fake.namespaceDeletePreviewArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAPIClient) NamespaceDeletePreviewReturns(result1 models.NamespaceDeletePreview, result2 error) {
+	fake.namespaceDeletePreviewMutex.Lock()
+	defer fake.namespaceDeletePreviewMutex.Unlock()
+	fake.NamespaceDeletePreviewStub = nil
+	fake.namespaceDeletePreviewReturns = struct {
+		result1 models.NamespaceDeletePreview
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespaceDeletePreviewReturnsOnCall(i int, result1 models.NamespaceDeletePreview, result2 error) {
+	fake.namespaceDeletePreviewMutex.Lock()
+	defer fake.namespaceDeletePreviewMutex.Unlock()
+	fake.NamespaceDeletePreviewStub = nil
+	if fake.namespaceDeletePreviewReturnsOnCall == nil {
+		fake.namespaceDeletePreviewReturnsOnCall = make(map[int]struct {
+			result1 models.NamespaceDeletePreview
+			result2 error
+		})
+	}
+	fake.namespaceDeletePreviewReturnsOnCall[i] = struct {
+		result1 models.NamespaceDeletePreview
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespaceProtect(arg1 string, arg2 bool) (models.Response, error) {
+	fake.namespaceProtectMutex.Lock()
+	ret, specificReturn := fake.namespaceProtectReturnsOnCall[len(fake.namespaceProtectArgsForCall)]
+	fake.namespaceProtectArgsForCall = append(fake.namespaceProtectArgsForCall, struct {
+		arg1 string
+		arg2 bool
+	}{arg1, arg2})
+	stub := fake.NamespaceProtectStub
+	fakeReturns := fake.namespaceProtectReturns
+	fake.recordInvocation("NamespaceProtect", []interface{}{arg1, arg2})
+	fake.namespaceProtectMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) NamespaceProtectCallCount() int {
+	fake.namespaceProtectMutex.RLock()
+	defer fake.namespaceProtectMutex.RUnlock()
+	return len(fake.namespaceProtectArgsForCall)
+}
+
+func (fake *FakeAPIClient) NamespaceProtectCalls(stub func(string, bool) (models.Response, error)) {
+	fake.namespaceProtectMutex.Lock()
+	defer fake.namespaceProtectMutex.Unlock()
+	fake.NamespaceProtectStub = stub
+}
+
+func (fake *FakeAPIClient) NamespaceProtectArgsForCall(i int) (string, bool) {
+	fake.namespaceProtectMutex.RLock()
+	defer fake.namespaceProtectMutex.RUnlock()
+	argsForCall := fake.namespaceProtectArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) NamespaceProtectReturns(result1 models.Response, result2 error) {
+	fake.namespaceProtectMutex.Lock()
+	defer fake.namespaceProtectMutex.Unlock()
+	fake.NamespaceProtectStub = nil
+	fake.namespaceProtectReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespaceProtectReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.namespaceProtectMutex.Lock()
+	defer fake.namespaceProtectMutex.Unlock()
+	fake.NamespaceProtectStub = nil
+	if fake.namespaceProtectReturnsOnCall == nil {
+		fake.namespaceProtectReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.namespaceProtectReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespaceQuota(arg1 string) (models.NamespaceQuotaResponse, error) {
+	fake.namespaceQuotaMutex.Lock()
+	ret, specificReturn := fake.namespaceQuotaReturnsOnCall[len(fake.namespaceQuotaArgsForCall)]
+	fake.namespaceQuotaArgsForCall = append(fake.namespaceQuotaArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.NamespaceQuotaStub
+	fakeReturns := fake.namespaceQuotaReturns
+	fake.recordInvocation("NamespaceQuota", []interface{}{arg1})
+	fake.namespaceQuotaMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) NamespaceQuotaCallCount() int {
+	fake.namespaceQuotaMutex.RLock()
+	defer fake.namespaceQuotaMutex.RUnlock()
+	return len(fake.namespaceQuotaArgsForCall)
+}
+
+func (fake *FakeAPIClient) NamespaceQuotaCalls(stub func(string) (models.NamespaceQuotaResponse, error)) {
+	fake.namespaceQuotaMutex.Lock()
+	defer fake.namespaceQuotaMutex.Unlock()
+	fake.NamespaceQuotaStub = stub
+}
+
+func (fake *FakeAPIClient) NamespaceQuotaArgsForCall(i int) string {
+	fake.namespaceQuotaMutex.RLock()
+	defer fake.namespaceQuotaMutex.RUnlock()
+	argsForCall := fake.namespaceQuotaArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAPIClient) NamespaceQuotaReturns(result1 models.NamespaceQuotaResponse, result2 error) {
+	fake.namespaceQuotaMutex.Lock()
+	defer fake.namespaceQuotaMutex.Unlock()
+	fake.NamespaceQuotaStub = nil
+	fake.namespaceQuotaReturns = struct {
+		result1 models.NamespaceQuotaResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespaceQuotaReturnsOnCall(i int, result1 models.NamespaceQuotaResponse, result2 error) {
+	fake.namespaceQuotaMutex.Lock()
+	defer fake.namespaceQuotaMutex.Unlock()
+	fake.NamespaceQuotaStub = nil
+	if fake.namespaceQuotaReturnsOnCall == nil {
+		fake.namespaceQuotaReturnsOnCall = make(map[int]struct {
+			result1 models.NamespaceQuotaResponse
+			result2 error
+		})
+	}
+	fake.namespaceQuotaReturnsOnCall[i] = struct {
+		result1 models.NamespaceQuotaResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespaceQuotaSet(arg1 models.NamespaceQuota, arg2 string) (models.Response, error) {
+	fake.namespaceQuotaSetMutex.Lock()
+	ret, specificReturn := fake.namespaceQuotaSetReturnsOnCall[len(fake.namespaceQuotaSetArgsForCall)]
+	fake.namespaceQuotaSetArgsForCall = append(fake.namespaceQuotaSetArgsForCall, struct {
+		arg1 models.NamespaceQuota
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.NamespaceQuotaSetStub
+	fakeReturns := fake.namespaceQuotaSetReturns
+	fake.recordInvocation("NamespaceQuotaSet", []interface{}{arg1, arg2})
+	fake.namespaceQuotaSetMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) NamespaceQuotaSetCallCount() int {
+	fake.namespaceQuotaSetMutex.RLock()
+	defer fake.namespaceQuotaSetMutex.RUnlock()
+	return len(fake.namespaceQuotaSetArgsForCall)
+}
+
+func (fake *FakeAPIClient) NamespaceQuotaSetCalls(stub func(models.NamespaceQuota, string) (models.Response, error)) {
+	fake.namespaceQuotaSetMutex.Lock()
+	defer fake.namespaceQuotaSetMutex.Unlock()
+	fake.NamespaceQuotaSetStub = stub
+}
+
+func (fake *FakeAPIClient) NamespaceQuotaSetArgsForCall(i int) (models.NamespaceQuota, string) {
+	fake.namespaceQuotaSetMutex.RLock()
+	defer fake.namespaceQuotaSetMutex.RUnlock()
+	argsForCall := fake.namespaceQuotaSetArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) NamespaceQuotaSetReturns(result1 models.Response, result2 error) {
+	fake.namespaceQuotaSetMutex.Lock()
+	defer fake.namespaceQuotaSetMutex.Unlock()
+	fake.NamespaceQuotaSetStub = nil
+	fake.namespaceQuotaSetReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespaceQuotaSetReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.namespaceQuotaSetMutex.Lock()
+	defer fake.namespaceQuotaSetMutex.Unlock()
+	fake.NamespaceQuotaSetStub = nil
+	if fake.namespaceQuotaSetReturnsOnCall == nil {
+		fake.namespaceQuotaSetReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.namespaceQuotaSetReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespaceRestore(arg1 string, arg2 string) (models.NamespaceRestoreResponse, error) {
+	fake.namespaceRestoreMutex.Lock()
+	ret, specificReturn := fake.namespaceRestoreReturnsOnCall[len(fake.namespaceRestoreArgsForCall)]
+	fake.namespaceRestoreArgsForCall = append(fake.namespaceRestoreArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.NamespaceRestoreStub
+	fakeReturns := fake.namespaceRestoreReturns
+	fake.recordInvocation("NamespaceRestore", []interface{}{arg1, arg2})
+	fake.namespaceRestoreMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) NamespaceRestoreCallCount() int {
+	fake.namespaceRestoreMutex.RLock()
+	defer fake.namespaceRestoreMutex.RUnlock()
+	return len(fake.namespaceRestoreArgsForCall)
+}
+
+func (fake *FakeAPIClient) NamespaceRestoreCalls(stub func(string, string) (models.NamespaceRestoreResponse, error)) {
+	fake.namespaceRestoreMutex.Lock()
+	defer fake.namespaceRestoreMutex.Unlock()
+	fake.NamespaceRestoreStub = stub
+}
+
+func (fake *FakeAPIClient) NamespaceRestoreArgsForCall(i int) (string, string) {
+	fake.namespaceRestoreMutex.RLock()
+	defer fake.namespaceRestoreMutex.RUnlock()
+	argsForCall := fake.namespaceRestoreArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) NamespaceRestoreReturns(result1 models.NamespaceRestoreResponse, result2 error) {
+	fake.namespaceRestoreMutex.Lock()
+	defer fake.namespaceRestoreMutex.Unlock()
+	fake.NamespaceRestoreStub = nil
+	fake.namespaceRestoreReturns = struct {
+		result1 models.NamespaceRestoreResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespaceRestoreReturnsOnCall(i int, result1 models.NamespaceRestoreResponse, result2 error) {
+	fake.namespaceRestoreMutex.Lock()
+	defer fake.namespaceRestoreMutex.Unlock()
+	fake.NamespaceRestoreStub = nil
+	if fake.namespaceRestoreReturnsOnCall == nil {
+		fake.namespaceRestoreReturnsOnCall = make(map[int]struct {
+			result1 models.NamespaceRestoreResponse
+			result2 error
+		})
+	}
+	fake.namespaceRestoreReturnsOnCall[i] = struct {
+		result1 models.NamespaceRestoreResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespaceShow(arg1 string) (models.Namespace, error) {
+	fake.namespaceShowMutex.Lock()
+	ret, specificReturn := fake.namespaceShowReturnsOnCall[len(fake.namespaceShowArgsForCall)]
+	fake.namespaceShowArgsForCall = append(fake.namespaceShowArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.NamespaceShowStub
+	fakeReturns := fake.namespaceShowReturns
+	fake.recordInvocation("NamespaceShow", []interface{}{arg1})
+	fake.namespaceShowMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) NamespaceShowCallCount() int {
+	fake.namespaceShowMutex.RLock()
+	defer fake.namespaceShowMutex.RUnlock()
+	return len(fake.namespaceShowArgsForCall)
+}
+
+func (fake *FakeAPIClient) NamespaceShowCalls(stub func(string) (models.Namespace, error)) {
+	fake.namespaceShowMutex.Lock()
+	defer fake.namespaceShowMutex.Unlock()
+	fake.NamespaceShowStub = stub
+}
+
+func (fake *FakeAPIClient) NamespaceShowArgsForCall(i int) string {
+	fake.namespaceShowMutex.RLock()
+	defer fake.namespaceShowMutex.RUnlock()
+	argsForCall := fake.namespaceShowArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAPIClient) NamespaceShowReturns(result1 models.Namespace, result2 error) {
+	fake.namespaceShowMutex.Lock()
+	defer fake.namespaceShowMutex.Unlock()
+	fake.NamespaceShowStub = nil
+	fake.namespaceShowReturns = struct {
+		result1 models.Namespace
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespaceShowReturnsOnCall(i int, result1 models.Namespace, result2 error) {
+	fake.namespaceShowMutex.Lock()
+	defer fake.namespaceShowMutex.Unlock()
+	fake.NamespaceShowStub = nil
+	if fake.namespaceShowReturnsOnCall == nil {
+		fake.namespaceShowReturnsOnCall = make(map[int]struct {
+			result1 models.Namespace
+			result2 error
+		})
+	}
+	fake.namespaceShowReturnsOnCall[i] = struct {
+		result1 models.Namespace
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) Namespaces() (models.NamespaceList, error) {
+	fake.namespacesMutex.Lock()
+	ret, specificReturn := fake.namespacesReturnsOnCall[len(fake.namespacesArgsForCall)]
+	fake.namespacesArgsForCall = append(fake.namespacesArgsForCall, struct {
+	}{})
+	stub := fake.NamespacesStub
+	fakeReturns := fake.namespacesReturns
+	fake.recordInvocation("Namespaces", []interface{}{})
+	fake.namespacesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) NamespacesCallCount() int {
+	fake.namespacesMutex.RLock()
+	defer fake.namespacesMutex.RUnlock()
+	return len(fake.namespacesArgsForCall)
+}
+
+func (fake *FakeAPIClient) NamespacesCalls(stub func() (models.NamespaceList, error)) {
+	fake.namespacesMutex.Lock()
+	defer fake.namespacesMutex.Unlock()
+	fake.NamespacesStub = stub
+}
+
+func (fake *FakeAPIClient) NamespacesReturns(result1 models.NamespaceList, result2 error) {
+	fake.namespacesMutex.Lock()
+	defer fake.namespacesMutex.Unlock()
+	fake.NamespacesStub = nil
+	fake.namespacesReturns = struct {
+		result1 models.NamespaceList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespacesReturnsOnCall(i int, result1 models.NamespaceList, result2 error) {
+	fake.namespacesMutex.Lock()
+	defer fake.namespacesMutex.Unlock()
+	fake.NamespacesStub = nil
+	if fake.namespacesReturnsOnCall == nil {
+		fake.namespacesReturnsOnCall = make(map[int]struct {
+			result1 models.NamespaceList
+			result2 error
+		})
+	}
+	fake.namespacesReturnsOnCall[i] = struct {
+		result1 models.NamespaceList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespacesMatch(arg1 string) (models.NamespacesMatchResponse, error) {
+	fake.namespacesMatchMutex.Lock()
+	ret, specificReturn := fake.namespacesMatchReturnsOnCall[len(fake.namespacesMatchArgsForCall)]
+	fake.namespacesMatchArgsForCall = append(fake.namespacesMatchArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.NamespacesMatchStub
+	fakeReturns := fake.namespacesMatchReturns
+	fake.recordInvocation("NamespacesMatch", []interface{}{arg1})
+	fake.namespacesMatchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) NamespacesMatchCallCount() int {
+	fake.namespacesMatchMutex.RLock()
+	defer fake.namespacesMatchMutex.RUnlock()
+	return len(fake.namespacesMatchArgsForCall)
+}
+
+func (fake *FakeAPIClient) NamespacesMatchCalls(stub func(string) (models.NamespacesMatchResponse, error)) {
+	fake.namespacesMatchMutex.Lock()
+	defer fake.namespacesMatchMutex.Unlock()
+	fake.NamespacesMatchStub = stub
+}
+
+func (fake *FakeAPIClient) NamespacesMatchArgsForCall(i int) string {
+	fake.namespacesMatchMutex.RLock()
+	defer fake.namespacesMatchMutex.RUnlock()
+	argsForCall := fake.namespacesMatchArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAPIClient) NamespacesMatchReturns(result1 models.NamespacesMatchResponse, result2 error) {
+	fake.namespacesMatchMutex.Lock()
+	defer fake.namespacesMatchMutex.Unlock()
+	fake.NamespacesMatchStub = nil
+	fake.namespacesMatchReturns = struct {
+		result1 models.NamespacesMatchResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespacesMatchReturnsOnCall(i int, result1 models.NamespacesMatchResponse, result2 error) {
+	fake.namespacesMatchMutex.Lock()
+	defer fake.namespacesMatchMutex.Unlock()
+	fake.NamespacesMatchStub = nil
+	if fake.namespacesMatchReturnsOnCall == nil {
+		fake.namespacesMatchReturnsOnCall = make(map[int]struct {
+			result1 models.NamespacesMatchResponse
+			result2 error
+		})
+	}
+	fake.namespacesMatchReturnsOnCall[i] = struct {
+		result1 models.NamespacesMatchResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) Operation(arg1 string) (models.Operation, error) {
+	fake.operationMutex.Lock()
+	ret, specificReturn := fake.operationReturnsOnCall[len(fake.operationArgsForCall)]
+	fake.operationArgsForCall = append(fake.operationArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.OperationStub
+	fakeReturns := fake.operationReturns
+	fake.recordInvocation("Operation", []interface{}{arg1})
+	fake.operationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) OperationCallCount() int {
+	fake.operationMutex.RLock()
+	defer fake.operationMutex.RUnlock()
+	return len(fake.operationArgsForCall)
+}
+
+func (fake *FakeAPIClient) OperationCalls(stub func(string) (models.Operation, error)) {
+	fake.operationMutex.Lock()
+	defer fake.operationMutex.Unlock()
+	fake.OperationStub = stub
+}
+
+func (fake *FakeAPIClient) OperationArgsForCall(i int) string {
+	fake.operationMutex.RLock()
+	defer fake.operationMutex.RUnlock()
+	argsForCall := fake.operationArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAPIClient) OperationReturns(result1 models.Operation, result2 error) {
+	fake.operationMutex.Lock()
+	defer fake.operationMutex.Unlock()
+	fake.OperationStub = nil
+	fake.operationReturns = struct {
+		result1 models.Operation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) OperationReturnsOnCall(i int, result1 models.Operation, result2 error) {
+	fake.operationMutex.Lock()
+	defer fake.operationMutex.Unlock()
+	fake.OperationStub = nil
+	if fake.operationReturnsOnCall == nil {
+		fake.operationReturnsOnCall = make(map[int]struct {
+			result1 models.Operation
+			result2 error
+		})
+	}
+	fake.operationReturnsOnCall[i] = struct {
+		result1 models.Operation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) Policies(arg1 string) (models.PolicyList, error) {
+	fake.policiesMutex.Lock()
+	ret, specificReturn := fake.policiesReturnsOnCall[len(fake.policiesArgsForCall)]
+	fake.policiesArgsForCall = append(fake.policiesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.PoliciesStub
+	fakeReturns := fake.policiesReturns
+	fake.recordInvocation("Policies", []interface{}{arg1})
+	fake.policiesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) PoliciesCallCount() int {
+	fake.policiesMutex.RLock()
+	defer fake.policiesMutex.RUnlock()
+	return len(fake.policiesArgsForCall)
+}
+
+func (fake *FakeAPIClient) PoliciesCalls(stub func(string) (models.PolicyList, error)) {
+	fake.policiesMutex.Lock()
+	defer fake.policiesMutex.Unlock()
+	fake.PoliciesStub = stub
+}
+
+func (fake *FakeAPIClient) PoliciesArgsForCall(i int) string {
+	fake.policiesMutex.RLock()
+	defer fake.policiesMutex.RUnlock()
+	argsForCall := fake.policiesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAPIClient) PoliciesReturns(result1 models.PolicyList, result2 error) {
+	fake.policiesMutex.Lock()
+	defer fake.policiesMutex.Unlock()
+	fake.PoliciesStub = nil
+	fake.policiesReturns = struct {
+		result1 models.PolicyList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) PoliciesReturnsOnCall(i int, result1 models.PolicyList, result2 error) {
+	fake.policiesMutex.Lock()
+	defer fake.policiesMutex.Unlock()
+	fake.PoliciesStub = nil
+	if fake.policiesReturnsOnCall == nil {
+		fake.policiesReturnsOnCall = make(map[int]struct {
+			result1 models.PolicyList
+			result2 error
+		})
+	}
+	fake.policiesReturnsOnCall[i] = struct {
+		result1 models.PolicyList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) PolicyShow(arg1 string, arg2 string) (models.Policy, error) {
+	fake.policyShowMutex.Lock()
+	ret, specificReturn := fake.policyShowReturnsOnCall[len(fake.policyShowArgsForCall)]
+	fake.policyShowArgsForCall = append(fake.policyShowArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.PolicyShowStub
+	fakeReturns := fake.policyShowReturns
+	fake.recordInvocation("PolicyShow", []interface{}{arg1, arg2})
+	fake.policyShowMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -3246,63 +5577,64 @@ func (fake *FakeAPIClient) NamespaceDelete(arg1 string) (models.Response, error)
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeAPIClient) NamespaceDeleteCallCount() int {
-	fake.namespaceDeleteMutex.RLock()
-	defer fake.namespaceDeleteMutex.RUnlock()
-	return len(fake.namespaceDeleteArgsForCall)
+func (fake *FakeAPIClient) PolicyShowCallCount() int {
+	fake.policyShowMutex.RLock()
+	defer fake.policyShowMutex.RUnlock()
+	return len(fake.policyShowArgsForCall)
 }
 
-func (fake *FakeAPIClient) NamespaceDeleteCalls(stub func(string) (models.Response, error)) {
-	fake.namespaceDeleteMutex.Lock()
-	defer fake.namespaceDeleteMutex.Unlock()
-	fake.NamespaceDeleteStub = stub
+func (fake *FakeAPIClient) PolicyShowCalls(stub func(string, string) (models.Policy, error)) {
+	fake.policyShowMutex.Lock()
+	defer fake.policyShowMutex.Unlock()
+	fake.PolicyShowStub = stub
 }
 
-func (fake *FakeAPIClient) NamespaceDeleteArgsForCall(i int) string {
-	fake.namespaceDeleteMutex.RLock()
-	defer fake.namespaceDeleteMutex.RUnlock()
-	argsForCall := fake.namespaceDeleteArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeAPIClient) PolicyShowArgsForCall(i int) (string, string) {
+	fake.policyShowMutex.RLock()
+	defer fake.policyShowMutex.RUnlock()
+	argsForCall := fake.policyShowArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeAPIClient) NamespaceDeleteReturns(result1 models.Response, result2 error) {
-	fake.namespaceDeleteMutex.Lock()
-	defer fake.namespaceDeleteMutex.Unlock()
-	fake.NamespaceDeleteStub = nil
-	fake.namespaceDeleteReturns = struct {
-		result1 models.Response
+func (fake *FakeAPIClient) PolicyShowReturns(result1 models.Policy, result2 error) {
+	fake.policyShowMutex.Lock()
+	defer fake.policyShowMutex.Unlock()
+	fake.PolicyShowStub = nil
+	fake.policyShowReturns = struct {
+		result1 models.Policy
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) NamespaceDeleteReturnsOnCall(i int, result1 models.Response, result2 error) {
-	fake.namespaceDeleteMutex.Lock()
-	defer fake.namespaceDeleteMutex.Unlock()
-	fake.NamespaceDeleteStub = nil
-	if fake.namespaceDeleteReturnsOnCall == nil {
-		fake.namespaceDeleteReturnsOnCall = make(map[int]struct {
-			result1 models.Response
+func (fake *FakeAPIClient) PolicyShowReturnsOnCall(i int, result1 models.Policy, result2 error) {
+	fake.policyShowMutex.Lock()
+	defer fake.policyShowMutex.Unlock()
+	fake.PolicyShowStub = nil
+	if fake.policyShowReturnsOnCall == nil {
+		fake.policyShowReturnsOnCall = make(map[int]struct {
+			result1 models.Policy
 			result2 error
 		})
 	}
-	fake.namespaceDeleteReturnsOnCall[i] = struct {
-		result1 models.Response
+	fake.policyShowReturnsOnCall[i] = struct {
+		result1 models.Policy
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) NamespaceShow(arg1 string) (models.Namespace, error) {
-	fake.namespaceShowMutex.Lock()
-	ret, specificReturn := fake.namespaceShowReturnsOnCall[len(fake.namespaceShowArgsForCall)]
-	fake.namespaceShowArgsForCall = append(fake.namespaceShowArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	stub := fake.NamespaceShowStub
-	fakeReturns := fake.namespaceShowReturns
-	fake.recordInvocation("NamespaceShow", []interface{}{arg1})
-	fake.namespaceShowMutex.Unlock()
+func (fake *FakeAPIClient) RegistryCredentialCreate(arg1 models.RegistryCredentialCreateRequest, arg2 string) (models.Response, error) {
+	fake.registryCredentialCreateMutex.Lock()
+	ret, specificReturn := fake.registryCredentialCreateReturnsOnCall[len(fake.registryCredentialCreateArgsForCall)]
+	fake.registryCredentialCreateArgsForCall = append(fake.registryCredentialCreateArgsForCall, struct {
+		arg1 models.RegistryCredentialCreateRequest
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RegistryCredentialCreateStub
+	fakeReturns := fake.registryCredentialCreateReturns
+	fake.recordInvocation("RegistryCredentialCreate", []interface{}{arg1, arg2})
+	fake.registryCredentialCreateMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -3310,62 +5642,64 @@ func (fake *FakeAPIClient) NamespaceShow(arg1 string) (models.Namespace, error)
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeAPIClient) NamespaceShowCallCount() int {
-	fake.namespaceShowMutex.RLock()
-	defer fake.namespaceShowMutex.RUnlock()
-	return len(fake.namespaceShowArgsForCall)
+func (fake *FakeAPIClient) RegistryCredentialCreateCallCount() int {
+	fake.registryCredentialCreateMutex.RLock()
+	defer fake.registryCredentialCreateMutex.RUnlock()
+	return len(fake.registryCredentialCreateArgsForCall)
 }
 
-func (fake *FakeAPIClient) NamespaceShowCalls(stub func(string) (models.Namespace, error)) {
-	fake.namespaceShowMutex.Lock()
-	defer fake.namespaceShowMutex.Unlock()
-	fake.NamespaceShowStub = stub
+func (fake *FakeAPIClient) RegistryCredentialCreateCalls(stub func(models.RegistryCredentialCreateRequest, string) (models.Response, error)) {
+	fake.registryCredentialCreateMutex.Lock()
+	defer fake.registryCredentialCreateMutex.Unlock()
+	fake.RegistryCredentialCreateStub = stub
 }
 
-func (fake *FakeAPIClient) NamespaceShowArgsForCall(i int) string {
-	fake.namespaceShowMutex.RLock()
-	defer fake.namespaceShowMutex.RUnlock()
-	argsForCall := fake.namespaceShowArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeAPIClient) RegistryCredentialCreateArgsForCall(i int) (models.RegistryCredentialCreateRequest, string) {
+	fake.registryCredentialCreateMutex.RLock()
+	defer fake.registryCredentialCreateMutex.RUnlock()
+	argsForCall := fake.registryCredentialCreateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeAPIClient) NamespaceShowReturns(result1 models.Namespace, result2 error) {
-	fake.namespaceShowMutex.Lock()
-	defer fake.namespaceShowMutex.Unlock()
-	fake.NamespaceShowStub = nil
-	fake.namespaceShowReturns = struct {
-		result1 models.Namespace
+func (fake *FakeAPIClient) RegistryCredentialCreateReturns(result1 models.Response, result2 error) {
+	fake.registryCredentialCreateMutex.Lock()
+	defer fake.registryCredentialCreateMutex.Unlock()
+	fake.RegistryCredentialCreateStub = nil
+	fake.registryCredentialCreateReturns = struct {
+		result1 models.Response
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) NamespaceShowReturnsOnCall(i int, result1 models.Namespace, result2 error) {
-	fake.namespaceShowMutex.Lock()
-	defer fake.namespaceShowMutex.Unlock()
-	fake.NamespaceShowStub = nil
-	if fake.namespaceShowReturnsOnCall == nil {
-		fake.namespaceShowReturnsOnCall = make(map[int]struct {
-			result1 models.Namespace
+func (fake *FakeAPIClient) RegistryCredentialCreateReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.registryCredentialCreateMutex.Lock()
+	defer fake.registryCredentialCreateMutex.Unlock()
+	fake.RegistryCredentialCreateStub = nil
+	if fake.registryCredentialCreateReturnsOnCall == nil {
+		fake.registryCredentialCreateReturnsOnCall = make(map[int]struct {
+			result1 models.Response
 			result2 error
 		})
 	}
-	fake.namespaceShowReturnsOnCall[i] = struct {
-		result1 models.Namespace
+	fake.registryCredentialCreateReturnsOnCall[i] = struct {
+		result1 models.Response
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) Namespaces() (models.NamespaceList, error) {
-	fake.namespacesMutex.Lock()
-	ret, specificReturn := fake.namespacesReturnsOnCall[len(fake.namespacesArgsForCall)]
-	fake.namespacesArgsForCall = append(fake.namespacesArgsForCall, struct {
-	}{})
-	stub := fake.NamespacesStub
-	fakeReturns := fake.namespacesReturns
-	fake.recordInvocation("Namespaces", []interface{}{})
-	fake.namespacesMutex.Unlock()
+func (fake *FakeAPIClient) RegistryCredentialDelete(arg1 string, arg2 string) (models.Response, error) {
+	fake.registryCredentialDeleteMutex.Lock()
+	ret, specificReturn := fake.registryCredentialDeleteReturnsOnCall[len(fake.registryCredentialDeleteArgsForCall)]
+	fake.registryCredentialDeleteArgsForCall = append(fake.registryCredentialDeleteArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RegistryCredentialDeleteStub
+	fakeReturns := fake.registryCredentialDeleteReturns
+	fake.recordInvocation("RegistryCredentialDelete", []interface{}{arg1, arg2})
+	fake.registryCredentialDeleteMutex.Unlock()
 	if stub != nil {
-		return stub()
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -3373,54 +5707,61 @@ func (fake *FakeAPIClient) Namespaces() (models.NamespaceList, error) {
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeAPIClient) NamespacesCallCount() int {
-	fake.namespacesMutex.RLock()
-	defer fake.namespacesMutex.RUnlock()
-	return len(fake.namespacesArgsForCall)
+func (fake *FakeAPIClient) RegistryCredentialDeleteCallCount() int {
+	fake.registryCredentialDeleteMutex.RLock()
+	defer fake.registryCredentialDeleteMutex.RUnlock()
+	return len(fake.registryCredentialDeleteArgsForCall)
 }
 
-func (fake *FakeAPIClient) NamespacesCalls(stub func() (models.NamespaceList, error)) {
-	fake.namespacesMutex.Lock()
-	defer fake.namespacesMutex.Unlock()
-	fake.NamespacesStub = stub
+func (fake *FakeAPIClient) RegistryCredentialDeleteCalls(stub func(string, string) (models.Response, error)) {
+	fake.registryCredentialDeleteMutex.Lock()
+	defer fake.registryCredentialDeleteMutex.Unlock()
+	fake.RegistryCredentialDeleteStub = stub
 }
 
-func (fake *FakeAPIClient) NamespacesReturns(result1 models.NamespaceList, result2 error) {
-	fake.namespacesMutex.Lock()
-	defer fake.namespacesMutex.Unlock()
-	fake.NamespacesStub = nil
-	fake.namespacesReturns = struct {
-		result1 models.NamespaceList
+func (fake *FakeAPIClient) RegistryCredentialDeleteArgsForCall(i int) (string, string) {
+	fake.registryCredentialDeleteMutex.RLock()
+	defer fake.registryCredentialDeleteMutex.RUnlock()
+	argsForCall := fake.registryCredentialDeleteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) RegistryCredentialDeleteReturns(result1 models.Response, result2 error) {
+	fake.registryCredentialDeleteMutex.Lock()
+	defer fake.registryCredentialDeleteMutex.Unlock()
+	fake.RegistryCredentialDeleteStub = nil
+	fake.registryCredentialDeleteReturns = struct {
+		result1 models.Response
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) NamespacesReturnsOnCall(i int, result1 models.NamespaceList, result2 error) {
-	fake.namespacesMutex.Lock()
-	defer fake.namespacesMutex.Unlock()
-	fake.NamespacesStub = nil
-	if fake.namespacesReturnsOnCall == nil {
-		fake.namespacesReturnsOnCall = make(map[int]struct {
-			result1 models.NamespaceList
+func (fake *FakeAPIClient) RegistryCredentialDeleteReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.registryCredentialDeleteMutex.Lock()
+	defer fake.registryCredentialDeleteMutex.Unlock()
+	fake.RegistryCredentialDeleteStub = nil
+	if fake.registryCredentialDeleteReturnsOnCall == nil {
+		fake.registryCredentialDeleteReturnsOnCall = make(map[int]struct {
+			result1 models.Response
 			result2 error
 		})
 	}
-	fake.namespacesReturnsOnCall[i] = struct {
-		result1 models.NamespaceList
+	fake.registryCredentialDeleteReturnsOnCall[i] = struct {
+		result1 models.Response
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) NamespacesMatch(arg1 string) (models.NamespacesMatchResponse, error) {
-	fake.namespacesMatchMutex.Lock()
-	ret, specificReturn := fake.namespacesMatchReturnsOnCall[len(fake.namespacesMatchArgsForCall)]
-	fake.namespacesMatchArgsForCall = append(fake.namespacesMatchArgsForCall, struct {
+func (fake *FakeAPIClient) RegistryCredentials(arg1 string) (models.RegistryCredentialList, error) {
+	fake.registryCredentialsMutex.Lock()
+	ret, specificReturn := fake.registryCredentialsReturnsOnCall[len(fake.registryCredentialsArgsForCall)]
+	fake.registryCredentialsArgsForCall = append(fake.registryCredentialsArgsForCall, struct {
 		arg1 string
 	}{arg1})
-	stub := fake.NamespacesMatchStub
-	fakeReturns := fake.namespacesMatchReturns
-	fake.recordInvocation("NamespacesMatch", []interface{}{arg1})
-	fake.namespacesMatchMutex.Unlock()
+	stub := fake.RegistryCredentialsStub
+	fakeReturns := fake.registryCredentialsReturns
+	fake.recordInvocation("RegistryCredentials", []interface{}{arg1})
+	fake.registryCredentialsMutex.Unlock()
 	if stub != nil {
 		return stub(arg1)
 	}
@@ -3430,47 +5771,47 @@ func (fake *FakeAPIClient) NamespacesMatch(arg1 string) (models.NamespacesMatchR
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeAPIClient) NamespacesMatchCallCount() int {
-	fake.namespacesMatchMutex.RLock()
-	defer fake.namespacesMatchMutex.RUnlock()
-	return len(fake.namespacesMatchArgsForCall)
+func (fake *FakeAPIClient) RegistryCredentialsCallCount() int {
+	fake.registryCredentialsMutex.RLock()
+	defer fake.registryCredentialsMutex.RUnlock()
+	return len(fake.registryCredentialsArgsForCall)
 }
 
-func (fake *FakeAPIClient) NamespacesMatchCalls(stub func(string) (models.NamespacesMatchResponse, error)) {
-	fake.namespacesMatchMutex.Lock()
-	defer fake.namespacesMatchMutex.Unlock()
-	fake.NamespacesMatchStub = stub
+func (fake *FakeAPIClient) RegistryCredentialsCalls(stub func(string) (models.RegistryCredentialList, error)) {
+	fake.registryCredentialsMutex.Lock()
+	defer fake.registryCredentialsMutex.Unlock()
+	fake.RegistryCredentialsStub = stub
 }
 
-func (fake *FakeAPIClient) NamespacesMatchArgsForCall(i int) string {
-	fake.namespacesMatchMutex.RLock()
-	defer fake.namespacesMatchMutex.RUnlock()
-	argsForCall := fake.namespacesMatchArgsForCall[i]
+func (fake *FakeAPIClient) RegistryCredentialsArgsForCall(i int) string {
+	fake.registryCredentialsMutex.RLock()
+	defer fake.registryCredentialsMutex.RUnlock()
+	argsForCall := fake.registryCredentialsArgsForCall[i]
 	return argsForCall.arg1
 }
 
-func (fake *FakeAPIClient) NamespacesMatchReturns(result1 models.NamespacesMatchResponse, result2 error) {
-	fake.namespacesMatchMutex.Lock()
-	defer fake.namespacesMatchMutex.Unlock()
-	fake.NamespacesMatchStub = nil
-	fake.namespacesMatchReturns = struct {
-		result1 models.NamespacesMatchResponse
+func (fake *FakeAPIClient) RegistryCredentialsReturns(result1 models.RegistryCredentialList, result2 error) {
+	fake.registryCredentialsMutex.Lock()
+	defer fake.registryCredentialsMutex.Unlock()
+	fake.RegistryCredentialsStub = nil
+	fake.registryCredentialsReturns = struct {
+		result1 models.RegistryCredentialList
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) NamespacesMatchReturnsOnCall(i int, result1 models.NamespacesMatchResponse, result2 error) {
-	fake.namespacesMatchMutex.Lock()
-	defer fake.namespacesMatchMutex.Unlock()
-	fake.NamespacesMatchStub = nil
-	if fake.namespacesMatchReturnsOnCall == nil {
-		fake.namespacesMatchReturnsOnCall = make(map[int]struct {
-			result1 models.NamespacesMatchResponse
+func (fake *FakeAPIClient) RegistryCredentialsReturnsOnCall(i int, result1 models.RegistryCredentialList, result2 error) {
+	fake.registryCredentialsMutex.Lock()
+	defer fake.registryCredentialsMutex.Unlock()
+	fake.RegistryCredentialsStub = nil
+	if fake.registryCredentialsReturnsOnCall == nil {
+		fake.registryCredentialsReturnsOnCall = make(map[int]struct {
+			result1 models.RegistryCredentialList
 			result2 error
 		})
 	}
-	fake.namespacesMatchReturnsOnCall[i] = struct {
-		result1 models.NamespacesMatchResponse
+	fake.registryCredentialsReturnsOnCall[i] = struct {
+		result1 models.RegistryCredentialList
 		result2 error
 	}{result1, result2}
 }
@@ -3980,6 +6321,72 @@ func (fake *FakeAPIClient) ServiceMatchReturnsOnCall(i int, result1 models.Servi
 	}{result1, result2}
 }
 
+func (fake *FakeAPIClient) ServiceProtect(arg1 string, arg2 string, arg3 bool) (models.Response, error) {
+	fake.serviceProtectMutex.Lock()
+	ret, specificReturn := fake.serviceProtectReturnsOnCall[len(fake.serviceProtectArgsForCall)]
+	fake.serviceProtectArgsForCall = append(fake.serviceProtectArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 bool
+	}{arg1, arg2, arg3})
+	stub := fake.ServiceProtectStub
+	fakeReturns := fake.serviceProtectReturns
+	fake.recordInvocation("ServiceProtect", []interface{}{arg1, arg2, arg3})
+	fake.serviceProtectMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) ServiceProtectCallCount() int {
+	fake.serviceProtectMutex.RLock()
+	defer fake.serviceProtectMutex.RUnlock()
+	return len(fake.serviceProtectArgsForCall)
+}
+
+func (fake *FakeAPIClient) ServiceProtectCalls(stub func(string, string, bool) (models.Response, error)) {
+	fake.serviceProtectMutex.Lock()
+	defer fake.serviceProtectMutex.Unlock()
+	fake.ServiceProtectStub = stub
+}
+
+func (fake *FakeAPIClient) ServiceProtectArgsForCall(i int) (string, string, bool) {
+	fake.serviceProtectMutex.RLock()
+	defer fake.serviceProtectMutex.RUnlock()
+	argsForCall := fake.serviceProtectArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAPIClient) ServiceProtectReturns(result1 models.Response, result2 error) {
+	fake.serviceProtectMutex.Lock()
+	defer fake.serviceProtectMutex.Unlock()
+	fake.ServiceProtectStub = nil
+	fake.serviceProtectReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) ServiceProtectReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.serviceProtectMutex.Lock()
+	defer fake.serviceProtectMutex.Unlock()
+	fake.ServiceProtectStub = nil
+	if fake.serviceProtectReturnsOnCall == nil {
+		fake.serviceProtectReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.serviceProtectReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeAPIClient) ServiceShow(arg1 *models.ServiceShowRequest, arg2 string) (*models.Service, error) {
 	fake.serviceShowMutex.Lock()
 	ret, specificReturn := fake.serviceShowReturnsOnCall[len(fake.serviceShowArgsForCall)]
@@ -4108,6 +6515,71 @@ func (fake *FakeAPIClient) ServiceUnbindReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeAPIClient) StagingCancel(arg1 string, arg2 string) (models.Response, error) {
+	fake.stagingCancelMutex.Lock()
+	ret, specificReturn := fake.stagingCancelReturnsOnCall[len(fake.stagingCancelArgsForCall)]
+	fake.stagingCancelArgsForCall = append(fake.stagingCancelArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.StagingCancelStub
+	fakeReturns := fake.stagingCancelReturns
+	fake.recordInvocation("StagingCancel", []interface{}{arg1, arg2})
+	fake.stagingCancelMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) StagingCancelCallCount() int {
+	fake.stagingCancelMutex.RLock()
+	defer fake.stagingCancelMutex.RUnlock()
+	return len(fake.stagingCancelArgsForCall)
+}
+
+func (fake *FakeAPIClient) StagingCancelCalls(stub func(string, string) (models.Response, error)) {
+	fake.stagingCancelMutex.Lock()
+	defer fake.stagingCancelMutex.Unlock()
+	fake.StagingCancelStub = stub
+}
+
+func (fake *FakeAPIClient) StagingCancelArgsForCall(i int) (string, string) {
+	fake.stagingCancelMutex.RLock()
+	defer fake.stagingCancelMutex.RUnlock()
+	argsForCall := fake.stagingCancelArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) StagingCancelReturns(result1 models.Response, result2 error) {
+	fake.stagingCancelMutex.Lock()
+	defer fake.stagingCancelMutex.Unlock()
+	fake.StagingCancelStub = nil
+	fake.stagingCancelReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) StagingCancelReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.stagingCancelMutex.Lock()
+	defer fake.stagingCancelMutex.Unlock()
+	fake.StagingCancelStub = nil
+	if fake.stagingCancelReturnsOnCall == nil {
+		fake.stagingCancelReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.stagingCancelReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeAPIClient) StagingComplete(arg1 string, arg2 string) (models.Response, error) {
 	fake.stagingCompleteMutex.Lock()
 	ret, specificReturn := fake.stagingCompleteReturnsOnCall[len(fake.stagingCompleteArgsForCall)]
@@ -4173,6 +6645,70 @@ func (fake *FakeAPIClient) StagingCompleteReturnsOnCall(i int, result1 models.Re
 	}{result1, result2}
 }
 
+func (fake *FakeAPIClient) StagingStatus(arg1 string) (models.StagingStatusResponse, error) {
+	fake.stagingStatusMutex.Lock()
+	ret, specificReturn := fake.stagingStatusReturnsOnCall[len(fake.stagingStatusArgsForCall)]
+	fake.stagingStatusArgsForCall = append(fake.stagingStatusArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.StagingStatusStub
+	fakeReturns := fake.stagingStatusReturns
+	fake.recordInvocation("StagingStatus", []interface{}{arg1})
+	fake.stagingStatusMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) StagingStatusCallCount() int {
+	fake.stagingStatusMutex.RLock()
+	defer fake.stagingStatusMutex.RUnlock()
+	return len(fake.stagingStatusArgsForCall)
+}
+
+func (fake *FakeAPIClient) StagingStatusCalls(stub func(string) (models.StagingStatusResponse, error)) {
+	fake.stagingStatusMutex.Lock()
+	defer fake.stagingStatusMutex.Unlock()
+	fake.StagingStatusStub = stub
+}
+
+func (fake *FakeAPIClient) StagingStatusArgsForCall(i int) string {
+	fake.stagingStatusMutex.RLock()
+	defer fake.stagingStatusMutex.RUnlock()
+	argsForCall := fake.stagingStatusArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAPIClient) StagingStatusReturns(result1 models.StagingStatusResponse, result2 error) {
+	fake.stagingStatusMutex.Lock()
+	defer fake.stagingStatusMutex.Unlock()
+	fake.StagingStatusStub = nil
+	fake.stagingStatusReturns = struct {
+		result1 models.StagingStatusResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) StagingStatusReturnsOnCall(i int, result1 models.StagingStatusResponse, result2 error) {
+	fake.stagingStatusMutex.Lock()
+	defer fake.stagingStatusMutex.Unlock()
+	fake.StagingStatusStub = nil
+	if fake.stagingStatusReturnsOnCall == nil {
+		fake.stagingStatusReturnsOnCall = make(map[int]struct {
+			result1 models.StagingStatusResponse
+			result2 error
+		})
+	}
+	fake.stagingStatusReturnsOnCall[i] = struct {
+		result1 models.StagingStatusResponse
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeAPIClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -4182,12 +6718,18 @@ func (fake *FakeAPIClient) Invocations() map[string][][]interface{} {
 	defer fake.allConfigurationsMutex.RUnlock()
 	fake.allServicesMutex.RLock()
 	defer fake.allServicesMutex.RUnlock()
+	fake.appBuildCacheMutex.RLock()
+	defer fake.appBuildCacheMutex.RUnlock()
+	fake.appBuildCachePurgeMutex.RLock()
+	defer fake.appBuildCachePurgeMutex.RUnlock()
 	fake.appCreateMutex.RLock()
 	defer fake.appCreateMutex.RUnlock()
 	fake.appDeleteMutex.RLock()
 	defer fake.appDeleteMutex.RUnlock()
 	fake.appDeployMutex.RLock()
 	defer fake.appDeployMutex.RUnlock()
+	fake.appEventsMutex.RLock()
+	defer fake.appEventsMutex.RUnlock()
 	fake.appExecMutex.RLock()
 	defer fake.appExecMutex.RUnlock()
 	fake.appGetPartMutex.RLock()
@@ -4198,20 +6740,38 @@ func (fake *FakeAPIClient) Invocations() map[string][][]interface{} {
 	defer fake.appLogsMutex.RUnlock()
 	fake.appMatchMutex.RLock()
 	defer fake.appMatchMutex.RUnlock()
+	fake.appMetricsMutex.RLock()
+	defer fake.appMetricsMutex.RUnlock()
 	fake.appPortForwardMutex.RLock()
 	defer fake.appPortForwardMutex.RUnlock()
+	fake.appProtectMutex.RLock()
+	defer fake.appProtectMutex.RUnlock()
 	fake.appRestartMutex.RLock()
 	defer fake.appRestartMutex.RUnlock()
 	fake.appRunningMutex.RLock()
 	defer fake.appRunningMutex.RUnlock()
+	fake.appSBOMMutex.RLock()
+	defer fake.appSBOMMutex.RUnlock()
 	fake.appShowMutex.RLock()
 	defer fake.appShowMutex.RUnlock()
+	fake.appShowRevealedMutex.RLock()
+	defer fake.appShowRevealedMutex.RUnlock()
 	fake.appStageMutex.RLock()
 	defer fake.appStageMutex.RUnlock()
 	fake.appUpdateMutex.RLock()
 	defer fake.appUpdateMutex.RUnlock()
 	fake.appUploadMutex.RLock()
 	defer fake.appUploadMutex.RUnlock()
+	fake.appUploadChunkedMutex.RLock()
+	defer fake.appUploadChunkedMutex.RUnlock()
+	fake.appUploadMatchMutex.RLock()
+	defer fake.appUploadMatchMutex.RUnlock()
+	fake.appWebhookMutex.RLock()
+	defer fake.appWebhookMutex.RUnlock()
+	fake.appWebhookEnableMutex.RLock()
+	defer fake.appWebhookEnableMutex.RUnlock()
+	fake.appWebhookRotateMutex.RLock()
+	defer fake.appWebhookRotateMutex.RUnlock()
 	fake.appsMutex.RLock()
 	defer fake.appsMutex.RUnlock()
 	fake.authTokenMutex.RLock()
@@ -4242,26 +6802,60 @@ func (fake *FakeAPIClient) Invocations() map[string][][]interface{} {
 	defer fake.configurationsMutex.RUnlock()
 	fake.envListMutex.RLock()
 	defer fake.envListMutex.RUnlock()
+	fake.envListRevealedMutex.RLock()
+	defer fake.envListRevealedMutex.RUnlock()
 	fake.envMatchMutex.RLock()
 	defer fake.envMatchMutex.RUnlock()
 	fake.envSetMutex.RLock()
 	defer fake.envSetMutex.RUnlock()
+	fake.envSetFromMutex.RLock()
+	defer fake.envSetFromMutex.RUnlock()
+	fake.envSetSecretMutex.RLock()
+	defer fake.envSetSecretMutex.RUnlock()
 	fake.envShowMutex.RLock()
 	defer fake.envShowMutex.RUnlock()
+	fake.envShowRevealedMutex.RLock()
+	defer fake.envShowRevealedMutex.RUnlock()
+	fake.envStagingUnsetMutex.RLock()
+	defer fake.envStagingUnsetMutex.RUnlock()
 	fake.envUnsetMutex.RLock()
 	defer fake.envUnsetMutex.RUnlock()
 	fake.infoMutex.RLock()
 	defer fake.infoMutex.RUnlock()
+	fake.namespaceBackupMutex.RLock()
+	defer fake.namespaceBackupMutex.RUnlock()
 	fake.namespaceCreateMutex.RLock()
 	defer fake.namespaceCreateMutex.RUnlock()
 	fake.namespaceDeleteMutex.RLock()
 	defer fake.namespaceDeleteMutex.RUnlock()
+	fake.namespaceDeletePreviewMutex.RLock()
+	defer fake.namespaceDeletePreviewMutex.RUnlock()
+	fake.namespaceProtectMutex.RLock()
+	defer fake.namespaceProtectMutex.RUnlock()
+	fake.namespaceQuotaMutex.RLock()
+	defer fake.namespaceQuotaMutex.RUnlock()
+	fake.namespaceQuotaSetMutex.RLock()
+	defer fake.namespaceQuotaSetMutex.RUnlock()
+	fake.namespaceRestoreMutex.RLock()
+	defer fake.namespaceRestoreMutex.RUnlock()
 	fake.namespaceShowMutex.RLock()
 	defer fake.namespaceShowMutex.RUnlock()
 	fake.namespacesMutex.RLock()
 	defer fake.namespacesMutex.RUnlock()
 	fake.namespacesMatchMutex.RLock()
 	defer fake.namespacesMatchMutex.RUnlock()
+	fake.operationMutex.RLock()
+	defer fake.operationMutex.RUnlock()
+	fake.policiesMutex.RLock()
+	defer fake.policiesMutex.RUnlock()
+	fake.policyShowMutex.RLock()
+	defer fake.policyShowMutex.RUnlock()
+	fake.registryCredentialCreateMutex.RLock()
+	defer fake.registryCredentialCreateMutex.RUnlock()
+	fake.registryCredentialDeleteMutex.RLock()
+	defer fake.registryCredentialDeleteMutex.RUnlock()
+	fake.registryCredentialsMutex.RLock()
+	defer fake.registryCredentialsMutex.RUnlock()
 	fake.serviceBindMutex.RLock()
 	defer fake.serviceBindMutex.RUnlock()
 	fake.serviceCatalogMutex.RLock()
@@ -4278,12 +6872,18 @@ func (fake *FakeAPIClient) Invocations() map[string][][]interface{} {
 	defer fake.serviceListMutex.RUnlock()
 	fake.serviceMatchMutex.RLock()
 	defer fake.serviceMatchMutex.RUnlock()
+	fake.serviceProtectMutex.RLock()
+	defer fake.serviceProtectMutex.RUnlock()
 	fake.serviceShowMutex.RLock()
 	defer fake.serviceShowMutex.RUnlock()
 	fake.serviceUnbindMutex.RLock()
 	defer fake.serviceUnbindMutex.RUnlock()
+	fake.stagingCancelMutex.RLock()
+	defer fake.stagingCancelMutex.RUnlock()
 	fake.stagingCompleteMutex.RLock()
 	defer fake.stagingCompleteMutex.RUnlock()
+	fake.stagingStatusMutex.RLock()
+	defer fake.stagingStatusMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,95 @@
+package usercmd
+
+import (
+	"context"
+
+	api "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// AppWebhookShow displays the git webhook of the named application
+func (c *EpinioClient) AppWebhookShow(ctx context.Context, appName string) error {
+	log := c.Log.WithName("AppWebhookShow").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		Msg("Show application webhook")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	webhook, err := c.API.AppWebhook(c.Settings.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	if !webhook.Enabled {
+		c.ui.Exclamation().Msgf("The webhook is not enabled. Enable it with `epinio app webhook enable %s`", appName)
+		return nil
+	}
+
+	c.reportWebhook(webhook)
+	return nil
+}
+
+// AppWebhookEnable enables the git webhook of the named application, and displays it
+func (c *EpinioClient) AppWebhookEnable(ctx context.Context, appName string) error {
+	log := c.Log.WithName("AppWebhookEnable").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		Msg("Enable application webhook")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	webhook, err := c.API.AppWebhookEnable(c.Settings.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	c.reportWebhook(webhook)
+	return nil
+}
+
+// AppWebhookRotate replaces the secret of the git webhook of the named application, and
+// displays the webhook
+func (c *EpinioClient) AppWebhookRotate(ctx context.Context, appName string) error {
+	log := c.Log.WithName("AppWebhookRotate").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		Msg("Rotate application webhook secret")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	webhook, err := c.API.AppWebhookRotate(c.Settings.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	c.reportWebhook(webhook)
+	c.ui.Exclamation().Msg("Update the secret of the webhook at the git hosting service. Notifications signed with the old secret are rejected.")
+	return nil
+}
+
+func (c *EpinioClient) reportWebhook(webhook models.AppWebhook) {
+	c.ui.Success().WithTable("Key", "Value").
+		WithTableRow("URL", c.Settings.API+api.Root+webhook.Path).
+		WithTableRow("Content Type", "application/json").
+		WithTableRow("Secret", webhook.Secret).
+		Msg("Webhook. Configure it for push events at the git hosting service:")
+}
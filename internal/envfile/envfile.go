@@ -0,0 +1,213 @@
+// Package envfile reads and writes application environments in the file formats users
+// keep them in: dotenv, JSON, and YAML.
+package envfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Supported formats.
+const (
+	FormatDotenv = "dotenv"
+	FormatJSON   = "json"
+	FormatYAML   = "yaml"
+)
+
+// plainValue matches the dotenv values which can be written without quotes.
+var plainValue = regexp.MustCompile(`^[A-Za-z0-9_./:@+,-]*$`)
+
+// FormatOf returns the format of the file, as indicated by its extension. Files without
+// a known extension are taken to be dotenv.
+func FormatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	return FormatDotenv
+}
+
+// Parse decodes the data as an environment in the specified format.
+func Parse(data []byte, format string) (models.EnvVariableMap, error) {
+	switch format {
+	case FormatDotenv:
+		return parseDotenv(data)
+	case FormatJSON:
+		var values map[string]interface{}
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, errors.Wrap(err, "bad JSON")
+		}
+		return fromValues(values)
+	case FormatYAML:
+		var values map[string]interface{}
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return nil, errors.Wrap(err, "bad YAML")
+		}
+		return fromValues(values)
+	}
+	return nil, fmt.Errorf("unknown format '%s', expected one of dotenv, json, yaml", format)
+}
+
+// Format encodes the environment in the specified format. The variables are sorted by
+// name.
+func Format(environment models.EnvVariableMap, format string) ([]byte, error) {
+	switch format {
+	case FormatDotenv:
+		var out bytes.Buffer
+		for _, ev := range environment.List() {
+			fmt.Fprintf(&out, "%s=%s\n", ev.Name, quote(ev.Value))
+		}
+		return out.Bytes(), nil
+	case FormatJSON:
+		out, err := json.MarshalIndent(environment, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(out, '\n'), nil
+	case FormatYAML:
+		// yaml.v2 sorts map keys.
+		return yaml.Marshal(map[string]string(environment))
+	}
+	return nil, fmt.Errorf("unknown format '%s', expected one of dotenv, json, yaml", format)
+}
+
+// fromValues converts the decoded values of a JSON or YAML document into an
+// environment. Only scalar values are accepted.
+func fromValues(values map[string]interface{}) (models.EnvVariableMap, error) {
+	result := models.EnvVariableMap{}
+	for name, value := range values {
+		if err := checkName(name); err != nil {
+			return nil, err
+		}
+		switch v := value.(type) {
+		case nil:
+			result[name] = ""
+		case string:
+			result[name] = v
+		case bool, int, int64, float64:
+			result[name] = fmt.Sprint(v)
+		default:
+			return nil, fmt.Errorf("variable '%s' has a structured value, expected a scalar", name)
+		}
+	}
+	return result, nil
+}
+
+// parseDotenv decodes dotenv data. It supports comments, an optional `export` prefix,
+// single-quoted (literal) and double-quoted (escaped) values, both of which may span
+// lines, and unquoted values with trailing comments.
+func parseDotenv(data []byte) (models.EnvVariableMap, error) {
+	result := models.EnvVariableMap{}
+	lines := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimSuffix(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		pieces := strings.SplitN(line, "=", 2)
+		if len(pieces) != 2 {
+			return nil, fmt.Errorf("line %d: expected NAME=VALUE", lineNo)
+		}
+		name := strings.TrimSpace(pieces[0])
+		if err := checkName(name); err != nil {
+			return nil, errors.Wrapf(err, "line %d", lineNo)
+		}
+		value := strings.TrimLeft(pieces[1], " \t")
+
+		if value == "" || (value[0] != '"' && value[0] != '\'') {
+			// Unquoted. A comment has to be separated by whitespace.
+			if idx := strings.Index(value, " #"); idx >= 0 {
+				value = value[:idx]
+			}
+			result[name] = strings.TrimSpace(value)
+			continue
+		}
+
+		// Quoted, collect lines until the closing quote.
+		quoteChar := value[0]
+		text := value[1:]
+		for {
+			end := closingQuote(text, quoteChar)
+			if end >= 0 {
+				rest := strings.TrimSpace(text[end+1:])
+				if rest != "" && !strings.HasPrefix(rest, "#") {
+					return nil, fmt.Errorf("line %d: unexpected text after closing quote", lineNo)
+				}
+				text = text[:end]
+				break
+			}
+			i++
+			if i >= len(lines) {
+				return nil, fmt.Errorf("line %d: missing closing quote", lineNo)
+			}
+			text += "\n" + lines[i]
+		}
+
+		if quoteChar == '"' {
+			text = unescape(text)
+		}
+		result[name] = text
+	}
+
+	return result, nil
+}
+
+// closingQuote returns the index of the unescaped quote character ending the text, or -1.
+// Single quoted text has no escapes.
+func closingQuote(text string, quoteChar byte) int {
+	for i := 0; i < len(text); i++ {
+		if quoteChar == '"' && text[i] == '\\' {
+			i++
+			continue
+		}
+		if text[i] == quoteChar {
+			return i
+		}
+	}
+	return -1
+}
+
+var unescaper = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\n`, "\n", `\r`, "\r", `\t`, "\t")
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+func unescape(text string) string {
+	return unescaper.Replace(text)
+}
+
+// quote returns the value in the form for a dotenv file, double-quoted and escaped if
+// necessary.
+func quote(value string) string {
+	if plainValue.MatchString(value) {
+		return value
+	}
+	return `"` + escaper.Replace(value) + `"`
+}
+
+func checkName(name string) error {
+	if name == "" || strings.ContainsAny(name, "= \t\n") {
+		return fmt.Errorf("bad variable name '%s'", name)
+	}
+	return nil
+}
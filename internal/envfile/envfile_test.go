@@ -0,0 +1,100 @@
+package envfile_test
+
+import (
+	. "github.com/epinio/epinio/internal/envfile"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Environment files", func() {
+	Describe("FormatOf", func() {
+		It("detects the format from the extension", func() {
+			Expect(FormatOf("app.json")).To(Equal(FormatJSON))
+			Expect(FormatOf("app.YML")).To(Equal(FormatYAML))
+			Expect(FormatOf("app.yaml")).To(Equal(FormatYAML))
+			Expect(FormatOf(".env")).To(Equal(FormatDotenv))
+			Expect(FormatOf("production.env")).To(Equal(FormatDotenv))
+		})
+	})
+
+	Describe("dotenv", func() {
+		It("parses assignments, comments and quotes", func() {
+			env, err := Parse([]byte(`# leading comment
+PLAIN=value
+export EXPORTED=yes
+SPACED = trimmed   # comment
+HASH=a#b
+EMPTY=
+SINGLE='literal \n $HOME'
+DOUBLE="tab\tquote\" backslash\\"
+MULTI="line one
+line two"
+`), FormatDotenv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(env).To(Equal(models.EnvVariableMap{
+				"PLAIN":    "value",
+				"EXPORTED": "yes",
+				"SPACED":   "trimmed",
+				"HASH":     "a#b",
+				"EMPTY":    "",
+				"SINGLE":   `literal \n $HOME`,
+				"DOUBLE":   "tab\tquote\" backslash\\",
+				"MULTI":    "line one\nline two",
+			}))
+		})
+
+		It("rejects malformed lines", func() {
+			_, err := Parse([]byte("NOVALUE\n"), FormatDotenv)
+			Expect(err).To(MatchError(ContainSubstring("line 1")))
+
+			_, err = Parse([]byte("OPEN=\"never closed\n"), FormatDotenv)
+			Expect(err).To(MatchError(ContainSubstring("missing closing quote")))
+		})
+
+		It("round-trips values through formatting", func() {
+			env := models.EnvVariableMap{
+				"A": "simple",
+				"B": "with space",
+				"C": "multi\nline \"quoted\" \\ back",
+				"D": "",
+			}
+			data, err := Format(env, FormatDotenv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(HavePrefix("A=simple\nB=\"with space\"\n"))
+
+			parsed, err := Parse(data, FormatDotenv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed).To(Equal(env))
+		})
+	})
+
+	Describe("JSON and YAML", func() {
+		It("converts scalars to strings", func() {
+			env, err := Parse([]byte(`{"PORT": 8080, "DEBUG": true, "NAME": "app"}`), FormatJSON)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(env).To(Equal(models.EnvVariableMap{"PORT": "8080", "DEBUG": "true", "NAME": "app"}))
+
+			env, err = Parse([]byte("PORT: 8080\nTEXT: |\n  one\n  two\n"), FormatYAML)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(env).To(Equal(models.EnvVariableMap{"PORT": "8080", "TEXT": "one\ntwo\n"}))
+		})
+
+		It("rejects structured values", func() {
+			_, err := Parse([]byte(`{"LIST": [1, 2]}`), FormatJSON)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("round-trips values through formatting", func() {
+			env := models.EnvVariableMap{"A": "multi\nline", "B": "x"}
+			for _, format := range []string{FormatJSON, FormatYAML} {
+				data, err := Format(env, format)
+				Expect(err).ToNot(HaveOccurred())
+				parsed, err := Parse(data, format)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(parsed).To(Equal(env))
+			}
+		})
+	})
+})
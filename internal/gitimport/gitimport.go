@@ -0,0 +1,279 @@
+// Package gitimport fetches the sources of applications from git repositories. A
+// revision is resolved as branch, tag, or commit SHA, in that order. Branches and tags
+// are cloned shallow, commits require a full clone. Private repositories are accessed
+// with the credentials of a secret in the namespace of the application, holding either
+// a username and password (or token) for HTTPS, or a private key for SSH.
+package gitimport
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Keys of the credentials secret. These are the keys of the `kubernetes.io/basic-auth`
+// and `kubernetes.io/ssh-auth` secret types, with `known_hosts` added for SSH.
+const (
+	UsernameKey   = "username"
+	PasswordKey   = "password"
+	SSHKeyKey     = "ssh-privatekey"
+	KnownHostsKey = "known_hosts"
+)
+
+// sshUser is the user of SSH URLs without one.
+const sshUser = "git"
+
+var commitSHA = regexp.MustCompile(`^[0-9a-fA-F]{4,40}$`)
+
+// ErrNoCredentials is returned by Credentials for secrets holding neither a password nor
+// a private key.
+var ErrNoCredentials = errors.New("git credentials have to provide a password or an ssh private key")
+
+// IsCommitSHA returns true if the revision looks like a, possibly abbreviated, commit SHA.
+func IsCommitSHA(revision string) bool {
+	return commitSHA.MatchString(revision)
+}
+
+// Credentials returns the authentication for the repository, from the named secret of
+// the namespace. A missing secret is reported as kube NotFound error.
+func Credentials(ctx context.Context, cluster *kubernetes.Cluster, namespace, name, url string) (transport.AuthMethod, error) {
+	secret, err := cluster.GetSecret(ctx, namespace, name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		return nil, errors.Wrapf(err, "reading git credentials '%s'", name)
+	}
+
+	return Auth(secret.Data, url)
+}
+
+// Auth returns the authentication described by the data of a credentials secret. A
+// private key is used for SSH URLs, username and password otherwise. Without known hosts
+// the host key of the SSH server is not verified.
+func Auth(data map[string][]byte, url string) (transport.AuthMethod, error) {
+	username := string(data[UsernameKey])
+
+	if isSSH(url) {
+		key := data[SSHKeyKey]
+		if len(key) == 0 {
+			return nil, errors.New("git credentials for ssh repositories have to provide an ssh private key")
+		}
+		if username == "" {
+			username = sshUser
+		}
+		auth, err := gitssh.NewPublicKeys(username, key, string(data[PasswordKey]))
+		if err != nil {
+			return nil, errors.Wrap(err, "bad ssh private key")
+		}
+
+		auth.HostKeyCallback = ssh.InsecureIgnoreHostKey() // nolint:gosec // without known hosts only
+		if hosts := data[KnownHostsKey]; len(hosts) > 0 {
+			callback, err := hostKeyCallback(hosts)
+			if err != nil {
+				return nil, err
+			}
+			auth.HostKeyCallback = callback
+		}
+		return auth, nil
+	}
+
+	password := string(data[PasswordKey])
+	if password == "" {
+		return nil, ErrNoCredentials
+	}
+	// Token based access ignores the user, yet requires one to be present.
+	if username == "" {
+		username = sshUser
+	}
+	return &githttp.BasicAuth{Username: username, Password: password}, nil
+}
+
+// Clone clones the revision of the repository into the directory, and returns the commit
+// the revision resolved to. Without revision the default branch is cloned.
+func Clone(ctx context.Context, dir string, ref models.GitRef, auth transport.AuthMethod) (string, error) {
+	options := func() *git.CloneOptions {
+		options := &git.CloneOptions{
+			URL:  ref.URL,
+			Auth: auth,
+		}
+		if ref.Submodules {
+			options.RecurseSubmodules = git.DefaultSubmoduleRecursionDepth
+		}
+		return options
+	}
+
+	if ref.Revision == "" {
+		shallow := options()
+		shallow.SingleBranch = true
+		shallow.Depth = 1
+		return clone(ctx, dir, shallow)
+	}
+
+	for _, name := range []plumbing.ReferenceName{
+		plumbing.NewBranchReferenceName(ref.Revision),
+		plumbing.NewTagReferenceName(ref.Revision),
+	} {
+		shallow := options()
+		shallow.ReferenceName = name
+		shallow.SingleBranch = true
+		shallow.Depth = 1
+
+		commit, err := clone(ctx, dir, shallow)
+		if err == nil {
+			return commit, nil
+		}
+		if !errors.Is(err, git.NoMatchingRefSpecError{}) {
+			return "", err
+		}
+	}
+
+	if !IsCommitSHA(ref.Revision) {
+		return "", errors.Errorf("revision '%s' is neither branch, tag, nor commit", ref.Revision)
+	}
+
+	return cloneCommit(ctx, dir, ref, options())
+}
+
+// SourceDir returns the directory of the sources in the cloned repository. That is the
+// subdirectory, if specified. It is an error for the subdirectory to be missing, or
+// outside of the repository.
+func SourceDir(dir, subdirectory string) (string, error) {
+	if subdirectory == "" {
+		return dir, nil
+	}
+
+	clean := filepath.Clean(subdirectory)
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", errors.Errorf("subdirectory '%s' has to be inside the repository", subdirectory)
+	}
+
+	source := filepath.Join(dir, clean)
+	info, err := os.Stat(source)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", errors.Errorf("subdirectory '%s' not found in the repository", subdirectory)
+		}
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", errors.Errorf("subdirectory '%s' is not a directory", subdirectory)
+	}
+
+	return source, nil
+}
+
+// clone clones into the directory, and returns the commit of the checked out HEAD. The
+// directory is removed again if the clone fails.
+func clone(ctx context.Context, dir string, options *git.CloneOptions) (string, error) {
+	repo, err := git.PlainCloneContext(ctx, dir, false, options)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return "", err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", errors.Wrap(err, "resolving the cloned revision")
+	}
+
+	return head.Hash().String(), nil
+}
+
+// cloneCommit fully clones the repository and checks out the commit. Servers do not
+// generally allow fetching a commit by its SHA.
+func cloneCommit(ctx context.Context, dir string, ref models.GitRef, options *git.CloneOptions) (string, error) {
+	// Submodules are for the commit, not the default branch.
+	submodules := options.RecurseSubmodules
+	options.RecurseSubmodules = git.NoRecurseSubmodules
+	options.NoCheckout = true
+
+	repo, err := git.PlainCloneContext(ctx, dir, false, options)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return "", err
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref.Revision))
+	if err != nil {
+		return "", errors.Wrapf(err, "revision '%s' is neither branch, tag, nor commit", ref.Revision)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	err = worktree.Checkout(&git.CheckoutOptions{Hash: *hash, Force: true})
+	if err != nil {
+		return "", errors.Wrapf(err, "checking out commit %s", hash.String())
+	}
+
+	if submodules != git.NoRecurseSubmodules {
+		modules, err := worktree.Submodules()
+		if err != nil {
+			return "", errors.Wrap(err, "reading the submodules")
+		}
+		err = modules.UpdateContext(ctx, &git.SubmoduleUpdateOptions{
+			Init:              true,
+			RecurseSubmodules: submodules,
+			Auth:              options.Auth,
+		})
+		if err != nil {
+			return "", errors.Wrap(err, "updating the submodules")
+		}
+	}
+
+	return hash.String(), nil
+}
+
+// isSSH returns true for URLs accessing the repository over SSH, i.e. `ssh://` URLs and
+// the scp-like `user@host:path` form.
+func isSSH(url string) bool {
+	if strings.HasPrefix(url, "ssh://") {
+		return true
+	}
+	if strings.Contains(url, "://") {
+		return false
+	}
+	colon := strings.Index(url, ":")
+	return colon > 0 && !strings.Contains(url[:colon], "/")
+}
+
+func hostKeyCallback(hosts []byte) (ssh.HostKeyCallback, error) {
+	file, err := ioutil.TempFile("", "epinio-known-hosts")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(hosts)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	callback, err := knownhosts.New(file.Name())
+	if err != nil {
+		return nil, errors.Wrap(err, "bad known hosts")
+	}
+
+	// The callback holds the hosts in memory, the file is not needed anymore.
+	return callback, nil
+}
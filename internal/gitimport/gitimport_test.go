@@ -0,0 +1,202 @@
+package gitimport_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/epinio/epinio/internal/gitimport"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Git import", func() {
+	var tmpDir, origin, first, second string
+
+	commit := func(repo *git.Repository, file, content string) string {
+		worktree, err := repo.Worktree()
+		Expect(err).ToNot(HaveOccurred())
+
+		full := filepath.Join(origin, file)
+		Expect(os.MkdirAll(filepath.Dir(full), 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(full, []byte(content), 0600)).To(Succeed())
+		_, err = worktree.Add(file)
+		Expect(err).ToNot(HaveOccurred())
+
+		hash, err := worktree.Commit("add "+file, &git.CommitOptions{
+			Author: &object.Signature{Name: "epinio", Email: "epinio@example.com", When: time.Now()},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		return hash.String()
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "gitimport")
+		Expect(err).ToNot(HaveOccurred())
+
+		// Origin repository: `v1` tags the first commit, `main` is at the second,
+		// which adds a subdirectory.
+		origin = filepath.Join(tmpDir, "origin")
+		repo, err := git.PlainInit(origin, false)
+		Expect(err).ToNot(HaveOccurred())
+
+		first = commit(repo, "README", "first")
+		_, err = repo.CreateTag("v1", plumbing.NewHash(first), nil)
+		Expect(err).ToNot(HaveOccurred())
+		second = commit(repo, "services/api/main.go", "package main")
+
+		head, err := repo.Head()
+		Expect(err).ToNot(HaveOccurred())
+		err = repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("main"), head.Hash()))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	Describe("Clone", func() {
+		clone := func(revision string) (string, string, error) {
+			dir := filepath.Join(tmpDir, "clone")
+			commit, err := gitimport.Clone(context.Background(), dir, models.GitRef{
+				URL:      "file://" + origin,
+				Revision: revision,
+			}, nil)
+			return dir, commit, err
+		}
+
+		It("clones the default branch without revision", func() {
+			_, commit, err := clone("")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(commit).To(Equal(second))
+		})
+
+		It("resolves branches", func() {
+			_, commit, err := clone("main")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(commit).To(Equal(second))
+		})
+
+		It("resolves tags", func() {
+			dir, commit, err := clone("v1")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(commit).To(Equal(first))
+			Expect(filepath.Join(dir, "services")).ToNot(BeADirectory())
+		})
+
+		It("resolves commits", func() {
+			dir, commit, err := clone(first)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(commit).To(Equal(first))
+			Expect(filepath.Join(dir, "README")).To(BeARegularFile())
+			Expect(filepath.Join(dir, "services")).ToNot(BeADirectory())
+		})
+
+		It("resolves abbreviated commits", func() {
+			_, commit, err := clone(first[:10])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(commit).To(Equal(first))
+		})
+
+		It("fails for unknown revisions", func() {
+			_, _, err := clone("nonexistent")
+			Expect(err).To(MatchError(ContainSubstring("neither branch, tag, nor commit")))
+		})
+	})
+
+	Describe("SourceDir", func() {
+		It("returns the repository without subdirectory", func() {
+			Expect(gitimport.SourceDir(origin, "")).To(Equal(origin))
+		})
+
+		It("returns the subdirectory", func() {
+			Expect(gitimport.SourceDir(origin, "services/api/")).To(Equal(filepath.Join(origin, "services/api")))
+		})
+
+		It("rejects subdirectories outside of the repository", func() {
+			_, err := gitimport.SourceDir(origin, "../other")
+			Expect(err).To(MatchError(ContainSubstring("inside the repository")))
+		})
+
+		It("rejects missing subdirectories", func() {
+			_, err := gitimport.SourceDir(origin, "services/web")
+			Expect(err).To(MatchError(ContainSubstring("not found")))
+		})
+
+		It("rejects files", func() {
+			_, err := gitimport.SourceDir(origin, "README")
+			Expect(err).To(MatchError(ContainSubstring("not a directory")))
+		})
+	})
+
+	Describe("IsCommitSHA", func() {
+		It("accepts full and abbreviated SHAs", func() {
+			Expect(gitimport.IsCommitSHA("0a1b2c3d4e5f60718293a4b5c6d7e8f901234567")).To(BeTrue())
+			Expect(gitimport.IsCommitSHA("0a1b2c3")).To(BeTrue())
+		})
+
+		It("rejects other revisions", func() {
+			Expect(gitimport.IsCommitSHA("main")).To(BeFalse())
+			Expect(gitimport.IsCommitSHA("abc")).To(BeFalse())
+			Expect(gitimport.IsCommitSHA("v1.0.0")).To(BeFalse())
+		})
+	})
+
+	Describe("Auth", func() {
+		It("uses username and token for https repositories", func() {
+			auth, err := gitimport.Auth(map[string][]byte{
+				gitimport.PasswordKey: []byte("token"),
+			}, "https://github.com/epinio/private.git")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(auth).To(Equal(&githttp.BasicAuth{Username: "git", Password: "token"}))
+		})
+
+		It("requires a password for https repositories", func() {
+			_, err := gitimport.Auth(map[string][]byte{
+				gitimport.UsernameKey: []byte("user"),
+			}, "https://github.com/epinio/private.git")
+			Expect(err).To(MatchError(gitimport.ErrNoCredentials))
+		})
+
+		It("uses the private key for ssh repositories", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			der, err := x509.MarshalECPrivateKey(key)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, url := range []string{
+				"git@github.com:epinio/private.git",
+				"ssh://git@github.com/epinio/private.git",
+			} {
+				auth, err := gitimport.Auth(map[string][]byte{
+					gitimport.SSHKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}),
+				}, url)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(auth).To(BeAssignableToTypeOf(&gitssh.PublicKeys{}))
+				Expect(auth.(*gitssh.PublicKeys).User).To(Equal("git"))
+			}
+		})
+
+		It("requires a private key for ssh repositories", func() {
+			_, err := gitimport.Auth(map[string][]byte{
+				gitimport.PasswordKey: []byte("token"),
+			}, "git@github.com:epinio/private.git")
+			Expect(err).To(MatchError(ContainSubstring("ssh private key")))
+		})
+	})
+})
@@ -0,0 +1,223 @@
+// Package gitwebhook authenticates and decodes the push notifications git hosting
+// services deliver to webhooks. GitHub, GitLab, and Gitea are supported. GitHub and
+// Gitea sign the payload with an HMAC-SHA256 of the shared secret, GitLab sends the
+// secret itself as token.
+package gitwebhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Providers
+const (
+	GitHub = "github"
+	GitLab = "gitlab"
+	Gitea  = "gitea"
+)
+
+const (
+	// branchPrefix is the start of the refs of branches.
+	branchPrefix = "refs/heads/"
+
+	// zeroCommit is the commit of a ref after a push deleting it.
+	zeroCommit = "0000000000000000000000000000000000000000"
+)
+
+var (
+	// ErrUnknownProvider is returned for requests not identifying a supported provider.
+	ErrUnknownProvider = errors.New("unknown webhook provider, expected a push event of GitHub, GitLab, or Gitea")
+
+	// ErrSignature is returned for requests without a valid signature.
+	ErrSignature = errors.New("missing or bad webhook signature")
+)
+
+// Push is a decoded push notification.
+type Push struct {
+	Provider string
+	// Event is the name of the event. Only push events are decoded.
+	Event string
+	// Ref is the full name of the pushed ref, e.g. `refs/heads/main`.
+	Ref string
+	// Commit is the commit the ref points to after the push.
+	Commit string
+	// Repositories are the URLs of the pushed repository.
+	Repositories []string
+	// DefaultBranch is the default branch of the pushed repository, if known.
+	DefaultBranch string
+}
+
+// Deleted returns true if the push deleted the ref.
+func (p Push) Deleted() bool {
+	return p.Commit == zeroCommit
+}
+
+// Branch returns the name of the pushed branch, or the empty string if the push was not
+// to a branch.
+func (p Push) Branch() string {
+	if !strings.HasPrefix(p.Ref, branchPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(p.Ref, branchPrefix)
+}
+
+// IsPush returns true if the notification is about a push.
+func (p Push) IsPush() bool {
+	switch p.Provider {
+	case GitHub, Gitea:
+		return p.Event == "push"
+	case GitLab:
+		return p.Event == "Push Hook"
+	}
+	return false
+}
+
+// Provider returns the provider of the notification, as identified by its headers.
+func Provider(header http.Header) (string, error) {
+	// Gitea sends GitHub's headers as well, check for it first.
+	switch {
+	case header.Get("X-Gitea-Event") != "":
+		return Gitea, nil
+	case header.Get("X-GitHub-Event") != "":
+		return GitHub, nil
+	case header.Get("X-Gitlab-Event") != "":
+		return GitLab, nil
+	}
+	return "", ErrUnknownProvider
+}
+
+// Verify checks that the notification was sent by the provider holding the secret.
+func Verify(header http.Header, body []byte, secret string) error {
+	provider, err := Provider(header)
+	if err != nil {
+		return err
+	}
+
+	switch provider {
+	case GitHub:
+		signature := header.Get("X-Hub-Signature-256")
+		if !strings.HasPrefix(signature, "sha256=") {
+			return ErrSignature
+		}
+		return checkMAC(strings.TrimPrefix(signature, "sha256="), body, secret)
+	case Gitea:
+		return checkMAC(header.Get("X-Gitea-Signature"), body, secret)
+	case GitLab:
+		token := header.Get("X-Gitlab-Token")
+		if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			return ErrSignature
+		}
+		return nil
+	}
+
+	return ErrUnknownProvider
+}
+
+// Sign returns the signature of the body with the secret, as sent by GitHub and Gitea.
+func Sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Parse decodes the notification. Notifications other than pushes are returned with
+// their provider and event only.
+func Parse(header http.Header, body []byte) (Push, error) {
+	provider, err := Provider(header)
+	if err != nil {
+		return Push{}, err
+	}
+
+	push := Push{Provider: provider}
+	switch provider {
+	case GitHub:
+		push.Event = header.Get("X-GitHub-Event")
+	case Gitea:
+		push.Event = header.Get("X-Gitea-Event")
+	case GitLab:
+		push.Event = header.Get("X-Gitlab-Event")
+	}
+	if !push.IsPush() {
+		return push, nil
+	}
+
+	// The payloads of all providers have `ref` and `after`. The repository is
+	// described under different keys.
+	var payload struct {
+		Ref        string `json:"ref"`
+		After      string `json:"after"`
+		Repository struct {
+			CloneURL      string `json:"clone_url"`
+			HTMLURL       string `json:"html_url"`
+			SSHURL        string `json:"ssh_url"`
+			GitHTTPURL    string `json:"git_http_url"`
+			GitSSHURL     string `json:"git_ssh_url"`
+			Homepage      string `json:"homepage"`
+			DefaultBranch string `json:"default_branch"`
+		} `json:"repository"`
+		Project struct {
+			GitHTTPURL    string `json:"git_http_url"`
+			GitSSHURL     string `json:"git_ssh_url"`
+			WebURL        string `json:"web_url"`
+			DefaultBranch string `json:"default_branch"`
+		} `json:"project"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return push, errors.Wrap(err, "bad push payload")
+	}
+
+	push.Ref = payload.Ref
+	push.Commit = payload.After
+	push.DefaultBranch = payload.Repository.DefaultBranch
+	if push.DefaultBranch == "" {
+		push.DefaultBranch = payload.Project.DefaultBranch
+	}
+	for _, url := range []string{
+		payload.Repository.CloneURL,
+		payload.Repository.HTMLURL,
+		payload.Repository.SSHURL,
+		payload.Repository.GitHTTPURL,
+		payload.Repository.GitSSHURL,
+		payload.Repository.Homepage,
+		payload.Project.GitHTTPURL,
+		payload.Project.GitSSHURL,
+		payload.Project.WebURL,
+	} {
+		if url != "" {
+			push.Repositories = append(push.Repositories, url)
+		}
+	}
+
+	return push, nil
+}
+
+// SameRepository returns true if the URLs reference the same repository. Differences in
+// case, a trailing `.git`, and a trailing slash are ignored.
+func SameRepository(a, b string) bool {
+	return normalize(a) == normalize(b)
+}
+
+func normalize(url string) string {
+	url = strings.ToLower(strings.TrimSpace(url))
+	url = strings.TrimSuffix(url, "/")
+	url = strings.TrimSuffix(url, ".git")
+	return url
+}
+
+func checkMAC(signature string, body []byte, secret string) error {
+	if signature == "" {
+		return ErrSignature
+	}
+	expected := Sign(body, secret)
+	if !hmac.Equal([]byte(strings.ToLower(signature)), []byte(expected)) {
+		return ErrSignature
+	}
+	return nil
+}
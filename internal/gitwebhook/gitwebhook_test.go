@@ -0,0 +1,129 @@
+package gitwebhook_test
+
+import (
+	"net/http"
+
+	"github.com/epinio/epinio/internal/gitwebhook"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Git webhooks", func() {
+	secret := "s3cr3t"
+	body := []byte(`{
+  "ref": "refs/heads/main",
+  "after": "1111111111111111111111111111111111111111",
+  "repository": {
+    "clone_url": "https://github.com/epinio/sample.git",
+    "html_url": "https://github.com/epinio/sample",
+    "default_branch": "main"
+  }
+}`)
+
+	headers := func(kv ...string) http.Header {
+		header := http.Header{}
+		for i := 0; i < len(kv); i += 2 {
+			header.Set(kv[i], kv[i+1])
+		}
+		return header
+	}
+
+	Describe("Verify", func() {
+		It("accepts GitHub notifications signed with the secret", func() {
+			header := headers("X-GitHub-Event", "push",
+				"X-Hub-Signature-256", "sha256="+gitwebhook.Sign(body, secret))
+			Expect(gitwebhook.Verify(header, body, secret)).To(Succeed())
+		})
+
+		It("rejects GitHub notifications signed with another secret", func() {
+			header := headers("X-GitHub-Event", "push",
+				"X-Hub-Signature-256", "sha256="+gitwebhook.Sign(body, "other"))
+			Expect(gitwebhook.Verify(header, body, secret)).To(MatchError(gitwebhook.ErrSignature))
+		})
+
+		It("rejects unsigned GitHub notifications", func() {
+			header := headers("X-GitHub-Event", "push")
+			Expect(gitwebhook.Verify(header, body, secret)).To(MatchError(gitwebhook.ErrSignature))
+		})
+
+		It("accepts Gitea notifications signed with the secret", func() {
+			header := headers("X-Gitea-Event", "push", "X-GitHub-Event", "push",
+				"X-Gitea-Signature", gitwebhook.Sign(body, secret))
+			Expect(gitwebhook.Verify(header, body, secret)).To(Succeed())
+		})
+
+		It("rejects modified Gitea notifications", func() {
+			header := headers("X-Gitea-Event", "push",
+				"X-Gitea-Signature", gitwebhook.Sign(body, secret))
+			Expect(gitwebhook.Verify(header, append(body, ' '), secret)).To(MatchError(gitwebhook.ErrSignature))
+		})
+
+		It("accepts GitLab notifications carrying the secret", func() {
+			header := headers("X-Gitlab-Event", "Push Hook", "X-Gitlab-Token", secret)
+			Expect(gitwebhook.Verify(header, body, secret)).To(Succeed())
+		})
+
+		It("rejects GitLab notifications carrying another token", func() {
+			header := headers("X-Gitlab-Event", "Push Hook", "X-Gitlab-Token", "other")
+			Expect(gitwebhook.Verify(header, body, secret)).To(MatchError(gitwebhook.ErrSignature))
+		})
+
+		It("rejects notifications of unknown providers", func() {
+			Expect(gitwebhook.Verify(http.Header{}, body, secret)).To(MatchError(gitwebhook.ErrUnknownProvider))
+		})
+	})
+
+	Describe("Parse", func() {
+		It("decodes GitHub pushes", func() {
+			push, err := gitwebhook.Parse(headers("X-GitHub-Event", "push"), body)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(push.IsPush()).To(BeTrue())
+			Expect(push.Provider).To(Equal(gitwebhook.GitHub))
+			Expect(push.Branch()).To(Equal("main"))
+			Expect(push.DefaultBranch).To(Equal("main"))
+			Expect(push.Commit).To(Equal("1111111111111111111111111111111111111111"))
+			Expect(push.Deleted()).To(BeFalse())
+			Expect(push.Repositories).To(ConsistOf(
+				"https://github.com/epinio/sample.git",
+				"https://github.com/epinio/sample"))
+		})
+
+		It("decodes GitLab pushes", func() {
+			push, err := gitwebhook.Parse(headers("X-Gitlab-Event", "Push Hook"), []byte(`{
+  "ref": "refs/heads/release",
+  "after": "0000000000000000000000000000000000000000",
+  "project": {
+    "git_http_url": "https://gitlab.com/epinio/sample.git",
+    "default_branch": "main"
+  }
+}`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(push.Provider).To(Equal(gitwebhook.GitLab))
+			Expect(push.Branch()).To(Equal("release"))
+			Expect(push.DefaultBranch).To(Equal("main"))
+			Expect(push.Deleted()).To(BeTrue())
+			Expect(push.Repositories).To(ConsistOf("https://gitlab.com/epinio/sample.git"))
+		})
+
+		It("does not decode other events", func() {
+			push, err := gitwebhook.Parse(headers("X-GitHub-Event", "ping"), []byte(`not json`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(push.IsPush()).To(BeFalse())
+			Expect(push.Event).To(Equal("ping"))
+		})
+
+		It("reports tags as not being branches", func() {
+			push, err := gitwebhook.Parse(headers("X-GitHub-Event", "push"), []byte(`{"ref":"refs/tags/v1"}`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(push.Branch()).To(BeEmpty())
+		})
+	})
+
+	Describe("SameRepository", func() {
+		It("ignores case, trailing slash and .git suffix", func() {
+			Expect(gitwebhook.SameRepository("https://github.com/Epinio/Sample.git", "https://github.com/epinio/sample/")).To(BeTrue())
+			Expect(gitwebhook.SameRepository("https://github.com/epinio/sample", "https://github.com/epinio/other")).To(BeFalse())
+		})
+	})
+})
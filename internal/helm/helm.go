@@ -26,18 +26,20 @@ import (
 )
 
 type ChartParameters struct {
-	models.AppRef                        // Application: name & namespace
-	Context        context.Context       // Operation context
-	Cluster        *kubernetes.Cluster   // Cluster to talk to.
-	Chart          string                // Name of Chart CR to use for deployment
-	ImageURL       string                // Application Image
-	Username       string                // User causing the (re)deployment
-	Instances      int32                 // Number Of Desired Replicas
-	StageID        string                // Stage ID that produced ImageURL
-	Environment    models.EnvVariableMap // App Environment
-	Configurations []string              // Bound Configurations (list of names)
-	Routes         []string              // Desired application routes
-	Start          *int64                // Nano-epoch of deployment. Optional. Used to force a restart, even when nothing else has changed.
+	models.AppRef                         // Application: name & namespace
+	Context        context.Context        // Operation context
+	Cluster        *kubernetes.Cluster    // Cluster to talk to.
+	Chart          string                 // Name of Chart CR to use for deployment
+	ImageURL       string                 // Application Image
+	Username       string                 // User causing the (re)deployment
+	Instances      int32                  // Number Of Desired Replicas
+	StageID        string                 // Stage ID that produced ImageURL
+	Commit         string                 // Git commit ImageURL was built from, if known
+	Environment    models.EnvVariableMap  // App Environment
+	EnvFrom        models.EnvReferenceMap // App Environment sourced from configurations
+	Configurations []string               // Bound Configurations (list of names)
+	Routes         []string               // Desired application routes
+	Start          *int64                 // Nano-epoch of deployment. Optional. Used to force a restart, even when nothing else has changed.
 }
 
 func Values(cluster *kubernetes.Cluster, logger logr.Logger, app models.AppRef) ([]byte, error) {
@@ -90,10 +92,11 @@ func Deploy(logger logr.Logger, parameters ChartParameters) error {
 	}
 
 	environment := `[]`
-	if len(parameters.Environment) > 0 {
+	if len(parameters.Environment) > 0 || len(parameters.EnvFrom) > 0 {
 		// TODO: Simplify the chain of conversions. Single `AsYAML` ?
-		environment = fmt.Sprintf(`[ %s ]`, strings.Join(parameters.Environment.List().Assignments(),
-			","))
+		assignments := append(parameters.Environment.List().Assignments(),
+			parameters.EnvFrom.Assignments()...)
+		environment = fmt.Sprintf(`[ %s ]`, strings.Join(assignments, ","))
 	}
 
 	routesYaml := "~"
@@ -122,6 +125,7 @@ func Deploy(logger logr.Logger, parameters ChartParameters) error {
 	yamlParameters := fmt.Sprintf(`
 epinio:
   appName: "%[9]s"
+  commit: "%[12]s"
   env: %[6]s
   imageURL: "%[3]s"
   ingress: %[10]s
@@ -143,6 +147,7 @@ epinio:
 		parameters.Name,
 		ingress,
 		viper.GetString("tls-issuer"),
+		parameters.Commit,
 	)
 
 	logger.Info("app helm setup", "parameters", yamlParameters)
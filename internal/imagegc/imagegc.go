@@ -0,0 +1,343 @@
+// Package imagegc removes old application images from the Epinio registry. Every staging
+// pushes a new image, tagged with its stage ID, and nothing else deletes them. Per
+// application the images of the last stagings are kept, as are the images of the last
+// releases, deployed or available for a rollback. The number of images kept is set for
+// the server, and can be overridden per namespace, by annotation. Without such a number
+// all images are kept.
+//
+// Images are removed through the registry HTTP API. The registry has to allow deletions,
+// and frees the space of the removed layers with its own garbage collection.
+package imagegc
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/helm"
+	"github.com/epinio/epinio/internal/names"
+	"github.com/epinio/epinio/internal/namespaces"
+	"github.com/epinio/epinio/internal/registry"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/go-logr/logr"
+	hc "github.com/mittwald/go-helm-client"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// DefaultInterval is the time between collections, if the server does not specify
+	// one.
+	DefaultInterval = time.Hour
+
+	// RetentionAnnotation of a namespace is the number of images kept per application
+	// of the namespace. It overrides the number set for the server. 0 keeps all images.
+	RetentionAnnotation = "epinio.io/image-retention"
+)
+
+// Retention returns the number of images kept per application of the namespace. A bad
+// annotation is reported, and the number of the server returned.
+func Retention(namespace namespaces.Namespace, global int) (int, error) {
+	value, ok := namespace.Annotations[RetentionAnnotation]
+	if !ok {
+		return global, nil
+	}
+
+	keep, err := strconv.Atoi(value)
+	if err != nil || keep < 0 {
+		return global, errors.Errorf("bad image retention '%s' of namespace %s", value, namespace.Name)
+	}
+	return keep, nil
+}
+
+// Select returns the tags of the images of an application to remove. The history lists
+// the stage IDs of the application's last stagings, oldest first. The images of the
+// last keep stagings with an image are kept, as are the pinned ones. Images of stagings
+// not in the history predate it, and are removed only when keep images are found in the
+// history. Without keep no image is removed.
+func Select(tags, history []string, keep int, pinned map[string]struct{}) []string {
+	removed := []string{}
+	if keep <= 0 {
+		return removed
+	}
+
+	exists := map[string]struct{}{}
+	for _, tag := range tags {
+		exists[tag] = struct{}{}
+	}
+
+	tracked := map[string]struct{}{}
+	for _, stageID := range history {
+		tracked[stageID] = struct{}{}
+	}
+
+	kept := map[string]struct{}{}
+	for i := len(history) - 1; i >= 0 && len(kept) < keep; i-- {
+		if _, ok := exists[history[i]]; ok {
+			kept[history[i]] = struct{}{}
+		}
+	}
+
+	for _, tag := range tags {
+		if _, ok := pinned[tag]; ok {
+			continue
+		}
+		if _, ok := kept[tag]; ok {
+			continue
+		}
+		if _, ok := tracked[tag]; !ok && len(kept) < keep {
+			continue
+		}
+		removed = append(removed, tag)
+	}
+
+	return removed
+}
+
+// candidate is an image selected for removal.
+type candidate struct {
+	image    models.RemovedImage
+	manifest registry.Manifest
+}
+
+// Collect finds the images to remove in the namespaces with a retention and, unless
+// dryRun, deletes them. The retention is the number of images kept per application, for
+// namespaces not setting their own.
+func Collect(ctx context.Context, cluster *kubernetes.Cluster, logger logr.Logger, retention int, dryRun bool) (models.ImageGCReport, error) {
+	report := models.ImageGCReport{
+		DryRun:     dryRun,
+		Retention:  retention,
+		Retentions: map[string]int{},
+	}
+
+	client, registryNamespace, err := registry.NewEpinioClient(ctx, cluster)
+	if err != nil {
+		return report, err
+	}
+
+	appClient, err := cluster.ClientApp()
+	if err != nil {
+		return report, err
+	}
+
+	epinioNamespaces, err := namespaces.List(ctx, cluster)
+	if err != nil {
+		return report, errors.Wrap(err, "listing namespaces")
+	}
+
+	candidates := []candidate{}
+	keptBlobs := map[string]struct{}{}
+
+	for _, namespace := range epinioNamespaces {
+		keep, err := Retention(namespace, retention)
+		if err != nil {
+			logger.Info("using the retention of the server", "error", err.Error())
+		}
+		if keep <= 0 {
+			continue
+		}
+		report.Retentions[namespace.Name] = keep
+
+		apps, err := appClient.Namespace(namespace.Name).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return report, errors.Wrapf(err, "listing the applications of namespace %s", namespace.Name)
+		}
+		helmClient, err := helm.GetHelmClient(cluster.RestConfig, logger, namespace.Name)
+		if err != nil {
+			return report, errors.Wrap(err, "create a helm client")
+		}
+
+		for i := range apps.Items {
+			app := &apps.Items[i]
+			repository := registry.Repository(registryNamespace, app.GetNamespace(), app.GetName())
+
+			tags, err := client.Tags(ctx, repository)
+			if err != nil {
+				return report, err
+			}
+			if len(tags) == 0 {
+				continue
+			}
+			report.Images += len(tags)
+
+			pinned, err := pinnedTags(helmClient, app, repository, keep)
+			if err != nil {
+				return report, errors.Wrapf(err, "reading the releases of application %s/%s", app.GetNamespace(), app.GetName())
+			}
+			removed := map[string]struct{}{}
+			for _, tag := range Select(tags, application.StageHistory(app), keep, pinned) {
+				removed[tag] = struct{}{}
+			}
+
+			// Images are deleted by digest, with all their tags. An image also
+			// tagged for a kept stage is kept.
+			keptDigests := map[string]struct{}{}
+			manifests := map[string]registry.Manifest{}
+			for _, tag := range tags {
+				manifest, err := client.Manifest(ctx, repository, tag)
+				if err != nil {
+					return report, err
+				}
+				manifests[tag] = manifest
+
+				if _, ok := removed[tag]; !ok {
+					keptDigests[manifest.Digest] = struct{}{}
+					for digest := range manifest.Blobs {
+						keptBlobs[digest] = struct{}{}
+					}
+				}
+			}
+
+			kept := len(tags)
+			for _, tag := range tags {
+				manifest := manifests[tag]
+				if _, ok := removed[tag]; !ok {
+					continue
+				}
+				if _, ok := keptDigests[manifest.Digest]; ok {
+					continue
+				}
+
+				kept--
+				candidates = append(candidates, candidate{
+					image: models.RemovedImage{
+						Namespace: app.GetNamespace(),
+						App:       app.GetName(),
+						Tag:       tag,
+						Digest:    manifest.Digest,
+						Size:      manifest.Size(),
+					},
+					manifest: manifest,
+				})
+			}
+			report.Kept += kept
+		}
+	}
+
+	// Layers are shared between images, and only those of no kept image are freed.
+	reclaimed := map[string]int64{}
+	for _, c := range candidates {
+		for digest, size := range c.manifest.Blobs {
+			if _, ok := keptBlobs[digest]; !ok {
+				reclaimed[digest] = size
+			}
+		}
+	}
+	for _, size := range reclaimed {
+		report.Reclaimed += size
+	}
+
+	for _, c := range candidates {
+		image := c.image
+		if !dryRun {
+			repository := registry.Repository(registryNamespace, image.Namespace, image.App)
+			if err := client.DeleteManifest(ctx, repository, image.Digest); err != nil {
+				return report, errors.Wrapf(err, "deleting image %s:%s", repository, image.Tag)
+			}
+			image.Deleted = true
+
+			logger.Info("deleted image", "app", image.App, "namespace", image.Namespace, "tag", image.Tag, "size", image.Size)
+		}
+
+		report.Removed = append(report.Removed, image)
+	}
+
+	return report, nil
+}
+
+// Start runs the collection of old images in the background, every interval, until the
+// context is done. Without an interval no collection is run. The retention is the number
+// of images kept per application, for namespaces not setting their own.
+func Start(ctx context.Context, logger logr.Logger, interval time.Duration, retention int) {
+	logger = logger.WithName("ImageGC")
+	if interval <= 0 {
+		logger.Info("disabled")
+		return
+	}
+
+	go func() {
+		logger.Info("start", "interval", interval.String(), "retention", retention)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Info("stop")
+				return
+			case <-ticker.C:
+			}
+
+			cluster, err := kubernetes.GetCluster(ctx)
+			if err != nil {
+				logger.Info("collection failed", "error", err.Error())
+				continue
+			}
+			report, err := Collect(ctx, cluster, logger, retention, false)
+			if err != nil {
+				logger.Info("collection failed", "error", err.Error())
+				continue
+			}
+			logger.Info("collected", "images", report.Images, "deleted", len(report.Removed), "reclaimed", report.Reclaimed)
+		}
+	}()
+}
+
+// pinnedTags returns the tags of the images of the application which are deployed, or
+// used by one of the last keep releases of the application.
+func pinnedTags(helmClient hc.Client, app *unstructured.Unstructured, repository string, keep int) (map[string]struct{}, error) {
+	pinned := map[string]struct{}{}
+
+	imageURL, err := application.ImageURL(app)
+	if err != nil {
+		return nil, err
+	}
+	if tag := imageTag(imageURL, repository); tag != "" {
+		pinned[tag] = struct{}{}
+	}
+
+	releases, err := helmClient.ListReleaseHistory(names.ReleaseName(app.GetName()), keep)
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return pinned, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].Version > releases[j].Version
+	})
+	if len(releases) > keep {
+		releases = releases[:keep]
+	}
+
+	for _, release := range releases {
+		imageURL, _, _ := unstructured.NestedString(release.Config, "epinio", "imageURL")
+		if tag := imageTag(imageURL, repository); tag != "" {
+			pinned[tag] = struct{}{}
+		}
+	}
+
+	return pinned, nil
+}
+
+// imageTag returns the tag of the image, if it is an image of the repository. The
+// registry part of the image is ignored, it differs for images pulled through the
+// internal URL of the registry.
+func imageTag(imageURL, repository string) string {
+	i := strings.LastIndex(imageURL, ":")
+	if i < 0 || strings.Contains(imageURL[i+1:], "/") {
+		return ""
+	}
+	if !strings.HasSuffix(imageURL[:i], "/"+repository) {
+		return ""
+	}
+	return imageURL[i+1:]
+}
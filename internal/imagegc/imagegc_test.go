@@ -0,0 +1,65 @@
+package imagegc_test
+
+import (
+	"github.com/epinio/epinio/internal/imagegc"
+	"github.com/epinio/epinio/internal/namespaces"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Select", func() {
+	none := map[string]struct{}{}
+
+	It("keeps the images of the last stagings", func() {
+		tags := []string{"d4", "a1", "c3", "b2"}
+		history := []string{"a1", "b2", "c3", "d4"}
+		Expect(imagegc.Select(tags, history, 2, none)).To(ConsistOf("a1", "b2"))
+	})
+
+	It("keeps pinned images", func() {
+		tags := []string{"d4", "a1", "c3", "b2"}
+		history := []string{"a1", "b2", "c3", "d4"}
+		pinned := map[string]struct{}{"a1": {}}
+		Expect(imagegc.Select(tags, history, 2, pinned)).To(ConsistOf("b2"))
+	})
+
+	It("does not count stagings without image", func() {
+		tags := []string{"a1", "b2", "c3"}
+		history := []string{"a1", "b2", "c3", "failed"}
+		Expect(imagegc.Select(tags, history, 2, none)).To(ConsistOf("a1"))
+	})
+
+	It("removes images older than the history only when enough are kept", func() {
+		tags := []string{"old", "a1", "b2"}
+		Expect(imagegc.Select(tags, []string{"a1", "b2"}, 3, none)).To(BeEmpty())
+		Expect(imagegc.Select(tags, []string{"a1", "b2"}, 2, none)).To(ConsistOf("old"))
+	})
+
+	It("keeps all images without retention", func() {
+		tags := []string{"a1", "b2", "c3"}
+		history := []string{"a1", "b2", "c3"}
+		Expect(imagegc.Select(tags, history, 0, none)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Retention", func() {
+	namespace := func(annotations map[string]string) namespaces.Namespace {
+		return namespaces.Namespace{Name: "workspace", Annotations: annotations}
+	}
+
+	It("uses the retention of the server by default", func() {
+		Expect(imagegc.Retention(namespace(nil), 5)).To(Equal(5))
+	})
+
+	It("uses the retention of the namespace", func() {
+		Expect(imagegc.Retention(namespace(map[string]string{imagegc.RetentionAnnotation: "2"}), 5)).To(Equal(2))
+		Expect(imagegc.Retention(namespace(map[string]string{imagegc.RetentionAnnotation: "0"}), 5)).To(Equal(0))
+	})
+
+	It("reports bad retentions of the namespace", func() {
+		keep, err := imagegc.Retention(namespace(map[string]string{imagegc.RetentionAnnotation: "many"}), 5)
+		Expect(err).To(HaveOccurred())
+		Expect(keep).To(Equal(5))
+	})
+})
@@ -223,6 +223,11 @@ func UpdateICE(manifest models.ApplicationManifest, cmd *cobra.Command) (models.
 		return manifest, err
 	}
 
+	manifest, err = UpdateStagingEnvironment(manifest, cmd)
+	if err != nil {
+		return manifest, err
+	}
+
 	return manifest, nil
 }
 
@@ -264,27 +269,54 @@ func UpdateConfigurations(manifest models.ApplicationManifest, cmd *cobra.Comman
 
 // UpdateEnvironment updates the incoming manifest with information pulled from the --env option
 func UpdateEnvironment(manifest models.ApplicationManifest, cmd *cobra.Command) (models.ApplicationManifest, error) {
-	evAssignments, err := cmd.Flags().GetStringSlice("env")
+	environment, err := assignments(cmd, "env")
+	if err != nil {
+		return manifest, err
+	}
+
+	// E:nvironment - Replace
+
+	if len(environment) > 0 {
+		manifest.Configuration.Environment = environment
+	}
+
+	return manifest, nil
+}
+
+// UpdateStagingEnvironment updates the incoming manifest with information pulled from the
+// --staging-env option
+func UpdateStagingEnvironment(manifest models.ApplicationManifest, cmd *cobra.Command) (models.ApplicationManifest, error) {
+	environment, err := assignments(cmd, "staging-env")
 	if err != nil {
-		return manifest, errors.Wrap(err, "failed to read option --env")
+		return manifest, err
+	}
+
+	// Staging environment - Replace
+
+	if len(environment) > 0 {
+		manifest.Staging.Environment = environment
+	}
+
+	return manifest, nil
+}
+
+// assignments returns the `name=value` assignments of the named option as map.
+func assignments(cmd *cobra.Command, option string) (models.EnvVariableMap, error) {
+	evAssignments, err := cmd.Flags().GetStringSlice(option)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read option --"+option)
 	}
 
 	environment := models.EnvVariableMap{}
 	for _, assignment := range evAssignments {
 		pieces := strings.SplitN(assignment, "=", 2)
 		if len(pieces) < 2 {
-			return manifest, errors.New("Bad --env assignment `" + assignment + "`, expected `name=value` as value")
+			return nil, errors.New("Bad --" + option + " assignment `" + assignment + "`, expected `name=value` as value")
 		}
 		environment[pieces[0]] = pieces[1]
 	}
 
-	// E:nvironment - Replace
-
-	if len(environment) > 0 {
-		manifest.Configuration.Environment = environment
-	}
-
-	return manifest, nil
+	return environment, nil
 }
 
 // Get reads the manifest at the spcified path into
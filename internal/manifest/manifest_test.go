@@ -86,6 +86,8 @@ d: e`), 0600)
 				err := ioutil.WriteFile("goodyaml.yml", []byte(`name: foo
 staging:
   builder: snafu
+  environment:
+    BP_NODE_VERSION: "18"
 origin:
   git:
     revision: off
@@ -136,10 +138,103 @@ configuration:
 					},
 					Staging: models.ApplicationStage{
 						Builder: "snafu",
+						Environment: models.EnvVariableMap{
+							"BP_NODE_VERSION": "18",
+						},
 					},
 				}))
 
 			})
 		})
+
+		When("the desired manifest file selects a dockerfile build", func() {
+			BeforeEach(func() {
+				err := ioutil.WriteFile("dockeryaml.yml", []byte(`name: foo
+staging:
+  strategy: dockerfile
+  dockerfile:
+    path: build/Dockerfile
+    target: runtime
+    buildArgs:
+      VERSION: "1.2"
+`), 0600)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				err := os.Remove("dockeryaml.yml")
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("carries the dockerfile settings", func() {
+				m, err := manifest.Get("dockeryaml.yml")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(m.Staging).To(Equal(models.ApplicationStage{
+					Strategy: models.StagingStrategyDockerfile,
+					Dockerfile: &models.DockerfileBuild{
+						Path:   "build/Dockerfile",
+						Target: "runtime",
+						BuildArgs: map[string]string{
+							"VERSION": "1.2",
+						},
+					},
+				}))
+			})
+		})
+
+		When("the desired manifest file clears the staging environment", func() {
+			BeforeEach(func() {
+				err := ioutil.WriteFile("clearyaml.yml", []byte(`name: foo
+staging:
+  environment: {}
+`), 0600)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				err := os.Remove("clearyaml.yml")
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("carries an empty, not a missing, staging environment", func() {
+				m, err := manifest.Get("clearyaml.yml")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(m.Staging.Environment).ToNot(BeNil())
+				Expect(m.Staging.Environment).To(BeEmpty())
+			})
+		})
+
+		When("the desired manifest file imports from a private monorepo", func() {
+			BeforeEach(func() {
+				err := ioutil.WriteFile("gityaml.yml", []byte(`name: foo
+origin:
+  git:
+    url: git@example.com:org/mono.git
+    revision: v1.2.0
+    credentials: deploy-key
+    subdirectory: services/api
+    submodules: true
+`), 0600)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				err := os.Remove("gityaml.yml")
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("carries the git import settings", func() {
+				m, err := manifest.Get("gityaml.yml")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(m.Origin.Kind).To(Equal(models.OriginGit))
+				Expect(m.Origin.Git).To(Equal(&models.GitRef{
+					URL:          "git@example.com:org/mono.git",
+					Revision:     "v1.2.0",
+					Credentials:  "deploy-key",
+					Subdirectory: "services/api",
+					Submodules:   true,
+				}))
+			})
+		})
 	})
 })
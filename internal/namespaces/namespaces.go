@@ -8,6 +8,7 @@ import (
 
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/internal/duration"
+	"github.com/epinio/epinio/internal/registry"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -16,8 +17,9 @@ import (
 
 // Namespace represents an epinio-controlled namespace in the system
 type Namespace struct {
-	Name      string
-	CreatedAt metav1.Time
+	Name        string
+	CreatedAt   metav1.Time
+	Annotations map[string]string
 }
 
 func (n Namespace) Namespace() string {
@@ -37,8 +39,9 @@ func List(ctx context.Context, kubeClient *kubernetes.Cluster) ([]Namespace, err
 	result := []Namespace{}
 	for _, namespace := range namespaceList.Items {
 		result = append(result, Namespace{
-			Name:      namespace.ObjectMeta.Name,
-			CreatedAt: namespace.ObjectMeta.CreationTimestamp,
+			Name:        namespace.ObjectMeta.Name,
+			CreatedAt:   namespace.ObjectMeta.CreationTimestamp,
+			Annotations: namespace.ObjectMeta.Annotations,
 		})
 	}
 
@@ -125,7 +128,8 @@ func Delete(ctx context.Context, kubeClient *kubernetes.Cluster, namespace strin
 
 // createServiceAccount is a helper to `Create` which creates the
 // service account applications pushed to the namespace need for
-// permission handling.
+// permission handling. The registry credentials of the namespace
+// extend its image pull secrets, see AddRegistryCredential.
 func createServiceAccount(ctx context.Context, kubeClient *kubernetes.Cluster, targetNamespace string) error {
 	automountServiceAccountToken := true
 	_, err := kubeClient.Kubectl.CoreV1().ServiceAccounts(targetNamespace).Create(
@@ -135,7 +139,7 @@ func createServiceAccount(ctx context.Context, kubeClient *kubernetes.Cluster, t
 				Name: targetNamespace,
 			},
 			ImagePullSecrets: []corev1.LocalObjectReference{
-				{Name: "registry-creds"},
+				{Name: registry.CredentialsSecretName},
 			},
 			AutomountServiceAccountToken: &automountServiceAccountToken,
 		}, metav1.CreateOptions{})
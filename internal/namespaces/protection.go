@@ -0,0 +1,50 @@
+package namespaces
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ProtectedAnnotation marks namespaces, applications and services as protected. Protected
+// resources cannot be deleted until the protection is cleared.
+const ProtectedAnnotation = "epinio.io/protected"
+
+// Protected returns true if the namespace is protected.
+func (n Namespace) Protected() bool {
+	return IsProtected(n.Annotations)
+}
+
+// IsProtected returns true if the annotations of a resource mark it as protected.
+func IsProtected(annotations map[string]string) bool {
+	return annotations[ProtectedAnnotation] == "true"
+}
+
+// ProtectionPatch returns the merge patch setting or clearing the protection of a
+// resource.
+func ProtectionPatch(protected bool) ([]byte, error) {
+	var value interface{} // nil removes the annotation
+	if protected {
+		value = "true"
+	}
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				ProtectedAnnotation: value,
+			},
+		},
+	})
+}
+
+// SetProtected sets or clears the protection of the namespace.
+func SetProtected(ctx context.Context, kubeClient *kubernetes.Cluster, namespace string, protected bool) error {
+	patch, err := ProtectionPatch(protected)
+	if err != nil {
+		return err
+	}
+	_, err = kubeClient.Kubectl.CoreV1().Namespaces().Patch(ctx, namespace, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
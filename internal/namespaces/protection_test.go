@@ -0,0 +1,28 @@
+package namespaces_test
+
+import (
+	"github.com/epinio/epinio/internal/namespaces"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Protection", func() {
+	It("recognizes protected resources", func() {
+		Expect(namespaces.Namespace{Annotations: map[string]string{namespaces.ProtectedAnnotation: "true"}}.Protected()).To(BeTrue())
+		Expect(namespaces.Namespace{Annotations: map[string]string{namespaces.ProtectedAnnotation: "false"}}.Protected()).To(BeFalse())
+		Expect(namespaces.Namespace{}.Protected()).To(BeFalse())
+	})
+
+	It("sets the protection", func() {
+		patch, err := namespaces.ProtectionPatch(true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(patch)).To(Equal(`{"metadata":{"annotations":{"epinio.io/protected":"true"}}}`))
+	})
+
+	It("clears the protection", func() {
+		patch, err := namespaces.ProtectionPatch(false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(patch)).To(Equal(`{"metadata":{"annotations":{"epinio.io/protected":null}}}`))
+	})
+})
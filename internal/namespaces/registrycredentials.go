@@ -0,0 +1,165 @@
+package namespaces
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/registry"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// RegistryCredentialLabel marks the image pull secrets of the registry credentials
+	// of a namespace. The value is the name of the credential.
+	RegistryCredentialLabel = "epinio.io/registry-credential"
+
+	registryCredentialPrefix = "registry-credential-"
+)
+
+// ValidateRegistryCredential checks the name and settings of a registry credential to
+// add to a namespace.
+func ValidateRegistryCredential(req models.RegistryCredentialCreateRequest) error {
+	if errorMsgs := validation.IsDNS1123Label(req.Name); len(errorMsgs) > 0 {
+		return errors.Errorf("registry credential name '%s' is invalid: %v", req.Name, errorMsgs)
+	}
+	if req.URL == "" {
+		return errors.New("registry url must be specified")
+	}
+	if err := registry.Validate(req.URL, "", req.Username, req.Password); err != nil {
+		return err
+	}
+	if req.Username == "" || req.Password == "" {
+		return errors.New("registry username and password must be specified")
+	}
+	return nil
+}
+
+// RegistryCredentials returns the registry credentials of the namespace, sorted by name.
+func RegistryCredentials(ctx context.Context, kubeClient *kubernetes.Cluster, namespace string) (models.RegistryCredentialList, error) {
+	secrets, err := kubeClient.Kubectl.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: RegistryCredentialLabel,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := models.RegistryCredentialList{}
+	for _, secret := range secrets.Items {
+		var config registry.DockerConfigJSON
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+			return nil, errors.Wrapf(err, "reading registry credential %s", secret.Labels[RegistryCredentialLabel])
+		}
+		for url, auth := range config.Auths {
+			result = append(result, models.RegistryCredential{
+				Name:     secret.Labels[RegistryCredentialLabel],
+				URL:      url,
+				Username: auth.Username,
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// AddRegistryCredential stores the registry credential in an image pull secret of the
+// namespace, and adds the secret to the service account of the applications. Their
+// images are then pulled with the credential.
+func AddRegistryCredential(ctx context.Context, kubeClient *kubernetes.Cluster, namespace string, req models.RegistryCredentialCreateRequest) error {
+	details := registry.ConnectionDetails{
+		RegistryCredentials: []registry.RegistryCredentials{
+			{URL: req.URL, Username: req.Username, Password: req.Password},
+		},
+	}
+	config, err := details.DockerConfigJSON()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	secretName := registryCredentialPrefix + req.Name
+	_, err = kubeClient.Kubectl.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: secretName,
+			Labels: map[string]string{
+				RegistryCredentialLabel: req.Name,
+			},
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: data,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+
+	return updatePullSecrets(ctx, kubeClient, namespace, func(secrets []corev1.LocalObjectReference) []corev1.LocalObjectReference {
+		for _, secret := range secrets {
+			if secret.Name == secretName {
+				return secrets
+			}
+		}
+		return append(secrets, corev1.LocalObjectReference{Name: secretName})
+	})
+}
+
+// RemoveRegistryCredential removes the registry credential from the service account of
+// the applications of the namespace, and deletes its image pull secret.
+func RemoveRegistryCredential(ctx context.Context, kubeClient *kubernetes.Cluster, namespace, name string) error {
+	secretName := registryCredentialPrefix + name
+	secret, err := kubeClient.Kubectl.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if _, ok := secret.Labels[RegistryCredentialLabel]; !ok {
+		return apierrors.NewNotFound(corev1.Resource("secrets"), secretName)
+	}
+
+	err = updatePullSecrets(ctx, kubeClient, namespace, func(secrets []corev1.LocalObjectReference) []corev1.LocalObjectReference {
+		kept := []corev1.LocalObjectReference{}
+		for _, secret := range secrets {
+			if secret.Name != secretName {
+				kept = append(kept, secret)
+			}
+		}
+		return kept
+	})
+	if err != nil {
+		return err
+	}
+
+	return kubeClient.Kubectl.CoreV1().Secrets(namespace).Delete(ctx, secretName, metav1.DeleteOptions{})
+}
+
+// updatePullSecrets replaces the image pull secrets of the service account of the
+// applications of the namespace, see createServiceAccount.
+func updatePullSecrets(ctx context.Context, kubeClient *kubernetes.Cluster, namespace string, update func([]corev1.LocalObjectReference) []corev1.LocalObjectReference) error {
+	client := kubeClient.Kubectl.CoreV1().ServiceAccounts(namespace)
+
+	return errors.Wrap(retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		account, err := client.Get(ctx, namespace, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		account.ImagePullSecrets = update(account.ImagePullSecrets)
+
+		_, err = client.Update(ctx, account, metav1.UpdateOptions{})
+		return err
+	}), "updating the image pull secrets of the application service account")
+}
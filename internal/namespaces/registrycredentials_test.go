@@ -0,0 +1,41 @@
+package namespaces_test
+
+import (
+	"github.com/epinio/epinio/internal/namespaces"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateRegistryCredential", func() {
+	var request models.RegistryCredentialCreateRequest
+
+	BeforeEach(func() {
+		request = models.RegistryCredentialCreateRequest{
+			Name:     "private",
+			URL:      "private.example.com",
+			Username: "team",
+			Password: "secret",
+		}
+	})
+
+	It("accepts a complete credential", func() {
+		Expect(namespaces.ValidateRegistryCredential(request)).To(Succeed())
+	})
+
+	It("rejects bad names", func() {
+		request.Name = "Private_Registry"
+		Expect(namespaces.ValidateRegistryCredential(request)).To(MatchError(ContainSubstring("name 'Private_Registry' is invalid")))
+	})
+
+	It("rejects credentials without url", func() {
+		request.URL = ""
+		Expect(namespaces.ValidateRegistryCredential(request)).To(MatchError("registry url must be specified"))
+	})
+
+	It("rejects credentials without username or password", func() {
+		request.Password = ""
+		Expect(namespaces.ValidateRegistryCredential(request)).To(MatchError("registry username and password must be specified"))
+	})
+})
@@ -0,0 +1,223 @@
+// Package operations implements the API server's pool of workers for long-running
+// operations, like the import of application sources from git. Submitting an operation
+// returns its ID right away, the operation itself is run by the next free worker. The
+// state of an operation is polled by its ID.
+//
+// Operations are kept in memory. Finished operations are kept for the retention time.
+// Operations do not survive restarts of the API server. They are also not shared between
+// replicas of the API server: the pool only works with a single replica, with more a
+// poll of an operation may reach a replica which does not know it, and fail with a 404.
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultWorkers is the number of workers, if the server does not specify one.
+	DefaultWorkers = 4
+
+	// DefaultRetention is the time finished operations are kept, if the server does
+	// not specify one.
+	DefaultRetention = time.Hour
+
+	// queueSize is the number of submitted operations waiting for a worker. Beyond
+	// it submissions are rejected.
+	queueSize = 100
+
+	// interval is the time between the checks for expired operations.
+	interval = time.Minute
+)
+
+// ErrBusy is returned by Submit when too many operations wait for a worker.
+var ErrBusy = errors.New("too many pending operations, try again later")
+
+// Task is the work of an operation. It reports the step it is at, and returns the result
+// of the operation. The result is kept in its JSON encoding.
+type Task func(ctx context.Context, step func(string)) (interface{}, error)
+
+type job struct {
+	id   string
+	task Task
+}
+
+// Pool runs the submitted operations on its workers.
+type Pool struct {
+	mu         sync.Mutex
+	operations map[string]*models.Operation
+	queue      chan job
+	workers    int
+	retention  time.Duration
+}
+
+// pool is the memo of the pool run by the API server. See Start.
+var pool *Pool
+
+// NewPool returns a pool with the number of workers, keeping finished operations for the
+// retention time.
+func NewPool(workers int, retention time.Duration) *Pool {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &Pool{
+		operations: map[string]*models.Operation{},
+		queue:      make(chan job, queueSize),
+		workers:    workers,
+		retention:  retention,
+	}
+}
+
+// Start creates the pool for the API server, and runs it in the background until the
+// context is done.
+func Start(ctx context.Context, logger logr.Logger, workers int, retention time.Duration) {
+	pool = NewPool(workers, retention)
+	go pool.Run(ctx, logger)
+}
+
+// Running returns the pool started by Start, or nil if there is none.
+func Running() *Pool {
+	return pool
+}
+
+// Submit queues the task as operation of the kind, for the application. It returns the
+// pending operation.
+func (p *Pool) Submit(kind string, app models.AppRef, task Task) (models.Operation, error) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	operation := &models.Operation{
+		ID:        uuid.New().String(),
+		Kind:      kind,
+		Namespace: app.Namespace,
+		App:       app.Name,
+		State:     models.OperationPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	select {
+	case p.queue <- job{id: operation.ID, task: task}:
+	default:
+		return models.Operation{}, ErrBusy
+	}
+
+	p.operations[operation.ID] = operation
+	return *operation, nil
+}
+
+// Get returns the identified operation. The boolean result is false for unknown and
+// expired operations.
+func (p *Pool) Get(id string) (models.Operation, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	operation, ok := p.operations[id]
+	if !ok {
+		return models.Operation{}, false
+	}
+	return *operation, true
+}
+
+// Run runs the workers and the expiry of finished operations, until the context is done.
+func (p *Pool) Run(ctx context.Context, logger logr.Logger) {
+	logger = logger.WithName("Operations")
+	logger.Info("start", "workers", p.workers, "retention", p.retention.String())
+
+	for i := 0; i < p.workers; i++ {
+		go p.work(requestctx.WithLogger(ctx, logger.WithValues("worker", i)))
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("stop")
+			return
+		case now := <-ticker.C:
+			p.expire(now)
+		}
+	}
+}
+
+// work runs queued operations, one at a time, until the context is done.
+func (p *Pool) work(ctx context.Context) {
+	log := requestctx.Logger(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case next := <-p.queue:
+			p.update(next.id, func(operation *models.Operation) {
+				operation.State = models.OperationRunning
+			})
+
+			result, err := next.task(ctx, func(step string) {
+				p.update(next.id, func(operation *models.Operation) {
+					operation.Step = step
+				})
+			})
+
+			var encoded []byte
+			if err == nil {
+				encoded, err = json.Marshal(result)
+				err = errors.Wrap(err, "encoding the result")
+			}
+
+			p.update(next.id, func(operation *models.Operation) {
+				if err != nil {
+					log.Info("operation failed", "id", next.id, "kind", operation.Kind, "error", err.Error())
+					operation.State = models.OperationFailed
+					operation.Error = err.Error()
+					return
+				}
+				operation.State = models.OperationSucceeded
+				operation.Result = encoded
+			})
+		}
+	}
+}
+
+// update changes the identified operation, and records the time of the change.
+func (p *Pool) update(id string, change func(*models.Operation)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	operation, ok := p.operations[id]
+	if !ok {
+		return
+	}
+	change(operation)
+	operation.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+}
+
+// expire removes the operations which finished longer than the retention time before now.
+func (p *Pool) expire(now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for id, operation := range p.operations {
+		if !operation.Finished() {
+			continue
+		}
+		updated, err := time.Parse(time.RFC3339, operation.UpdatedAt)
+		if err != nil || now.Sub(updated) > p.retention {
+			delete(p.operations, id)
+		}
+	}
+}
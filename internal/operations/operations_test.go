@@ -0,0 +1,123 @@
+package operations_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/epinio/epinio/internal/operations"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pool", func() {
+	app := models.NewAppRef("sample", "workspace")
+
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		pool   *operations.Pool
+	)
+
+	state := func(id string) func() string {
+		return func() string {
+			operation, ok := pool.Get(id)
+			Expect(ok).To(BeTrue())
+			return operation.State
+		}
+	}
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		pool = operations.NewPool(1, time.Hour)
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	It("runs submitted operations and keeps their result", func() {
+		go pool.Run(ctx, logr.Discard())
+
+		operation, err := pool.Submit(models.OperationImportGit, app,
+			func(ctx context.Context, step func(string)) (interface{}, error) {
+				step("cloning")
+				return &models.ImportGitResponse{BlobUID: "blob", Commit: "abc"}, nil
+			})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(operation.ID).ToNot(BeEmpty())
+		Expect(operation.Kind).To(Equal(models.OperationImportGit))
+		Expect(operation.Namespace).To(Equal("workspace"))
+		Expect(operation.App).To(Equal("sample"))
+
+		Eventually(state(operation.ID)).Should(Equal(models.OperationSucceeded))
+
+		operation, _ = pool.Get(operation.ID)
+		Expect(operation.Finished()).To(BeTrue())
+		Expect(operation.Step).To(Equal("cloning"))
+		Expect(operation.Error).To(BeEmpty())
+
+		result := models.ImportGitResponse{}
+		Expect(operation.DecodeResult(&result)).To(Succeed())
+		Expect(result).To(Equal(models.ImportGitResponse{BlobUID: "blob", Commit: "abc"}))
+	})
+
+	It("records the error of failed operations", func() {
+		go pool.Run(ctx, logr.Discard())
+
+		operation, err := pool.Submit(models.OperationImportGit, app,
+			func(ctx context.Context, step func(string)) (interface{}, error) {
+				return nil, errors.New("repository not found")
+			})
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(state(operation.ID)).Should(Equal(models.OperationFailed))
+
+		operation, _ = pool.Get(operation.ID)
+		Expect(operation.Error).To(Equal("repository not found"))
+		Expect(operation.Result).To(BeEmpty())
+	})
+
+	It("keeps operations pending while the workers are busy", func() {
+		go pool.Run(ctx, logr.Discard())
+
+		release := make(chan struct{})
+		blocking := func(ctx context.Context, step func(string)) (interface{}, error) {
+			<-release
+			return &models.ImportGitResponse{}, nil
+		}
+
+		first, err := pool.Submit(models.OperationImportGit, app, blocking)
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(state(first.ID)).Should(Equal(models.OperationRunning))
+
+		second, err := pool.Submit(models.OperationImportGit, app, blocking)
+		Expect(err).ToNot(HaveOccurred())
+		Consistently(state(second.ID), "200ms").Should(Equal(models.OperationPending))
+
+		close(release)
+		Eventually(state(first.ID)).Should(Equal(models.OperationSucceeded))
+		Eventually(state(second.ID)).Should(Equal(models.OperationSucceeded))
+	})
+
+	It("rejects submissions when too many operations are pending", func() {
+		// Without Run nothing takes operations off the queue.
+		noop := func(ctx context.Context, step func(string)) (interface{}, error) {
+			return nil, nil
+		}
+
+		var err error
+		for i := 0; i < 1000 && err == nil; i++ {
+			_, err = pool.Submit(models.OperationImportGit, app, noop)
+		}
+		Expect(err).To(MatchError(operations.ErrBusy))
+	})
+
+	It("does not know unknown operations", func() {
+		_, ok := pool.Get("unknown")
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,361 @@
+// Package policy implements the admission policies of Epinio. Policies are ConfigMaps
+// labeled with PolicyLabel. Those in the namespace of Epinio apply to all namespaces,
+// those in a namespace of applications only to that namespace. The keys of a policy
+// ConfigMap are:
+//
+//	allowedRegistries   patterns of the registries container images may come from
+//	allowedBuilders     patterns of the builder images applications may be staged with
+//	maxInstances        the maximum number of instances of an application
+//	requireHealthCheck  "true" to require app charts providing health checks
+//	forbiddenEnv        patterns of environment variable names applications must not set
+//
+// Lists separate their entries by commas or newlines. Patterns use the syntax of
+// path.Match. App charts provide health checks when they are annotated with
+// HealthCheckAnnotation.
+//
+// Policies are evaluated when applications are created, updated, staged and deployed.
+// A policy ConfigMap which cannot be read rejects everything, instead of silently
+// enforcing nothing.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/appchart"
+	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/epinio/epinio/internal/registry"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PolicyLabel marks the ConfigMaps holding policies.
+	PolicyLabel = "epinio.io/policy"
+
+	// HealthCheckAnnotation marks the app charts whose deployments have health checks.
+	HealthCheckAnnotation = "epinio.io/health-check"
+
+	// Keys of the policy ConfigMaps
+	KeyAllowedRegistries  = "allowedRegistries"
+	KeyAllowedBuilders    = "allowedBuilders"
+	KeyMaxInstances       = "maxInstances"
+	KeyRequireHealthCheck = "requireHealthCheck"
+	KeyForbiddenEnv       = "forbiddenEnv"
+)
+
+// Names of the rules of a policy, as reported by violations.
+const (
+	RuleAllowedRegistries  = KeyAllowedRegistries
+	RuleAllowedBuilders    = KeyAllowedBuilders
+	RuleMaxInstances       = KeyMaxInstances
+	RuleRequireHealthCheck = KeyRequireHealthCheck
+	RuleForbiddenEnv       = KeyForbiddenEnv
+)
+
+// List returns the global policies and the policies of the namespace, ordered by name.
+// Global policies come first for identical names.
+func List(ctx context.Context, cluster *kubernetes.Cluster, namespace string) (models.PolicyList, error) {
+	policies, err := list(ctx, cluster, helmchart.Namespace(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	if namespace != "" && namespace != helmchart.Namespace() {
+		local, err := list(ctx, cluster, namespace, namespace)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, local...)
+	}
+
+	sort.SliceStable(policies, func(i, j int) bool {
+		return policies[i].Name < policies[j].Name
+	})
+
+	return policies, nil
+}
+
+// Lookup returns the named policy applying to the namespace, or nil. A policy of the
+// namespace shadows a global policy of the same name.
+func Lookup(ctx context.Context, cluster *kubernetes.Cluster, namespace, name string) (*models.Policy, error) {
+	policies, err := List(ctx, cluster, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *models.Policy
+	for i := range policies {
+		if policies[i].Name == name {
+			result = &policies[i]
+		}
+	}
+	return result, nil
+}
+
+// FromConfigMap returns the policy held by the ConfigMap. Policies of the namespace of
+// Epinio are global, and have no namespace.
+func FromConfigMap(configMap corev1.ConfigMap) (models.Policy, error) {
+	policy := models.Policy{
+		Name: configMap.Name,
+	}
+	if configMap.Namespace != helmchart.Namespace() {
+		policy.Namespace = configMap.Namespace
+	}
+
+	data := configMap.Data
+	policy.AllowedRegistries = splitList(data[KeyAllowedRegistries])
+	policy.AllowedBuilders = splitList(data[KeyAllowedBuilders])
+	policy.ForbiddenEnv = splitList(data[KeyForbiddenEnv])
+
+	for _, patterns := range [][]string{policy.AllowedRegistries, policy.AllowedBuilders, policy.ForbiddenEnv} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return policy, errors.Errorf("policy %s: bad pattern '%s'", configMap.Name, pattern)
+			}
+		}
+	}
+
+	if value := strings.TrimSpace(data[KeyMaxInstances]); value != "" {
+		max, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || max < 0 {
+			return policy, errors.Errorf("policy %s: bad %s '%s'", configMap.Name, KeyMaxInstances, value)
+		}
+		policy.MaxInstances = int32(max)
+	}
+
+	if value := strings.TrimSpace(data[KeyRequireHealthCheck]); value != "" {
+		require, err := strconv.ParseBool(value)
+		if err != nil {
+			return policy, errors.Errorf("policy %s: bad %s '%s'", configMap.Name, KeyRequireHealthCheck, value)
+		}
+		policy.RequireHealthCheck = require
+	}
+
+	return policy, nil
+}
+
+// Check evaluates the policies applying to the namespace against the subject, and
+// returns the violations found. The health checks of the app chart of the subject are
+// only looked for when a policy requires them.
+func Check(ctx context.Context, cluster *kubernetes.Cluster, namespace string, subject models.PolicySubject) ([]models.PolicyViolation, error) {
+	policies, err := List(ctx, cluster, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	healthCheck := false
+	if subject.AppChart != "" && requiresHealthCheck(policies) {
+		healthCheck, err = HasHealthCheck(ctx, cluster, subject.AppChart)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return Evaluate(policies, subject, healthCheck), nil
+}
+
+// Enforce evaluates the policies applying to the namespace against the application
+// settings, and rejects them with an error per violation.
+func Enforce(ctx context.Context, cluster *kubernetes.Cluster, namespace string, subject models.PolicySubject) apierror.APIErrors {
+	violations, err := Check(ctx, cluster, namespace, subject)
+	if err != nil {
+		return apierror.InternalError(err, "failed to evaluate policies")
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	issues := []apierror.APIError{}
+	for _, violation := range violations {
+		issues = append(issues, apierror.NewAPIError(
+			fmt.Sprintf("Rejected by policy '%s'", violation.Policy),
+			fmt.Sprintf("%s: %s", violation.Rule, violation.Message),
+			http.StatusForbidden))
+	}
+	return apierror.NewMultiError(issues)
+}
+
+// EnvironmentNames returns the sorted names of all environment variables of the
+// application settings, and the added names, for the evaluation of the policies. This
+// includes the variables of the staging environment, and those sourced from
+// configurations.
+func EnvironmentNames(settings models.ApplicationUpdateRequest, added ...string) []string {
+	names := map[string]struct{}{}
+	for name := range settings.Environment {
+		names[name] = struct{}{}
+	}
+	for name := range settings.EnvironmentFrom {
+		names[name] = struct{}{}
+	}
+	if settings.StagingEnvironment != nil {
+		for name := range *settings.StagingEnvironment {
+			names[name] = struct{}{}
+		}
+	}
+	for _, name := range added {
+		names[name] = struct{}{}
+	}
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// HasHealthCheck returns true if the named app chart is annotated as providing health
+// checks. Unknown charts provide none.
+func HasHealthCheck(ctx context.Context, cluster *kubernetes.Cluster, chart string) (bool, error) {
+	chartCR, err := appchart.Get(ctx, cluster, chart)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	enabled, _ := strconv.ParseBool(chartCR.GetAnnotations()[HealthCheckAnnotation])
+	return enabled, nil
+}
+
+// Evaluate returns the violations of the policies by the subject. Unset fields of the
+// subject are not evaluated. healthCheck tells whether the app chart of the subject
+// provides health checks.
+func Evaluate(policies models.PolicyList, subject models.PolicySubject, healthCheck bool) []models.PolicyViolation {
+	violations := []models.PolicyViolation{}
+	violation := func(policy models.Policy, rule, message string, args ...interface{}) {
+		violations = append(violations, models.PolicyViolation{
+			Policy:    policy.Name,
+			Namespace: policy.Namespace,
+			Rule:      rule,
+			Message:   fmt.Sprintf(message, args...),
+		})
+	}
+
+	for _, policy := range policies {
+		if subject.ContainerImage != "" && len(policy.AllowedRegistries) > 0 &&
+			!registryAllowed(policy.AllowedRegistries, subject.ContainerImage) {
+			violation(policy, RuleAllowedRegistries,
+				"container image '%s' is not from an allowed registry (%s)",
+				subject.ContainerImage, strings.Join(policy.AllowedRegistries, ", "))
+		}
+
+		if subject.Builder != "" && len(policy.AllowedBuilders) > 0 &&
+			!matchAny(policy.AllowedBuilders, subject.Builder) {
+			violation(policy, RuleAllowedBuilders,
+				"builder image '%s' is not allowed (%s)",
+				subject.Builder, strings.Join(policy.AllowedBuilders, ", "))
+		}
+
+		if subject.Instances != nil && policy.MaxInstances > 0 && *subject.Instances > policy.MaxInstances {
+			violation(policy, RuleMaxInstances,
+				"%d instances requested, at most %d are allowed",
+				*subject.Instances, policy.MaxInstances)
+		}
+
+		if subject.AppChart != "" && policy.RequireHealthCheck && !healthCheck {
+			violation(policy, RuleRequireHealthCheck,
+				"app chart '%s' provides no health checks", subject.AppChart)
+		}
+
+		for _, name := range subject.Environment {
+			if matchAny(policy.ForbiddenEnv, name) {
+				violation(policy, RuleForbiddenEnv,
+					"environment variable '%s' is forbidden", name)
+			}
+		}
+	}
+
+	return violations
+}
+
+// list returns the policies found in the kube namespace, with the given policy namespace.
+func list(ctx context.Context, cluster *kubernetes.Cluster, kubeNamespace, namespace string) (models.PolicyList, error) {
+	configMaps, err := cluster.Kubectl.CoreV1().ConfigMaps(kubeNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: PolicyLabel + "=true",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	policies := models.PolicyList{}
+	for _, configMap := range configMaps.Items {
+		policy, err := FromConfigMap(configMap)
+		if err != nil {
+			return nil, err
+		}
+		policy.Namespace = namespace
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// requiresHealthCheck returns true if any of the policies requires health checks.
+func requiresHealthCheck(policies models.PolicyList) bool {
+	for _, policy := range policies {
+		if policy.RequireHealthCheck {
+			return true
+		}
+	}
+	return false
+}
+
+// registryAllowed returns true if the registry of the image matches any of the
+// patterns. Patterns containing a slash are matched against the whole image reference
+// instead, to allow images from a part of a registry only.
+func registryAllowed(patterns []string, image string) bool {
+	host, _, err := registry.ExtractImageParts(image)
+	if err != nil {
+		return false
+	}
+
+	for _, pattern := range patterns {
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := path.Match(pattern, host); ok {
+				return true
+			}
+			continue
+		}
+		if strings.HasPrefix(image, strings.TrimSuffix(pattern, "/")+"/") {
+			return true
+		}
+		if ok, _ := path.Match(pattern, image); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAny returns true if the value matches any of the patterns.
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList returns the entries of a list separated by commas or newlines.
+func splitList(value string) []string {
+	var result []string
+	for _, entry := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
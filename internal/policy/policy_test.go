@@ -0,0 +1,154 @@
+package policy_test
+
+import (
+	"github.com/epinio/epinio/internal/policy"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Policies", func() {
+	Describe("FromConfigMap", func() {
+		configMap := func(data map[string]string) corev1.ConfigMap {
+			return corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "guardrails", Namespace: "workspace"},
+				Data:       data,
+			}
+		}
+
+		It("reads all rules", func() {
+			p, err := policy.FromConfigMap(configMap(map[string]string{
+				policy.KeyAllowedRegistries:  "ghcr.io, registry.example.com/team\n*.internal",
+				policy.KeyAllowedBuilders:    "paketobuildpacks/builder:*",
+				policy.KeyMaxInstances:       "5",
+				policy.KeyRequireHealthCheck: "true",
+				policy.KeyForbiddenEnv:       "AWS_*,DEBUG",
+			}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(p).To(Equal(models.Policy{
+				Name:               "guardrails",
+				Namespace:          "workspace",
+				AllowedRegistries:  []string{"ghcr.io", "registry.example.com/team", "*.internal"},
+				AllowedBuilders:    []string{"paketobuildpacks/builder:*"},
+				MaxInstances:       5,
+				RequireHealthCheck: true,
+				ForbiddenEnv:       []string{"AWS_*", "DEBUG"},
+			}))
+		})
+
+		It("rejects bad settings", func() {
+			_, err := policy.FromConfigMap(configMap(map[string]string{policy.KeyMaxInstances: "many"}))
+			Expect(err).To(MatchError(ContainSubstring("bad maxInstances 'many'")))
+
+			_, err = policy.FromConfigMap(configMap(map[string]string{policy.KeyRequireHealthCheck: "perhaps"}))
+			Expect(err).To(MatchError(ContainSubstring("bad requireHealthCheck")))
+
+			_, err = policy.FromConfigMap(configMap(map[string]string{policy.KeyForbiddenEnv: "[A-"}))
+			Expect(err).To(MatchError(ContainSubstring("bad pattern '[A-'")))
+		})
+	})
+
+	Describe("Evaluate", func() {
+		var policies models.PolicyList
+
+		BeforeEach(func() {
+			policies = models.PolicyList{
+				{
+					Name:              "registries",
+					AllowedRegistries: []string{"ghcr.io", "registry.example.com/team", "*.internal"},
+				},
+				{
+					Name:            "builders",
+					Namespace:       "workspace",
+					AllowedBuilders: []string{"paketobuildpacks/builder:*"},
+					MaxInstances:    3,
+					ForbiddenEnv:    []string{"AWS_*"},
+				},
+				{
+					Name:               "health",
+					RequireHealthCheck: true,
+				},
+			}
+		})
+
+		rules := func(violations []models.PolicyViolation) []string {
+			result := []string{}
+			for _, violation := range violations {
+				result = append(result, violation.Policy+"/"+violation.Rule)
+			}
+			return result
+		}
+
+		It("accepts an empty subject", func() {
+			Expect(policy.Evaluate(policies, models.PolicySubject{}, false)).To(BeEmpty())
+		})
+
+		It("checks the registry of container images", func() {
+			for _, image := range []string{
+				"ghcr.io/epinio/app:1",
+				"registry.example.com/team/app",
+				"registry.example.com/team/sub/app:2",
+				"cache.internal/app",
+			} {
+				Expect(policy.Evaluate(policies, models.PolicySubject{ContainerImage: image}, false)).To(BeEmpty(), image)
+			}
+
+			violations := policy.Evaluate(policies, models.PolicySubject{ContainerImage: "registry.example.com/other/app"}, false)
+			Expect(rules(violations)).To(Equal([]string{"registries/allowedRegistries"}))
+			Expect(violations[0].Message).To(ContainSubstring("'registry.example.com/other/app' is not from an allowed registry"))
+
+			violations = policy.Evaluate(policies, models.PolicySubject{ContainerImage: "nginx"}, false)
+			Expect(rules(violations)).To(Equal([]string{"registries/allowedRegistries"}))
+		})
+
+		It("checks builders", func() {
+			Expect(policy.Evaluate(policies, models.PolicySubject{Builder: "paketobuildpacks/builder:full"}, false)).To(BeEmpty())
+
+			violations := policy.Evaluate(policies, models.PolicySubject{Builder: "example/builder:latest"}, false)
+			Expect(violations).To(Equal([]models.PolicyViolation{{
+				Policy:    "builders",
+				Namespace: "workspace",
+				Rule:      policy.RuleAllowedBuilders,
+				Message:   "builder image 'example/builder:latest' is not allowed (paketobuildpacks/builder:*)",
+			}}))
+		})
+
+		It("checks the number of instances", func() {
+			three, four := int32(3), int32(4)
+			Expect(policy.Evaluate(policies, models.PolicySubject{Instances: &three}, false)).To(BeEmpty())
+
+			violations := policy.Evaluate(policies, models.PolicySubject{Instances: &four}, false)
+			Expect(rules(violations)).To(Equal([]string{"builders/maxInstances"}))
+			Expect(violations[0].Message).To(Equal("4 instances requested, at most 3 are allowed"))
+		})
+
+		It("checks health checks of the app chart", func() {
+			Expect(policy.Evaluate(policies, models.PolicySubject{AppChart: "probed"}, true)).To(BeEmpty())
+
+			violations := policy.Evaluate(policies, models.PolicySubject{AppChart: "standard"}, false)
+			Expect(rules(violations)).To(Equal([]string{"health/requireHealthCheck"}))
+		})
+
+		It("checks environment variable names", func() {
+			violations := policy.Evaluate(policies, models.PolicySubject{
+				Environment: []string{"PORT", "AWS_SECRET_ACCESS_KEY", "AWS_REGION"},
+			}, false)
+			Expect(rules(violations)).To(Equal([]string{"builders/forbiddenEnv", "builders/forbiddenEnv"}))
+			Expect(violations[0].Message).To(Equal("environment variable 'AWS_SECRET_ACCESS_KEY' is forbidden"))
+		})
+	})
+
+	Describe("EnvironmentNames", func() {
+		It("collects the names of all environments, sorted and without duplicates", func() {
+			staging := models.EnvVariableMap{"BP_NODE_VERSION": "18", "PORT": "8000"}
+			names := policy.EnvironmentNames(models.ApplicationUpdateRequest{
+				Environment:        models.EnvVariableMap{"PORT": "8080"},
+				EnvironmentFrom:    models.EnvReferenceMap{"DB_URL": {Configuration: "db", Key: "url"}},
+				StagingEnvironment: &staging,
+			}, "AWS_REGION", "PORT")
+			Expect(names).To(Equal([]string{"AWS_REGION", "BP_NODE_VERSION", "DB_URL", "PORT"}))
+		})
+	})
+})
@@ -0,0 +1,317 @@
+// Package quota limits the resources of Epinio namespaces. The quota of a namespace is
+// kept in a kubernetes ResourceQuota, which limits the memory and CPU requested by the
+// workloads of the namespace. A LimitRange provides default requests for containers
+// without any, as the ResourceQuota rejects them otherwise. The limits on the number
+// of apps, instances, services and configurations are annotations of the ResourceQuota,
+// and are checked by Epinio when these are created or scaled.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/configurations"
+	"github.com/epinio/epinio/internal/services"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ResourceQuotaName is the name of the ResourceQuota holding the quota of a namespace.
+	ResourceQuotaName = "epinio-quota"
+
+	// LimitRangeName is the name of the LimitRange providing the default requests of
+	// the containers of a namespace with quota.
+	LimitRangeName = "epinio-quota-defaults"
+
+	annotationApps           = "epinio.io/quota-apps"
+	annotationInstances      = "epinio.io/quota-instances"
+	annotationServices       = "epinio.io/quota-services"
+	annotationConfigurations = "epinio.io/quota-configurations"
+)
+
+// Default requests of containers without any. They are lowered to the quota, if it is
+// smaller.
+var (
+	defaultMemoryRequest = resource.MustParse("128Mi")
+	defaultCPURequest    = resource.MustParse("100m")
+)
+
+// Validate checks the settings of a quota.
+func Validate(quota models.NamespaceQuota) error {
+	for name, count := range map[string]int32{
+		"apps":           quota.Apps,
+		"instances":      quota.Instances,
+		"services":       quota.Services,
+		"configurations": quota.Configurations,
+	} {
+		if count < 0 {
+			return errors.Errorf("quota of %s must not be negative", name)
+		}
+	}
+
+	for name, value := range map[string]string{"memory": quota.Memory, "cpu": quota.CPU} {
+		if value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return errors.Errorf("bad %s quota '%s': %s", name, value, err.Error())
+		}
+		if quantity.Sign() <= 0 {
+			return errors.Errorf("quota of %s must be positive", name)
+		}
+	}
+	return nil
+}
+
+// Get returns the quota of the namespace, or nil if it has none.
+func Get(ctx context.Context, cluster *kubernetes.Cluster, namespace string) (*models.NamespaceQuota, error) {
+	resourceQuota, err := cluster.Kubectl.CoreV1().ResourceQuotas(namespace).Get(ctx, ResourceQuotaName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	quota := models.NamespaceQuota{
+		Apps:           annotationCount(resourceQuota, annotationApps),
+		Instances:      annotationCount(resourceQuota, annotationInstances),
+		Services:       annotationCount(resourceQuota, annotationServices),
+		Configurations: annotationCount(resourceQuota, annotationConfigurations),
+	}
+	if memory, ok := resourceQuota.Spec.Hard[corev1.ResourceRequestsMemory]; ok {
+		quota.Memory = memory.String()
+	}
+	if cpu, ok := resourceQuota.Spec.Hard[corev1.ResourceRequestsCPU]; ok {
+		quota.CPU = cpu.String()
+	}
+	return &quota, nil
+}
+
+// Set replaces the quota of the namespace. An empty quota removes it.
+func Set(ctx context.Context, cluster *kubernetes.Cluster, namespace string, quota models.NamespaceQuota) error {
+	if err := Validate(quota); err != nil {
+		return err
+	}
+
+	if quota.IsEmpty() {
+		return Remove(ctx, cluster, namespace)
+	}
+
+	hard := corev1.ResourceList{}
+	defaults := corev1.ResourceList{}
+	if quota.Memory != "" {
+		memory := resource.MustParse(quota.Memory)
+		hard[corev1.ResourceRequestsMemory] = memory
+		defaults[corev1.ResourceMemory] = minQuantity(defaultMemoryRequest, memory)
+	}
+	if quota.CPU != "" {
+		cpu := resource.MustParse(quota.CPU)
+		hard[corev1.ResourceRequestsCPU] = cpu
+		defaults[corev1.ResourceCPU] = minQuantity(defaultCPURequest, cpu)
+	}
+
+	resourceQuota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ResourceQuotaName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "epinio",
+			},
+			Annotations: map[string]string{
+				annotationApps:           strconv.Itoa(int(quota.Apps)),
+				annotationInstances:      strconv.Itoa(int(quota.Instances)),
+				annotationServices:       strconv.Itoa(int(quota.Services)),
+				annotationConfigurations: strconv.Itoa(int(quota.Configurations)),
+			},
+		},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: hard,
+		},
+	}
+
+	client := cluster.Kubectl.CoreV1().ResourceQuotas(namespace)
+	current, err := client.Get(ctx, ResourceQuotaName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = client.Create(ctx, resourceQuota, metav1.CreateOptions{})
+	case err == nil:
+		resourceQuota.ResourceVersion = current.ResourceVersion
+		_, err = client.Update(ctx, resourceQuota, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return errors.Wrap(err, "saving the resource quota")
+	}
+
+	return setLimitRange(ctx, cluster, namespace, defaults)
+}
+
+// Remove deletes the quota of the namespace, if it has one.
+func Remove(ctx context.Context, cluster *kubernetes.Cluster, namespace string) error {
+	err := cluster.Kubectl.CoreV1().ResourceQuotas(namespace).Delete(ctx, ResourceQuotaName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "deleting the resource quota")
+	}
+
+	return setLimitRange(ctx, cluster, namespace, nil)
+}
+
+// Usage returns the use of the resources of the namespace limited by quotas. Memory
+// and CPU are only known for namespaces with a quota limiting them.
+func Usage(ctx context.Context, cluster *kubernetes.Cluster, namespace string) (models.NamespaceUsage, error) {
+	usage := models.NamespaceUsage{}
+
+	appRefs, err := application.ListAppRefs(ctx, cluster, namespace)
+	if err != nil {
+		return usage, err
+	}
+	usage.Apps = int32(len(appRefs))
+
+	for _, appRef := range appRefs {
+		instances, err := application.Scaling(ctx, cluster, appRef)
+		if err != nil {
+			return usage, errors.Wrapf(err, "reading the instances of app %s", appRef.Name)
+		}
+		usage.Instances += instances
+	}
+
+	serviceClient, err := services.NewKubernetesServiceClient(cluster)
+	if err != nil {
+		return usage, err
+	}
+	serviceList, err := serviceClient.ListInNamespace(ctx, namespace)
+	if err != nil {
+		return usage, err
+	}
+	usage.Services = int32(len(serviceList))
+
+	configurationList, err := configurations.List(ctx, cluster, namespace)
+	if err != nil {
+		return usage, err
+	}
+	usage.Configurations = int32(len(configurationList))
+
+	resourceQuota, err := cluster.Kubectl.CoreV1().ResourceQuotas(namespace).Get(ctx, ResourceQuotaName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return usage, nil
+		}
+		return usage, err
+	}
+	if memory, ok := resourceQuota.Status.Used[corev1.ResourceRequestsMemory]; ok {
+		usage.Memory = memory.String()
+	}
+	if cpu, ok := resourceQuota.Status.Used[corev1.ResourceRequestsCPU]; ok {
+		usage.CPU = cpu.String()
+	}
+
+	return usage, nil
+}
+
+// Check returns the reasons why the quota of the namespace does not allow the additional
+// use of resources. Only the apps, instances, services and configurations of the
+// request are checked, the memory and CPU are limited by kubernetes itself. Namespaces
+// without quota allow everything.
+func Check(ctx context.Context, cluster *kubernetes.Cluster, namespace string, request models.NamespaceUsage) ([]string, error) {
+	quota, err := Get(ctx, cluster, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if quota == nil {
+		return nil, nil
+	}
+
+	usage, err := Usage(ctx, cluster, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return Exceeded(*quota, usage, request), nil
+}
+
+// Exceeded returns the reasons why the quota does not allow the requested additional use
+// of resources on top of the current usage.
+func Exceeded(quota models.NamespaceQuota, usage, request models.NamespaceUsage) []string {
+	reasons := []string{}
+	for _, limit := range []struct {
+		name           string
+		max, used, add int32
+	}{
+		{"apps", quota.Apps, usage.Apps, request.Apps},
+		{"instances", quota.Instances, usage.Instances, request.Instances},
+		{"services", quota.Services, usage.Services, request.Services},
+		{"configurations", quota.Configurations, usage.Configurations, request.Configurations},
+	} {
+		if limit.max > 0 && limit.add > 0 && limit.used+limit.add > limit.max {
+			reasons = append(reasons, fmt.Sprintf("%d %s requested, with %d in use of at most %d",
+				limit.add, limit.name, limit.used, limit.max))
+		}
+	}
+	return reasons
+}
+
+// setLimitRange replaces the default requests of the containers of the namespace. No
+// defaults remove the LimitRange.
+func setLimitRange(ctx context.Context, cluster *kubernetes.Cluster, namespace string, defaults corev1.ResourceList) error {
+	client := cluster.Kubectl.CoreV1().LimitRanges(namespace)
+
+	if len(defaults) == 0 {
+		err := client.Delete(ctx, LimitRangeName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrap(err, "deleting the limit range")
+		}
+		return nil
+	}
+
+	limitRange := &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      LimitRangeName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "epinio",
+			},
+		},
+		Spec: corev1.LimitRangeSpec{
+			Limits: []corev1.LimitRangeItem{{
+				Type:           corev1.LimitTypeContainer,
+				DefaultRequest: defaults,
+			}},
+		},
+	}
+
+	current, err := client.Get(ctx, LimitRangeName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = client.Create(ctx, limitRange, metav1.CreateOptions{})
+	case err == nil:
+		limitRange.ResourceVersion = current.ResourceVersion
+		_, err = client.Update(ctx, limitRange, metav1.UpdateOptions{})
+	}
+	return errors.Wrap(err, "saving the limit range")
+}
+
+// annotationCount returns the count of the annotation of the ResourceQuota, 0 if missing.
+func annotationCount(resourceQuota *corev1.ResourceQuota, annotation string) int32 {
+	count, err := strconv.ParseInt(resourceQuota.Annotations[annotation], 10, 32)
+	if err != nil || count < 0 {
+		return 0
+	}
+	return int32(count)
+}
+
+// minQuantity returns the smaller of the quantities.
+func minQuantity(a, b resource.Quantity) resource.Quantity {
+	if a.Cmp(b) > 0 {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,58 @@
+package quota_test
+
+import (
+	"github.com/epinio/epinio/internal/quota"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Quota", func() {
+	Describe("Validate", func() {
+		It("accepts counts and quantities", func() {
+			Expect(quota.Validate(models.NamespaceQuota{})).To(Succeed())
+			Expect(quota.Validate(models.NamespaceQuota{
+				Apps:      10,
+				Instances: 20,
+				Memory:    "4Gi",
+				CPU:       "2500m",
+			})).To(Succeed())
+		})
+
+		It("rejects negative counts", func() {
+			Expect(quota.Validate(models.NamespaceQuota{Services: -1})).
+				To(MatchError("quota of services must not be negative"))
+		})
+
+		It("rejects bad quantities", func() {
+			Expect(quota.Validate(models.NamespaceQuota{Memory: "lots"})).
+				To(MatchError(ContainSubstring("bad memory quota 'lots'")))
+			Expect(quota.Validate(models.NamespaceQuota{CPU: "0"})).
+				To(MatchError("quota of cpu must be positive"))
+		})
+	})
+
+	Describe("Exceeded", func() {
+		limits := models.NamespaceQuota{Apps: 2, Instances: 5, Configurations: 3}
+		usage := models.NamespaceUsage{Apps: 1, Instances: 4, Services: 7, Configurations: 3}
+
+		It("allows use within the quota", func() {
+			Expect(quota.Exceeded(limits, usage, models.NamespaceUsage{Apps: 1, Instances: 1})).To(BeEmpty())
+		})
+
+		It("ignores unlimited resources", func() {
+			Expect(quota.Exceeded(limits, usage, models.NamespaceUsage{Services: 1})).To(BeEmpty())
+		})
+
+		It("ignores released resources", func() {
+			Expect(quota.Exceeded(limits, usage, models.NamespaceUsage{Instances: -2})).To(BeEmpty())
+		})
+
+		It("reports the exceeded limits", func() {
+			Expect(quota.Exceeded(limits, usage, models.NamespaceUsage{Apps: 1, Instances: 2, Configurations: 1})).To(Equal([]string{
+				"2 instances requested, with 4 in use of at most 5",
+				"1 configurations requested, with 3 in use of at most 3",
+			}))
+		})
+	})
+})
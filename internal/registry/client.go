@@ -0,0 +1,483 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// This file implements a client of the registry HTTP API (distribution v2), used to find
+// and remove the images pushed by stagings, and to copy images in and out of namespace
+// backups. Removing an image deletes its manifest. The registry has to allow deletions,
+// and the space of the layers is only freed by the registry's own garbage collection.
+
+// manifestTypes are the media types of the manifests the client accepts.
+var manifestTypes = []string{
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+}
+
+// ErrDeleteDisabled is returned by DeleteManifest when the registry does not allow the
+// deletion of images.
+var ErrDeleteDisabled = errors.New("the registry does not allow the deletion of images")
+
+// Client talks to a registry, with the credentials of the connection details.
+type Client struct {
+	url      string
+	username string
+	password string
+	token    string // token is the last bearer token handed out, for registries using them
+	http     *http.Client
+}
+
+// Manifest describes the manifest of an image. Blobs maps the digests of the config
+// and layers, or of the platform manifests of an index, to their sizes. Config and
+// Layers are the digests of the config and the layers of an image, in order. They are
+// empty for an index. MediaType and Content are the manifest as sent by the registry.
+type Manifest struct {
+	Digest    string
+	Blobs     map[string]int64
+	Config    string
+	Layers    []string
+	MediaType string
+	Content   []byte
+}
+
+// Size returns the total size of the blobs of the manifest.
+func (m Manifest) Size() int64 {
+	size := int64(0)
+	for _, blobSize := range m.Blobs {
+		size += blobSize
+	}
+	return size
+}
+
+// NewClient returns a client of the registry at the URL of the credentials. The URL
+// defaults to https. The CA, if any, is trusted in addition to the system's.
+func NewClient(credentials RegistryCredentials, ca []byte) (*Client, error) {
+	if credentials.URL == "" {
+		return nil, errors.New("url must be specified")
+	}
+
+	base := credentials.URL
+	if !strings.Contains(base, "://") {
+		base = "https://" + base
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(ca) > 0 {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if ok := rootCAs.AppendCertsFromPEM(ca); !ok {
+			return nil, errors.New("cannot append registry ca to client")
+		}
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		transport.TLSClientConfig.RootCAs = rootCAs
+	}
+
+	return &Client{
+		url:      strings.TrimSuffix(base, "/"),
+		username: credentials.Username,
+		password: credentials.Password,
+		http:     &http.Client{Transport: transport},
+	}, nil
+}
+
+// Tags returns the tags of the repository. A repository which does not exist has no
+// tags.
+func (c *Client) Tags(ctx context.Context, repository string) ([]string, error) {
+	tags := []string{}
+
+	next := fmt.Sprintf("/v2/%s/tags/list", repository)
+	for next != "" {
+		resp, err := c.do(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusNotFound {
+			resp.Body.Close()
+			return tags, nil
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, statusError(resp, "listing the tags of "+repository)
+		}
+
+		page := struct {
+			Tags []string `json:"tags"`
+		}{}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding the tags of %s", repository)
+		}
+
+		tags = append(tags, page.Tags...)
+		next = nextPage(resp.Header.Get("Link"))
+	}
+
+	return tags, nil
+}
+
+// Manifest returns the manifest of the tagged image.
+func (c *Client) Manifest(ctx context.Context, repository, tag string) (Manifest, error) {
+	manifest := Manifest{Blobs: map[string]int64{}}
+
+	header := http.Header{}
+	header.Set("Accept", strings.Join(manifestTypes, ", "))
+
+	resp, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v2/%s/manifests/%s", repository, tag), header)
+	if err != nil {
+		return manifest, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return manifest, statusError(resp, fmt.Sprintf("fetching the manifest of %s:%s", repository, tag))
+	}
+
+	manifest.Digest = resp.Header.Get("Docker-Content-Digest")
+	if manifest.Digest == "" {
+		return manifest, errors.Errorf("the registry returned no digest for %s:%s", repository, tag)
+	}
+	manifest.MediaType = resp.Header.Get("Content-Type")
+
+	manifest.Content, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return manifest, errors.Wrapf(err, "reading the manifest of %s:%s", repository, tag)
+	}
+
+	type descriptor struct {
+		Digest string `json:"digest"`
+		Size   int64  `json:"size"`
+	}
+	content := struct {
+		Config    *descriptor  `json:"config"`
+		Layers    []descriptor `json:"layers"`
+		Manifests []descriptor `json:"manifests"`
+	}{}
+	if err := json.Unmarshal(manifest.Content, &content); err != nil {
+		return manifest, errors.Wrapf(err, "decoding the manifest of %s:%s", repository, tag)
+	}
+
+	blobs := append(content.Layers, content.Manifests...)
+	if content.Config != nil {
+		blobs = append(blobs, *content.Config)
+	}
+	for _, blob := range blobs {
+		manifest.Blobs[blob.Digest] = blob.Size
+	}
+	if content.Config != nil {
+		manifest.Config = content.Config.Digest
+	}
+	for _, layer := range content.Layers {
+		manifest.Layers = append(manifest.Layers, layer.Digest)
+	}
+
+	return manifest, nil
+}
+
+// Blob returns a reader of the contents of the blob with the digest. The caller has to
+// close it.
+func (c *Client) Blob(ctx context.Context, repository, digest string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v2/%s/blobs/%s", repository, digest), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp, fmt.Sprintf("fetching blob %s of %s", digest, repository))
+	}
+	return resp.Body, nil
+}
+
+// PutManifest tags the manifest with the media type in the repository. The blobs it
+// references have to be pushed before.
+func (c *Client) PutManifest(ctx context.Context, repository, tag, mediaType string, content []byte) error {
+	header := http.Header{}
+	header.Set("Content-Type", mediaType)
+
+	resp, err := c.send(ctx, http.MethodPut, fmt.Sprintf("/v2/%s/manifests/%s", repository, tag), header,
+		bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return statusError(resp, fmt.Sprintf("pushing the manifest of %s:%s", repository, tag))
+	}
+	return nil
+}
+
+// BlobExists returns true if the repository has the blob with the digest.
+func (c *Client) BlobExists(ctx context.Context, repository, digest string) (bool, error) {
+	resp, err := c.do(ctx, http.MethodHead, fmt.Sprintf("/v2/%s/blobs/%s", repository, digest), nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, statusError(resp, fmt.Sprintf("checking blob %s of %s", digest, repository))
+}
+
+// PutBlob pushes the size bytes of the reader as the blob with the digest into the
+// repository, in a single upload.
+func (c *Client) PutBlob(ctx context.Context, repository, digest string, size int64, content io.Reader) error {
+	resp, err := c.do(ctx, http.MethodPost, fmt.Sprintf("/v2/%s/blobs/uploads/", repository), nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return statusError(resp, fmt.Sprintf("starting the upload of blob %s to %s", digest, repository))
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.Header.Get("Location") == "" {
+		return errors.Errorf("the registry returned no upload location for blob %s of %s", digest, repository)
+	}
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/octet-stream")
+
+	resp, err = c.send(ctx, http.MethodPut, location.RequestURI(), header, content, size)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return statusError(resp, fmt.Sprintf("uploading blob %s to %s", digest, repository))
+	}
+	return nil
+}
+
+// DeleteManifest deletes the manifest with the digest, and with it all tags of the
+// image.
+func (c *Client) DeleteManifest(ctx context.Context, repository, digest string) error {
+	resp, err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/v2/%s/manifests/%s", repository, digest), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusAccepted, http.StatusOK, http.StatusNotFound:
+		return nil
+	case http.StatusMethodNotAllowed:
+		return ErrDeleteDisabled
+	}
+	return statusError(resp, fmt.Sprintf("deleting %s@%s", repository, digest))
+}
+
+// Repository returns the name of the repository holding the images of the application.
+// See the stage endpoint.
+func Repository(registryNamespace, namespace, app string) string {
+	name := fmt.Sprintf("%s-%s", namespace, app)
+	if registryNamespace == "" {
+		return name
+	}
+	return registryNamespace + "/" + name
+}
+
+// NewEpinioClient returns a client of the Epinio registry, and the namespace of the
+// registry holding the application images.
+func NewEpinioClient(ctx context.Context, cluster *kubernetes.Cluster) (*Client, string, error) {
+	details, err := GetConnectionDetails(ctx, cluster, helmchart.Namespace(), CredentialsSecretName)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "fetching the registry connection details")
+	}
+	publicURL, err := details.PublicRegistryURL()
+	if err != nil {
+		return nil, "", err
+	}
+	if publicURL == "" {
+		return nil, "", errors.New("no public registry URL found")
+	}
+
+	credentials := RegistryCredentials{URL: publicURL}
+	for _, c := range details.RegistryCredentials {
+		if c.URL == publicURL {
+			credentials = c
+		}
+	}
+
+	ca := []byte{}
+	if secretName := viper.GetString("registry-certificate-secret"); secretName != "" {
+		secret, err := cluster.GetSecret(ctx, helmchart.Namespace(), secretName)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "getting registry certificate secret %s", secretName)
+		}
+		ca = append(ca, secret.Data["tls.crt"]...)
+		ca = append(ca, secret.Data["ca.crt"]...)
+	}
+
+	client, err := NewClient(credentials, ca)
+	if err != nil {
+		return nil, "", err
+	}
+	return client, details.Namespace, nil
+}
+
+// do sends the request without body, see send.
+func (c *Client) do(ctx context.Context, method, path string, header http.Header) (*http.Response, error) {
+	return c.send(ctx, method, path, header, nil, 0)
+}
+
+// send sends the request, authenticated with the credentials. When the registry asks
+// for a bearer token, one is fetched from its token service, and the request sent again.
+// Requests with a body are only sent again if the body can be read again, i.e. for
+// bytes.Reader bodies. Others have to be preceded by a request of the same scope.
+func (c *Client) send(ctx context.Context, method, path string, header http.Header, body io.Reader, size int64) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, c.url+path, body)
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			req.ContentLength = size
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		} else if c.username != "" {
+			req.SetBasicAuth(c.username, c.password)
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s %s", method, path)
+		}
+
+		challenge := resp.Header.Get("WWW-Authenticate")
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 ||
+			!strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+			return resp, nil
+		}
+		if body != nil {
+			replay, ok := body.(*bytes.Reader)
+			if !ok {
+				return resp, nil
+			}
+			if _, err := replay.Seek(0, io.SeekStart); err != nil {
+				return resp, nil
+			}
+		}
+		resp.Body.Close()
+
+		// Tokens are scoped to a repository and actions. A token for another
+		// repository, or without the action, is replaced.
+		c.token, err = c.fetchToken(ctx, challenge)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// fetchToken returns a token from the token service named by the challenge of the
+// registry.
+func (c *Client) fetchToken(ctx context.Context, challenge string) (string, error) {
+	params := map[string]string{}
+	for _, match := range challengeParam.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	if params["realm"] == "" {
+		return "", errors.Errorf("bad authentication challenge '%s'", challenge)
+	}
+
+	query := url.Values{}
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, params["realm"]+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "fetching a registry token")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError(resp, "fetching a registry token")
+	}
+
+	answer := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+		return "", errors.Wrap(err, "decoding the registry token")
+	}
+	if answer.Token != "" {
+		return answer.Token, nil
+	}
+	return answer.AccessToken, nil
+}
+
+// nextPage returns the path of the next page from the Link header of a paginated
+// answer, or the empty string for the last page.
+func nextPage(link string) string {
+	if !strings.Contains(link, `rel="next"`) {
+		return ""
+	}
+	start := strings.Index(link, "<")
+	end := strings.Index(link, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	next := link[start+1 : end]
+
+	// Registries may answer with absolute URLs.
+	if u, err := url.Parse(next); err == nil && u.IsAbs() {
+		next = u.RequestURI()
+	}
+	return next
+}
+
+func statusError(resp *http.Response, action string) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	resp.Body.Close()
+	return errors.Errorf("%s: registry answered %s: %s", action, resp.Status, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,182 @@
+package registry_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/epinio/epinio/internal/registry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		server  *httptest.Server
+		mux     *http.ServeMux
+		client  *registry.Client
+		deleted []string
+	)
+
+	BeforeEach(func() {
+		deleted = []string{}
+		mux = http.NewServeMux()
+		server = httptest.NewServer(mux)
+
+		var err error
+		client, err = registry.NewClient(registry.RegistryCredentials{
+			URL:      server.URL,
+			Username: "user",
+			Password: "secret",
+		}, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		mux.HandleFunc("/v2/apps/ns-app/tags/list", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("last") == "" {
+				w.Header().Set("Link", `</v2/apps/ns-app/tags/list?last=b&n=2>; rel="next"`)
+				fmt.Fprint(w, `{"name":"apps/ns-app","tags":["a","b"]}`)
+				return
+			}
+			fmt.Fprint(w, `{"name":"apps/ns-app","tags":["c"]}`)
+		})
+		mux.HandleFunc("/v2/apps/ns-app/manifests/", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodDelete {
+				deleted = append(deleted, r.URL.Path)
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", "sha256:m")
+			fmt.Fprint(w, `{"config":{"digest":"sha256:c","size":10},"layers":[{"digest":"sha256:l1","size":100},{"digest":"sha256:l2","size":1000}]}`)
+		})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("lists all pages of tags", func() {
+		tags, err := client.Tags(context.Background(), "apps/ns-app")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tags).To(Equal([]string{"a", "b", "c"}))
+	})
+
+	It("lists no tags for unknown repositories", func() {
+		tags, err := client.Tags(context.Background(), "apps/unknown")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tags).To(BeEmpty())
+	})
+
+	It("returns the digest and blob sizes of manifests", func() {
+		manifest, err := client.Manifest(context.Background(), "apps/ns-app", "a")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Digest).To(Equal("sha256:m"))
+		Expect(manifest.Blobs).To(HaveLen(3))
+		Expect(manifest.Size()).To(Equal(int64(1110)))
+		Expect(manifest.Config).To(Equal("sha256:c"))
+		Expect(manifest.Layers).To(Equal([]string{"sha256:l1", "sha256:l2"}))
+		Expect(manifest.Content).To(ContainSubstring(`"layers"`))
+	})
+
+	It("returns the contents of blobs", func() {
+		mux.HandleFunc("/v2/apps/ns-app/blobs/sha256:c", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"config":{}}`)
+		})
+
+		reader, err := client.Blob(context.Background(), "apps/ns-app", "sha256:c")
+		Expect(err).ToNot(HaveOccurred())
+		defer reader.Close()
+		data, err := io.ReadAll(reader)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal(`{"config":{}}`))
+
+		_, err = client.Blob(context.Background(), "apps/ns-app", "sha256:missing")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("deletes manifests by digest", func() {
+		Expect(client.DeleteManifest(context.Background(), "apps/ns-app", "sha256:m")).To(Succeed())
+		Expect(deleted).To(Equal([]string{"/v2/apps/ns-app/manifests/sha256:m"}))
+	})
+
+	It("reports registries not allowing deletions", func() {
+		mux.HandleFunc("/v2/apps/locked/manifests/", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		})
+		err := client.DeleteManifest(context.Background(), "apps/locked", "sha256:m")
+		Expect(err).To(Equal(registry.ErrDeleteDisabled))
+	})
+
+	It("pushes blobs and manifests", func() {
+		uploads := map[string]string{}
+		manifests := map[string]string{}
+		mux.HandleFunc("/v2/apps/copy/blobs/uploads/", func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost:
+				w.Header().Set("Location", server.URL+"/v2/apps/copy/blobs/uploads/u1?state=s")
+				w.WriteHeader(http.StatusAccepted)
+			case http.MethodPut:
+				Expect(r.URL.Query().Get("state")).To(Equal("s"))
+				data, err := io.ReadAll(r.Body)
+				Expect(err).ToNot(HaveOccurred())
+				uploads[r.URL.Query().Get("digest")] = string(data)
+				w.WriteHeader(http.StatusCreated)
+			}
+		})
+		mux.HandleFunc("/v2/apps/copy/blobs/", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v2/apps/copy/blobs/sha256:known" {
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		})
+		mux.HandleFunc("/v2/apps/copy/manifests/", func(w http.ResponseWriter, r *http.Request) {
+			data, err := io.ReadAll(r.Body)
+			Expect(err).ToNot(HaveOccurred())
+			manifests[r.URL.Path] = r.Header.Get("Content-Type") + " " + string(data)
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		ctx := context.Background()
+		Expect(client.PutBlob(ctx, "apps/copy", "sha256:l1", 5, strings.NewReader("layer"))).To(Succeed())
+		Expect(uploads).To(Equal(map[string]string{"sha256:l1": "layer"}))
+
+		exists, err := client.BlobExists(ctx, "apps/copy", "sha256:known")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeTrue())
+		exists, err = client.BlobExists(ctx, "apps/copy", "sha256:l2")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+
+		Expect(client.PutManifest(ctx, "apps/copy", "s1", "application/vnd.oci.image.manifest.v1+json", []byte(`{}`))).To(Succeed())
+		Expect(manifests).To(Equal(map[string]string{
+			"/v2/apps/copy/manifests/s1": "application/vnd.oci.image.manifest.v1+json {}",
+		}))
+	})
+
+	It("authenticates with bearer tokens on request", func() {
+		mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			if !ok || username != "user" || password != "secret" ||
+				r.URL.Query().Get("scope") != "repository:apps/private:pull" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"token":"t0k3n"}`)
+		})
+		mux.HandleFunc("/v2/apps/private/tags/list", func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer t0k3n" {
+				w.Header().Set("WWW-Authenticate",
+					fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:apps/private:pull"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"name":"apps/private","tags":["x"]}`)
+		})
+
+		tags, err := client.Tags(context.Background(), "apps/private")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tags).To(Equal([]string{"x"}))
+	})
+})
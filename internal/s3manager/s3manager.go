@@ -9,6 +9,8 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/internal/helmchart"
@@ -234,6 +236,144 @@ func (m *Manager) Upload(ctx context.Context, filepath string, metadata map[stri
 	return objectName, nil
 }
 
+// Part is an uploaded part of a multipart upload.
+type Part struct {
+	Number int
+	Size   int64
+	ETag   string
+}
+
+// Upload describes an incomplete multipart upload.
+type Upload struct {
+	BlobUID   string
+	UploadID  string
+	Initiated time.Time
+}
+
+// ListUploads returns the incomplete multipart uploads of the bucket.
+func (m *Manager) ListUploads(ctx context.Context) ([]Upload, error) {
+	uploads := []Upload{}
+	keyMarker := ""
+	uploadIDMarker := ""
+	for {
+		result, err := m.core().ListMultipartUploads(ctx, m.connectionDetails.Bucket,
+			"", keyMarker, uploadIDMarker, "", 1000)
+		if err != nil {
+			if IsNotFound(err) {
+				// Missing bucket, nothing stored yet.
+				return uploads, nil
+			}
+			return nil, errors.Wrap(err, "listing multipart uploads")
+		}
+		for _, upload := range result.Uploads {
+			uploads = append(uploads, Upload{
+				BlobUID:   upload.Key,
+				UploadID:  upload.UploadID,
+				Initiated: upload.Initiated,
+			})
+		}
+		if !result.IsTruncated {
+			return uploads, nil
+		}
+		keyMarker = result.NextKeyMarker
+		uploadIDMarker = result.NextUploadIDMarker
+	}
+}
+
+// InitiateUpload starts a multipart upload of a new blob. It returns the blobUID and the
+// ID of the upload. The parts of the upload are sent with UploadPart, and assembled into
+// the blob by CompleteUpload.
+func (m *Manager) InitiateUpload(ctx context.Context, metadata map[string]string) (string, string, error) {
+	if err := m.EnsureBucket(ctx); err != nil {
+		return "", "", errors.Wrap(err, "ensuring bucket")
+	}
+
+	objectName := uuid.New().String()
+	contentType := "application/tar"
+
+	uploadID, err := m.core().NewMultipartUpload(ctx, m.connectionDetails.Bucket,
+		objectName, minio.PutObjectOptions{
+			ContentType:  contentType,
+			UserMetadata: metadata,
+		})
+	if err != nil {
+		return "", "", errors.Wrap(err, "starting the multipart upload")
+	}
+
+	return objectName, uploadID, nil
+}
+
+// UploadPart stores the numbered part of the multipart upload of the blob. Uploading a
+// part again replaces it.
+func (m *Manager) UploadPart(ctx context.Context, blobUID, uploadID string, number int, data io.Reader, size int64) (Part, error) {
+	part, err := m.core().PutObjectPart(ctx, m.connectionDetails.Bucket,
+		blobUID, uploadID, number, data, size, "", "", nil)
+	if err != nil {
+		return Part{}, errors.Wrapf(err, "writing part %d", number)
+	}
+
+	return Part{Number: part.PartNumber, Size: part.Size, ETag: part.ETag}, nil
+}
+
+// ListParts returns the parts uploaded so far for the multipart upload of the blob,
+// ordered by their number.
+func (m *Manager) ListParts(ctx context.Context, blobUID, uploadID string) ([]Part, error) {
+	parts := []Part{}
+	marker := 0
+	for {
+		result, err := m.core().ListObjectParts(ctx, m.connectionDetails.Bucket,
+			blobUID, uploadID, marker, 1000)
+		if err != nil {
+			return nil, errors.Wrap(err, "listing the uploaded parts")
+		}
+		for _, part := range result.ObjectParts {
+			parts = append(parts, Part{Number: part.PartNumber, Size: part.Size, ETag: part.ETag})
+		}
+		if !result.IsTruncated {
+			return parts, nil
+		}
+		marker = result.NextPartNumberMarker
+	}
+}
+
+// CompleteUpload assembles the uploaded parts into the blob. The parts have to be
+// numbered without gaps, starting at 1.
+func (m *Manager) CompleteUpload(ctx context.Context, blobUID, uploadID string) error {
+	parts, err := m.ListParts(ctx, blobUID, uploadID)
+	if err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return errors.New("no parts uploaded")
+	}
+
+	complete := []minio.CompletePart{}
+	for i, part := range parts {
+		if part.Number != i+1 {
+			return errors.Errorf("part %d is missing", i+1)
+		}
+		complete = append(complete, minio.CompletePart{PartNumber: part.Number, ETag: part.ETag})
+	}
+
+	_, err = m.core().CompleteMultipartUpload(ctx, m.connectionDetails.Bucket,
+		blobUID, uploadID, complete, minio.PutObjectOptions{})
+	if err != nil {
+		return errors.Wrap(err, "completing the multipart upload")
+	}
+
+	return nil
+}
+
+// AbortUpload discards the multipart upload of the blob, and its uploaded parts.
+func (m *Manager) AbortUpload(ctx context.Context, blobUID, uploadID string) error {
+	return m.core().AbortMultipartUpload(ctx, m.connectionDetails.Bucket, blobUID, uploadID)
+}
+
+// core returns the low-level client, for multipart uploads.
+func (m *Manager) core() minio.Core {
+	return minio.Core{Client: m.minioClient}
+}
+
 // EnsureBucket creates our bucket if it's missing
 func (m *Manager) EnsureBucket(ctx context.Context) error {
 	exists, err := m.minioClient.BucketExists(ctx, m.connectionDetails.Bucket)
@@ -253,3 +393,115 @@ func (m *Manager) DeleteObject(ctx context.Context, objectID string) error {
 	return m.minioClient.RemoveObject(ctx, m.connectionDetails.Bucket, objectID,
 		minio.RemoveObjectOptions{})
 }
+
+// PutObject stores the data under the given object name, replacing any existing object
+// of that name.
+func (m *Manager) PutObject(ctx context.Context, objectName string, data io.Reader, size int64, contentType string, metadata map[string]string) error {
+	if err := m.EnsureBucket(ctx); err != nil {
+		return errors.Wrap(err, "ensuring bucket")
+	}
+
+	_, err := m.minioClient.PutObject(ctx, m.connectionDetails.Bucket,
+		objectName, data, size, minio.PutObjectOptions{
+			ContentType:  contentType,
+			UserMetadata: metadata,
+		})
+	if err != nil {
+		return errors.Wrap(err, "writing the object")
+	}
+
+	return nil
+}
+
+// GetObject returns the contents of the named object. Use IsNotFound to distinguish a
+// missing object from other errors.
+func (m *Manager) GetObject(ctx context.Context, objectName string) ([]byte, error) {
+	object, err := m.minioClient.GetObject(ctx, m.connectionDetails.Bucket, objectName,
+		minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	// Note: Errors of the request, like a missing object, surface on the first read.
+	return io.ReadAll(object)
+}
+
+// ListObjects returns the names of all objects whose name starts with the prefix.
+func (m *Manager) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	names := []string{}
+	for object := range m.minioClient.ListObjects(ctx, m.connectionDetails.Bucket,
+		minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			if IsNotFound(object.Err) {
+				// Missing bucket, nothing stored yet.
+				return names, nil
+			}
+			return nil, errors.Wrap(object.Err, "listing objects")
+		}
+		names = append(names, object.Key)
+	}
+
+	return names, nil
+}
+
+// Blob describes a stored blob of application sources.
+type Blob struct {
+	UID          string
+	Size         int64
+	LastModified time.Time
+}
+
+// ListBlobs returns the blobs of application sources. These are the objects at the top of
+// the bucket named by a UUID, see Upload. Other objects, like staging logs, are not
+// returned.
+func (m *Manager) ListBlobs(ctx context.Context) ([]Blob, error) {
+	blobs := []Blob{}
+	for object := range m.minioClient.ListObjects(ctx, m.connectionDetails.Bucket,
+		minio.ListObjectsOptions{}) {
+		if object.Err != nil {
+			if IsNotFound(object.Err) {
+				// Missing bucket, nothing stored yet.
+				return blobs, nil
+			}
+			return nil, errors.Wrap(object.Err, "listing objects")
+		}
+		// Note: Without recursion the objects below a prefix are reported as the prefix.
+		if strings.Contains(object.Key, "/") {
+			continue
+		}
+		if _, err := uuid.Parse(object.Key); err != nil {
+			continue
+		}
+		blobs = append(blobs, Blob{
+			UID:          object.Key,
+			Size:         object.Size,
+			LastModified: object.LastModified,
+		})
+	}
+
+	return blobs, nil
+}
+
+// DeletePrefix deletes all objects whose name starts with the prefix.
+func (m *Manager) DeletePrefix(ctx context.Context, prefix string) error {
+	names, err := m.ListObjects(ctx, prefix)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := m.DeleteObject(ctx, name); err != nil {
+			return errors.Wrapf(err, "deleting object %s", name)
+		}
+	}
+
+	return nil
+}
+
+// IsNotFound returns true if the error reports a missing object, bucket, or multipart
+// upload.
+func IsNotFound(err error) bool {
+	code := minio.ToErrorResponse(errors.Cause(err)).Code
+	return code == "NoSuchKey" || code == "NoSuchBucket" || code == "NoSuchUpload"
+}
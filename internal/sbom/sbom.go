@@ -0,0 +1,311 @@
+// Package sbom keeps the software bill of materials and the provenance of staged
+// application images. Buildpacks write SBOMs for the layers they contribute, and the
+// lifecycle adds them to the image as a layer of their own. When a staging run succeeds
+// the API server reads that layer from the registry and stores its documents, together
+// with a provenance record of the build, in the blob store. They are keyed by namespace,
+// application and stage ID, i.e. by the release using the image. Like the staging logs
+// the recording is best-effort, see package staginglogs.
+package sbom
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/blobstore"
+	"github.com/epinio/epinio/internal/registry"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// prefix is the start of the names of all stored SBOMs.
+	prefix = "sbom/"
+
+	// metadataLabel is the image label the lifecycle records the layers of the image
+	// in, the SBOM layer among them.
+	metadataLabel = "io.buildpacks.lifecycle.metadata"
+
+	// layerDir is the directory of the SBOM layer holding the documents.
+	layerDir = "layers/sbom/"
+
+	// maxDocumentSize limits the size of a single document read from the SBOM layer.
+	maxDocumentSize = 32 << 20
+)
+
+// formats maps the file names of the SBOM documents written by buildpacks to their
+// formats.
+var formats = map[string]string{
+	"sbom.cdx.json":  "cyclonedx",
+	"sbom.spdx.json": "spdx",
+	"sbom.syft.json": "syft",
+}
+
+// ObjectName returns the name of the object holding the SBOM of the identified staging
+// run of the application.
+func ObjectName(app models.AppRef, stageID string) string {
+	return fmt.Sprintf("%s%s/%s/%s", prefix, app.Namespace, app.Name, stageID)
+}
+
+// Capture records the SBOM and provenance of the image of a staging run, once its job
+// ended. See staginglogs.Capture for the wait. Failed and cancelled runs have no image,
+// and nothing is recorded for them.
+func Capture(ctx context.Context, cluster *kubernetes.Cluster, provenance models.Provenance) error {
+	job, err := application.StagingJob(ctx, cluster, provenance.App.Namespace, provenance.StageID)
+	if err != nil {
+		return err
+	}
+	if job == nil || job.Status.Succeeded == 0 {
+		return nil
+	}
+
+	client, registryNamespace, err := registry.NewEpinioClient(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	repository := registry.Repository(registryNamespace, provenance.App.Namespace, provenance.App.Name)
+	digest, documents, err := Image(ctx, client, repository, provenance.StageID)
+	if err != nil {
+		return errors.Wrapf(err, "reading the SBOM of image %s:%s", repository, provenance.StageID)
+	}
+	provenance.ImageDigest = digest
+
+	return Store(ctx, cluster, models.AppSBOM{
+		Provenance: provenance,
+		Documents:  documents,
+	})
+}
+
+// Image returns the digest of the tagged image of the repository, and the SBOM
+// documents of its SBOM layer. Images without such a layer, e.g. images built from a
+// Dockerfile, have no documents.
+func Image(ctx context.Context, client *registry.Client, repository, tag string) (string, []models.SBOMDocument, error) {
+	manifest, err := client.Manifest(ctx, repository, tag)
+	if err != nil {
+		return "", nil, err
+	}
+	if manifest.Config == "" {
+		return manifest.Digest, nil, errors.Errorf("%s:%s is not an image manifest", repository, tag)
+	}
+
+	config, err := readBlob(ctx, client, repository, manifest.Config)
+	if err != nil {
+		return manifest.Digest, nil, err
+	}
+
+	diffID, diffIDs, err := sbomLayer(config)
+	if err != nil {
+		return manifest.Digest, nil, err
+	}
+	if diffID == "" {
+		return manifest.Digest, nil, nil
+	}
+
+	// The layers of the manifest are in the order of the uncompressed layers of the
+	// config.
+	index := -1
+	for i, id := range diffIDs {
+		if id == diffID && i < len(manifest.Layers) {
+			index = i
+		}
+	}
+	if index < 0 {
+		return manifest.Digest, nil, errors.Errorf("SBOM layer %s not found in the image", diffID)
+	}
+
+	layer, err := client.Blob(ctx, repository, manifest.Layers[index])
+	if err != nil {
+		return manifest.Digest, nil, err
+	}
+	defer layer.Close()
+
+	documents, err := Extract(layer)
+	return manifest.Digest, documents, err
+}
+
+// Extract returns the SBOM documents of the SBOM layer, ordered by path. The layer is
+// a tar archive, usually compressed with gzip.
+func Extract(layer io.Reader) ([]models.SBOMDocument, error) {
+	reader := bufio.NewReader(layer)
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		unzipped, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, errors.Wrap(err, "decompressing the SBOM layer")
+		}
+		defer unzipped.Close()
+		layer = unzipped
+	} else {
+		layer = reader
+	}
+
+	documents := []models.SBOMDocument{}
+	archive := tar.NewReader(layer)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "reading the SBOM layer")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := strings.TrimPrefix(header.Name, "/")
+		if !strings.HasPrefix(name, layerDir) {
+			continue
+		}
+		format, ok := formats[path.Base(name)]
+		if !ok {
+			continue
+		}
+		if header.Size > maxDocumentSize {
+			return nil, errors.Errorf("SBOM document %s is too large", name)
+		}
+
+		content, err := io.ReadAll(archive)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading SBOM document %s", name)
+		}
+		if !json.Valid(content) {
+			return nil, errors.Errorf("SBOM document %s is not valid JSON", name)
+		}
+
+		documents = append(documents, models.SBOMDocument{
+			Path:    strings.TrimPrefix(name, layerDir),
+			Format:  format,
+			Content: content,
+		})
+	}
+
+	// Layers list their files in no particular order.
+	sort.Slice(documents, func(i, j int) bool {
+		return documents[i].Path < documents[j].Path
+	})
+	return documents, nil
+}
+
+// Store saves the SBOM of a staging run, see ObjectName.
+func Store(ctx context.Context, cluster *kubernetes.Cluster, sbom models.AppSBOM) error {
+	data, err := json.Marshal(sbom)
+	if err != nil {
+		return err
+	}
+
+	store, err := blobstore.New(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	provenance := sbom.Provenance
+	return store.PutObject(ctx, ObjectName(provenance.App, provenance.StageID), bytes.NewReader(data), int64(len(data)),
+		"application/json", map[string]string{
+			"App":       provenance.App.Name,
+			"Namespace": provenance.App.Namespace,
+			"StageID":   provenance.StageID,
+		})
+}
+
+// Load returns the stored SBOM of the identified staging run of the application. The
+// boolean result is false if no SBOM is stored for the run.
+func Load(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef, stageID string) (models.AppSBOM, bool, error) {
+	sbom := models.AppSBOM{}
+
+	store, err := blobstore.New(ctx, cluster)
+	if err != nil {
+		return sbom, false, err
+	}
+
+	data, err := store.GetObject(ctx, ObjectName(app, stageID))
+	if err != nil {
+		if blobstore.IsNotFound(err) {
+			return sbom, false, nil
+		}
+		return sbom, false, errors.Wrap(err, "reading the stored SBOM")
+	}
+
+	if err := json.Unmarshal(data, &sbom); err != nil {
+		return sbom, false, errors.Wrap(err, "bad stored SBOM")
+	}
+	return sbom, true, nil
+}
+
+// Delete removes the stored SBOMs of all staging runs of the application.
+func Delete(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef) error {
+	store, err := blobstore.New(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	return store.DeletePrefix(ctx, fmt.Sprintf("%s%s/%s/", prefix, app.Namespace, app.Name))
+}
+
+// DeleteNamespace removes the stored SBOMs of all staging runs of all applications of
+// the namespace.
+func DeleteNamespace(ctx context.Context, cluster *kubernetes.Cluster, namespace string) error {
+	store, err := blobstore.New(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	return store.DeletePrefix(ctx, fmt.Sprintf("%s%s/", prefix, namespace))
+}
+
+// readBlob returns the contents of a small blob of the repository, like an image config.
+func readBlob(ctx context.Context, client *registry.Client, repository, digest string) ([]byte, error) {
+	blob, err := client.Blob(ctx, repository, digest)
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+
+	return io.ReadAll(io.LimitReader(blob, maxDocumentSize))
+}
+
+// sbomLayer returns the uncompressed digest of the SBOM layer named by the lifecycle
+// metadata of the image config, and the uncompressed digests of all layers. The digest
+// is empty for images without SBOM layer.
+func sbomLayer(config []byte) (string, []string, error) {
+	image := struct {
+		Config struct {
+			Labels map[string]string `json:"Labels"`
+		} `json:"config"`
+		RootFS struct {
+			DiffIDs []string `json:"diff_ids"`
+		} `json:"rootfs"`
+	}{}
+	if err := json.Unmarshal(config, &image); err != nil {
+		return "", nil, errors.Wrap(err, "decoding the image config")
+	}
+
+	label, ok := image.Config.Labels[metadataLabel]
+	if !ok {
+		return "", nil, nil
+	}
+
+	metadata := struct {
+		SBOM *struct {
+			SHA string `json:"sha"`
+		} `json:"sbom"`
+	}{}
+	if err := json.Unmarshal([]byte(label), &metadata); err != nil {
+		return "", nil, errors.Wrap(err, "decoding the lifecycle metadata of the image")
+	}
+	if metadata.SBOM == nil {
+		return "", nil, nil
+	}
+
+	return metadata.SBOM.SHA, image.RootFS.DiffIDs, nil
+}
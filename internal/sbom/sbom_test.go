@@ -0,0 +1,122 @@
+package sbom_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/epinio/epinio/internal/registry"
+	"github.com/epinio/epinio/internal/sbom"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// layer returns a gzipped tar archive of the files.
+func layer(files map[string]string) []byte {
+	var out bytes.Buffer
+	zipper := gzip.NewWriter(&out)
+	archive := tar.NewWriter(zipper)
+	for name, content := range files {
+		Expect(archive.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})).To(Succeed())
+		_, err := archive.Write([]byte(content))
+		Expect(err).ToNot(HaveOccurred())
+	}
+	Expect(archive.Close()).To(Succeed())
+	Expect(zipper.Close()).To(Succeed())
+	return out.Bytes()
+}
+
+var _ = Describe("SBOM", func() {
+	sbomLayer := map[string]string{
+		"/layers/sbom/launch/paketo-buildpacks_node-engine/node/sbom.cdx.json":  `{"bomFormat":"CycloneDX"}`,
+		"/layers/sbom/launch/paketo-buildpacks_node-engine/node/sbom.spdx.json": `{"spdxVersion":"SPDX-2.2"}`,
+		"/layers/sbom/launch/paketo-buildpacks_node-engine/node/notes.txt":      `not an SBOM`,
+		"/layers/config/metadata.toml":                                          `[run]`,
+	}
+
+	Describe("Extract", func() {
+		It("returns the SBOM documents of the layer, ordered by path", func() {
+			documents, err := sbom.Extract(bytes.NewReader(layer(sbomLayer)))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(documents).To(HaveLen(2))
+
+			Expect(documents[0].Path).To(Equal("launch/paketo-buildpacks_node-engine/node/sbom.cdx.json"))
+			Expect(documents[0].Format).To(Equal("cyclonedx"))
+			Expect(string(documents[0].Content)).To(Equal(`{"bomFormat":"CycloneDX"}`))
+			Expect(documents[1].Format).To(Equal("spdx"))
+		})
+
+		It("rejects documents which are not JSON", func() {
+			_, err := sbom.Extract(bytes.NewReader(layer(map[string]string{
+				"layers/sbom/launch/bp/layer/sbom.syft.json": `{broken`,
+			})))
+			Expect(err).To(MatchError(ContainSubstring("is not valid JSON")))
+		})
+	})
+
+	Describe("Image", func() {
+		var (
+			server *httptest.Server
+			mux    *http.ServeMux
+			client *registry.Client
+			labels map[string]string
+		)
+
+		BeforeEach(func() {
+			mux = http.NewServeMux()
+			server = httptest.NewServer(mux)
+			labels = map[string]string{
+				"io.buildpacks.lifecycle.metadata": `{"sbom":{"sha":"sha256:d2"}}`,
+			}
+
+			var err error
+			client, err = registry.NewClient(registry.RegistryCredentials{URL: server.URL}, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			mux.HandleFunc("/v2/apps/ns-app/manifests/s1", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Docker-Content-Digest", "sha256:m")
+				fmt.Fprint(w, `{"config":{"digest":"sha256:c","size":10},"layers":[{"digest":"sha256:l1","size":100},{"digest":"sha256:l2","size":1000}]}`)
+			})
+			mux.HandleFunc("/v2/apps/ns-app/blobs/sha256:c", func(w http.ResponseWriter, r *http.Request) {
+				config := map[string]interface{}{
+					"config": map[string]interface{}{"Labels": labels},
+					"rootfs": map[string]interface{}{"diff_ids": []string{"sha256:d1", "sha256:d2"}},
+				}
+				Expect(json.NewEncoder(w).Encode(config)).To(Succeed())
+			})
+			mux.HandleFunc("/v2/apps/ns-app/blobs/sha256:l2", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(layer(sbomLayer))
+			})
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("reads the documents of the SBOM layer of the image", func() {
+			digest, documents, err := sbom.Image(context.Background(), client, "apps/ns-app", "s1")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(digest).To(Equal("sha256:m"))
+			Expect(documents).To(HaveLen(2))
+		})
+
+		It("returns no documents for images without SBOM layer", func() {
+			labels = map[string]string{}
+
+			digest, documents, err := sbom.Image(context.Background(), client, "apps/ns-app", "s1")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(digest).To(Equal("sha256:m"))
+			Expect(documents).To(BeEmpty())
+		})
+	})
+})
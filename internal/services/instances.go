@@ -8,10 +8,12 @@ import (
 	"github.com/epinio/epinio/internal/helm"
 	"github.com/epinio/epinio/internal/helmchart"
 	"github.com/epinio/epinio/internal/names"
+	"github.com/epinio/epinio/internal/namespaces"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 
 	helmapiv1 "github.com/k3s-io/helm-controller/pkg/apis/helm.cattle.io/v1"
 	helmdriver "helm.sh/helm/v3/pkg/storage/driver"
@@ -67,6 +69,7 @@ func (s *ServiceClient) Get(ctx context.Context, namespace, name string) (*model
 		},
 		CatalogService:        fmt.Sprintf("%s%s", catalogServicePrefix, catalogServiceName),
 		CatalogServiceVersion: catalogServiceVersion,
+		Protected:             namespaces.IsProtected(srv.GetAnnotations()),
 	}
 
 	logger := tracelog.NewLogger().WithName("ServiceStatus")
@@ -123,6 +126,34 @@ func (s *ServiceClient) Create(ctx context.Context, namespace, name string, cata
 	return errors.Wrap(err, "error creating helm chart")
 }
 
+// Values returns the values the service was installed with, i.e. the values of its
+// catalog service at creation.
+func (s *ServiceClient) Values(ctx context.Context, namespace, name string) (string, error) {
+	srv, err := s.helmChartsKubeClient.Namespace(helmchart.Namespace()).Get(ctx,
+		names.ServiceHelmChartName(name, namespace), metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrap(err, "fetching the service instance")
+	}
+
+	values, _, err := unstructured.NestedString(srv.UnstructuredContent(), "spec", "valuesContent")
+	if err != nil {
+		return "", errors.Wrap(err, "looking up valuesContent as a string")
+	}
+	return values, nil
+}
+
+// SetProtected sets or clears the protection of the service against deletion.
+func (s *ServiceClient) SetProtected(ctx context.Context, namespace, name string, protected bool) error {
+	patch, err := namespaces.ProtectionPatch(protected)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.helmChartsKubeClient.Namespace(helmchart.Namespace()).Patch(ctx,
+		names.ServiceHelmChartName(name, namespace), types.MergePatchType, patch, metav1.PatchOptions{})
+	return errors.Wrap(err, "error protecting service")
+}
+
 // Delete deletes the helmcharts that matches the given service which is
 // installed on the namespace (that's the targetNamespace).
 func (s *ServiceClient) Delete(ctx context.Context, namespace, service string) error {
@@ -215,6 +246,7 @@ func (s *ServiceClient) list(ctx context.Context, namespace string) (models.Serv
 			},
 			CatalogService:        catalogServiceName,
 			CatalogServiceVersion: srv.GetLabels()[CatalogServiceVersionLabelKey],
+			Protected:             namespaces.IsProtected(srv.GetAnnotations()),
 		}
 
 		logger := tracelog.NewLogger().WithName("ServiceStatus")
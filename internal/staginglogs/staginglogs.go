@@ -0,0 +1,253 @@
+// Package staginglogs keeps the logs of staging runs beyond the life of their jobs and
+// pods. The API server captures the logs of each run as they stream, and stores them in
+// the blob store when the run ends, keyed by namespace, application and stage ID.
+//
+// The capture is best-effort. It runs in the background of the API server instance which
+// started the run, and nothing resumes it elsewhere. When that instance stops before the
+// run ends, the logs of the run, and its SBOM (see package sbom), are not stored. Load
+// then reports them as missing, the run itself is not affected.
+package staginglogs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/helpers/kubernetes/tailer"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/blobstore"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// prefix is the start of the names of all stored staging logs.
+	prefix = "staging-logs/"
+
+	// grace is the time given to the log streams to deliver the last lines after the
+	// staging job ended.
+	grace = 5 * time.Second
+)
+
+// ObjectName returns the name of the object holding the logs of the identified
+// staging run of the application.
+func ObjectName(app models.AppRef, stageID string) string {
+	return fmt.Sprintf("%s%s/%s/%s", prefix, app.Namespace, app.Name, stageID)
+}
+
+// Encode returns the log lines as JSON lines.
+func Encode(lines []tailer.ContainerLogLine) ([]byte, error) {
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	for _, line := range lines {
+		if err := encoder.Encode(line); err != nil {
+			return nil, err
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// Decode returns the log lines encoded by Encode.
+func Decode(data []byte) ([]tailer.ContainerLogLine, error) {
+	lines := []tailer.ContainerLogLine{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for decoder.More() {
+		var line tailer.ContainerLogLine
+		if err := decoder.Decode(&line); err != nil {
+			return nil, errors.Wrap(err, "bad stored log line")
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// Capture collects the logs of the identified staging run of the application until
+// its job ends, and stores them. When the pod is still around after the end, its
+// complete logs are fetched in container order and stored instead of the streamed
+// lines.
+func Capture(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef, stageID string) error {
+	log := requestctx.Logger(ctx).WithName("staging-logs").WithValues("app", app.Name, "namespace", app.Namespace, "stage", stageID)
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	logChan := make(chan tailer.ContainerLogLine)
+	streamed := []tailer.ContainerLogLine{}
+	var tailWg sync.WaitGroup
+	collected := make(chan struct{})
+
+	go func() {
+		for line := range logChan {
+			streamed = append(streamed, line)
+		}
+		close(collected)
+	}()
+
+	// Streaming runs until cancelled, see below.
+	go func() {
+		err := application.Logs(streamCtx, logChan, &tailWg, cluster, true, app.Name, stageID, app.Namespace)
+		if err != nil {
+			log.Info("streaming failed", "error", err.Error())
+		}
+		tailWg.Wait()
+		close(logChan)
+	}()
+
+	err := waitForJobEnd(ctx, cluster, app, stageID)
+	if err != nil {
+		log.Info("waiting for the job failed", "error", err.Error())
+	}
+
+	time.Sleep(grace)
+	cancel()
+	<-collected
+
+	lines := streamed
+	if fetched, err := fetch(ctx, cluster, app, stageID); err == nil && len(fetched) > 0 {
+		lines = fetched
+	}
+
+	log.Info("store", "lines", len(lines))
+	return Store(ctx, cluster, app, stageID, lines)
+}
+
+// Store saves the log lines of the identified staging run of the application.
+func Store(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef, stageID string, lines []tailer.ContainerLogLine) error {
+	data, err := Encode(lines)
+	if err != nil {
+		return err
+	}
+
+	store, err := blobstore.New(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	return store.PutObject(ctx, ObjectName(app, stageID), bytes.NewReader(data), int64(len(data)),
+		"application/x-ndjson", map[string]string{
+			"App":       app.Name,
+			"Namespace": app.Namespace,
+			"StageID":   stageID,
+		})
+}
+
+// Load returns the stored log lines of the identified staging run of the namespace. The
+// boolean result is false if no logs are stored for the run.
+func Load(ctx context.Context, cluster *kubernetes.Cluster, namespace, stageID string) ([]tailer.ContainerLogLine, bool, error) {
+	store, err := blobstore.New(ctx, cluster)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The request does not tell the application, locate the run among all stored
+	// runs of the namespace.
+	names, err := store.ListObjects(ctx, fmt.Sprintf("%s%s/", prefix, namespace))
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, name := range names {
+		if !strings.HasSuffix(name, "/"+stageID) {
+			continue
+		}
+
+		data, err := store.GetObject(ctx, name)
+		if err != nil {
+			if blobstore.IsNotFound(err) {
+				return nil, false, nil
+			}
+			return nil, false, errors.Wrap(err, "reading stored logs")
+		}
+
+		lines, err := Decode(data)
+		if err != nil {
+			return nil, false, err
+		}
+		return lines, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// Delete removes the stored logs of all staging runs of the application.
+func Delete(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef) error {
+	store, err := blobstore.New(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	return store.DeletePrefix(ctx, fmt.Sprintf("%s%s/%s/", prefix, app.Namespace, app.Name))
+}
+
+// DeleteNamespace removes the stored logs of all staging runs of all applications of the
+// namespace.
+func DeleteNamespace(ctx context.Context, cluster *kubernetes.Cluster, namespace string) error {
+	store, err := blobstore.New(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	return store.DeletePrefix(ctx, fmt.Sprintf("%s%s/", prefix, namespace))
+}
+
+// fetch returns the complete logs of the identified staging run, in container order, if
+// its pod still exists.
+func fetch(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef, stageID string) ([]tailer.ContainerLogLine, error) {
+	logChan := make(chan tailer.ContainerLogLine)
+	lines := []tailer.ContainerLogLine{}
+	var tailWg sync.WaitGroup
+	collected := make(chan struct{})
+
+	go func() {
+		for line := range logChan {
+			lines = append(lines, line)
+		}
+		close(collected)
+	}()
+
+	err := application.Logs(ctx, logChan, &tailWg, cluster, false, app.Name, stageID, app.Namespace)
+	tailWg.Wait()
+	close(logChan)
+	<-collected
+
+	return lines, err
+}
+
+// waitForJobEnd waits until the job of the identified staging run is complete, failed,
+// or gone.
+func waitForJobEnd(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef, stageID string) error {
+	return wait.PollImmediateUntil(2*time.Second, func() (bool, error) {
+		job, err := application.StagingJob(ctx, cluster, app.Namespace, stageID)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		return job == nil || !application.JobStaging(*job), nil
+	}, ctx.Done())
+}
+
+// PodExists returns true if a pod of the identified staging run of the namespace exists.
+func PodExists(ctx context.Context, cluster *kubernetes.Cluster, namespace, stageID string) (bool, error) {
+	selector := fmt.Sprintf("app.kubernetes.io/component=staging,app.kubernetes.io/part-of=%s,%s=%s",
+		namespace, models.EpinioStageIDLabel, stageID)
+
+	pods, err := cluster.Kubectl.CoreV1().Pods(helmchart.Namespace()).List(ctx,
+		metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return false, err
+	}
+
+	return len(pods.Items) > 0, nil
+}
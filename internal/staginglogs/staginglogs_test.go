@@ -0,0 +1,46 @@
+package staginglogs_test
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes/tailer"
+	"github.com/epinio/epinio/internal/staginglogs"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Staging logs", func() {
+	Describe("ObjectName", func() {
+		It("keys the logs by namespace, application, and stage", func() {
+			name := staginglogs.ObjectName(models.NewAppRef("app", "workspace"), "stage-1")
+			Expect(name).To(Equal("staging-logs/workspace/app/stage-1"))
+		})
+	})
+
+	Describe("Encode and Decode", func() {
+		It("round-trips the log lines in order", func() {
+			lines := []tailer.ContainerLogLine{
+				{Message: "first", ContainerName: "download", PodName: "pod", Namespace: "epinio"},
+				{Message: "second\nwith \"quotes\"", ContainerName: "buildpack", PodName: "pod", Namespace: "epinio"},
+			}
+
+			data, err := staginglogs.Encode(lines)
+			Expect(err).ToNot(HaveOccurred())
+
+			decoded, err := staginglogs.Decode(data)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decoded).To(Equal(lines))
+		})
+
+		It("decodes empty data to no lines", func() {
+			decoded, err := staginglogs.Decode([]byte{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decoded).To(BeEmpty())
+		})
+
+		It("fails for bad data", func() {
+			_, err := staginglogs.Decode([]byte("not json"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,248 @@
+// Package stagingqueue implements the API server's scheduler of staging jobs. It limits
+// the number of builds running at the same time, cluster-wide and per namespace.
+// Staging jobs beyond these limits are created suspended, and form a FIFO queue ordered
+// by creation time. The scheduler resumes them in order as running builds finish.
+//
+// As the queue is kept in the cluster, as suspended jobs, it survives restarts of the
+// API server.
+package stagingqueue
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// interval is the time between the scheduler's checks for finished builds.
+const interval = 5 * time.Second
+
+// Limits are the maximum numbers of concurrently running builds. Zero means unlimited.
+type Limits struct {
+	Global    int
+	Namespace int
+}
+
+// Scheduler resumes queued staging jobs as the limits allow.
+type Scheduler struct {
+	mu     sync.Mutex
+	limits Limits
+}
+
+// scheduler is the memo of the scheduler run by the API server. See Start.
+var scheduler *Scheduler
+
+// NewScheduler returns a scheduler for the limits.
+func NewScheduler(limits Limits) *Scheduler {
+	return &Scheduler{limits: limits}
+}
+
+// Start creates the scheduler for the API server, and runs it in the background until
+// the context is done. Without limits no scheduler is started, and staging jobs are not
+// queued.
+func Start(ctx context.Context, logger logr.Logger, limits Limits) {
+	if limits.Global <= 0 && limits.Namespace <= 0 {
+		logger.Info("staging queue disabled")
+		return
+	}
+
+	scheduler = NewScheduler(limits)
+	go scheduler.Run(ctx, logger)
+}
+
+// Running returns the scheduler started by Start, or nil if staging is not queued.
+func Running() *Scheduler {
+	return scheduler
+}
+
+// Limits returns the limits of the scheduler.
+func (s *Scheduler) Limits() Limits {
+	return s.limits
+}
+
+// Run resumes queued staging jobs every interval, until the context is done.
+func (s *Scheduler) Run(ctx context.Context, logger logr.Logger) {
+	logger = logger.WithName("StagingQueue")
+	logger.Info("start", "global", s.limits.Global, "namespace", s.limits.Namespace)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		cluster, err := kubernetes.GetCluster(ctx)
+		if err == nil {
+			err = s.Dispatch(ctx, cluster)
+		}
+		if err != nil {
+			logger.V(1).Info("dispatch failed", "error", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("stop")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch resumes the queued staging jobs for which the limits have room.
+func (s *Scheduler) Dispatch(ctx context.Context, cluster *kubernetes.Cluster) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobList, err := cluster.ListJobs(ctx, helmchart.Namespace(), "app.kubernetes.io/component=staging")
+	if err != nil {
+		return errors.Wrap(err, "listing staging jobs")
+	}
+
+	resume, _ := Plan(jobList.Items, s.limits)
+
+	for _, job := range resume {
+		_, err := cluster.Kubectl.BatchV1().Jobs(job.Namespace).Patch(ctx, job.Name,
+			types.MergePatchType, []byte(`{"spec":{"suspend":false}}`), metav1.PatchOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "resuming staging job %s", job.Name)
+		}
+	}
+
+	return nil
+}
+
+// Plan splits the queued staging jobs into the jobs to resume now, and the jobs staying
+// queued, both in FIFO order. Finished jobs are ignored. A job held back by the limit of
+// its namespace does not hold back the jobs of other namespaces behind it.
+func Plan(jobs []batchv1.Job, limits Limits) ([]batchv1.Job, []batchv1.Job) {
+	running, queued := split(jobs)
+
+	global := len(running)
+	perNamespace := map[string]int{}
+	for _, job := range running {
+		perNamespace[namespaceOf(job)]++
+	}
+
+	resume := []batchv1.Job{}
+	remaining := []batchv1.Job{}
+	for _, job := range queued {
+		namespace := namespaceOf(job)
+
+		if (limits.Global > 0 && global >= limits.Global) ||
+			(limits.Namespace > 0 && perNamespace[namespace] >= limits.Namespace) {
+			remaining = append(remaining, job)
+			continue
+		}
+
+		global++
+		perNamespace[namespace]++
+		resume = append(resume, job)
+	}
+
+	return resume, remaining
+}
+
+// Status returns the running and queued builds of the namespace. The position of queued
+// builds is their place in the cluster-wide queue, starting at 1.
+func Status(ctx context.Context, cluster *kubernetes.Cluster, namespace string) ([]models.StagingBuild, error) {
+	jobList, err := cluster.ListJobs(ctx, helmchart.Namespace(), "app.kubernetes.io/component=staging")
+	if err != nil {
+		return nil, errors.Wrap(err, "listing staging jobs")
+	}
+
+	return Builds(jobList.Items, namespace), nil
+}
+
+// Builds returns the running and queued builds of the namespace among the jobs, running
+// builds first.
+func Builds(jobs []batchv1.Job, namespace string) []models.StagingBuild {
+	running, queued := split(jobs)
+
+	result := []models.StagingBuild{}
+	for _, job := range running {
+		if namespaceOf(job) == namespace {
+			result = append(result, build(job, models.StagingRunning, 0))
+		}
+	}
+	for i, job := range queued {
+		if namespaceOf(job) == namespace {
+			result = append(result, build(job, models.StagingQueued, i+1))
+		}
+	}
+
+	return result
+}
+
+// Position returns the place of the identified staging run in the cluster-wide queue,
+// starting at 1. It is 0 when the run is not queued.
+func Position(ctx context.Context, cluster *kubernetes.Cluster, stageID string) (int, error) {
+	jobList, err := cluster.ListJobs(ctx, helmchart.Namespace(), "app.kubernetes.io/component=staging")
+	if err != nil {
+		return 0, errors.Wrap(err, "listing staging jobs")
+	}
+
+	_, queued := split(jobList.Items)
+	for i, job := range queued {
+		if job.Labels[models.EpinioStageIDLabel] == stageID {
+			return i + 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// Suspended returns true if the job is suspended, i.e. queued.
+func Suspended(job batchv1.Job) bool {
+	return job.Spec.Suspend != nil && *job.Spec.Suspend
+}
+
+// split returns the running and the queued staging jobs, the latter in FIFO order.
+func split(jobs []batchv1.Job) ([]batchv1.Job, []batchv1.Job) {
+	running := []batchv1.Job{}
+	queued := []batchv1.Job{}
+
+	for _, job := range jobs {
+		if !application.JobStaging(job) {
+			continue
+		}
+		if Suspended(job) {
+			queued = append(queued, job)
+			continue
+		}
+		running = append(running, job)
+	}
+
+	sort.SliceStable(queued, func(i, j int) bool {
+		ti := queued[i].CreationTimestamp
+		tj := queued[j].CreationTimestamp
+		if ti.Equal(&tj) {
+			return queued[i].Name < queued[j].Name
+		}
+		return ti.Before(&tj)
+	})
+
+	return running, queued
+}
+
+// namespaceOf returns the namespace of the application staged by the job.
+func namespaceOf(job batchv1.Job) string {
+	return job.Labels["app.kubernetes.io/part-of"]
+}
+
+func build(job batchv1.Job, status string, position int) models.StagingBuild {
+	return models.StagingBuild{
+		Stage:     models.NewStage(job.Labels[models.EpinioStageIDLabel]),
+		App:       models.NewAppRef(job.Labels["app.kubernetes.io/name"], namespaceOf(job)),
+		Status:    status,
+		Position:  position,
+		CreatedAt: job.CreationTimestamp.Format(time.RFC3339),
+	}
+}
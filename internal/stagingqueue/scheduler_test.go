@@ -0,0 +1,128 @@
+package stagingqueue_test
+
+import (
+	"time"
+
+	. "github.com/epinio/epinio/internal/stagingqueue"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scheduler", func() {
+	start := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// job returns a staging job for the app in the namespace, created the given number of
+	// seconds after start.
+	job := func(namespace, app string, created int, suspended bool) batchv1.Job {
+		return batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "stage-" + namespace + "-" + app,
+				CreationTimestamp: metav1.NewTime(start.Add(time.Duration(created) * time.Second)),
+				Labels: map[string]string{
+					"app.kubernetes.io/name":    app,
+					"app.kubernetes.io/part-of": namespace,
+					models.EpinioStageIDLabel:   app + "-id",
+				},
+			},
+			Spec: batchv1.JobSpec{
+				Suspend: pointer.Bool(suspended),
+			},
+		}
+	}
+
+	done := func(j batchv1.Job) batchv1.Job {
+		j.Status.Conditions = []batchv1.JobCondition{{
+			Type:   batchv1.JobComplete,
+			Status: corev1.ConditionTrue,
+		}}
+		return j
+	}
+
+	names := func(jobs []batchv1.Job) []string {
+		result := []string{}
+		for _, j := range jobs {
+			result = append(result, j.Labels["app.kubernetes.io/name"])
+		}
+		return result
+	}
+
+	Describe("Plan", func() {
+		It("resumes queued jobs in FIFO order up to the global limit", func() {
+			jobs := []batchv1.Job{
+				job("ns", "c", 3, true),
+				job("ns", "a", 1, true),
+				job("ns", "b", 2, true),
+				job("ns", "running", 0, false),
+			}
+
+			resume, queued := Plan(jobs, Limits{Global: 3})
+			Expect(names(resume)).To(Equal([]string{"a", "b"}))
+			Expect(names(queued)).To(Equal([]string{"c"}))
+		})
+
+		It("ignores finished jobs", func() {
+			jobs := []batchv1.Job{
+				done(job("ns", "finished", 0, false)),
+				job("ns", "a", 1, true),
+			}
+
+			resume, queued := Plan(jobs, Limits{Global: 1})
+			Expect(names(resume)).To(Equal([]string{"a"}))
+			Expect(queued).To(BeEmpty())
+		})
+
+		It("does not let a full namespace hold back other namespaces", func() {
+			jobs := []batchv1.Job{
+				job("busy", "running", 0, false),
+				job("busy", "a", 1, true),
+				job("quiet", "b", 2, true),
+			}
+
+			resume, queued := Plan(jobs, Limits{Namespace: 1})
+			Expect(names(resume)).To(Equal([]string{"b"}))
+			Expect(names(queued)).To(Equal([]string{"a"}))
+		})
+
+		It("applies both limits", func() {
+			jobs := []batchv1.Job{
+				job("one", "a", 1, true),
+				job("one", "b", 2, true),
+				job("two", "c", 3, true),
+				job("three", "d", 4, true),
+			}
+
+			resume, queued := Plan(jobs, Limits{Global: 2, Namespace: 1})
+			Expect(names(resume)).To(Equal([]string{"a", "c"}))
+			Expect(names(queued)).To(Equal([]string{"b", "d"}))
+		})
+	})
+
+	Describe("Builds", func() {
+		It("reports the running and queued builds of the namespace, with cluster-wide positions", func() {
+			jobs := []batchv1.Job{
+				job("other", "x", 1, true),
+				job("ns", "b", 2, true),
+				job("ns", "a", 0, false),
+				done(job("ns", "finished", 0, false)),
+			}
+
+			builds := Builds(jobs, "ns")
+			Expect(builds).To(HaveLen(2))
+
+			Expect(builds[0].App.Name).To(Equal("a"))
+			Expect(builds[0].Status).To(Equal(models.StagingRunning))
+			Expect(builds[0].Position).To(Equal(0))
+
+			Expect(builds[1].App.Name).To(Equal("b"))
+			Expect(builds[1].Stage.ID).To(Equal("b-id"))
+			Expect(builds[1].Status).To(Equal(models.StagingQueued))
+			Expect(builds[1].Position).To(Equal(2))
+		})
+	})
+})
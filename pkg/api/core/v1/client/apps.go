@@ -120,11 +120,51 @@ func (c *Client) AllApps() (models.AppList, error) {
 	return resp, nil
 }
 
-// AppShow shows an app
+// AppShow shows an app. The values of its secret env vars are masked.
 func (c *Client) AppShow(namespace string, appName string) (models.App, error) {
+	return c.appShow(api.Routes.Path("AppShow", namespace, appName))
+}
+
+// AppShowRevealed shows an app, with the values of its secret env vars.
+func (c *Client) AppShowRevealed(namespace string, appName string) (models.App, error) {
+	return c.appShow(api.Routes.Path("AppShow", namespace, appName) + "?reveal=true")
+}
+
+func (c *Client) appShow(endpoint string) (models.App, error) {
 	var resp models.App
 
-	data, err := c.get(api.Routes.Path("AppShow", namespace, appName))
+	data, err := c.get(endpoint)
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppMetrics returns the recorded history of the resource usage of an app
+func (c *Client) AppMetrics(namespace string, appName string, since string, step string) (models.AppMetricsResponse, error) {
+	var resp models.AppMetricsResponse
+
+	queryParams := url.Values{}
+	if since != "" {
+		queryParams.Add("since", since)
+	}
+	if step != "" {
+		queryParams.Add("step", step)
+	}
+
+	endpoint := api.Routes.Path("AppMetrics", namespace, appName)
+	if len(queryParams) > 0 {
+		endpoint = fmt.Sprintf("%s?%s", endpoint, queryParams.Encode())
+	}
+
+	data, err := c.get(endpoint)
 	if err != nil {
 		return resp, err
 	}
@@ -258,11 +298,39 @@ func (c *Client) AppDelete(namespace string, name string) (models.ApplicationDel
 	return resp, nil
 }
 
+// AppProtect sets or clears the protection of an app against deletion
+func (c *Client) AppProtect(namespace string, name string, protected bool) (models.Response, error) {
+	resp := models.Response{}
+
+	b, err := json.Marshal(models.ProtectionRequest{Protected: protected})
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := c.do(api.Routes.Path("AppProtection", namespace, name), "PUT", string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
 // AppUpload uploads a tarball for the named app, which is later used in staging
-func (c *Client) AppUpload(namespace string, name string, tarball string) (models.UploadResponse, error) {
+func (c *Client) AppUpload(namespace string, name string, tarball string, sourceHash string) (models.UploadResponse, error) {
 	resp := models.UploadResponse{}
 
-	data, err := c.upload(api.Routes.Path("AppUpload", namespace, name), tarball)
+	endpoint := api.Routes.Path("AppUpload", namespace, name)
+	if sourceHash != "" {
+		endpoint = fmt.Sprintf("%s?hash=%s", endpoint, url.QueryEscape(sourceHash))
+	}
+
+	data, err := c.upload(endpoint, tarball)
 	if err != nil {
 		return resp, errors.Wrap(err, "can't upload archive")
 	}
@@ -276,11 +344,45 @@ func (c *Client) AppUpload(namespace string, name string, tarball string) (model
 	return resp, nil
 }
 
-// AppImportGit asks the server to import a git repo and put in into the blob store
-func (c *Client) AppImportGit(app models.AppRef, gitRef models.GitRef) (*models.ImportGitResponse, error) {
+// AppUploadMatch asks the server for the stored sources of the app with the hash, and for
+// a stage built from them with the requested settings
+func (c *Client) AppUploadMatch(app models.AppRef, req models.UploadMatchRequest) (models.UploadMatchResponse, error) {
+	resp := models.UploadMatchResponse{}
+
+	out, err := json.Marshal(req)
+	if err != nil {
+		return resp, errors.Wrap(err, "can't marshal upload match request")
+	}
+
+	data, err := c.post(api.Routes.Path("AppUploadMatch", app.Namespace, app.Name), string(out))
+	if err != nil {
+		return resp, errors.Wrap(err, "can't match sources")
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, errors.Wrap(err, "response body is not JSON")
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppImportGit asks the server to import a git repo and put in into the blob store. The
+// import runs in the background, the returned operation tracks it.
+func (c *Client) AppImportGit(app models.AppRef, gitRef models.GitRef) (*models.Operation, error) {
 	data := url.Values{}
 	data.Set("giturl", gitRef.URL)
 	data.Set("gitrev", gitRef.Revision)
+	if gitRef.Credentials != "" {
+		data.Set("gitcredentials", gitRef.Credentials)
+	}
+	if gitRef.Subdirectory != "" {
+		data.Set("gitsubdir", gitRef.Subdirectory)
+	}
+	if gitRef.Submodules {
+		data.Set("gitsubmodules", "true")
+	}
 
 	url := fmt.Sprintf("%s%s/%s", c.URL, api.Root, api.Routes.Path("AppImportGit", app.Namespace, app.Name))
 	request, err := http.NewRequest("POST", url, strings.NewReader(data.Encode()))
@@ -306,7 +408,7 @@ func (c *Client) AppImportGit(app models.AppRef, gitRef models.GitRef) (*models.
 			string(bodyBytes))
 	}
 
-	resp := &models.ImportGitResponse{}
+	resp := &models.Operation{}
 	if err := json.Unmarshal(bodyBytes, resp); err != nil {
 		return nil, err
 	}
@@ -422,6 +524,55 @@ func (c *Client) AppLogs(namespace, appName, stageID string, follow bool, printC
 	}
 }
 
+// AppEvents streams the events of the applications in the namespace, restricted to the
+// named application, if any, calling the callback for each event. It returns when the
+// server closes the connection.
+func (c *Client) AppEvents(namespace, appName string, callback func(models.AppEvent)) error {
+	token, err := c.AuthToken()
+	if err != nil {
+		return err
+	}
+
+	queryParams := url.Values{}
+	queryParams.Add("app", appName)
+	queryParams.Add("authtoken", token)
+
+	endpoint := api.WsRoutes.Path("AppEvents", namespace)
+
+	websocketURL := fmt.Sprintf("%s%s/%s?%s", c.WsURL, api.WsRoot, endpoint, queryParams.Encode())
+	webSocketConn, resp, err := websocket.DefaultDialer.Dial(websocketURL, http.Header{})
+	if err != nil {
+		// Report detailed error found in the server response
+		if resp != nil && resp.StatusCode != http.StatusOK {
+			defer resp.Body.Close()
+			bodyBytes, errBody := ioutil.ReadAll(resp.Body)
+
+			if errBody != nil {
+				return errBody
+			}
+
+			return formatError(bodyBytes, resp)
+		}
+
+		return errors.Wrap(err, fmt.Sprintf("Failed to connect to websockets endpoint. Response was = %+v\nThe error is", resp))
+	}
+	defer webSocketConn.Close()
+
+	for {
+		_, message, err := webSocketConn.ReadMessage()
+		if err != nil {
+			return nil
+		}
+
+		var event models.AppEvent
+		if err := json.Unmarshal(message, &event); err != nil {
+			return errors.Wrap(err, "error parsing event")
+		}
+
+		callback(event)
+	}
+}
+
 // StagingComplete checks if the staging process is complete
 func (c *Client) StagingComplete(namespace string, id string) (models.Response, error) {
 	resp := models.Response{}
@@ -437,8 +588,9 @@ func (c *Client) StagingComplete(namespace string, id string) (models.Response,
 			return err
 		},
 		retry.RetryIf(func(err error) bool {
-			// Bail out early when staging failed - Do not retry
-			if strings.Contains(err.Error(), "Failed to stage") {
+			// Bail out early when staging failed, or was cancelled - Do not retry
+			if strings.Contains(err.Error(), "Failed to stage") ||
+				strings.Contains(err.Error(), "Staging was cancelled") {
 				return false
 			}
 			if r, ok := err.(interface{ StatusCode() int }); ok {
@@ -471,6 +623,42 @@ func (c *Client) StagingComplete(namespace string, id string) (models.Response,
 	return resp, nil
 }
 
+// StagingStatus returns the running and queued staging runs of the namespace
+func (c *Client) StagingStatus(namespace string) (models.StagingStatusResponse, error) {
+	resp := models.StagingStatusResponse{}
+
+	data, err := c.get(api.Routes.Path("StagingStatus", namespace))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// StagingCancel stops the identified staging run
+func (c *Client) StagingCancel(namespace string, id string) (models.Response, error) {
+	resp := models.Response{}
+
+	data, err := c.delete(api.Routes.Path("StagingCancel", namespace, id))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
 // AppRunning checks if the app is running
 func (c *Client) AppRunning(app models.AppRef) (models.Response, error) {
 	resp := models.Response{}
@@ -652,3 +840,117 @@ func (c *Client) AppRestart(namespace string, appName string) error {
 
 	return nil
 }
+
+// AppBuildCache returns the details of the build cache of the named application
+func (c *Client) AppBuildCache(namespace string, appName string) (models.BuildCache, error) {
+	resp := models.BuildCache{}
+
+	data, err := c.get(api.Routes.Path("AppBuildCache", namespace, appName))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppSBOM returns the SBOM and provenance of the running image of the named application,
+// or of the image of the identified staging run
+func (c *Client) AppSBOM(namespace string, appName string, stageID string) (models.AppSBOM, error) {
+	resp := models.AppSBOM{}
+
+	endpoint := api.Routes.Path("AppSBOM", namespace, appName)
+	if stageID != "" {
+		endpoint = fmt.Sprintf("%s?%s", endpoint, url.Values{"stage": []string{stageID}}.Encode())
+	}
+
+	data, err := c.get(endpoint)
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppBuildCachePurge removes the build cache of the named application
+func (c *Client) AppBuildCachePurge(namespace string, appName string) (models.Response, error) {
+	resp := models.Response{}
+
+	data, err := c.delete(api.Routes.Path("AppBuildCachePurge", namespace, appName))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppWebhook returns the git webhook of the named application
+func (c *Client) AppWebhook(namespace string, appName string) (models.AppWebhook, error) {
+	resp := models.AppWebhook{}
+
+	data, err := c.get(api.Routes.Path("AppWebhook", namespace, appName))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded")
+
+	return resp, nil
+}
+
+// AppWebhookEnable enables the git webhook of the named application
+func (c *Client) AppWebhookEnable(namespace string, appName string) (models.AppWebhook, error) {
+	resp := models.AppWebhook{}
+
+	data, err := c.post(api.Routes.Path("AppWebhookEnable", namespace, appName), "")
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded")
+
+	return resp, nil
+}
+
+// AppWebhookRotate replaces the secret of the git webhook of the named application
+func (c *Client) AppWebhookRotate(namespace string, appName string) (models.AppWebhook, error) {
+	resp := models.AppWebhook{}
+
+	data, err := c.post(api.Routes.Path("AppWebhookRotate", namespace, appName), "")
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded")
+
+	return resp, nil
+}
@@ -9,11 +9,21 @@ import (
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 )
 
-// EnvList returns a map of all env vars for an app
+// EnvList returns a map of all env vars for an app. The values of secret vars are masked.
 func (c *Client) EnvList(namespace string, appName string) (models.EnvVariableMap, error) {
+	return c.envList(api.Routes.Path("EnvList", namespace, appName))
+}
+
+// EnvListRevealed returns a map of all env vars for an app, with the values of secret
+// vars.
+func (c *Client) EnvListRevealed(namespace string, appName string) (models.EnvVariableMap, error) {
+	return c.envList(api.Routes.Path("EnvList", namespace, appName) + "?reveal=true")
+}
+
+func (c *Client) envList(endpoint string) (models.EnvVariableMap, error) {
 	var resp models.EnvVariableMap
 
-	data, err := c.get(api.Routes.Path("EnvList", namespace, appName))
+	data, err := c.get(endpoint)
 	if err != nil {
 		return resp, err
 	}
@@ -29,6 +39,15 @@ func (c *Client) EnvList(namespace string, appName string) (models.EnvVariableMa
 
 // EnvSet set env vars for an app
 func (c *Client) EnvSet(req models.EnvVariableMap, namespace string, appName string) (models.Response, error) {
+	return c.envSet(req, api.Routes.Path("EnvSet", namespace, appName))
+}
+
+// EnvSetSecret set env vars for an app, and marks them as secret
+func (c *Client) EnvSetSecret(req models.EnvVariableMap, namespace string, appName string) (models.Response, error) {
+	return c.envSet(req, api.Routes.Path("EnvSet", namespace, appName)+"?secret=true")
+}
+
+func (c *Client) envSet(req models.EnvVariableMap, endpoint string) (models.Response, error) {
 	resp := models.Response{}
 
 	b, err := json.Marshal(req)
@@ -36,7 +55,7 @@ func (c *Client) EnvSet(req models.EnvVariableMap, namespace string, appName str
 		return resp, nil
 	}
 
-	data, err := c.post(api.Routes.Path("EnvSet", namespace, appName), string(b))
+	data, err := c.post(endpoint, string(b))
 	if err != nil {
 		return resp, err
 	}
@@ -50,11 +69,43 @@ func (c *Client) EnvSet(req models.EnvVariableMap, namespace string, appName str
 	return resp, nil
 }
 
-// EnvShow shows an env variable
+// EnvSetFrom sets env variables to keys of configurations
+func (c *Client) EnvSetFrom(req models.EnvReferenceMap, namespace string, appName string) (models.Response, error) {
+	resp := models.Response{}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, nil
+	}
+
+	data, err := c.post(api.Routes.Path("EnvSetFrom", namespace, appName), string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, errors.Wrap(err, "response body is not JSON")
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// EnvShow shows an env variable. The value of a secret var is masked.
 func (c *Client) EnvShow(namespace string, appName string, envName string) (models.EnvVariable, error) {
+	return c.envShow(api.Routes.Path("EnvShow", namespace, appName, envName))
+}
+
+// EnvShowRevealed shows an env variable, with the value of a secret var.
+func (c *Client) EnvShowRevealed(namespace string, appName string, envName string) (models.EnvVariable, error) {
+	return c.envShow(api.Routes.Path("EnvShow", namespace, appName, envName) + "?reveal=true")
+}
+
+func (c *Client) envShow(endpoint string) (models.EnvVariable, error) {
 	resp := models.EnvVariable{}
 
-	data, err := c.get(api.Routes.Path("EnvShow", namespace, appName, envName))
+	data, err := c.get(endpoint)
 	if err != nil {
 		return resp, err
 	}
@@ -86,6 +137,24 @@ func (c *Client) EnvUnset(namespace string, appName string, envName string) (mod
 	return resp, nil
 }
 
+// EnvStagingUnset removes a var from the staging environment of an app
+func (c *Client) EnvStagingUnset(namespace string, appName string, envName string) (models.Response, error) {
+	resp := models.Response{}
+
+	data, err := c.delete(api.Routes.Path("EnvStagingUnset", namespace, appName, envName))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
 // EnvMatch returns all env vars matching the prefix
 func (c *Client) EnvMatch(namespace string, appName string, prefix string) (models.EnvMatchResponse, error) {
 	resp := models.EnvMatchResponse{}
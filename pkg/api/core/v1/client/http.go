@@ -108,6 +108,38 @@ func (c *Client) upload(endpoint string, path string) ([]byte, error) {
 	return bodyBytes, nil
 }
 
+// put sends the size bytes of the reader as the raw body of a PUT request
+func (c *Client) put(endpoint string, data io.Reader, size int64) ([]byte, error) {
+	uri := fmt.Sprintf("%s%s/%s", c.URL, api.Root, endpoint)
+	c.log.Info(fmt.Sprintf("PUT %s", uri))
+
+	request, err := http.NewRequest("PUT", uri, data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build request")
+	}
+
+	request.SetBasicAuth(c.user, c.password)
+	request.Header.Add("Content-Type", "application/octet-stream")
+	request.ContentLength = size
+
+	response, err := (&http.Client{}).Do(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to PUT")
+	}
+	defer response.Body.Close()
+
+	bodyBytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, wrapResponseError(err, response.StatusCode)
+	}
+
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusCreated {
+		return bodyBytes, wrapResponseError(formatError(bodyBytes, response), response.StatusCode)
+	}
+
+	return bodyBytes, nil
+}
+
 func (c *Client) do(endpoint, method, requestBody string) ([]byte, error) {
 	uri := fmt.Sprintf("%s%s/%s", c.URL, api.Root, endpoint)
 	c.log.Info(fmt.Sprintf("%s %s", method, uri))
@@ -2,9 +2,16 @@ package client
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
 
 	api "github.com/epinio/epinio/internal/api/v1"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
 )
 
 // NamespaceCreate creates a namespace
@@ -48,6 +55,48 @@ func (c *Client) NamespaceDelete(namespace string) (models.Response, error) {
 	return resp, nil
 }
 
+// NamespaceDeletePreview returns what the deletion of the namespace would remove,
+// without deleting anything
+func (c *Client) NamespaceDeletePreview(namespace string) (models.NamespaceDeletePreview, error) {
+	resp := models.NamespaceDeletePreview{}
+
+	data, err := c.delete(api.Routes.Path("NamespaceDelete", namespace) + "?dryrun=true")
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// NamespaceProtect sets or clears the protection of a namespace against deletion
+func (c *Client) NamespaceProtect(namespace string, protected bool) (models.Response, error) {
+	resp := models.Response{}
+
+	b, err := json.Marshal(models.ProtectionRequest{Protected: protected})
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := c.do(api.Routes.Path("NamespaceProtection", namespace), "PUT", string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
 // NamespaceShow shows a namespace
 func (c *Client) NamespaceShow(namespace string) (models.Namespace, error) {
 	resp := models.Namespace{}
@@ -101,3 +150,182 @@ func (c *Client) Namespaces() (models.NamespaceList, error) {
 
 	return resp, nil
 }
+
+// RegistryCredentials returns the registry credentials of a namespace
+func (c *Client) RegistryCredentials(namespace string) (models.RegistryCredentialList, error) {
+	resp := models.RegistryCredentialList{}
+
+	data, err := c.get(api.Routes.Path("RegistryCredentials", namespace))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// RegistryCredentialCreate adds a registry credential to a namespace
+func (c *Client) RegistryCredentialCreate(req models.RegistryCredentialCreateRequest, namespace string) (models.Response, error) {
+	var resp models.Response
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := c.post(api.Routes.Path("RegistryCredentialCreate", namespace), string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// RegistryCredentialDelete removes a registry credential from a namespace
+func (c *Client) RegistryCredentialDelete(namespace string, name string) (models.Response, error) {
+	resp := models.Response{}
+
+	data, err := c.delete(api.Routes.Path("RegistryCredentialDelete", namespace, name))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// NamespaceQuota returns the quota of a namespace and the use of the limited resources
+func (c *Client) NamespaceQuota(namespace string) (models.NamespaceQuotaResponse, error) {
+	resp := models.NamespaceQuotaResponse{}
+
+	data, err := c.get(api.Routes.Path("NamespaceQuota", namespace))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// NamespaceQuotaSet replaces the quota of a namespace
+func (c *Client) NamespaceQuotaSet(req models.NamespaceQuota, namespace string) (models.Response, error) {
+	var resp models.Response
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := c.do(api.Routes.Path("NamespaceQuotaSet", namespace), "PUT", string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// NamespaceBackup stores the archive of a namespace in the destination file. The images
+// of the applications are included on request. An incomplete archive is removed.
+func (c *Client) NamespaceBackup(namespace, destinationPath string, images bool) error {
+	endpoint := api.Routes.Path("NamespaceBackup", namespace)
+	if images {
+		endpoint = fmt.Sprintf("%s?images=true", endpoint)
+	}
+	method := "GET"
+
+	// inlined c.get/c.do to stream the response into the file.
+	uri := fmt.Sprintf("%s%s/%s", c.URL, api.Root, endpoint)
+	c.log.Info(fmt.Sprintf("%s %s", method, uri))
+
+	reqLog := requestLogger(c.log, method, uri, "")
+
+	request, err := http.NewRequest(method, uri, nil)
+	if err != nil {
+		reqLog.V(1).Error(err, "cannot build request")
+		return err
+	}
+
+	request.SetBasicAuth(c.user, c.password)
+
+	response, err := (&http.Client{}).Do(request)
+	if err != nil {
+		reqLog.V(1).Error(err, "request failed")
+		return errors.Wrap(err, "making the request")
+	}
+	defer response.Body.Close()
+	reqLog.V(1).Info("request finished")
+
+	if response.StatusCode != http.StatusOK {
+		bodyBytes, _ := ioutil.ReadAll(response.Body)
+		return wrapResponseError(formatError(bodyBytes, response), response.StatusCode)
+	}
+
+	out, err := os.Create(destinationPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, response.Body)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(destinationPath)
+		return errors.Wrap(err, "storing the archive")
+	}
+
+	c.log.V(1).Info("response stored")
+
+	return nil
+}
+
+// NamespaceRestore recreates the namespace of the archive file, under its own name, or
+// the new name, if not empty
+func (c *Client) NamespaceRestore(archivePath, newName string) (models.NamespaceRestoreResponse, error) {
+	resp := models.NamespaceRestoreResponse{}
+
+	endpoint := api.Routes.Path("NamespaceRestore")
+	if newName != "" {
+		endpoint = fmt.Sprintf("%s?as=%s", endpoint, url.QueryEscape(newName))
+	}
+
+	data, err := c.upload(endpoint, archivePath)
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
@@ -0,0 +1,26 @@
+package client
+
+import (
+	"encoding/json"
+
+	api "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// Operation returns the state of the identified long-running operation
+func (c *Client) Operation(id string) (models.Operation, error) {
+	var resp models.Operation
+
+	data, err := c.get(api.Routes.Path("OperationShow", id))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"encoding/json"
+
+	api "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// Policies returns the policies applying to a namespace
+func (c *Client) Policies(namespace string) (models.PolicyList, error) {
+	resp := models.PolicyList{}
+
+	data, err := c.get(api.Routes.Path("Policies", namespace))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// PolicyShow returns the named policy applying to a namespace
+func (c *Client) PolicyShow(namespace string, name string) (models.Policy, error) {
+	resp := models.Policy{}
+
+	data, err := c.get(api.Routes.Path("PolicyShow", namespace, name))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// PolicyEvaluate evaluates the policies applying to a namespace against the application
+// settings, without changing anything
+func (c *Client) PolicyEvaluate(req models.PolicySubject, namespace string) (models.PolicyEvaluationResponse, error) {
+	resp := models.PolicyEvaluationResponse{}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := c.post(api.Routes.Path("PolicyEvaluate", namespace), string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
@@ -149,6 +149,29 @@ func (c *Client) ServiceDelete(req models.ServiceDeleteRequest, namespace string
 	return resp, nil
 }
 
+// ServiceProtect sets or clears the protection of a service against deletion
+func (c *Client) ServiceProtect(namespace string, name string, protected bool) (models.Response, error) {
+	resp := models.Response{}
+
+	b, err := json.Marshal(models.ProtectionRequest{Protected: protected})
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := c.do(api.Routes.Path("ServiceProtection", namespace, name), "PUT", string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
 func (c *Client) ServiceBind(req *models.ServiceBindRequest, namespace, name string) error {
 	b, err := json.Marshal(req)
 	if err != nil {
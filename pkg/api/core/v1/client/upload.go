@@ -0,0 +1,215 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	api "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// uploadPartSize is the size of the parts of chunked uploads.
+	uploadPartSize = 8 << 20
+
+	// uploadAttempts is the number of times a part is sent before the upload fails.
+	uploadAttempts = 5
+)
+
+// uploadRetryDelay is the delay before the first retry of a failed part. It doubles with
+// each further retry of the part.
+var uploadRetryDelay = 500 * time.Millisecond
+
+// UploadProgress is called during chunked uploads with the number of bytes uploaded so
+// far, and the total number of bytes to upload.
+type UploadProgress func(done, total int64)
+
+// AppUploadChunked uploads a tarball for the named app in parts, like AppUpload. A failed
+// part is sent again, resuming after the last part the server acknowledged. The progress,
+// if any, is reported as the bytes are sent.
+func (c *Client) AppUploadChunked(namespace string, name string, tarball string, sourceHash string, progress UploadProgress) (models.UploadResponse, error) {
+	resp := models.UploadResponse{}
+	app := models.NewAppRef(name, namespace)
+	if progress == nil {
+		progress = func(int64, int64) {}
+	}
+
+	file, err := os.Open(tarball)
+	if err != nil {
+		return resp, errors.Wrap(err, "failed to open tarball")
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return resp, errors.Wrap(err, "failed to read tarball size")
+	}
+
+	session, err := c.uploadSessionCreate(app, sourceHash)
+	if err != nil {
+		return resp, errors.Wrap(err, "can't start upload")
+	}
+
+	err = c.uploadParts(app, session, file, info.Size(), progress)
+	if err != nil {
+		// The stored parts are of no use anymore. Failing to discard them is not
+		// reported, the original error is more relevant.
+		_, _ = c.delete(sessionEndpoint("AppUploadSessionDelete", app, session))
+		return resp, errors.Wrap(err, "can't upload archive")
+	}
+
+	data, err := c.post(sessionEndpoint("AppUploadSessionComplete", app, session), "")
+	if err != nil {
+		return resp, errors.Wrap(err, "can't complete upload")
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, errors.Wrap(err, "response body is not JSON")
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// uploadParts sends the file in parts. After a failed part it asks the server for the
+// parts stored so far, and continues with the first part missing.
+func (c *Client) uploadParts(app models.AppRef, session models.UploadSession, file io.ReaderAt, total int64, progress UploadProgress) error {
+	count := int((total + uploadPartSize - 1) / uploadPartSize)
+	if count == 0 {
+		count = 1
+	}
+
+	attempts := 0
+	for number := 1; number <= count; number++ {
+		offset := int64(number-1) * uploadPartSize
+		size := partSize(number, total)
+
+		body := &countingReader{
+			reader: io.NewSectionReader(file, offset, size),
+			report: func(sent int64) { progress(offset+sent, total) },
+		}
+		endpoint := sessionEndpoint("AppUploadSessionPart", app, session, strconv.Itoa(number))
+
+		_, err := c.put(endpoint, body, size)
+		if err == nil {
+			attempts = 0
+			progress(offset+size, total)
+			continue
+		}
+
+		attempts++
+		if attempts >= uploadAttempts {
+			return errors.Wrapf(err, "sending part %d of %d", number, count)
+		}
+
+		c.log.Info("retrying upload", "part", number, "attempt", attempts, "error", err.Error())
+		time.Sleep(uploadRetryDelay * time.Duration(1<<(attempts-1)))
+
+		// Continue with the first part the server did not store. Without an answer
+		// from the server the failed part is sent again.
+		resume := number
+		if stored, err := c.uploadSessionShow(app, session); err == nil {
+			resume = firstMissingPart(stored.Parts, count, total)
+		}
+		if resume > number {
+			attempts = 0
+		}
+		progress(int64(resume-1)*uploadPartSize, total)
+		number = resume - 1
+	}
+
+	return nil
+}
+
+func (c *Client) uploadSessionCreate(app models.AppRef, sourceHash string) (models.UploadSession, error) {
+	session := models.UploadSession{}
+
+	out, err := json.Marshal(models.UploadSessionRequest{Hash: sourceHash})
+	if err != nil {
+		return session, errors.Wrap(err, "can't marshal upload session request")
+	}
+
+	data, err := c.post(api.Routes.Path("AppUploadSessionCreate", app.Namespace, app.Name), string(out))
+	if err != nil {
+		return session, err
+	}
+
+	if err := json.Unmarshal(data, &session); err != nil {
+		return session, errors.Wrap(err, "response body is not JSON")
+	}
+
+	c.log.V(1).Info("response decoded", "response", session)
+
+	return session, nil
+}
+
+func (c *Client) uploadSessionShow(app models.AppRef, session models.UploadSession) (models.UploadSession, error) {
+	stored := models.UploadSession{}
+
+	data, err := c.get(sessionEndpoint("AppUploadSessionShow", app, session))
+	if err != nil {
+		return stored, err
+	}
+
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return stored, errors.Wrap(err, "response body is not JSON")
+	}
+
+	c.log.V(1).Info("response decoded", "response", stored)
+
+	return stored, nil
+}
+
+// firstMissingPart returns the number of the first of the count parts which is not
+// stored with its full size.
+func firstMissingPart(parts []models.UploadPart, count int, total int64) int {
+	stored := map[int]int64{}
+	for _, part := range parts {
+		stored[part.Number] = part.Size
+	}
+
+	for number := 1; number <= count; number++ {
+		size, ok := stored[number]
+		if !ok || size != partSize(number, total) {
+			return number
+		}
+	}
+	return count + 1
+}
+
+// partSize returns the size of the numbered part, for an upload of total bytes.
+func partSize(number int, total int64) int64 {
+	size := total - int64(number-1)*uploadPartSize
+	if size > uploadPartSize {
+		size = uploadPartSize
+	}
+	return size
+}
+
+// sessionEndpoint returns the endpoint of the route for the upload session. The route
+// parameters after the blob follow the session.
+func sessionEndpoint(route string, app models.AppRef, session models.UploadSession, params ...interface{}) string {
+	params = append([]interface{}{app.Namespace, app.Name, session.BlobUID}, params...)
+	return fmt.Sprintf("%s?upload=%s", api.Routes.Path(route, params...), url.QueryEscape(session.UploadID))
+}
+
+// countingReader reports the number of bytes read so far.
+type countingReader struct {
+	reader io.Reader
+	read   int64
+	report func(int64)
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	r.report(r.read)
+	return n, err
+}
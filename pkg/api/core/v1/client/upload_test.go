@@ -0,0 +1,140 @@
+package client_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/client"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func DescribeAppUploadChunked() {
+
+	const size = 20 << 20 // 3 parts
+
+	var epinioClient *client.Client
+	var tmpDir, tarball string
+
+	// Requests received by the server, as `METHOD last-path-element`.
+	var mu sync.Mutex
+	var requests []string
+	var stored map[int]int64
+
+	// failPart fails the first PUT of the part. With storeFailed the part is
+	// stored nevertheless, like for a response lost on the way back.
+	var failPart int
+	var storeFailed bool
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "epinio-upload")
+		Expect(err).ToNot(HaveOccurred())
+		tarball = filepath.Join(tmpDir, "blob.tar")
+		Expect(ioutil.WriteFile(tarball, make([]byte, size), 0600)).To(Succeed())
+
+		requests = []string{}
+		stored = map[int]int64{}
+		failPart = 0
+		storeFailed = false
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			elements := strings.Split(r.URL.Path, "/")
+			last := elements[len(elements)-1]
+			requests = append(requests, r.Method+" "+last)
+
+			switch {
+			case r.Method == "POST" && last == "sessions":
+				fmt.Fprint(w, `{"blobuid":"blob","uploadid":"up/1"}`)
+			case r.Method == "PUT":
+				Expect(r.URL.Query().Get("upload")).To(Equal("up/1"))
+				number, _ := strconv.Atoi(last)
+				n, _ := io.Copy(ioutil.Discard, r.Body)
+				if number == failPart {
+					failPart = 0
+					if storeFailed {
+						stored[number] = n
+					}
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				stored[number] = n
+				fmt.Fprintf(w, `{"number":%d,"size":%d}`, number, n)
+			case r.Method == "GET":
+				session := models.UploadSession{BlobUID: "blob", UploadID: "up/1"}
+				for number, n := range stored {
+					session.Parts = append(session.Parts, models.UploadPart{Number: number, Size: n})
+				}
+				Expect(json.NewEncoder(w).Encode(session)).To(Succeed())
+			case r.Method == "POST" && last == "complete":
+				fmt.Fprint(w, `{"blobuid":"blob"}`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		DeferCleanup(srv.Close)
+
+		epinioClient = client.New(srv.URL, "", "", "")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	upload := func() (int64, int64) {
+		var done, total int64
+		resp, err := epinioClient.AppUploadChunked("namespace-foo", "appname", tarball, "hash", func(d, t int64) {
+			done, total = d, t
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.BlobUID).To(Equal("blob"))
+		return done, total
+	}
+
+	When("all parts are sent", func() {
+		It("uploads the tarball in parts, reporting the progress", func() {
+			done, total := upload()
+			Expect(done).To(Equal(int64(size)))
+			Expect(total).To(Equal(int64(size)))
+
+			Expect(requests).To(Equal([]string{"POST sessions", "PUT 1", "PUT 2", "PUT 3", "POST complete"}))
+			Expect(stored).To(Equal(map[int]int64{1: 8 << 20, 2: 8 << 20, 3: 4 << 20}))
+		})
+	})
+
+	When("a part fails", func() {
+		BeforeEach(func() {
+			failPart = 2
+		})
+
+		It("resumes with the failed part", func() {
+			upload()
+			Expect(requests).To(Equal([]string{"POST sessions", "PUT 1", "PUT 2", "GET blob", "PUT 2", "PUT 3", "POST complete"}))
+		})
+	})
+
+	When("the answer for a stored part is lost", func() {
+		BeforeEach(func() {
+			failPart = 2
+			storeFailed = true
+		})
+
+		It("resumes after the last part the server acknowledged", func() {
+			upload()
+			Expect(requests).To(Equal([]string{"POST sessions", "PUT 1", "PUT 2", "GET blob", "PUT 3", "POST complete"}))
+		})
+	})
+}
@@ -148,6 +148,31 @@ func ServiceIsNotKnown(service string) APIError {
 		http.StatusNotFound)
 }
 
+// NamespaceIsProtected constructs an API error for when the namespace to delete, or a
+// resource in it, is protected
+func NamespaceIsProtected(namespace string) APIError {
+	return NewAPIError(
+		fmt.Sprintf("Namespace '%s' is protected", namespace),
+		"clear the protection to delete it",
+		http.StatusForbidden)
+}
+
+// AppIsProtected constructs an API error for when the app to delete is protected
+func AppIsProtected(app string) APIError {
+	return NewAPIError(
+		fmt.Sprintf("Application '%s' is protected", app),
+		"clear the protection to delete it",
+		http.StatusForbidden)
+}
+
+// ServiceIsProtected constructs an API error for when the service to delete is protected
+func ServiceIsProtected(service string) APIError {
+	return NewAPIError(
+		fmt.Sprintf("Service '%s' is protected", service),
+		"clear the protection to delete it",
+		http.StatusForbidden)
+}
+
 // ConfigurationIsNotKnown constructs an API error for when the desired configuration instance does not exist
 func ConfigurationIsNotKnown(configuration string) APIError {
 	return NewAPIError(
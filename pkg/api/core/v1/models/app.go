@@ -17,9 +17,19 @@ const (
 
 type ApplicationStatus string
 
+// GitRef references a revision of a git repository. The revision is a branch, tag, or
+// commit SHA. Credentials is the name of the secret in the application's namespace
+// providing access to a private repository. Subdirectory is the directory of the
+// application sources in the repository, and Submodules requests their checkout.
+// Commit is the commit the revision resolved to when the sources were imported. It is
+// recorded by the server.
 type GitRef struct {
-	Revision string `json:"revision,omitempty" yaml:"revision,omitempty"`
-	URL      string `json:"repository"         yaml:"url"`
+	Revision     string `json:"revision,omitempty"     yaml:"revision,omitempty"`
+	URL          string `json:"repository"             yaml:"url"`
+	Credentials  string `json:"credentials,omitempty"  yaml:"credentials,omitempty"`
+	Subdirectory string `json:"subdirectory,omitempty" yaml:"subdirectory,omitempty"`
+	Submodules   bool   `json:"submodules,omitempty"   yaml:"submodules,omitempty"`
+	Commit       string `json:"commit,omitempty"       yaml:"-"`
 }
 
 // App has all the application's properties, for at rest (Configuration), and active (Workload).
@@ -36,6 +46,7 @@ type App struct {
 	StatusMessage string                   `json:"statusmessage"`
 	StageID       string                   `json:"stage_id,omitempty"` // staging id, last run
 	ImageURL      string                   `json:"image_url"`
+	Protected     bool                     `json:"protected,omitempty"` // protected against deletion
 }
 
 type PodInfo struct {
@@ -47,6 +58,23 @@ type PodInfo struct {
 	Ready       bool   `json:"ready"`
 }
 
+// AppMetricSample contains the resources used by all instances of an application at a
+// point in time.
+type AppMetricSample struct {
+	Timestamp   string `json:"timestamp"`
+	MilliCPUs   int64  `json:"millicpus"`
+	MemoryBytes int64  `json:"memoryBytes"`
+	Instances   int    `json:"instances"`
+}
+
+// AppMetricsResponse contains the recorded history of an application's resource usage,
+// oldest sample first.
+type AppMetricsResponse struct {
+	Since   string            `json:"since"`
+	Step    string            `json:"step"`
+	Samples []AppMetricSample `json:"samples"`
+}
+
 // AppDeployment contains all the information specific to an active
 // application, i.e. one with a deployment in the cluster.
 type AppDeployment struct {
@@ -142,6 +170,24 @@ func (ar *AppRef) MakeEnvSecretName() string {
 	return names.GenerateResourceName(ar.Name + "-env")
 }
 
+// MakeEnvFromSecretName returns the name of the kube secret holding the
+// environment variables of the referenced application which are sourced from configurations
+func (ar *AppRef) MakeEnvFromSecretName() string {
+	return names.GenerateResourceName(ar.Name + "-envfrom")
+}
+
+// MakeStagingEnvSecretName returns the name of the kube secret holding the
+// build-time environment variables of the referenced application
+func (ar *AppRef) MakeStagingEnvSecretName() string {
+	return names.GenerateResourceName(ar.Name + "-stagingenv")
+}
+
+// MakeWebhookSecretName returns the name of the kube secret holding the
+// signing secret of the git webhook of the referenced application
+func (ar *AppRef) MakeWebhookSecretName() string {
+	return names.GenerateResourceName(ar.Name + "-webhook")
+}
+
 // MakeConfigurationSecretName returns the name of the kube secret holding the
 // bound configurations of the referenced application
 func (ar *AppRef) MakeConfigurationSecretName() string {
@@ -8,6 +8,9 @@ import (
 // This subsection of models provides structures related to the
 // environment variables of applications.
 
+// EnvMaskedValue replaces the value of secret EVs in listings, unless revealed.
+const EnvMaskedValue = "********"
+
 // EnvVariable represents the Show Response for a single environment variable
 type EnvVariable struct {
 	Name  string `json:"name"`
@@ -21,6 +24,17 @@ type EnvVariableList []EnvVariable
 // List Responses
 type EnvVariableMap map[string]string
 
+// EnvConfigurationRef references a key of a configuration. The value of an EV sourced
+// from a configuration is the value of that key.
+type EnvConfigurationRef struct {
+	Configuration string `json:"configuration" yaml:"configuration"`
+	Key           string `json:"key"           yaml:"key"`
+}
+
+// EnvReferenceMap is a collection of EVs sourced from configurations, keyed by EV name.
+// It is used for Set From Requests, and in the application configuration.
+type EnvReferenceMap map[string]EnvConfigurationRef
+
 // EnvVarnameList is a collection of EV names, it is used for Unset Requests, and as Match
 // Responses
 type EnvVarnameList []string
@@ -37,6 +51,30 @@ func (evm EnvVariableMap) List() EnvVariableList {
 	return result
 }
 
+// Names returns the sorted names of the referencing EVs.
+func (erm EnvReferenceMap) Names() []string {
+	result := []string{}
+	for name := range erm {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Assignments returns the EVs as kube container env entries, sourcing the value from
+// the secret of the referenced configuration.
+func (erm EnvReferenceMap) Assignments() []string {
+	assignments := []string{}
+
+	for _, name := range erm.Names() {
+		ref := erm[name]
+		assignments = append(assignments, fmt.Sprintf(`{"name":"%s","valueFrom":{"secretKeyRef":{"name":"%s","key":"%s"}}}`,
+			name, ref.Configuration, ref.Key))
+	}
+
+	return assignments
+}
+
 // Implement the Sort interface for EV definition slices
 
 // Len (Sort interface) returns the length of the EnvVariableList
@@ -0,0 +1,31 @@
+package models
+
+// AppEventType is the kind of change an AppEvent reports.
+type AppEventType string
+
+const (
+	AppEventCreated            AppEventType = "app-created"
+	AppEventStagingStarted     AppEventType = "staging-started"
+	AppEventStagingFinished    AppEventType = "staging-finished"
+	AppEventStagingFailed      AppEventType = "staging-failed"
+	AppEventDeployed           AppEventType = "deployed"
+	AppEventScaled             AppEventType = "scaled"
+	AppEventReplicaReady       AppEventType = "replica-ready"
+	AppEventReplicaCrashed     AppEventType = "replica-crashed"
+	AppEventConfigurationBound AppEventType = "configuration-bound"
+)
+
+// AppEvent is a change in the state of an application, as pushed by the events
+// websocket of a namespace. Depending on the type of the event the optional fields
+// carry the id of the staging run, the name of the replica, the desired number of
+// instances, or the name of the bound configuration.
+type AppEvent struct {
+	Type          AppEventType `json:"type"`
+	Time          string       `json:"time"`
+	App           AppRef       `json:"app"`
+	StageID       string       `json:"stageId,omitempty"`
+	Replica       string       `json:"replica,omitempty"`
+	Instances     int32        `json:"instances,omitempty"`
+	Configuration string       `json:"configuration,omitempty"`
+	Message       string       `json:"message,omitempty"`
+}
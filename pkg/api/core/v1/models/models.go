@@ -8,6 +8,7 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/epinio/epinio/helpers"
@@ -107,10 +108,30 @@ type ApplicationManifest struct {
 }
 
 // ApplicationStage is the part of the manifest holding information
-// relevant to staging the application's sources. This is, currently,
-// only the reference to the Paketo builder image to use.
+// relevant to staging the application's sources. This is the
+// strategy to build with, the reference to the Paketo builder image
+// to use, the Dockerfile settings, and the environment variables
+// given to the builder only, not to the running application.
 type ApplicationStage struct {
-	Builder string `yaml:"builder,omitempty"`
+	Strategy    string           `yaml:"strategy,omitempty"`
+	Builder     string           `yaml:"builder,omitempty"`
+	Dockerfile  *DockerfileBuild `yaml:"dockerfile,omitempty"`
+	Environment EnvVariableMap   `yaml:"environment,omitempty"`
+}
+
+// Staging strategies. Without a strategy the sources are built with buildpacks.
+const (
+	StagingStrategyBuildpacks = "buildpacks"
+	StagingStrategyDockerfile = "dockerfile"
+)
+
+// DockerfileBuild holds the settings of a build from a Dockerfile. The path of the
+// Dockerfile is relative to the root of the sources, and defaults to `Dockerfile`. The
+// target selects the stage of a multi-stage Dockerfile to build, defaulting to the last.
+type DockerfileBuild struct {
+	Path      string            `json:"path,omitempty"      yaml:"path,omitempty"`
+	BuildArgs map[string]string `json:"buildArgs,omitempty" yaml:"buildArgs,omitempty"`
+	Target    string            `json:"target,omitempty"    yaml:"target,omitempty"`
 }
 
 // ApplicationOrigin is the part of the manifest describing the origin of the application
@@ -163,16 +184,65 @@ type ApplicationCreateRequest struct {
 // run, and the configurations bound to it.
 // Note: Instances is a pointer to give us a nil value separate from
 // actual integers, as means of communicating `default`/`no change`.
+// StagingEnvironment is hidden from the yaml, as the manifest carries it in the
+// `staging` section. It is a pointer for the same reason as Instances: nil is `no
+// change`, while an empty map clears the staging environment.
 type ApplicationUpdateRequest struct {
-	Instances      *int32         `json:"instances"          yaml:"instances,omitempty"`
-	Configurations []string       `json:"configurations"     yaml:"configurations,omitempty"`
-	Environment    EnvVariableMap `json:"environment"        yaml:"environment,omitempty"`
-	Routes         []string       `json:"routes"             yaml:"routes,omitempty"`
-	AppChart       string         `json:"appchart,omitempty" yaml:"appchart,omitempty"`
+	Instances          *int32          `json:"instances"                    yaml:"instances,omitempty"`
+	Configurations     []string        `json:"configurations"               yaml:"configurations,omitempty"`
+	Environment        EnvVariableMap  `json:"environment"                  yaml:"environment,omitempty"`
+	EnvironmentFrom    EnvReferenceMap `json:"environmentFrom,omitempty"    yaml:"environmentFrom,omitempty"`
+	StagingEnvironment *EnvVariableMap `json:"stagingEnvironment,omitempty" yaml:"-"`
+	Routes             []string        `json:"routes"                       yaml:"routes,omitempty"`
+	AppChart           string          `json:"appchart,omitempty"           yaml:"appchart,omitempty"`
 }
 
 type ImportGitResponse struct {
 	BlobUID string `json:"blobuid,omitempty"`
+	Commit  string `json:"commit,omitempty"`
+}
+
+// Operation kinds
+const (
+	OperationImportGit = "import-git"
+)
+
+// Operation states
+const (
+	OperationPending   = "pending"
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+)
+
+// Operation is a long-running operation of the server, run in the background. Step is
+// the step a running operation is at. Result is set when the operation succeeded, Error
+// when it failed. The type of the result depends on the kind of the operation, e.g. an
+// ImportGitResponse for import-git. See DecodeResult.
+type Operation struct {
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	Namespace string          `json:"namespace"`
+	App       string          `json:"app"`
+	State     string          `json:"state"`
+	Step      string          `json:"step,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	CreatedAt string          `json:"createdAt"`
+	UpdatedAt string          `json:"updatedAt"`
+	Result    json.RawMessage `json:"result,omitempty"`
+}
+
+// DecodeResult decodes the result of the succeeded operation into the value pointed to.
+func (o Operation) DecodeResult(result interface{}) error {
+	if len(o.Result) == 0 {
+		return fmt.Errorf("operation %s has no result", o.ID)
+	}
+	return json.Unmarshal(o.Result, result)
+}
+
+// Finished returns true if the operation succeeded or failed.
+func (o Operation) Finished() bool {
+	return o.State == OperationSucceeded || o.State == OperationFailed
 }
 
 // UploadRequest is a multipart form
@@ -182,17 +252,125 @@ type UploadResponse struct {
 	BlobUID string `json:"blobuid,omitempty"`
 }
 
-// StageRequest represents and contains the data needed to stage an application
+// Limits of the parts of chunked uploads. All parts but the last have to be at least
+// MinUploadPartSize bytes.
+const (
+	MinUploadPartSize = 5 << 20
+	MaxUploadPartSize = 64 << 20
+)
+
+// UploadSessionRequest starts a chunked upload of the application's sources. The hash of
+// the sources is recorded with the blob, as for a regular upload.
+type UploadSessionRequest struct {
+	Hash string `json:"hash,omitempty"`
+}
+
+// UploadSession is a chunked upload of the application's sources into the blob. Parts
+// are the parts stored so far, numbered from 1.
+type UploadSession struct {
+	BlobUID  string       `json:"blobuid"`
+	UploadID string       `json:"uploadid"`
+	Parts    []UploadPart `json:"parts,omitempty"`
+}
+
+// UploadPart is a stored part of a chunked upload.
+type UploadPart struct {
+	Number int   `json:"number"`
+	Size   int64 `json:"size"`
+}
+
+// UploadMatchRequest asks for the stored blob of the application's sources with the
+// hash, and for a stage built from it with the staging settings. The staging settings
+// are as for a StageRequest.
+type UploadMatchRequest struct {
+	Hash         string           `json:"hash"`
+	BuilderImage string           `json:"builderimage,omitempty"`
+	Strategy     string           `json:"strategy,omitempty"`
+	Dockerfile   *DockerfileBuild `json:"dockerfile,omitempty"`
+}
+
+// UploadMatchResponse represents the server's response to an UploadMatchRequest. An
+// empty BlobUID means that the sources have to be uploaded. An empty StageID means that
+// the sources have to be staged. Otherwise ImageURL is the image built by the stage.
+type UploadMatchResponse struct {
+	BlobUID  string `json:"blobuid,omitempty"`
+	StageID  string `json:"stageid,omitempty"`
+	ImageURL string `json:"image,omitempty"`
+}
+
+// StageRequest represents and contains the data needed to stage an application.
+// Without a strategy, builder image, and Dockerfile settings, those of the
+// application's previous staging are used.
 type StageRequest struct {
-	App          AppRef `json:"app,omitempty"`
-	BlobUID      string `json:"blobuid,omitempty"`
-	BuilderImage string `json:"builderimage,omitempty"`
+	App          AppRef           `json:"app,omitempty"`
+	BlobUID      string           `json:"blobuid,omitempty"`
+	BuilderImage string           `json:"builderimage,omitempty"`
+	Strategy     string           `json:"strategy,omitempty"`
+	Dockerfile   *DockerfileBuild `json:"dockerfile,omitempty"`
 }
 
-// StageResponse represents the server's response to a successful app staging
+// StageResponse represents the server's response to a successful app staging.
+// QueuePosition is the place of the staging run in the queue of builds waiting to
+// run, starting at 1. It is 0 when the build is running.
 type StageResponse struct {
-	Stage    StageRef `json:"stage,omitempty"`
-	ImageURL string   `json:"image,omitempty"`
+	Stage         StageRef `json:"stage,omitempty"`
+	ImageURL      string   `json:"image,omitempty"`
+	QueuePosition int      `json:"queuePosition,omitempty"`
+}
+
+// Status values of staging builds
+const (
+	StagingQueued  = "queued"
+	StagingRunning = "running"
+)
+
+// StagingBuild describes a running or queued staging run. Position is the place of
+// a queued run in the cluster-wide queue, starting at 1.
+type StagingBuild struct {
+	Stage     StageRef `json:"stage"`
+	App       AppRef   `json:"app"`
+	Status    string   `json:"status"`
+	Position  int      `json:"position,omitempty"`
+	CreatedAt string   `json:"createdAt,omitempty"`
+}
+
+// StagingStatusResponse represents the server's response to a query for the running and
+// queued staging runs of a namespace, and the limits on concurrently running builds.
+// Limits of zero mean unlimited.
+type StagingStatusResponse struct {
+	MaxConcurrent          int            `json:"maxConcurrent"`
+	MaxConcurrentNamespace int            `json:"maxConcurrentNamespace"`
+	Builds                 []StagingBuild `json:"builds"`
+}
+
+// AppWebhook represents the server's response to queries and changes of the git webhook
+// of an application. Path is the location of the webhook endpoint, relative to the API
+// root. Secret signs the payloads delivered to the endpoint.
+type AppWebhook struct {
+	Enabled bool   `json:"enabled"`
+	Path    string `json:"path,omitempty"`
+	Secret  string `json:"secret,omitempty"`
+}
+
+// GitWebhookResponse represents the server's response to the delivery of a git push
+// notification. Reason tells why a notification did not trigger a build.
+type GitWebhookResponse struct {
+	Triggered bool   `json:"triggered"`
+	Commit    string `json:"commit,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// BuildCache represents the server's response to a query for the build cache of an
+// application. Size is the capacity of the cache volume. LastUsed is the time of the
+// last staging using the cache, if known.
+type BuildCache struct {
+	App          AppRef `json:"app"`
+	Name         string `json:"name"`
+	Size         string `json:"size,omitempty"`
+	StorageClass string `json:"storageClass,omitempty"`
+	Status       string `json:"status,omitempty"`
+	CreatedAt    string `json:"createdAt,omitempty"`
+	LastUsed     string `json:"lastUsed,omitempty"`
 }
 
 // DeployRequest represents and contains the data needed to deploy an application
@@ -330,6 +508,7 @@ type Service struct {
 	CatalogServiceVersion string        `json:"catalog_service_version,omitempty"`
 	Status                ServiceStatus `json:"status,omitempty"`
 	BoundApps             []string      `json:"boundapps"`
+	Protected             bool          `json:"protected,omitempty"` // protected against deletion
 }
 
 func (s Service) Namespace() string {
@@ -380,3 +559,143 @@ type AppChartList []AppChart
 type ChartMatchResponse struct {
 	Names []string `json:"names,omitempty"`
 }
+
+// BlobGCReport represents the result of a garbage collection of the source blobs. Blobs
+// is the number of stored blobs, Referenced the number of those still referenced by an
+// application or staging job. Orphans are the unreferenced blobs older than the grace
+// period. Younger unreferenced blobs are counted as Recent, and kept. Unless DryRun, the
+// orphans were deleted, freeing Reclaimed bytes. Uploads are the incomplete uploads
+// initiated longer than the grace period ago, which were aborted unless DryRun.
+type BlobGCReport struct {
+	DryRun      bool            `json:"dryrun"`
+	GracePeriod string          `json:"graceperiod"`
+	Blobs       int             `json:"blobs"`
+	Referenced  int             `json:"referenced"`
+	Recent      int             `json:"recent"`
+	Orphans     []OrphanBlob    `json:"orphans,omitempty"`
+	Uploads     []ExpiredUpload `json:"uploads,omitempty"`
+	Reclaimed   int64           `json:"reclaimed"`
+}
+
+// ExpiredUpload describes an abandoned upload of application sources. The application
+// and namespace are those the upload was started for.
+type ExpiredUpload struct {
+	BlobUID   string `json:"blobuid"`
+	Namespace string `json:"namespace,omitempty"`
+	App       string `json:"app,omitempty"`
+	Initiated string `json:"initiated"`
+	Aborted   bool   `json:"aborted,omitempty"`
+}
+
+// OrphanBlob describes a source blob no longer referenced. The application and namespace
+// are those the blob was uploaded for.
+type OrphanBlob struct {
+	BlobUID      string `json:"blobuid"`
+	Namespace    string `json:"namespace,omitempty"`
+	App          string `json:"app,omitempty"`
+	Size         int64  `json:"size"`
+	LastModified string `json:"lastModified"`
+	Deleted      bool   `json:"deleted,omitempty"`
+}
+
+// ImageGCReport represents the result of a garbage collection of the application images
+// in the Epinio registry. Retention is the number of images kept per application, as
+// configured for the server, and Retentions the numbers used for the namespaces whose
+// images were collected. Images is the number of images found, Kept the number of those
+// kept. Unless DryRun, the Removed images were deleted. Reclaimed estimates the bytes of
+// the layers used only by the removed images. The registry frees them with its own
+// garbage collection.
+type ImageGCReport struct {
+	DryRun     bool           `json:"dryrun"`
+	Retention  int            `json:"retention"`
+	Retentions map[string]int `json:"retentions,omitempty"`
+	Images     int            `json:"images"`
+	Kept       int            `json:"kept"`
+	Removed    []RemovedImage `json:"removed,omitempty"`
+	Reclaimed  int64          `json:"reclaimed"`
+}
+
+// RemovedImage describes an application image removed from the registry. Size is the
+// total size of its layers, including those shared with other images.
+type RemovedImage struct {
+	Namespace string `json:"namespace"`
+	App       string `json:"app"`
+	Tag       string `json:"tag"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+	Deleted   bool   `json:"deleted,omitempty"`
+}
+
+// AppSBOM is the software bill of materials of a staged application image, with the
+// provenance of the image. Documents are the SBOMs the buildpacks generated for the
+// layers of the image. Images built from a Dockerfile have none.
+type AppSBOM struct {
+	Provenance Provenance     `json:"provenance"`
+	Documents  []SBOMDocument `json:"documents,omitempty"`
+}
+
+// Provenance records how a staged application image was built.
+type Provenance struct {
+	App          AppRef      `json:"app"`
+	StageID      string      `json:"stageID"`
+	ImageURL     string      `json:"imageURL"`
+	ImageDigest  string      `json:"imageDigest,omitempty"`
+	BlobUID      string      `json:"blobUID"`
+	GitURL       string      `json:"gitURL,omitempty"`
+	GitCommit    string      `json:"gitCommit,omitempty"`
+	Strategy     string      `json:"strategy"`
+	BuilderImage string      `json:"builderImage"`
+	Username     string      `json:"username"`
+	CreatedAt    metav1.Time `json:"createdAt"`
+}
+
+// SBOMDocument is an SBOM generated by a buildpack. The path locates the document in the
+// SBOM layer of the image, naming the buildpack and the layer it describes. The format is
+// one of `cyclonedx`, `spdx`, and `syft`.
+type SBOMDocument struct {
+	Path    string          `json:"path"`
+	Format  string          `json:"format"`
+	Content json.RawMessage `json:"content"`
+}
+
+// Policy is an admission policy of Epinio, enforced when applications are created,
+// updated, staged and deployed. Policies without namespace apply to all namespaces. List
+// entries are patterns, `*` matching any characters but `/`. An empty list or a zero
+// maximum sets no restriction.
+type Policy struct {
+	Name               string   `json:"name"`
+	Namespace          string   `json:"namespace,omitempty"`
+	AllowedRegistries  []string `json:"allowedRegistries,omitempty"`
+	AllowedBuilders    []string `json:"allowedBuilders,omitempty"`
+	MaxInstances       int32    `json:"maxInstances,omitempty"`
+	RequireHealthCheck bool     `json:"requireHealthCheck,omitempty"`
+	ForbiddenEnv       []string `json:"forbiddenEnv,omitempty"`
+}
+
+// PolicyList is a collection of policies
+type PolicyList []Policy
+
+// PolicySubject describes the application settings evaluated by the policies. Unset
+// fields are not evaluated. It is also the request of the dry-run evaluation.
+type PolicySubject struct {
+	App            string   `json:"app,omitempty"`
+	Instances      *int32   `json:"instances,omitempty"`
+	Environment    []string `json:"environment,omitempty"`
+	ContainerImage string   `json:"containerImage,omitempty"`
+	Builder        string   `json:"builder,omitempty"`
+	AppChart       string   `json:"appChart,omitempty"`
+}
+
+// PolicyViolation describes a rule of a policy the evaluated settings break.
+type PolicyViolation struct {
+	Policy    string `json:"policy"`
+	Namespace string `json:"namespace,omitempty"`
+	Rule      string `json:"rule"`
+	Message   string `json:"message"`
+}
+
+// PolicyEvaluationResponse is the result of the dry-run evaluation of the policies.
+type PolicyEvaluationResponse struct {
+	Allowed    bool              `json:"allowed"`
+	Violations []PolicyViolation `json:"violations,omitempty"`
+}
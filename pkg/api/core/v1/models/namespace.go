@@ -1,11 +1,16 @@
 package models
 
-// Namespace has all the namespace properties, i.e. name, app names, and configuration names
+// Namespace has all the namespace properties, i.e. name, app names, configuration names,
+// the quota of the namespace with its usage, if it has one, and its protection against
+// deletion.
 // It is used in the CLI and API responses.
 type Namespace struct {
-	Meta           MetaLite `json:"meta,omitempty"`
-	Apps           []string `json:"apps,omitempty"`
-	Configurations []string `json:"configurations,omitempty"`
+	Meta           MetaLite        `json:"meta,omitempty"`
+	Apps           []string        `json:"apps,omitempty"`
+	Configurations []string        `json:"configurations,omitempty"`
+	Quota          *NamespaceQuota `json:"quota,omitempty"`
+	Usage          *NamespaceUsage `json:"usage,omitempty"`
+	Protected      bool            `json:"protected,omitempty"`
 }
 
 // NamespaceList is a collection of namespaces
@@ -31,3 +36,92 @@ func (al NamespaceList) Swap(i, j int) {
 func (al NamespaceList) Less(i, j int) bool {
 	return al[i].Meta.Name < al[j].Meta.Name
 }
+
+// RegistryCredential is the credential of a private container registry, used to pull
+// the images of the applications in a namespace. The password is not returned.
+type RegistryCredential struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	Username string `json:"username"`
+}
+
+// RegistryCredentialList is a collection of registry credentials
+type RegistryCredentialList []RegistryCredential
+
+// RegistryCredentialCreateRequest contains the registry credential to add to a namespace
+type RegistryCredentialCreateRequest struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// NamespaceQuota limits the resources of a namespace. Zero counts and empty quantities
+// set no limit. Memory and CPU are kubernetes quantities limiting the resources
+// requested by the workloads of the namespace.
+type NamespaceQuota struct {
+	Apps           int32  `json:"apps,omitempty"`
+	Instances      int32  `json:"instances,omitempty"`
+	Services       int32  `json:"services,omitempty"`
+	Configurations int32  `json:"configurations,omitempty"`
+	Memory         string `json:"memory,omitempty"`
+	CPU            string `json:"cpu,omitempty"`
+}
+
+// IsEmpty returns true if the quota sets no limit at all.
+func (q NamespaceQuota) IsEmpty() bool {
+	return q == NamespaceQuota{}
+}
+
+// NamespaceUsage is the use of the resources limited by a namespace quota.
+type NamespaceUsage struct {
+	Apps           int32  `json:"apps"`
+	Instances      int32  `json:"instances"`
+	Services       int32  `json:"services"`
+	Configurations int32  `json:"configurations"`
+	Memory         string `json:"memory,omitempty"`
+	CPU            string `json:"cpu,omitempty"`
+}
+
+// NamespaceQuotaResponse contains the quota of a namespace, and the use of the limited
+// resources.
+type NamespaceQuotaResponse struct {
+	Quota NamespaceQuota `json:"quota"`
+	Usage NamespaceUsage `json:"usage"`
+}
+
+// NamespaceRestoreResponse lists what the restore of a namespace archive created. The
+// warnings report the parts of the archive which could not be restored, e.g. services
+// whose catalog service is gone, or applications whose image is missing.
+type NamespaceRestoreResponse struct {
+	Namespace      string   `json:"namespace"`
+	Apps           []string `json:"apps,omitempty"`
+	Configurations []string `json:"configurations,omitempty"`
+	Services       []string `json:"services,omitempty"`
+	Warnings       []string `json:"warnings,omitempty"`
+}
+
+// NamespaceDeletePreview lists what the deletion of a namespace would remove, and the
+// protected resources preventing it. It is the response of a dry run of the deletion.
+type NamespaceDeletePreview struct {
+	Namespace         string   `json:"namespace"`
+	Apps              []string `json:"apps,omitempty"`
+	Services          []string `json:"services,omitempty"`
+	Configurations    []string `json:"configurations,omitempty"`
+	PVCs              []string `json:"pvcs,omitempty"`
+	Routes            []string `json:"routes,omitempty"`
+	Protected         bool     `json:"protected,omitempty"`
+	ProtectedApps     []string `json:"protectedApps,omitempty"`
+	ProtectedServices []string `json:"protectedServices,omitempty"`
+}
+
+// IsBlocked returns true if protected resources prevent the deletion of the namespace.
+func (p NamespaceDeletePreview) IsBlocked() bool {
+	return p.Protected || len(p.ProtectedApps) > 0 || len(p.ProtectedServices) > 0
+}
+
+// ProtectionRequest sets or clears the protection of a namespace, application or service
+// against deletion.
+type ProtectionRequest struct {
+	Protected bool `json:"protected"`
+}